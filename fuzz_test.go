@@ -0,0 +1,93 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/OmSingh2003/CATALOG-ASSIGNMENT/pkg/lagrange"
+	"github.com/OmSingh2003/CATALOG-ASSIGNMENT/pkg/share"
+)
+
+// FuzzParseInput feeds arbitrary documents to every input format and checks
+// that a parse that succeeds gives complete shares.
+func FuzzParseInput(f *testing.F) {
+	fixtures, err := filepath.Glob("testcase*")
+	if err != nil {
+		f.Fatal(err)
+	}
+	vectors, err := filepath.Glob("pkg/selftest/vectors/*.json")
+	if err != nil {
+		f.Fatal(err)
+	}
+	for _, path := range append(fixtures, vectors...) {
+		if strings.HasSuffix(path, ".expected.json") {
+			continue
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(data, filepath.Ext(path))
+	}
+	f.Add([]byte(`{"keys": {"n": 2, "k": 2}, "1": {"base": "10", "value": ""}, "2": {"base": "10", "value": "7"}}`), ".json")
+	f.Add([]byte(`{"keys": {"n": 2, "k": 2}, "1": {"base": "99999999999999999999", "value": "1"}}`), ".json")
+	f.Add([]byte(`{"keys": {"n": 2, "k": 1}, "1": {"base": "10", "value": "1"}, "1": {"base": "10", "value": "2"}}`), ".json")
+	f.Add([]byte(`{"keys": {"n": 1, "k": 1}, "1": null}`), ".json")
+	f.Add([]byte("x,y\n1,\n"), ".csv")
+
+	saved := logger
+	f.Cleanup(func() { logger = saved })
+	logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+	f.Fuzz(func(t *testing.T, data []byte, ext string) {
+		for _, format := range []string{"auto", "json", "csv", "yaml", "toml", "cbor", "msgpack"} {
+			shares, cases, err := parseInput(bytes.NewReader(data), "input"+ext, format, 2, true, nil)
+			switch {
+			case err != nil:
+				continue
+			case shares == nil && cases == nil:
+				t.Fatalf("%s: no shares, test cases or error", format)
+			case shares == nil:
+				for _, c := range cases {
+					checkParsedShares(t, format, c.Shares)
+				}
+			default:
+				checkParsedShares(t, format, shares)
+			}
+		}
+	})
+}
+
+// checkParsedShares fails t unless every share of s has an x and a y and s
+// does not differ from itself.
+func checkParsedShares(t *testing.T, format string, s *share.Shares) {
+	t.Helper()
+	if s == nil {
+		t.Fatalf("%s: nil test case shares", format)
+	}
+	for i, point := range s.Points {
+		if point.X == nil || point.Y == nil {
+			t.Fatalf("%s: share %d has x=%v y=%v", format, i, point.X, point.Y)
+		}
+	}
+	if diffs := share.Diff(s, s); len(diffs) > 0 {
+		t.Fatalf("%s: shares differ from themselves: %+v", format, diffs)
+	}
+	// Interpolation must not panic on anything that parses; bounding the
+	// shares keeps an iteration fast.
+	points, _, err := s.Select()
+	if err != nil || len(points) > 16 {
+		return
+	}
+	for _, point := range points {
+		if point.X.BitLen() > 256 || point.Y.BitLen() > 256 {
+			return
+		}
+	}
+	lagrange.InterpolateAt(points, big.NewInt(0))
+}
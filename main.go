@@ -1,147 +1,4240 @@
 package main
 
 import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
+	"flag"
 	"fmt"
+	"io"
+	"log"
+	"log/slog"
 	"math/big"
+	"net"
+	"net/http"
+	"net/url"
 	"os"
+	"path/filepath"
+	"runtime/debug"
+	"sort"
 	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+	"unicode/utf8"
+
+	"google.golang.org/grpc"
+
+	"github.com/OmSingh2003/CATALOG-ASSIGNMENT/pkg/envelope"
+	"github.com/OmSingh2003/CATALOG-ASSIGNMENT/pkg/fileshare"
+	"github.com/OmSingh2003/CATALOG-ASSIGNMENT/pkg/gf256"
+	"github.com/OmSingh2003/CATALOG-ASSIGNMENT/pkg/gf2m"
+	"github.com/OmSingh2003/CATALOG-ASSIGNMENT/pkg/grpcserver"
+	"github.com/OmSingh2003/CATALOG-ASSIGNMENT/pkg/lagrange"
+	"github.com/OmSingh2003/CATALOG-ASSIGNMENT/pkg/reconstruct"
+	"github.com/OmSingh2003/CATALOG-ASSIGNMENT/pkg/report"
+	"github.com/OmSingh2003/CATALOG-ASSIGNMENT/pkg/secret"
+	"github.com/OmSingh2003/CATALOG-ASSIGNMENT/pkg/selftest"
+	"github.com/OmSingh2003/CATALOG-ASSIGNMENT/pkg/server"
+	"github.com/OmSingh2003/CATALOG-ASSIGNMENT/pkg/share"
+	"github.com/OmSingh2003/CATALOG-ASSIGNMENT/pkg/terminal"
+	"github.com/OmSingh2003/CATALOG-ASSIGNMENT/pkg/testvectors"
+	"github.com/OmSingh2003/CATALOG-ASSIGNMENT/pkg/watch"
+	shamirv1 "github.com/OmSingh2003/CATALOG-ASSIGNMENT/proto/shamir/v1"
+)
+
+func parseModulus(s string) (*big.Int, error) {
+	digits, base := s, 10
+	if strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X") {
+		digits, base = s[2:], 16
+	}
+
+	modulus, ok := new(big.Int).SetString(digits, base)
+	if !ok {
+		return nil, fmt.Errorf("invalid modulus: %s", s)
+	}
+	if modulus.Cmp(big.NewInt(2)) < 0 {
+		return nil, fmt.Errorf("invalid modulus %s: must be at least 2", s)
+	}
+	return modulus, nil
+}
+
+// checkPrime refuses a composite modulus unless allowComposite is set:
+// interpolation divides by differences of x values, which only a prime
+// modulus guarantees to be invertible.
+func checkPrime(modulus *big.Int, allowComposite bool) error {
+	if allowComposite || modulus.ProbablyPrime(20) {
+		return nil
+	}
+	return usagef("invalid modulus %s: not a prime (use --allow-composite to use it anyway)", modulus.String())
+}
+
+// shareBinaryField returns the binary field of shares over GF(2^m): that of
+// the given polynomial, which must agree with any the keys object records,
+// or else of the recorded one.
+func shareBinaryField(given *big.Int, shares *share.Shares) (*gf2m.Field, error) {
+	poly := cmp.Or(given, shares.Poly)
+	switch {
+	case poly == nil:
+		return nil, usagef("--field gf2m needs --poly or shares whose keys object records their poly")
+	case given != nil && shares.Poly != nil && given.Cmp(shares.Poly) != 0:
+		return nil, usagef("--poly %#x differs from the poly %#x the keys object records", given, shares.Poly)
+	}
+	f, err := gf2m.New(poly)
+	if err != nil && given != nil {
+		return nil, classify(exitUsage, err)
+	}
+	return f, classify(exitInvalid, err)
+}
+
+// listFields prints the named prime fields --field accepts.
+func listFields(w io.Writer) {
+	for _, preset := range share.FieldPresets() {
+		fmt.Fprintf(w, "%-12s %d-bit, %s\n%12s %s\n", preset.Name, preset.Modulus.BitLen(), preset.Description, "", preset.Modulus.String())
+	}
+}
+
+// shareModulus returns the modulus to interpolate shares with: the given
+// one, which must agree with any the keys object records, or else the
+// recorded one. With auto the shares must record one, and shares with
+// commitments must have one, since they commit to a polynomial over a
+// prime field.
+func shareModulus(given *big.Int, auto, allowComposite bool, shares *share.Shares) (*big.Int, error) {
+	recorded := shares.Modulus
+	switch {
+	case recorded == nil && auto:
+		return nil, usagef("--mod auto needs shares whose keys object records their modulus")
+	case recorded == nil && given == nil && shares.Commitments != nil:
+		return nil, usagef("%s has %s commitments but its keys object records no modulus; give the --mod it was split with", shares.Source, commitmentName(shares.Commitments.Scheme))
+	case recorded == nil:
+		return given, nil
+	case given != nil && given.Cmp(recorded) != 0 && shares.Field != "":
+		return nil, usagef("the modulus %s given differs from that of the field %s the keys object records", given.String(), shares.Field)
+	case given != nil && given.Cmp(recorded) != 0:
+		return nil, usagef("the modulus %s given differs from the modulus %s the keys object records", given.String(), recorded.String())
+	}
+	if given == nil {
+		if err := checkPrime(recorded, allowComposite); err != nil {
+			return nil, err
+		}
+	}
+	return recorded, nil
+}
+
+func parseSecret(s string) (*big.Int, error) {
+	trimmed := strings.TrimSpace(s)
+	digits, base := trimmed, 10
+	negative := strings.HasPrefix(digits, "-")
+	if negative {
+		digits = digits[1:]
+	}
+	if strings.HasPrefix(digits, "0x") || strings.HasPrefix(digits, "0X") {
+		digits, base = digits[2:], 16
+	}
+
+	secret, ok := new(big.Int).SetString(digits, base)
+	if !ok || strings.HasPrefix(digits, "-") || strings.HasPrefix(digits, "+") {
+		return nil, fmt.Errorf("invalid secret: %q", trimmed)
+	}
+	if negative {
+		secret.Neg(secret)
+	}
+	return secret, nil
+}
+
+// checkThreshold rejects a --k and --n that no split satisfies.
+func checkThreshold(n, k int) error {
+	if k < 1 {
+		return usagef("invalid --k %d: must be at least 1", k)
+	}
+	if n < k {
+		return usagef("invalid --n %d: must be at least --k %d", n, k)
+	}
+	return nil
+}
+
+func parseBases(s string, n int) ([]string, error) {
+	fields := strings.Split(s, ",")
+	if len(fields) != 1 && len(fields) != n {
+		return nil, usagef("expected 1 or %d bases, got %d", n, len(fields))
+	}
+
+	bases := make([]string, 0, len(fields))
+	for _, field := range fields {
+		base := strings.TrimSpace(field)
+		if !share.ValidBase(base) {
+			return nil, usagef("invalid base: %s", field)
+		}
+		bases = append(bases, base)
+	}
+	return bases, nil
+}
+
+func runSplit(inv *invocation, args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("split", stderr)
+	secretFlag := fs.String("secret", "-", "secret to split (decimal or 0x hex), or - to read it from stdin")
+	nFlag := fs.Int("n", 0, "number of shares to generate")
+	kFlag := fs.Int("k", 0, "number of shares required to reconstruct")
+	baseFlag := fs.String("base", "10", "output base for the share values (2-62, 64, 64url or 85), or a comma-separated base per share")
+	modFlag := fs.String("mod", "", "split over the field of integers modulo this prime (decimal or 0x hex), or auto for the smallest prime of the next power-of-two bit length, at least 64, above the secret; the keys object records it")
+	allowCompositeFlag := fs.Bool("allow-composite", false, "accept a --mod that is not prime")
+	outFlag := fs.String("out", "", "write the share file here instead of stdout, or with --per-share-files or --in the directory to write the share files to")
+	forceFlag := fs.Bool("force", false, "let --out overwrite an existing share file")
+	formatFlag := fs.String("format", "json", "output format: json, cbor or msgpack")
+	textFlag := fs.Bool("text", false, "treat the secret as UTF-8 text and split its bytes")
+	inFlag := fs.String("in", "", "split the bytes of this file, or - for stdin, instead of --secret, a chunk at a time over GF(256), streaming a share file per share to the --out directory")
+	chunkSizeFlag := fs.Int("chunk-size", 64*1024, "with --in, the number of bytes of the file in each chunk")
+	fieldFlag := fs.String("field", "integer", "field to split over: integer, gf256 to share each byte of a hex or --text secret, gf2m for the binary field of --poly, a named prime field (secp256k1, p256, ed25519 or mersenne127) the keys object records, or list to print the named fields")
+	polyFlag := fs.String("poly", "", "with --field gf2m, the irreducible polynomial of degree m reducing GF(2^m), as a bit-vector in 0x hex or decimal: 0x20000000000000000000000000000000000000004000000000000000001 is x^233 + x^74 + 1")
+	vssFlag := fs.String("vss", share.SchemeFeldman, "commitments written with --mod: feldman, pedersen or none")
+	noSecretHashFlag := fs.Bool("no-secret-hash", false, "do not record the SHA-256 of the secret, which reconstruct checks its result against, in the keys object (it is never recorded with --vss pedersen)")
+	mnemonicFlag := fs.Bool("mnemonic", false, "write each share as a SLIP-39 style mnemonic on its own line (implies --field gf256)")
+	encryptFlag := fs.Bool("encrypt", false, "encrypt the share file under a passphrase, prompted for unless --passphrase-file is set")
+	fs.StringVar(&inv.passphraseFile, "passphrase-file", "", "read the --encrypt passphrase from the first line of this file")
+	seedFlag := fs.String("seed", "", "derive the coefficients from this hex seed instead of crypto/rand, for reproducible test fixtures (requires --insecure-deterministic)")
+	insecureFlag := fs.Bool("insecure-deterministic", false, "confirm that --seed shares are test fixtures that protect nothing")
+	perShareFlag := fs.Bool("per-share-files", false, "write each share to a file of its own in the --out directory, named by --name-template")
+	nameTemplateFlag := fs.String("name-template", "share_{x}.json", "with --per-share-files, the file name of each share; {x} is replaced by its x")
+	manifestFlag := fs.String("manifest", "", "with --per-share-files, also write a manifest listing the share files, without their values, to this file in the --out directory")
+	participantsFlag := fs.String("participants", "", "with --per-share-files, give the shares to the participants listed in this JSON file, [{\"name\": ..., \"weight\": ...}], weight shares each in a file per participant; --n defaults to the total weight")
+	installLogger := logFlags(fs, stderr)
+	if err := fs.Parse(args); err != nil {
+		return usageError{err}
+	}
+	if fs.NArg() > 0 {
+		return usagef("split takes no arguments, but got %q: give the secret with --secret", fs.Arg(0))
+	}
+	if err := installLogger(); err != nil {
+		return err
+	}
+	if *fieldFlag == "list" {
+		listFields(stdout)
+		return nil
+	}
+
+	var participants []share.Participant
+	if *participantsFlag != "" {
+		if !*perShareFlag {
+			return usagef("--participants requires --per-share-files")
+		}
+		data, err := os.ReadFile(*participantsFlag)
+		if err != nil {
+			return classify(exitIO, fmt.Errorf("failed to read participants: %w", err))
+		}
+		if participants, err = share.ParseParticipants(bytes.NewReader(data)); err != nil {
+			return err
+		}
+		for _, p := range participants {
+			if strings.ContainsAny(p.Name, `/\`) || p.Name == "." || p.Name == ".." {
+				return classify(exitInvalid, fmt.Errorf("invalid participants: %q cannot be part of a file name", p.Name))
+			}
+		}
+		switch total := share.TotalWeight(participants); {
+		case *nFlag == 0:
+			*nFlag = total
+		case *nFlag != total:
+			return usagef("--n is %d, but the participants hold weight %d in total", *nFlag, total)
+		}
+		templateSet := false
+		fs.Visit(func(f *flag.Flag) { templateSet = templateSet || f.Name == "name-template" })
+		if !templateSet {
+			*nameTemplateFlag = "share_{name}.json"
+		}
+	}
+	if err := checkThreshold(*nFlag, *kFlag); err != nil {
+		return err
+	}
+
+	if *perShareFlag {
+		if *mnemonicFlag || *fieldFlag == share.FieldGF256 || *formatFlag != "json" {
+			return usagef("--per-share-files is not supported together with --mnemonic, --field gf256 or a --format other than json")
+		}
+		placeholder := "{x}"
+		if participants != nil {
+			placeholder = "{name}"
+		}
+		if !strings.Contains(*nameTemplateFlag, placeholder) || strings.ContainsAny(*nameTemplateFlag, `/\`) {
+			return usagef("invalid --name-template: must be a file name containing %s", placeholder)
+		}
+	} else if *manifestFlag != "" {
+		return usagef("--manifest requires --per-share-files")
+	}
+
+	var seed []byte
+	if *seedFlag != "" {
+		if !*insecureFlag {
+			return usagef("--seed makes the shares predictable to anyone who knows it; add --insecure-deterministic to confirm they are only test fixtures")
+		}
+		if *encryptFlag || *mnemonicFlag || *formatFlag != "json" {
+			return usagef("--seed is not supported together with --encrypt, --mnemonic or a --format other than json")
+		}
+		digits := strings.TrimPrefix(strings.TrimPrefix(strings.TrimSpace(*seedFlag), "0x"), "0X")
+		var err error
+		if seed, err = hex.DecodeString(digits); err != nil || len(seed) == 0 {
+			return usagef("invalid --seed: must be non-empty hex")
+		}
+		logger.Warn("--seed makes every share predictable; never use these shares for a real secret")
+	} else if *insecureFlag {
+		return usagef("--insecure-deterministic requires --seed")
+	}
+
+	if *mnemonicFlag {
+		*fieldFlag = share.FieldGF256
+		if *encryptFlag {
+			return usagef("--mnemonic is not supported together with --encrypt")
+		}
+	}
+
+	switch *formatFlag {
+	case "json", "cbor", "msgpack":
+	default:
+		return usagef("unknown output format: %s", *formatFlag)
+	}
+	if *inFlag != "" {
+		var conflict string
+		base := "16"
+		fs.Visit(func(f *flag.Flag) {
+			switch f.Name {
+			case "secret", "text", "mod", "allow-composite", "poly", "vss", "no-secret-hash", "mnemonic", "encrypt", "passphrase-file", "per-share-files", "manifest", "participants":
+				conflict = f.Name
+			case "base":
+				base = strings.TrimSpace(*baseFlag)
+			}
+		})
+		switch {
+		case conflict != "":
+			return usagef("--in is not supported together with --%s", conflict)
+		case *fieldFlag != "integer" && *fieldFlag != share.FieldGF256 || *formatFlag != "json":
+			return usagef("--in only splits over --field gf256 into json share files")
+		case *chunkSizeFlag < 1:
+			return usagef("invalid --chunk-size %d: must be positive", *chunkSizeFlag)
+		}
+		dir := *outFlag
+		if dir == "" {
+			dir = "."
+		}
+		return inv.splitFile(*inFlag, dir, *nameTemplateFlag, *nFlag, *kFlag, *chunkSizeFlag, base, seed)
+	}
+	chunkSizeSet := false
+	fs.Visit(func(f *flag.Flag) { chunkSizeSet = chunkSizeSet || f.Name == "chunk-size" })
+	if chunkSizeSet {
+		return usagef("--chunk-size requires --in")
+	}
+	var preset *share.FieldPreset
+	var binary *gf2m.Field
+	if *polyFlag != "" && *fieldFlag != share.FieldGF2m {
+		return usagef("--poly requires --field gf2m")
+	}
+	switch *fieldFlag {
+	case "integer":
+	case share.FieldGF256:
+		if *modFlag != "" || *formatFlag != "json" {
+			return usagef("--field gf256 is not supported together with --mod or a --format other than json")
+		}
+	case share.FieldGF2m:
+		var conflict string
+		fs.Visit(func(f *flag.Flag) {
+			if f.Name == "mod" || f.Name == "vss" || f.Name == "mnemonic" {
+				conflict = f.Name
+			}
+		})
+		if conflict != "" || *formatFlag != "json" {
+			return usagef("--field gf2m is not supported together with --mod, --vss, --mnemonic or a --format other than json")
+		}
+		if *polyFlag == "" {
+			return usagef("--field gf2m requires --poly")
+		}
+		poly, err := share.ParsePoly(*polyFlag)
+		if err != nil {
+			return classify(exitUsage, err)
+		}
+		if binary, err = gf2m.New(poly); err != nil {
+			return classify(exitUsage, err)
+		}
+	default:
+		if preset = share.LookupField(*fieldFlag); preset == nil {
+			return usagef("unknown field: %s", *fieldFlag)
+		}
+		if *modFlag != "" {
+			return usagef("--field %s already gives the modulus; drop --mod", preset.Name)
+		}
+	}
+
+	secretText := *secretFlag
+	if secretText == "-" {
+		input, err := io.ReadAll(inv.stdin)
+		if err != nil {
+			return classify(exitIO, fmt.Errorf("failed to read secret from stdin: %w", err))
+		}
+		secretText = string(input)
+	}
+	if *fieldFlag == share.FieldGF256 {
+		base := "16"
+		var baseSet bool
+		fs.Visit(func(f *flag.Flag) {
+			if f.Name == "base" {
+				base, baseSet = strings.TrimSpace(*baseFlag), true
+			}
+		})
+		if *mnemonicFlag && baseSet {
+			return usagef("--mnemonic is not supported together with --base")
+		}
+		return inv.splitGF256(stdout, secretText, *textFlag, *nFlag, *kFlag, base, *mnemonicFlag, *encryptFlag, seed, *outFlag, *forceFlag)
+	}
+
+	var secret *big.Int
+	var err error
+	if *textFlag {
+		secret = new(big.Int).SetBytes([]byte(secretText))
+	} else if secret, err = parseSecret(secretText); err != nil {
+		return classify(exitUsage, err)
+	}
+
+	var modulus *big.Int
+	switch *modFlag {
+	case "":
+		if preset != nil {
+			modulus = preset.Modulus
+		}
+	case "auto":
+		if modulus, err = share.AutoModulus(secret); err != nil {
+			return classify(exitUsage, err)
+		}
+		fmt.Fprintf(errOut, "Using the %d-bit prime modulus %s\n", modulus.BitLen(), modulus.String())
+	default:
+		if modulus, err = parseModulus(*modFlag); err != nil {
+			return classify(exitUsage, err)
+		}
+		if err := checkPrime(modulus, *allowCompositeFlag); err != nil {
+			return err
+		}
+	}
+	if modulus != nil && (secret.Sign() < 0 || secret.Cmp(modulus) >= 0) {
+		return usagef("secret must be in the range [0, %s) in modular mode", modulus)
+	}
+	if binary != nil && !binary.Contains(secret) {
+		return usagef("secret must be an element of %s: a non-negative integer of at most %d bits", binary, binary.Degree())
+	}
+
+	bases, err := parseBases(*baseFlag, *nFlag)
+	if err != nil {
+		return err
+	}
+
+	scheme := *vssFlag
+	switch scheme {
+	case share.SchemeFeldman, share.SchemePedersen:
+	case "none":
+		scheme = ""
+	default:
+		return usagef("unknown --vss: %s", *vssFlag)
+	}
+	vssSet := false
+	fs.Visit(func(f *flag.Flag) { vssSet = vssSet || f.Name == "vss" })
+	if vssSet && scheme != "" && modulus == nil {
+		return usagef("--vss requires --mod")
+	}
+
+	splitOpts := []share.SplitOption{share.WithCommitments(scheme)}
+	if *noSecretHashFlag {
+		splitOpts = append(splitOpts, share.WithSecretHash(false))
+	}
+	if seed != nil {
+		splitOpts = append(splitOpts, share.WithSeed(seed))
+	}
+	var shares *share.Shares
+	if binary != nil {
+		shares, err = splitBinary(binary, secret, *nFlag, *kFlag, seed, !*noSecretHashFlag)
+	} else {
+		shares, err = share.Split(secret, *nFlag, *kFlag, modulus, splitOpts...)
+	}
+	if err != nil {
+		return err
+	}
+	switch {
+	case scheme != "" && modulus != nil && shares.Commitments == nil:
+		logger.Warn("--mod is not prime, so the shares carry no commitments", "scheme", commitmentName(scheme))
+	case shares.Commitments != nil && *formatFlag != "json":
+		logger.Warn("the output format does not carry the commitments", "format", *formatFlag, "scheme", commitmentName(scheme))
+	}
+
+	if preset != nil {
+		shares.Field = preset.Name
+	}
+	if participants != nil {
+		if err := share.AssignParticipants(shares, participants); err != nil {
+			return err
+		}
+	}
+	if *perShareFlag {
+		dir := *outFlag
+		if dir == "" {
+			dir = "."
+		}
+		return inv.writePerShareFiles(dir, *nameTemplateFlag, *manifestFlag, shares, bases, *encryptFlag)
+	}
+
+	var buf bytes.Buffer
+	switch *formatFlag {
+	case "cbor":
+		err = share.WriteCBOR(&buf, shares)
+	case "msgpack":
+		err = share.WriteMsgpack(&buf, shares)
+	default:
+		err = share.WriteShares(&buf, shares, bases)
+	}
+	if err != nil {
+		return err
+	}
+	return inv.writeSplitOutput(stdout, *outFlag, buf.Bytes(), *encryptFlag, *forceFlag)
+}
+
+// shareManifest is the manifest --per-share-files writes: which file holds
+// which share, but none of the share values.
+type shareManifest struct {
+	Version int                  `json:"version"`
+	N       int                  `json:"n"`
+	K       int                  `json:"k"`
+	Shares  []shareManifestEntry `json:"shares"`
+}
+
+type shareManifestEntry struct {
+	X           string `json:"x"`
+	File        string `json:"file"`
+	Base        string `json:"base"`
+	Participant string `json:"participant,omitempty"`
+}
+
+// writePerShareFiles writes every share of shares to a file of its own in
+// dir, named by template with {x} replaced by the share's x, and the
+// manifest to dir/manifest unless it is empty. Shares given to
+// participants are written to a file per participant instead, with {name}
+// replaced by its name. Nothing is written if any of the files already
+// exists.
+func (inv *invocation) writePerShareFiles(dir, template, manifest string, shares *share.Shares, bases []string, encrypt bool) error {
+	manifestData := shareManifest{Version: share.CurrentVersion, N: shares.N, K: shares.K}
+	var paths []string
+	for i, point := range shares.Points {
+		name := strings.ReplaceAll(template, "{x}", point.X.String())
+		if point.Participant != "" {
+			name = strings.ReplaceAll(template, "{name}", point.Participant)
+		}
+		manifestData.Shares = append(manifestData.Shares, shareManifestEntry{X: point.X.String(), File: name, Base: bases[i%len(bases)], Participant: point.Participant})
+		if point.Participant == "" {
+			paths = append(paths, filepath.Join(dir, name))
+		}
+	}
+	for _, p := range shares.Participants {
+		paths = append(paths, filepath.Join(dir, strings.ReplaceAll(template, "{name}", p.Name)))
+	}
+	if manifest != "" {
+		paths = append(paths, filepath.Join(dir, manifest))
+	}
+	for _, path := range paths {
+		if _, err := os.Lstat(path); err == nil {
+			return classify(exitIO, fmt.Errorf("output file %s already exists", path))
+		}
+	}
+	if encrypt {
+		if _, err := inv.readPassphrase("Passphrase for the share files: ", true); err != nil {
+			return err
+		}
+	}
+
+	for i, p := range shares.Participants {
+		var buf bytes.Buffer
+		if err := share.WriteParticipant(&buf, shares, p.Name, bases); err != nil {
+			return err
+		}
+		if err := inv.writePrivateFile(paths[i], buf.Bytes(), encrypt); err != nil {
+			return err
+		}
+	}
+	for i := range shares.Points {
+		if shares.Participants != nil {
+			break
+		}
+		var buf bytes.Buffer
+		if err := share.WriteShare(&buf, shares, i, bases[i%len(bases)]); err != nil {
+			return err
+		}
+		if err := inv.writePrivateFile(paths[i], buf.Bytes(), encrypt); err != nil {
+			return err
+		}
+	}
+	if manifest != "" {
+		data, _ := json.MarshalIndent(manifestData, "", "    ")
+		if err := inv.writePrivateFile(paths[len(paths)-1], append(data, '\n'), false); err != nil {
+			return err
+		}
+	}
+	files := len(shares.Points)
+	if shares.Participants != nil {
+		files = len(shares.Participants)
+	}
+	fmt.Fprintf(errOut, "Wrote %d share files to %s\n", files, dir)
+	return nil
+}
+
+// writePrivateFile creates path readable by its owner only, failing if it
+// exists, and writes data to it, sealed under the passphrase if encrypt is
+// set.
+func (inv *invocation) writePrivateFile(path string, data []byte, encrypt bool) error {
+	if encrypt {
+		var err error
+		if data, err = envelope.Seal(data, inv.passphrase); err != nil {
+			return err
+		}
+	}
+	file, err := createPrivate(path, false)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// writeSplitOutput writes the share file data to path, or to stdout when
+// path is empty, sealed in a passphrase envelope if encrypt is
+// set. The file is private to the user, and replaces an existing one only
+// with force.
+func (inv *invocation) writeSplitOutput(stdout io.Writer, path string, data []byte, encrypt, force bool) error {
+	if encrypt {
+		passphrase, err := inv.readPassphrase("Passphrase for the share file: ", true)
+		if err != nil {
+			return err
+		}
+		if data, err = envelope.Seal(data, passphrase); err != nil {
+			return err
+		}
+	}
+
+	if path == "" {
+		_, err := stdout.Write(data)
+		return err
+	}
+	return replaceFile(path, force, data)
+}
+
+// readPassphrase returns the passphrase of encrypted share files, the first
+// line of the --passphrase-file or else typed at prompt without echo, twice
+// when confirm is set.
+func (inv *invocation) readPassphrase(prompt string, confirm bool) ([]byte, error) {
+	if inv.passphrase != nil {
+		return inv.passphrase, nil
+	}
+	if inv.passphraseFile != "" {
+		data, err := os.ReadFile(inv.passphraseFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read passphrase file: %w", err)
+		}
+		line, _, _ := strings.Cut(string(data), "\n")
+		inv.passphrase = []byte(strings.TrimSuffix(line, "\r"))
+	} else {
+		typed, err := inv.prompt(prompt)
+		if errors.Is(err, terminal.ErrNoTerminal) {
+			return nil, errors.New("no terminal to prompt for the passphrase on (use --passphrase-file)")
+		}
+		if err != nil {
+			return nil, err
+		}
+		if confirm {
+			again, err := inv.prompt("Repeat the passphrase: ")
+			if err != nil {
+				return nil, err
+			}
+			if !bytes.Equal(typed, again) {
+				return nil, errors.New("passphrases do not match")
+			}
+		}
+		inv.passphrase = typed
+	}
+	if len(inv.passphrase) == 0 {
+		inv.passphrase = nil
+		return nil, errors.New("empty passphrase")
+	}
+	return inv.passphrase, nil
+}
+
+// splitBinary shares secret over the binary field f, recording the field
+// and, if hash is set, the SHA-256 of the secret in the keys object. A
+// non-nil seed replaces crypto/rand as for share.WithSeed.
+func splitBinary(f *gf2m.Field, secret *big.Int, n, k int, seed []byte, hash bool) (*share.Shares, error) {
+	random := rand.Reader
+	if seed != nil {
+		random = share.SeededReader(seed)
+	}
+	points, err := f.Split(random, secret, n, k)
+	if err != nil {
+		return nil, err
+	}
+	shares := &share.Shares{N: n, K: k, Points: points, Deterministic: seed != nil, Field: share.FieldGF2m, Poly: f.Poly()}
+	if hash {
+		shares.SecretSHA256 = share.SecretSHA256(secret)
+	}
+	return shares, nil
+}
+
+// splitGF256 shares the bytes of a secret written in hex, or of text when
+// asText is set, over GF(2^8) and writes them with every value in base, or
+// as one mnemonic per line when mnemonic is set, encrypted if encrypt is
+// set. A non-nil seed replaces crypto/rand as for share.WithSeed.
+func (inv *invocation) splitGF256(stdout io.Writer, secretText string, asText bool, n, k int, base string, mnemonic, encrypt bool, seed []byte, outPath string, force bool) error {
+	data := []byte(secretText)
+	if !asText {
+		digits := strings.TrimSpace(secretText)
+		if len(digits) > 1 && digits[0] == '0' && (digits[1] == 'x' || digits[1] == 'X') {
+			digits = digits[2:]
+		}
+		var err error
+		if data, err = hex.DecodeString(digits); err != nil {
+			return classify(exitUsage, fmt.Errorf("invalid secret: gf256 secrets are written in hex: %w", err))
+		}
+	}
+	switch base {
+	case "16", "64", "64url", "85":
+	default:
+		return usagef("invalid base for gf256 shares: %s (use 16, 64, 64url or 85)", base)
+	}
+	if mnemonic && n > share.MaxMnemonicShares {
+		return usagef("invalid n=%d: mnemonic splits have at most %d shares", n, share.MaxMnemonicShares)
+	}
+	if len(data) == 0 {
+		return usagef("cannot split an empty secret")
+	}
+	if err := gf256.CheckThreshold(n, k); err != nil {
+		return classify(exitUsage, err)
+	}
+
+	random := rand.Reader
+	if seed != nil {
+		random = share.SeededReader(seed)
+	}
+	shares, err := gf256.SplitFrom(random, data, n, k)
+	if err != nil {
+		return err
+	}
+	byteShares := &share.ByteShares{N: n, K: k, Deterministic: seed != nil}
+	for _, s := range shares {
+		byteShares.Points = append(byteShares.Points, share.BytePoint{X: s.X, Y: s.Y})
+	}
+
+	var buf bytes.Buffer
+	if mnemonic {
+		err = share.WriteMnemonics(&buf, byteShares)
+	} else {
+		err = share.WriteByteShares(&buf, byteShares, base)
+	}
+	if err != nil {
+		return err
+	}
+	return inv.writeSplitOutput(stdout, outPath, buf.Bytes(), encrypt, force)
+}
+
+// splitFile shares the bytes of the file at path with fileshare.Split,
+// writing the share at every x to a file of its own in dir named by
+// template. Nothing is written if any of the files already exists, and
+// nothing is left behind if a write fails.
+func (inv *invocation) splitFile(path, dir, template string, n, k, chunkSize int, base string, seed []byte) error {
+	if !strings.Contains(template, "{x}") || strings.ContainsAny(template, `/\`) {
+		return usagef("invalid --name-template: must be a file name containing {x}")
+	}
+	switch base {
+	case "16", "64", "64url", "85":
+	default:
+		return usagef("invalid base for gf256 shares: %s (use 16, 64, 64url or 85)", base)
+	}
+	if err := gf256.CheckThreshold(n, k); err != nil {
+		return classify(exitUsage, err)
+	}
+
+	input, name := io.Reader(inv.stdin), "stdin"
+	if path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			return classify(exitIO, fmt.Errorf("failed to read %s: %w", path, err))
+		}
+		defer file.Close()
+		input, name = file, path
+	}
+
+	paths := make([]string, n)
+	for i := range paths {
+		paths[i] = filepath.Join(dir, strings.ReplaceAll(template, "{x}", strconv.Itoa(i+1)))
+		if _, err := os.Lstat(paths[i]); err == nil {
+			return classify(exitIO, fmt.Errorf("output file %s already exists", paths[i]))
+		}
+	}
+	var files []*os.File
+	abort := func(err error) error {
+		for _, file := range files {
+			file.Close()
+			os.Remove(file.Name())
+		}
+		return err
+	}
+	writers := make([]io.Writer, n)
+	for i, p := range paths {
+		file, err := createPrivate(p, false)
+		if err != nil {
+			return abort(err)
+		}
+		files = append(files, file)
+		writers[i] = file
+	}
+
+	opts := fileshare.Options{ChunkSize: chunkSize, Base: base, Deterministic: seed != nil}
+	if seed != nil {
+		opts.Random = share.SeededReader(seed)
+	}
+	size, err := fileshare.Split(input, writers, k, opts)
+	var readErr *fileshare.ReadError
+	var writeErr *fileshare.WriteError
+	switch {
+	case errors.As(err, &readErr):
+		return abort(classify(exitIO, fmt.Errorf("failed to read %s: %w", name, readErr.Err)))
+	case errors.As(err, &writeErr):
+		return abort(classify(exitIO, fmt.Errorf("failed to write %s: %w", paths[writeErr.X-1], writeErr.Err)))
+	case err != nil:
+		return abort(err)
+	}
+	for i, file := range files {
+		if err := file.Close(); err != nil {
+			return abort(classify(exitIO, fmt.Errorf("failed to write %s: %w", paths[i], err)))
+		}
+	}
+	fmt.Fprintf(errOut, "Wrote %d share files of the %d bytes of %s to %s\n", n, size, name, dir)
+	return nil
+}
+
+// runMigrate upgrades a JSON share file to share.CurrentVersion, rewriting
+// it in place or writing the result to --out.
+func runMigrate(inv *invocation, args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("migrate", stderr)
+	outFlag := fs.String("out", "", "write the migrated file here instead of replacing the input")
+	forceFlag := fs.Bool("force", false, "let --out overwrite an existing file")
+	inputs, err := parseArgs(fs, args)
+	if err != nil {
+		return usageError{err}
+	}
+	if len(inputs) != 1 {
+		return usagef("migrate takes exactly one share file")
+	}
+	path := inputs[0]
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if envelope.IsEnvelope(data) {
+		return fmt.Errorf("%s is encrypted; migrate the decrypted file and encrypt it again", path)
+	}
+	shares, cases, err := share.ParseJSON(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	if cases != nil {
+		return fmt.Errorf("%s: multi-case files cannot be migrated", path)
+	}
+	if shares.Version == share.CurrentVersion && *outFlag == "" {
+		fmt.Fprintf(stdout, "%s is already at version %d\n", path, share.CurrentVersion)
+		return nil
+	}
+
+	// Keep the base every share was written in.
+	bases := make([]string, len(shares.Points))
+	for i, point := range shares.Points {
+		if bases[i] = point.Base; bases[i] == "" {
+			bases[i] = "10"
+		}
+	}
+	var buf bytes.Buffer
+	if err := share.WriteShares(&buf, shares, bases); err != nil {
+		return err
+	}
+
+	target, force := *outFlag, *forceFlag
+	if target == "" {
+		target, force = path, true
+	}
+	if err := replaceFile(target, force, buf.Bytes()); err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "Migrated %s from version %d to version %d\n", path, shares.Version, share.CurrentVersion)
+	return nil
+}
+
+// replaceFile writes data to target, created as by createPrivate, so that
+// with force an existing target is only replaced once data is complete.
+func replaceFile(target string, force bool, data []byte) error {
+	file, err := createPrivate(target, force)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return fmt.Errorf("failed to write %s: %w", target, err)
+	}
+	if err := file.Close(); err != nil {
+		os.Remove(file.Name())
+		return fmt.Errorf("failed to write %s: %w", target, err)
+	}
+	if file.Name() != target {
+		if err := os.Rename(file.Name(), target); err != nil {
+			os.Remove(file.Name())
+			return fmt.Errorf("failed to replace %s: %w", target, err)
+		}
+	}
+	return nil
+}
+
+// runConvert rewrites the values of a share file in other bases, keeping
+// everything else, and checks that the result decodes to the same shares.
+func runConvert(inv *invocation, args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("convert", stderr)
+	toBaseFlag := fs.String("to-base", "", "base to write every share value in (2-62, 64, 64url or 85), or a comma-separated base per share in order of x")
+	forceFlag := fs.Bool("force", false, "overwrite an existing output file, which may be the input itself")
+	inputs, err := parseArgs(fs, args)
+	if err != nil {
+		return usageError{err}
+	}
+	if len(inputs) != 2 {
+		return usagef("convert takes an input and an output share file")
+	}
+	if *toBaseFlag == "" {
+		return usagef("convert requires --to-base")
+	}
+	path, target := inputs[0], inputs[1]
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return classify(exitIO, fmt.Errorf("failed to read %s: %w", path, err))
+	}
+	if envelope.IsEnvelope(data) {
+		return fmt.Errorf("%s is encrypted; convert the decrypted file and encrypt it again", path)
+	}
+	shares, cases, err := share.ParseJSON(bytes.NewReader(data), share.BinaryField())
+	if err != nil {
+		return classify(exitInvalid, fmt.Errorf("%s: %w", path, err))
+	}
+	if cases != nil {
+		return fmt.Errorf("%s: multi-case files cannot be converted", path)
+	}
+	bases, err := parseBases(*toBaseFlag, len(shares.Points))
+	if err != nil {
+		return classify(exitUsage, fmt.Errorf("invalid --to-base: %w", err))
+	}
+
+	converted, err := share.Convert(shares, bases)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	if err := replaceFile(target, *forceFlag, converted); err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "Converted the %d shares of %s to base %s in %s\n", len(shares.Points), path, strings.Join(bases, ","), target)
+	return nil
+}
+
+// runAdd writes the shares of the sum of the secrets of two share files,
+// adding their shares at every x, to stdout or --out.
+func runAdd(inv *invocation, args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("add", stderr)
+	outFlag := fs.String("out", "", "write the share file of the sum to this new file, readable by its owner only, instead of stdout")
+	modFlag := fs.String("mod", "", "add modulo this prime (decimal or 0x hex) when the share files record no modulus")
+	allowCompositeFlag := fs.Bool("allow-composite", false, "accept a --mod that is not prime")
+	inputs, err := parseArgs(fs, args)
+	if err != nil {
+		return usageError{err}
+	}
+	if len(inputs) != 2 {
+		return usagef("add takes exactly two share files")
+	}
+	var modulus *big.Int
+	if *modFlag != "" {
+		if modulus, err = parseModulus(*modFlag); err != nil {
+			return classify(exitUsage, err)
+		}
+		if err := checkPrime(modulus, *allowCompositeFlag); err != nil {
+			return err
+		}
+	}
+
+	sets := make([]*share.Shares, len(inputs))
+	for i, path := range inputs {
+		shares, cases, err := inv.loadShares(io.Discard, []string{path}, "auto", 0, "", false, []share.ParseOption{share.BinaryField()})
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		if cases != nil {
+			return fmt.Errorf("%s: multi-case files cannot be added", path)
+		}
+		shares.Source = path
+		sets[i] = shares
+	}
+	sum, err := share.Add(sets[0], sets[1], modulus)
+	if err != nil {
+		return err
+	}
+
+	bases := make([]string, len(sum.Points))
+	for i, point := range sum.Points {
+		if bases[i] = point.Base; bases[i] == "" {
+			bases[i] = "10"
+		}
+	}
+	var buf bytes.Buffer
+	if err := share.WriteShares(&buf, sum, bases); err != nil {
+		return err
+	}
+	if *outFlag == "" {
+		_, err := stdout.Write(buf.Bytes())
+		return err
+	}
+	if err := inv.writePrivateFile(*outFlag, buf.Bytes(), false); err != nil {
+		return classify(exitIO, err)
+	}
+	fmt.Fprintf(errOut, "Wrote the shares of the sum of %s and %s to %s\n", inputs[0], inputs[1], *outFlag)
+	return nil
+}
+
+// runScale multiplies every share of a share file by a public constant,
+// giving shares of that multiple of the secret.
+func runScale(inv *invocation, args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("scale", stderr)
+	outFlag := fs.String("out", "", "write the scaled share file to this new file, readable by its owner only, instead of stdout")
+	modFlag := fs.String("mod", "", "scale modulo this prime (decimal or 0x hex) when the share file records no modulus")
+	allowCompositeFlag := fs.Bool("allow-composite", false, "accept a --mod that is not prime")
+	expectedResultFlag := fs.String("expected-result", "", "the scaled secret (decimal or 0x hex), whose SHA-256 the scaled share file records, checked against the one the input records; without it the scaled file records none")
+	inputs, err := parseArgs(fs, args)
+	if err != nil {
+		return usageError{err}
+	}
+	if len(inputs) != 2 {
+		return usagef("scale takes a share file and a scalar")
+	}
+	path := inputs[0]
+	c, err := parseSecret(inputs[1])
+	if err != nil {
+		return usagef("invalid scalar: %q must be an integer (decimal or 0x hex)", inputs[1])
+	}
+	var modulus *big.Int
+	if *modFlag != "" {
+		if modulus, err = parseModulus(*modFlag); err != nil {
+			return classify(exitUsage, err)
+		}
+		if err := checkPrime(modulus, *allowCompositeFlag); err != nil {
+			return err
+		}
+	}
+	var result *big.Int
+	if *expectedResultFlag != "" {
+		if result, err = parseSecret(*expectedResultFlag); err != nil {
+			return usagef("invalid --expected-result: %w", err)
+		}
+	}
+
+	shares, cases, err := inv.loadShares(io.Discard, []string{path}, "auto", 0, "", false, []share.ParseOption{share.BinaryField()})
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	if cases != nil {
+		return fmt.Errorf("%s: multi-case files cannot be scaled", path)
+	}
+	shares.Source = path
+	scaled, err := share.Scale(shares, c, modulus)
+	if err != nil {
+		return err
+	}
+	if result != nil {
+		if scaled.SecretSHA256, err = scaledSecretHash(shares, c, result, scaled.Modulus); err != nil {
+			return err
+		}
+	}
+
+	bases := make([]string, len(scaled.Points))
+	for i, point := range scaled.Points {
+		if bases[i] = point.Base; bases[i] == "" {
+			bases[i] = "10"
+		}
+	}
+	var buf bytes.Buffer
+	if err := share.WriteShares(&buf, scaled, bases); err != nil {
+		return err
+	}
+	if *outFlag == "" {
+		_, err := stdout.Write(buf.Bytes())
+		return err
+	}
+	if err := inv.writePrivateFile(*outFlag, buf.Bytes(), false); err != nil {
+		return classify(exitIO, err)
+	}
+	fmt.Fprintf(errOut, "Wrote the shares of %s times the secret of %s to %s\n", c, path, *outFlag)
+	return nil
+}
+
+// scaledSecretHash returns the secret hash of shares scaled by c whose
+// secret is claimed to be result. The claim is checked against the hash
+// shares records, if any, through the one secret c times which is result.
+func scaledSecretHash(shares *share.Shares, c, result, modulus *big.Int) (string, error) {
+	if modulus != nil {
+		c = new(big.Int).Mod(c, modulus)
+		result = new(big.Int).Mod(result, modulus)
+	}
+	var original *big.Int
+	switch {
+	case c.Sign() == 0:
+		if result.Sign() != 0 {
+			return "", classify(exitMismatch, fmt.Errorf("--expected-result %s is wrong: scaling by 0 gives 0", result))
+		}
+	case modulus != nil:
+		if inverse := new(big.Int).ModInverse(c, modulus); inverse != nil {
+			original = inverse.Mul(inverse, result)
+			original.Mod(original, modulus)
+		}
+	default:
+		remainder := new(big.Int)
+		original, remainder = new(big.Int).QuoRem(result, c, remainder)
+		if remainder.Sign() != 0 {
+			return "", classify(exitMismatch, fmt.Errorf("--expected-result %s is wrong: it is not a multiple of %s", result, c))
+		}
+	}
+	if original != nil {
+		if err := shares.VerifySecret(original); err != nil {
+			return "", fmt.Errorf("--expected-result %s is wrong: %w", result, err)
+		}
+	}
+	return share.SecretSHA256(result), nil
+}
+
+// diffResult is the document diff --output json prints.
+type diffResult struct {
+	A           string             `json:"a"`
+	B           string             `json:"b"`
+	Differences []share.Difference `json:"differences"`
+	Material    bool               `json:"material"`
+}
+
+// runDiff compares the decoded content of two share files, failing if they
+// hold different shares or, with --strict, describe them differently.
+func runDiff(inv *invocation, args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("diff", stderr)
+	strictFlag := fs.Bool("strict", false, "also fail on differences in metadata such as the version, labels, commitments or secret hash, though not in the bases values are written in")
+	outputFlag := fs.String("output", "text", "result format: text or json")
+	inputs, err := parseArgs(fs, args)
+	if err != nil {
+		return usageError{err}
+	}
+	if len(inputs) != 2 {
+		return usagef("diff takes exactly two share files")
+	}
+	if *outputFlag != "text" && *outputFlag != "json" {
+		return usagef("unknown output format: %s", *outputFlag)
+	}
+
+	sets := make([]*share.Shares, len(inputs))
+	for i, path := range inputs {
+		input, name, err := inv.openInput(path)
+		if err != nil {
+			return err
+		}
+		data, err := io.ReadAll(input)
+		input.Close()
+		if err != nil {
+			return classify(exitIO, fmt.Errorf("failed to read %s: %w", name, err))
+		}
+		// An altered share is what diff is for: compare it anyway, after
+		// saying its checksum fails.
+		opts := []share.ParseOption{share.BinaryField()}
+		shares, cases, err := parseInput(bytes.NewReader(data), path, "auto", 0, true, opts)
+		var checksumErr *share.ChecksumError
+		if errors.As(err, &checksumErr) {
+			logger.Warn("share fails its checksum", "file", name, "share", checksumErr.Key)
+			shares, cases, err = parseInput(bytes.NewReader(data), path, "auto", 0, true, append(opts, share.IgnoreChecksums()))
+		}
+		if err != nil {
+			return classify(exitInvalid, fmt.Errorf("%s: %w", name, err))
+		}
+		if cases != nil {
+			return fmt.Errorf("%s: multi-case files cannot be compared", name)
+		}
+		sets[i] = shares
+	}
+	diffs := share.Diff(sets[0], sets[1])
+
+	var material, metadata int
+	var keys, shares, meta, representation []share.Difference
+	for _, d := range diffs {
+		switch {
+		case d.Kind == share.ConflictBase:
+			representation = append(representation, d)
+		case !d.Material:
+			metadata++
+			meta = append(meta, d)
+		case d.X != "":
+			material++
+			shares = append(shares, d)
+		default:
+			material++
+			keys = append(keys, d)
+		}
+	}
+
+	if *outputFlag == "json" {
+		if diffs == nil {
+			diffs = []share.Difference{}
+		}
+		printJSON(stdout, diffResult{A: inputs[0], B: inputs[1], Differences: diffs, Material: material > 0})
+	} else {
+		value := func(v string) string { return cmp.Or(v, "none") }
+		describe := func(d share.Difference) string {
+			switch {
+			case d.Kind == share.ConflictX && d.B == "":
+				return fmt.Sprintf("x=%s: only in %s", d.X, inputs[0])
+			case d.Kind == share.ConflictX:
+				return fmt.Sprintf("x=%s: only in %s", d.X, inputs[1])
+			case d.X != "":
+				return fmt.Sprintf("x=%s: %s %s in %s, %s in %s", d.X, d.Kind, value(d.A), inputs[0], value(d.B), inputs[1])
+			}
+			return fmt.Sprintf("%s: %s in %s, %s in %s", d.Kind, value(d.A), inputs[0], value(d.B), inputs[1])
+		}
+		for _, section := range []struct {
+			title string
+			diffs []share.Difference
+		}{{"Keys", keys}, {"Shares", shares}, {"Metadata", meta}, {"Representation only", representation}} {
+			if len(section.diffs) == 0 {
+				continue
+			}
+			fmt.Fprintf(stdout, "%s:\n", section.title)
+			for _, d := range section.diffs {
+				fmt.Fprintf(stdout, "  %s\n", describe(d))
+			}
+		}
+		if len(diffs) == 0 {
+			fmt.Fprintf(stdout, "%s and %s hold the same shares\n", inputs[0], inputs[1])
+		} else if material == 0 {
+			fmt.Fprintf(stdout, "%s and %s hold the same shares, described differently\n", inputs[0], inputs[1])
+		}
+	}
+
+	switch {
+	case material > 0:
+		return classify(exitMismatch, fmt.Errorf("%s and %s have %d material differences", inputs[0], inputs[1], material))
+	case *strictFlag && metadata > 0:
+		return classify(exitMismatch, fmt.Errorf("%s and %s have %d differences in metadata, which --strict fails on", inputs[0], inputs[1], metadata))
+	}
+	return nil
+}
+
+// runGenTestVectors writes the corpus of package testvectors to --out.
+func runGenTestVectors(inv *invocation, args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("gen-testvectors", stderr)
+	outFlag := fs.String("out", "testvectors", "directory to write the share files and their .expected.json sidecars to")
+	digitsFlag := fs.Int("digits", 200, "decimal digits of the secret of the huge value vector")
+	seedFlag := fs.String("seed", "", "hex seed of the corpus, instead of the fixed default")
+	if err := fs.Parse(args); err != nil {
+		return usageError{err}
+	}
+	if fs.NArg() > 0 {
+		return usagef("gen-testvectors takes no arguments, but got %q", fs.Arg(0))
+	}
+
+	config := testvectors.Config{Digits: *digitsFlag}
+	if *seedFlag != "" {
+		seed, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(*seedFlag), "0x"))
+		if err != nil || len(seed) == 0 {
+			return usagef("invalid --seed: must be non-empty hex")
+		}
+		config.Seed = seed
+	}
+	vectors, err := testvectors.Generate(config)
+	if err != nil {
+		return err
+	}
+	if err := testvectors.Write(*outFlag, vectors); err != nil {
+		return fmt.Errorf("failed to write test vectors: %w", err)
+	}
+	fmt.Fprintf(stdout, "Wrote %d test vectors to %s\n", len(vectors), *outFlag)
+	return nil
+}
+
+// runSelftest runs the embedded known-answer vectors and fails if any of
+// them does not give its known result.
+func runSelftest(inv *invocation, args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("selftest", stderr)
+	verboseFlag := fs.Bool("verbose", false, "also list the vectors that passed")
+	if err := fs.Parse(args); err != nil {
+		return usageError{err}
+	}
+	if fs.NArg() > 0 {
+		return usagef("selftest takes no arguments, but got %q", fs.Arg(0))
+	}
+
+	results := selftest.Run()
+	failed := 0
+	for _, r := range results {
+		switch {
+		case r.Err != nil:
+			failed++
+			fmt.Fprintf(stdout, "FAIL %s: %v\n", r.Name, r.Err)
+		case *verboseFlag:
+			fmt.Fprintf(stdout, "ok   %s\n", r.Name)
+		}
+	}
+	fmt.Fprintf(stdout, "%d of %d self-test vectors passed\n", len(results)-failed, len(results))
+	if failed > 0 {
+		return fmt.Errorf("self-test failed: %d vectors gave the wrong result", failed)
+	}
+	return nil
+}
+
+// runServe serves reconstruction over HTTP, and split and reconstruct over
+// gRPC if --grpc-addr is given, until a listener fails.
+func runServe(inv *invocation, args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("serve", stderr)
+	addrFlag := fs.String("addr", "localhost:8080", "address to listen on")
+	grpcAddrFlag := fs.String("grpc-addr", "", "also serve the gRPC service of proto/shamir/v1 on this address")
+	modFlag := fs.String("mod", "", "reconstruct modulo this prime (decimal or 0x hex)")
+	allowCompositeFlag := fs.Bool("allow-composite", false, "accept a --mod that is not prime")
+	maxBodyFlag := fs.Int64("max-body-bytes", server.DefaultMaxBodyBytes, "largest request body accepted")
+	if err := fs.Parse(args); err != nil {
+		return usageError{err}
+	}
+	if fs.NArg() > 0 {
+		return usagef("serve takes no arguments, but got %q", fs.Arg(0))
+	}
+
+	opts := []server.Option{server.WithMaxBodyBytes(*maxBodyFlag), server.WithLogger(log.New(stderr, "", log.LstdFlags))}
+	var grpcOpts []grpcserver.Option
+	if *modFlag != "" {
+		modulus, err := parseModulus(*modFlag)
+		if err != nil {
+			return err
+		}
+		if err := checkPrime(modulus, *allowCompositeFlag); err != nil {
+			return err
+		}
+		opts = append(opts, server.WithModulus(modulus))
+		grpcOpts = append(grpcOpts, grpcserver.WithModulus(modulus))
+	}
+	if *maxBodyFlag < 1 {
+		return usagef("invalid --max-body-bytes %d: must be positive", *maxBodyFlag)
+	}
+
+	srv := &http.Server{
+		Addr:              *addrFlag,
+		Handler:           server.Handler(opts...),
+		ReadHeaderTimeout: 10 * time.Second,
+		ReadTimeout:       time.Minute,
+		WriteTimeout:      time.Minute,
+	}
+	errc := make(chan error, 2)
+	if *grpcAddrFlag != "" {
+		listener, err := net.Listen("tcp", *grpcAddrFlag)
+		if err != nil {
+			return classify(exitIO, err)
+		}
+		grpcServer := grpc.NewServer()
+		shamirv1.RegisterShamirServer(grpcServer, grpcserver.New(grpcOpts...))
+		fmt.Fprintf(stderr, "Serving gRPC on %s\n", listener.Addr())
+		go func() { errc <- grpcServer.Serve(listener) }()
+	}
+	fmt.Fprintf(stderr, "Listening on %s\n", *addrFlag)
+	go func() { errc <- srv.ListenAndServe() }()
+	return <-errc
+}
+
+// runReshare writes a fresh set of shares, for a new n and k, of the
+// secret of at least k existing shares.
+func runReshare(inv *invocation, args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("reshare", stderr)
+	nFlag := fs.Int("n", 0, "number of new shares to generate")
+	kFlag := fs.Int("k", 0, "number of new shares required to reconstruct")
+	modFlag := fs.String("mod", "", "reshare modulo this prime (decimal or 0x hex) when the share files record no modulus")
+	allowCompositeFlag := fs.Bool("allow-composite", false, "accept a --mod that is not prime")
+	vssFlag := fs.String("vss", share.SchemeFeldman, "commitments the new shares carry: feldman or none")
+	baseFlag := fs.String("base", "10", "output base for the new share values (2-62, 64, 64url or 85), or a comma-separated base per share")
+	outFlag := fs.String("out", "", "write the new share file here instead of stdout, or with --per-share-files the directory to write the share files to")
+	forceFlag := fs.Bool("force", false, "let --out overwrite an existing share file")
+	perShareFlag := fs.Bool("per-share-files", false, "write each new share to a file of its own in the --out directory, named by --name-template")
+	nameTemplateFlag := fs.String("name-template", "share_{x}.json", "with --per-share-files, the file name of each share; {x} is replaced by its x")
+	seedFlag := fs.String("seed", "", "derive the new coefficients from this hex seed instead of crypto/rand, for reproducible test fixtures (requires --insecure-deterministic)")
+	insecureFlag := fs.Bool("insecure-deterministic", false, "confirm that --seed shares are test fixtures that protect nothing")
+	inputs, err := parseArgs(fs, args)
+	if err != nil {
+		return usageError{err}
+	}
+	if len(inputs) == 0 {
+		return usagef("reshare takes the share files of at least k old shares")
+	}
+	if err := checkThreshold(*nFlag, *kFlag); err != nil {
+		return err
+	}
+	var modulus *big.Int
+	if *modFlag != "" {
+		if modulus, err = parseModulus(*modFlag); err != nil {
+			return classify(exitUsage, err)
+		}
+		if err := checkPrime(modulus, *allowCompositeFlag); err != nil {
+			return err
+		}
+	}
+	scheme := *vssFlag
+	switch scheme {
+	case share.SchemeFeldman:
+	case "none":
+		scheme = ""
+	default:
+		return usagef("unknown --vss: %s (reshare supports feldman or none)", *vssFlag)
+	}
+	opts := []share.SplitOption{share.WithCommitments(scheme)}
+	if *seedFlag != "" {
+		if !*insecureFlag {
+			return usagef("--seed makes the shares predictable to anyone who knows it; add --insecure-deterministic to confirm they are only test fixtures")
+		}
+		seed, err := hex.DecodeString(strings.TrimPrefix(strings.TrimPrefix(strings.TrimSpace(*seedFlag), "0x"), "0X"))
+		if err != nil || len(seed) == 0 {
+			return usagef("invalid --seed: must be non-empty hex")
+		}
+		opts = append(opts, share.WithSeed(seed))
+	} else if *insecureFlag {
+		return usagef("--insecure-deterministic requires --seed")
+	}
+	bases, err := parseBases(*baseFlag, *nFlag)
+	if err != nil {
+		return err
+	}
+	if *perShareFlag && (!strings.Contains(*nameTemplateFlag, "{x}") || strings.ContainsAny(*nameTemplateFlag, `/\`)) {
+		return usagef("invalid --name-template: must be a file name containing {x}")
+	}
+
+	old, cases, err := inv.loadShares(io.Discard, inputs, "auto", 0, "", false, nil)
+	if err != nil {
+		return err
+	}
+	if cases != nil {
+		return classify(exitInvalid, fmt.Errorf("%s: multi-case files cannot be reshared", inputs[0]))
+	}
+	if old.Commitments != nil && modulus == nil && old.Modulus == nil {
+		return usagef("shares with commitments were split with --mod; reshare them with the same --mod")
+	}
+	if modulus == nil && old.Modulus == nil && len(old.Points) >= old.K {
+		logger.Warn("shares over the integers are reshared by reconstructing the secret in memory")
+	}
+	reshared, err := reconstruct.Reshare(old, *nFlag, *kFlag, modulus, opts...)
+	if err != nil {
+		return err
+	}
+
+	if *perShareFlag {
+		dir := *outFlag
+		if dir == "" {
+			dir = "."
+		}
+		if err := inv.writePerShareFiles(dir, *nameTemplateFlag, "", reshared, bases, false); err != nil {
+			return err
+		}
+	} else {
+		var buf bytes.Buffer
+		if err := share.WriteShares(&buf, reshared, bases); err != nil {
+			return err
+		}
+		if err := inv.writeSplitOutput(stdout, *outFlag, buf.Bytes(), false, *forceFlag); err != nil {
+			return err
+		}
+	}
+	used := make([]string, len(old.Points))
+	for i, point := range old.Points {
+		used[i] = point.X.String()
+	}
+	fmt.Fprintf(errOut, "Reshared %d-of-%d as %d-of-%d: the old shares at x=%s are superseded and do not combine with the new ones; destroy them, since any %d of them still reconstruct the secret\n",
+		old.K, old.N, reshared.K, reshared.N, strings.Join(used, ", "), old.K)
+	return nil
+}
+
+// invocation is the state one run of a command shares with its helpers.
+// run makes a fresh one for every command, so that nothing a run read or
+// was given carries over to the next.
+type invocation struct {
+	// stdin is buffered so that the format of piped input can be sniffed
+	// before it is parsed.
+	stdin *bufio.Reader
+
+	fetch fetchOptions
+
+	// passphraseFile names the file holding the passphrase of encrypted
+	// share files, set by --passphrase-file. Without it the passphrase is
+	// prompted for on the terminal.
+	passphraseFile string
+
+	// passphrase caches the passphrase, so that it is asked for once per
+	// run.
+	passphrase []byte
+
+	// prompt reads a passphrase typed at the terminal.
+	prompt func(prompt string) ([]byte, error)
+}
+
+func newInvocation(stdin io.Reader) *invocation {
+	return &invocation{
+		stdin:  bufio.NewReader(stdin),
+		fetch:  fetchOptions{timeout: 30 * time.Second, maxBytes: 16 << 20},
+		prompt: terminal.ReadPassphrase,
+	}
+}
+
+// errOut receives the warnings of the helpers the subcommands share; run
+// points it at its stderr.
+var errOut io.Writer = os.Stderr
+
+// logger receives the diagnostics of the subcommands as structured
+// records. Records name shares by x and inputs by name; share values and
+// secrets are never logged, at any level.
+var logger = slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelWarn}))
+
+// logFlags adds --log-level and --log-format to fs and returns a function
+// that, once the flags are parsed, points logger at stderr accordingly.
+func logFlags(fs *flag.FlagSet, stderr io.Writer) func() error {
+	levelFlag := fs.String("log-level", "warn", "lowest level of the log records written to stderr: debug, info, warn or error")
+	formatFlag := fs.String("log-format", "text", "format of the log records: text or json")
+	return func() error {
+		var level slog.Level
+		if err := level.UnmarshalText([]byte(*levelFlag)); err != nil {
+			return usagef("invalid --log-level %q: must be debug, info, warn or error", *levelFlag)
+		}
+		options := &slog.HandlerOptions{Level: level}
+		switch *formatFlag {
+		case "text":
+			logger = slog.New(slog.NewTextHandler(stderr, options))
+		case "json":
+			logger = slog.New(slog.NewJSONHandler(stderr, options))
+		default:
+			return usagef("invalid --log-format %q: must be text or json", *formatFlag)
+		}
+		return nil
+	}
+}
+
+// openInput opens the share document at path, standard input when path
+// is "-", the environment variable NAME when path is "$NAME" or the body
+// fetched from an http or https URL, decrypting it if it is a passphrase
+// envelope. The returned name is what messages should call
+// the input.
+func (inv *invocation) openInput(path string) (io.ReadCloser, string, error) {
+	if path == "-" {
+		input, err := inv.decryptInput(inv.stdin, io.NopCloser(inv.stdin), "stdin")
+		return input, "stdin", err
+	}
+	if variable, ok := strings.CutPrefix(path, "$"); ok {
+		data, name, err := readEnvInput(variable)
+		if err != nil {
+			return nil, "", classify(exitIO, err)
+		}
+		br := bytes.NewReader(data)
+		input, err := inv.decryptInput(bufio.NewReader(br), io.NopCloser(br), name)
+		return input, name, err
+	}
+	if u, ok := parseURL(path); ok {
+		name := u.Redacted()
+		body, err := inv.fetchInput(u)
+		if err != nil {
+			return nil, "", classify(exitIO, err)
+		}
+		br := bytes.NewReader(body)
+		input, err := inv.decryptInput(bufio.NewReader(br), io.NopCloser(br), name)
+		return input, name, err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read file: %w", err)
+	}
+	input, err := inv.decryptInput(bufio.NewReader(file), file, path)
+	return input, path, err
+}
+
+// defaultSharesEnv is the environment variable read when no input is
+// given.
+const defaultSharesEnv = "SHARES_JSON"
+
+// readEnvInput returns the content of the environment variable, decoded
+// first if it looks like base64: no braces and valid standard or URL-safe
+// base64, as orchestration systems often wrap payloads. The returned name
+// is what messages should call the input.
+func readEnvInput(variable string) ([]byte, string, error) {
+	name := "environment variable " + variable
+	value, ok := os.LookupEnv(variable)
+	if !ok {
+		return nil, name, fmt.Errorf("%s is not set", name)
+	}
+	text := strings.TrimRight(value, "\r\n")
+	if text == "" {
+		return nil, name, fmt.Errorf("%s is empty", name)
+	}
+	if !strings.ContainsAny(text, "{}") {
+		for _, enc := range []*base64.Encoding{base64.StdEncoding, base64.URLEncoding, base64.RawStdEncoding, base64.RawURLEncoding} {
+			if data, err := enc.DecodeString(text); err == nil {
+				return data, name, nil
+			}
+		}
+	}
+	return []byte(value), name, nil
+}
+
+// fetchOptions configures how inputs given as URLs are fetched, set by
+// --url-timeout, --header and --max-url-bytes.
+type fetchOptions struct {
+	timeout  time.Duration
+	headers  stringList
+	maxBytes int64
+}
+
+// parseURL reports whether path is an http or https URL rather than a
+// file name.
+func parseURL(path string) (*url.URL, bool) {
+	u, err := url.Parse(path)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, false
+	}
+	return u, true
+}
+
+// fetchInput downloads the body at u with the fetch options of inv.
+func (inv *invocation) fetchInput(u *url.URL) ([]byte, error) {
+	name := u.Redacted()
+	ctx, cancel := context.WithTimeout(context.Background(), inv.fetch.timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL %s: %w", name, err)
+	}
+	for _, header := range inv.fetch.headers {
+		key, value, ok := strings.Cut(header, ":")
+		if !ok || strings.TrimSpace(key) == "" {
+			return nil, fmt.Errorf("invalid --header %q: want Name: value", header)
+		}
+		req.Header.Add(strings.TrimSpace(key), strings.TrimSpace(value))
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err == nil {
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("failed to fetch %s: %s", name, resp.Status)
+		}
+		var body []byte
+		body, err = io.ReadAll(io.LimitReader(resp.Body, inv.fetch.maxBytes+1))
+		if err == nil && int64(len(body)) > inv.fetch.maxBytes {
+			return nil, fmt.Errorf("failed to fetch %s: response is larger than %d bytes (see --max-url-bytes)", name, inv.fetch.maxBytes)
+		}
+		if err == nil {
+			return body, nil
+		}
+	}
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return nil, fmt.Errorf("failed to fetch %s: timed out after %s (see --url-timeout)", name, inv.fetch.timeout)
+	}
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		err = urlErr.Err
+	}
+	return nil, fmt.Errorf("failed to fetch %s: %w", name, err)
+}
+
+// inputExt returns the extension of the input path, or of the path of a URL.
+func inputExt(path string) string {
+	if u, ok := parseURL(path); ok {
+		path = u.Path
+	}
+	return filepath.Ext(path)
+}
+
+// decryptInput returns the input br reads, closed by closer, or its
+// plaintext if it is a passphrase envelope.
+func (inv *invocation) decryptInput(br *bufio.Reader, closer io.Closer, name string) (io.ReadCloser, error) {
+	head, _ := br.Peek(512)
+	if !envelope.IsEnvelope(head) {
+		return struct {
+			io.Reader
+			io.Closer
+		}{br, closer}, nil
+	}
+	data, err := io.ReadAll(br)
+	closer.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", name, err)
+	}
+	passphrase, err := inv.readPassphrase(fmt.Sprintf("Passphrase for %s: ", name), false)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := envelope.Open(data, passphrase)
+	if err != nil {
+		return nil, classify(exitInvalid, err)
+	}
+	return io.NopCloser(bytes.NewReader(plaintext)), nil
+}
+
+// parseInput decodes one share document. An empty or "auto" format is
+// chosen from the file extension, then by sniffing for CBOR, and defaults
+// to JSON. A JSON document bundling several named test cases is returned
+// as cases instead. A standalone document is the only input and must hold
+// at least k shares.
+func parseInput(r io.Reader, path, format string, k int, standalone bool, opts []share.ParseOption) (*share.Shares, []share.Case, error) {
+	br := bufio.NewReader(r)
+	if format == "" || format == "auto" {
+		format = "json"
+		switch strings.ToLower(inputExt(path)) {
+		case ".csv":
+			format = "csv"
+		case ".yaml", ".yml":
+			format = "yaml"
+		case ".toml":
+			format = "toml"
+		case ".cbor":
+			format = "cbor"
+		case ".msgpack", ".mpk":
+			format = "msgpack"
+		default:
+			head, _ := br.Peek(3)
+			switch {
+			case share.IsCBOR(head):
+				format = "cbor"
+			case share.IsMsgpack(head):
+				format = "msgpack"
+			}
+		}
+	}
+
+	var shares *share.Shares
+	var err error
+	switch format {
+	case "json":
+		jsonOpts := opts
+		if standalone {
+			jsonOpts = append(opts[:len(opts):len(opts)], share.RequireThreshold())
+		}
+		var cases []share.Case
+		shares, cases, err = share.ParseJSON(br, jsonOpts...)
+		if cases != nil {
+			return nil, cases, nil
+		}
+		if err == nil && !shares.Checksummed {
+			name := path
+			if name == "-" {
+				name = "stdin"
+			}
+			logger.Warn("shares carry no checksums, so a corrupted value would go unnoticed (migrate the file to add them)", "file", name)
+		}
+	case "csv":
+		shares, err = share.ParseCSV(br, k, opts...)
+	case "yaml":
+		shares, err = share.ParseYAML(br, opts...)
+	case "toml":
+		shares, err = share.ParseTOML(br, opts...)
+	case "cbor":
+		shares, err = share.ParseCBOR(br, opts...)
+	case "msgpack":
+		shares, err = share.ParseMsgpack(br, opts...)
+	default:
+		err = fmt.Errorf("unknown input format: %s", format)
+	}
+	return shares, nil, err
+}
+
+// durationMillis returns the milliseconds since start, for log records.
+func durationMillis(start time.Time) float64 {
+	return float64(time.Since(start).Microseconds()) / 1000
+}
+
+// warnDeterministic warns that the shares of the input name were split
+// from a fixed seed.
+func warnDeterministic(name string) {
+	logger.Warn("shares were split from a fixed seed with --insecure-deterministic and protect nothing", "file", name)
+}
+
+// isMnemonicInput reports whether the input at path holds mnemonic shares
+// rather than a share document.
+func (inv *invocation) isMnemonicInput(path string) bool {
+	if path == "-" {
+		head, _ := inv.stdin.Peek(256)
+		return share.IsMnemonic(head)
+	}
+	if variable, ok := strings.CutPrefix(path, "$"); ok {
+		data, _, err := readEnvInput(variable)
+		return err == nil && share.IsMnemonic(data)
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+	head := make([]byte, 256)
+	n, _ := io.ReadFull(file, head)
+	return share.IsMnemonic(head[:n])
+}
+
+// isFileShareInput reports whether the input at path holds a share of a
+// file split with split --in.
+func (inv *invocation) isFileShareInput(path string) bool {
+	if path == "-" {
+		head, _ := inv.stdin.Peek(4096)
+		return share.IsFileShare(bytes.NewReader(head))
+	}
+	if variable, ok := strings.CutPrefix(path, "$"); ok {
+		data, _, err := readEnvInput(variable)
+		return err == nil && share.IsFileShare(bytes.NewReader(data))
+	}
+	if _, ok := parseURL(path); ok {
+		return false
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+	return share.IsFileShare(file)
+}
+
+// classifyFileShares gives the share documents fileshare reports invalid
+// the class of an input that does not parse.
+func classifyFileShares(err error) error {
+	var invalid *fileshare.InvalidError
+	if errors.As(err, &invalid) {
+		return classify(exitInvalid, invalid.Err)
+	}
+	return err
+}
+
+// reconstructFile reassembles the file split with split --in from the
+// share files at paths with fileshare.Combine, and writes it to outPath,
+// created as by createPrivate. The output is removed again unless
+// fileshare.Combine succeeds.
+func (inv *invocation) reconstructFile(info io.Writer, paths []string, outPath string, force, noVerify bool) error {
+	var readers []*share.FileShareReader
+	var names []string
+	for _, path := range paths {
+		input, name, err := inv.openInput(path)
+		if err != nil {
+			return err
+		}
+		defer input.Close()
+		r, err := share.NewFileShareReader(input)
+		if err != nil {
+			return classify(exitInvalid, fmt.Errorf("%s: %w", name, err))
+		}
+		if r.Deterministic {
+			warnDeterministic(name)
+		}
+		fmt.Fprintf(info, "Opened the share at x=%d of a file from %s\n", r.X, name)
+		readers, names = append(readers, r), append(names, name)
+	}
+	if err := fileshare.Check(readers, names); err != nil {
+		return classifyFileShares(err)
+	}
+
+	file, err := createPrivate(outPath, force)
+	if err != nil {
+		return err
+	}
+	abort := func(err error) error {
+		file.Close()
+		os.Remove(file.Name())
+		return err
+	}
+	w := bufio.NewWriter(file)
+	result, err := fileshare.Combine(w, readers, names, noVerify)
+	var writeErr *fileshare.WriteError
+	if errors.As(err, &writeErr) {
+		return abort(classify(exitIO, fmt.Errorf("failed to write %s: %w", outPath, writeErr.Err)))
+	}
+	if err != nil {
+		return abort(classifyFileShares(err))
+	}
+	if result.Verified > 0 {
+		fmt.Fprintf(info, "Verified %d additional shares against the reconstructed polynomials\n", result.Verified)
+	}
+	if err := w.Flush(); err != nil {
+		return abort(classify(exitIO, fmt.Errorf("failed to write %s: %w", outPath, err)))
+	}
+	if err := file.Close(); err != nil {
+		return abort(classify(exitIO, fmt.Errorf("failed to write %s: %w", outPath, err)))
+	}
+	if file.Name() != outPath {
+		if err := os.Rename(file.Name(), outPath); err != nil {
+			os.Remove(file.Name())
+			return classify(exitIO, fmt.Errorf("failed to write %s: %w", outPath, err))
+		}
+	}
+	fmt.Fprintf(info, "Verified the %d bytes against the SHA-256 recorded at split time\n", result.Size)
+	fmt.Fprintf(info, "Wrote the file to %s\n", outPath)
+	return nil
+}
+
+// reconstructGF256 combines the byte-wise shares of every input, checking
+// the shares beyond the first k against the recovered polynomials unless
+// noVerify is set. The inputs are JSON share files or mnemonics, told apart
+// unless format says which, or Vault unseal keys, as are unsealKeys, when
+// format is "vault". Vault shares do not record k, so all of them are used.
+func (inv *invocation) reconstructGF256(info io.Writer, paths []string, format string, unsealKeys []string, parseOpts []share.ParseOption, noVerify bool) ([]byte, reconstructResult, error) {
+	vault := format == "vault"
+	result := reconstructResult{Inputs: paths}
+	if len(paths) == 1 {
+		parseOpts = append(parseOpts[:len(parseOpts):len(parseOpts)], share.RequireThreshold())
+	}
+
+	sets := make([]*share.ByteShares, 0, len(paths)+1)
+	if len(unsealKeys) > 0 {
+		shares, err := share.ParseVault(strings.NewReader(strings.Join(unsealKeys, "\n")))
+		if err != nil {
+			return nil, result, classify(exitInvalid, fmt.Errorf("--unseal-key: %w", err))
+		}
+		shares.SetSource("--unseal-key")
+		sets = append(sets, shares)
+	}
+	for _, path := range paths {
+		input, inputName, err := inv.openInput(path)
+		if err != nil {
+			return nil, result, err
+		}
+		var shares *share.ByteShares
+		switch {
+		case vault:
+			shares, err = share.ParseVault(input)
+		case format == "mnemonic" || format == "auto" && inv.isMnemonicInput(path):
+			shares, err = share.ParseMnemonics(input)
+		default:
+			shares, err = share.ParseByteShares(input, parseOpts...)
+		}
+		input.Close()
+		if err = classify(exitInvalid, err); err != nil {
+			if len(paths) > 1 {
+				return nil, result, fmt.Errorf("%s: %w", inputName, err)
+			}
+			return nil, result, err
+		}
+		shares.SetSource(inputName)
+		if shares.Deterministic {
+			warnDeterministic(inputName)
+		}
+		fmt.Fprintf(info, "Successfully parsed %d points from %s\n", len(shares.Points), inputName)
+		sets = append(sets, shares)
+	}
+	shares, err := share.MergeBytes(sets...)
+	if err != nil {
+		return nil, result, err
+	}
+	if vault {
+		shares.K = len(shares.Points)
+	}
+	if len(shares.Points) < shares.K {
+		return nil, result, &share.InsufficientSharesError{Found: len(shares.Points), Needed: shares.K}
+	}
+
+	points := make([]gf256.Share, len(shares.Points))
+	for i, point := range shares.Points {
+		points[i] = gf256.Share{X: point.X, Y: point.Y}
+	}
+	selected, extra := points[:shares.K], points[shares.K:]
+
+	data, err := gf256.Combine(selected)
+	if err != nil {
+		return nil, result, err
+	}
+	if !noVerify && len(extra) > 0 {
+		var mismatches []string
+		for _, s := range extra {
+			y, err := gf256.InterpolateAt(selected, s.X)
+			if err != nil {
+				return nil, result, err
+			}
+			if !bytes.Equal(y, s.Y) {
+				mismatches = append(mismatches, strconv.Itoa(int(s.X)))
+			}
+		}
+		if len(mismatches) > 0 {
+			return nil, result, fmt.Errorf("shares inconsistent with the reconstructed polynomial at x=%s", strings.Join(mismatches, ", "))
+		}
+		fmt.Fprintf(info, "Verified %d additional shares against the reconstructed polynomial\n", len(extra))
+		result.Verified = len(extra)
+	}
+
+	result.PointsParsed = len(points)
+	result.K, result.N = shares.K, shares.N
+	for _, s := range selected {
+		result.XUsed = append(result.XUsed, strconv.Itoa(int(s.X)))
+	}
+	return data, result, nil
+}
+
+// loadShares parses every input and merges their shares into one set. A
+// single input holding several named test cases is returned as cases,
+// unless caseName selects one of them. An input whose share count differs
+// from the n it declares is a warning, or an error if strict is set.
+func (inv *invocation) loadShares(info io.Writer, paths []string, format string, k int, caseName string, strict bool, parseOpts []share.ParseOption) (*share.Shares, []share.Case, error) {
+	set := share.NewShareSet()
+	for _, path := range paths {
+		start := time.Now()
+		input, inputName, err := inv.openInput(path)
+		if err != nil {
+			return nil, nil, err
+		}
+		shares, cases, err := parseInput(input, path, format, k, len(paths) == 1, parseOpts)
+		input.Close()
+		if err = classify(exitInvalid, err); err != nil {
+			if len(paths) > 1 {
+				return nil, nil, fmt.Errorf("%s: %w", inputName, err)
+			}
+			return nil, nil, err
+		}
+
+		if cases != nil {
+			if len(paths) > 1 {
+				return nil, nil, fmt.Errorf("%s: files with multiple test cases cannot be combined with other inputs", inputName)
+			}
+			if caseName == "" {
+				return nil, cases, nil
+			}
+			if shares, err = selectCase(cases, caseName); err != nil {
+				return nil, nil, err
+			}
+			inputName = inputName + "#" + caseName
+		} else if caseName != "" {
+			return nil, nil, fmt.Errorf("%s does not contain named test cases", inputName)
+		}
+		shares.SetSource(inputName)
+		if shares.Deterministic {
+			warnDeterministic(inputName)
+		}
+		if err := shares.CheckCount(); err != nil {
+			if strict {
+				return nil, nil, classify(exitInvalid, fmt.Errorf("%s: %w", inputName, err))
+			}
+			logger.Warn(err.Error(), "file", inputName)
+		}
+
+		fmt.Fprintf(info, "Successfully parsed %d points from %s\n", len(shares.Points), inputName)
+		logger.Info("parsed shares", "file", inputName, "points", len(shares.Points), "k", shares.K, "checksummed", shares.Checksummed, "duration_ms", durationMillis(start))
+		set.AddFrom(inputName, shares)
+	}
+	for _, d := range set.Duplicates {
+		fmt.Fprintf(info, "Ignoring share x=%s in %s: the same share is in %s\n", d.X, d.Source, d.First)
+		logger.Debug("ignoring duplicate share", "share_x", d.X, "file", d.Source, "first", d.First)
+	}
+	for _, c := range set.Conflicts {
+		logger.Error("conflicting share sources", "kind", string(c.Kind), "share_x", c.X, "first", c.Sources[0], "second", c.Sources[1])
+	}
+	shares, err := set.Shares()
+	return shares, nil, err
+}
+
+// watchShares watches dir with watch.Dir and reports progress to info.
+// Files that do not parse, disagree with earlier ones or fail their
+// commitments unless noVSS is set are skipped with a warning. Shares whose
+// x arrived before are ignored with a notice.
+func (inv *invocation) watchShares(ctx context.Context, info io.Writer, dir string, interval time.Duration, parseOpts []share.ParseOption, noVSS bool) (*share.Shares, []string, error) {
+	fmt.Fprintf(info, "Watching %s for share files\n", dir)
+	return watch.Dir(ctx, dir, watch.Options{
+		Interval: interval,
+		Read: func(path string) (*share.Shares, error) {
+			shares, err := inv.readWatchedFile(path, parseOpts)
+			if err == nil && !noVSS {
+				err = shares.VerifyCommitments()
+			}
+			return shares, err
+		},
+		Skipped: func(path string, err error) {
+			// A parse error quotes the offending value, which is share
+			// material, so only its entry and field are logged.
+			var parseErr *share.ParseError
+			var conflictErr *share.ConflictError
+			switch {
+			case errors.As(err, &parseErr):
+				logger.Warn("skipping file", "file", path, "reason", "invalid share", "entry", parseErr.Key, "field", parseErr.Field)
+			case errors.As(err, &conflictErr):
+				for _, c := range conflictErr.Conflicts {
+					logger.Warn("skipping file", "file", path, "reason", c.Message, "kind", string(c.Kind))
+				}
+			default:
+				logger.Warn("skipping file", "file", path, "reason", err.Error())
+			}
+		},
+		Duplicate: func(d share.Duplicate) {
+			fmt.Fprintf(info, "Ignoring share x=%s in %s: already received in %s\n", d.X, d.Source, d.First)
+		},
+		Received: func(merged *share.Shares) {
+			fmt.Fprintf(info, "%d of %d shares received, need %d\n", len(merged.Points), merged.N, merged.K)
+		},
+	})
+}
+
+// readInteractive prompts on w for k and then for k shares read from r,
+// one per line as share.ParsePoint accepts them. A line that is not a
+// valid share, or repeats an x, is reported and may be typed again.
+func readInteractive(r io.Reader, w io.Writer, parseOpts []share.ParseOption) (*share.Shares, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1<<20)
+	next := func(prompt string) (string, bool) {
+		for {
+			fmt.Fprint(w, prompt)
+			if !scanner.Scan() {
+				fmt.Fprintln(w)
+				return "", false
+			}
+			if line := strings.TrimSpace(scanner.Text()); line != "" && !strings.HasPrefix(line, "#") {
+				return line, true
+			}
+		}
+	}
+
+	k := 0
+	for k == 0 {
+		line, ok := next("Number of shares needed (k): ")
+		if !ok {
+			return nil, errors.New("input ended before k was given")
+		}
+		if n, err := strconv.Atoi(line); err != nil || n < 1 {
+			fmt.Fprintf(w, "  invalid k %q: must be a positive integer\n", line)
+		} else {
+			k = n
+		}
+	}
+
+	fmt.Fprintln(w, "Enter each share as x base value, or as a JSON entry.")
+	shares := &share.Shares{N: k, K: k}
+	seen := make(map[string]bool)
+	for len(shares.Points) < k {
+		line, ok := next(fmt.Sprintf("Share %d of %d: ", len(shares.Points)+1, k))
+		if !ok {
+			return nil, &share.InsufficientSharesError{Found: len(shares.Points), Needed: k, Context: "entered"}
+		}
+		point, err := share.ParsePoint(line, parseOpts...)
+		if err != nil {
+			fmt.Fprintf(w, "  %v; type the share again\n", err)
+			continue
+		}
+		if key := point.X.String(); seen[key] {
+			fmt.Fprintf(w, "  already have a share with x=%s; type another share\n", key)
+			continue
+		}
+		seen[point.X.String()] = true
+		point.Source = "stdin"
+		shares.Points = append(shares.Points, point)
+		fmt.Fprintf(w, "  ok: x=%s\n", point.X.String())
+	}
+	sort.Slice(shares.Points, func(a, b int) bool { return shares.Points[a].X.Cmp(shares.Points[b].X) < 0 })
+	return shares, nil
+}
+
+// readWatchedFile parses one share file that --watch found.
+func (inv *invocation) readWatchedFile(path string, parseOpts []share.ParseOption) (*share.Shares, error) {
+	input, name, err := inv.openInput(path)
+	if err != nil {
+		return nil, err
+	}
+	defer input.Close()
+	shares, cases, err := parseInput(input, path, "json", 0, false, parseOpts)
+	if err != nil {
+		return nil, err
+	}
+	if cases != nil {
+		return nil, errors.New("files with multiple test cases are not supported")
+	}
+	shares.SetSource(name)
+	return shares, nil
+}
+
+func selectCase(cases []share.Case, name string) (*share.Shares, error) {
+	for _, c := range cases {
+		if c.Name == name {
+			return c.Shares, c.Err
+		}
+	}
+
+	names := make([]string, len(cases))
+	for i, c := range cases {
+		names[i] = c.Name
+	}
+	return nil, fmt.Errorf("unknown test case %q: available cases are %s", name, strings.Join(names, ", "))
+}
+
+// runCases reconstructs every test case and writes one line per case to w. It
+// reports whether any case failed.
+func runCases(w io.Writer, cases []share.Case, outputJSON, noVerify, noVSS bool, opts []lagrange.Option, expect expectation, render secretRenderer) bool {
+	type caseResult struct {
+		Name   string `json:"name"`
+		Secret string `json:"secret,omitempty"`
+		Error  string `json:"error,omitempty"`
+	}
+
+	failed := false
+	results := make([]caseResult, 0, len(cases))
+	for _, c := range cases {
+		err := c.Err
+		var text string
+		if err == nil {
+			var secret *big.Int
+			if secret, err = reconstructShares(c.Shares, noVerify, noVSS, opts, expect); err == nil {
+				text, err = render(secret)
+			}
+		}
+
+		result := caseResult{Name: c.Name}
+		if err != nil {
+			failed = true
+			result.Error = err.Error()
+		} else {
+			result.Secret = text
+		}
+		results = append(results, result)
+
+		if outputJSON {
+			continue
+		}
+		if err != nil {
+			fmt.Fprintf(w, "%s: error: %s\n", c.Name, err)
+		} else {
+			fmt.Fprintf(w, "%s: %s\n", c.Name, text)
+		}
+	}
+
+	if outputJSON {
+		printJSON(w, results)
+	}
+	return failed
+}
+
+func stdinIsPiped() bool {
+	info, err := os.Stdin.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice == 0
+}
+
+// isTerminal reports whether w is a terminal.
+func isTerminal(w io.Writer) bool {
+	file, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := file.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// progressInterval is how often a progress line is redrawn at most.
+const progressInterval = 250 * time.Millisecond
+
+// progressLine redraws one line of progress on a terminal.
+type progressLine struct {
+	w     io.Writer
+	last  time.Time
+	shown bool
+}
+
+// reporter returns a progress callback that draws format with the count
+// done, and the total when it is known, followed by an estimate of the
+// time left. The line is erased once done reaches total.
+func (p *progressLine) reporter(format string) func(done, total int) {
+	var start time.Time
+	return func(done, total int) {
+		if done == total {
+			p.clear()
+			return
+		}
+		now := time.Now()
+		if start.IsZero() {
+			start = now
+		}
+		if now.Sub(p.last) < progressInterval {
+			return
+		}
+		p.last = now
+		count, eta := strconv.Itoa(done), ""
+		if total > 0 {
+			count += "/" + strconv.Itoa(total)
+			if done > 0 {
+				left := time.Duration(float64(now.Sub(start)) * float64(total-done) / float64(done))
+				eta = fmt.Sprintf(" (ETA %s)", left.Round(time.Second))
+			}
+		}
+		fmt.Fprintf(p.w, "\r\033[K"+format+"%s", count, eta)
+		p.shown = true
+	}
+}
+
+// clear erases the progress line, if one is shown.
+func (p *progressLine) clear() {
+	if p.shown {
+		fmt.Fprint(p.w, "\r\033[K")
+		p.shown = false
+	}
+}
+
+func parseXList(s string) ([]*big.Int, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	var xs []*big.Int
+	for _, field := range strings.Split(s, ",") {
+		x, ok := new(big.Int).SetString(strings.TrimSpace(field), 10)
+		if !ok {
+			return nil, fmt.Errorf("invalid x value: %q", field)
+		}
+		xs = append(xs, x)
+	}
+	return xs, nil
+}
+
+// expectation checks reconstructed secrets against known values: the
+// --expected flag, or the document's own "expected" entry when it has one.
+type expectation struct {
+	want    *big.Int
+	modulus *big.Int
+}
+
+func (e expectation) check(got *big.Int, documentExpected string) error {
+	want := e.want
+	if documentExpected != "" {
+		w, err := parseSecret(documentExpected)
+		if err != nil {
+			return fmt.Errorf("invalid expected value: %w", err)
+		}
+		want = w
+	}
+	if want == nil {
+		return nil
+	}
+
+	if e.modulus != nil {
+		want = new(big.Int).Mod(want, e.modulus)
+	}
+	if got.Cmp(want) != 0 {
+		return fmt.Errorf("%w: got %s, want %s", errExpectedMismatch, got.String(), want.String())
+	}
+	return nil
+}
+
+// secretRenderer formats a reconstructed secret for output.
+type secretRenderer func(*big.Int) (string, error)
+
+type batchResult struct {
+	Line   int    `json:"line"`
+	Secret string `json:"secret,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// reconstructDocument parses a single share document and returns its
+// secret, checking the unused shares unless noVerify is set, every share
+// against the document's commitments unless noVSS is set, and the result
+// against expect.
+func reconstructDocument(data []byte, parseOpts []share.ParseOption, noVerify, noVSS bool, opts []lagrange.Option, expect expectation) (*big.Int, error) {
+	parseOpts = append(parseOpts[:len(parseOpts):len(parseOpts)], share.RequireThreshold())
+	shares, err := share.ParseShares(bytes.NewReader(data), parseOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return reconstructShares(shares, noVerify, noVSS, opts, expect)
+}
+
+// reconstructBlinding interpolates the blinding values of Pedersen shares
+// at x=0.
+func reconstructBlinding(points []share.Point, opts []lagrange.Option) (*big.Int, error) {
+	blinding := make([]share.Point, len(points))
+	for i, point := range points {
+		if point.Blinding == nil {
+			return nil, fmt.Errorf("share x=%s has no blinding value", point.X.String())
+		}
+		blinding[i] = share.Point{X: point.X, Y: point.Blinding}
+	}
+	return lagrange.Interpolate(blinding, opts...)
+}
+
+// commitmentName is how messages call a commitment scheme.
+func commitmentName(scheme string) string {
+	if scheme == share.SchemePedersen {
+		return "Pedersen"
+	}
+	return "Feldman"
+}
+
+func reconstructShares(shares *share.Shares, noVerify, noVSS bool, opts []lagrange.Option, expect expectation) (*big.Int, error) {
+	result, err := reconstruct.Shares(shares, reconstruct.Options{NoVerify: noVerify, NoVSS: noVSS, Lagrange: opts})
+	if err != nil {
+		return nil, err
+	}
+	if err := expect.check(result.Secret, shares.Expected); err != nil {
+		return nil, err
+	}
+	return result.Secret, nil
+}
+
+// runBatch reconstructs every line of a JSON Lines file, reading one line
+// at a time. It reports whether any line failed.
+func runBatch(w io.Writer, r io.Reader, parseOpts []share.ParseOption, outputJSON, keepGoing, noVerify, noVSS bool, opts []lagrange.Option, expect expectation, render secretRenderer) (bool, error) {
+	reader := bufio.NewReader(r)
+	failed := false
+	first := true
+
+	if outputJSON {
+		fmt.Fprint(w, "[")
+	}
+	for lineNumber := 1; ; lineNumber++ {
+		line, readErr := reader.ReadBytes('\n')
+		if readErr != nil && !errors.Is(readErr, io.EOF) {
+			return failed, fmt.Errorf("failed to read line %d: %w", lineNumber, readErr)
+		}
+
+		if trimmed := bytes.TrimSpace(line); len(trimmed) > 0 {
+			result := batchResult{Line: lineNumber}
+			secret, err := reconstructDocument(trimmed, parseOpts, noVerify, noVSS, opts, expect)
+			var text string
+			if err == nil {
+				text, err = render(secret)
+			}
+			if err != nil {
+				failed = true
+				result.Error = err.Error()
+			} else {
+				result.Secret = text
+			}
+
+			if outputJSON {
+				encoded, _ := json.Marshal(result)
+				if !first {
+					fmt.Fprint(w, ",")
+				}
+				fmt.Fprintf(w, "\n  %s", encoded)
+			} else if err != nil {
+				fmt.Fprintf(w, "line %d: error: %s\n", lineNumber, result.Error)
+			} else {
+				fmt.Fprintf(w, "line %d: %s\n", lineNumber, result.Secret)
+			}
+			first = false
+
+			if err != nil && !keepGoing {
+				break
+			}
+		}
+
+		if errors.Is(readErr, io.EOF) {
+			break
+		}
+	}
+	if outputJSON {
+		if !first {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintln(w, "]")
+	}
+	return failed, nil
+}
+
+// evaluate interpolates points with the named algorithm at every x in ats,
+// and also returns the polynomial's coefficients if withCoefficients is set.
+// Several evaluations with Lagrange share one barycentric Interpolator;
+// fast evaluates each with the product-tree algorithm.
+func evaluate(algorithm string, points []share.Point, ats []*big.Int, withCoefficients bool, opts []lagrange.Option) ([]*big.Int, []*big.Int, error) {
+	var at func(*big.Int) (*big.Int, error)
+	var coefficients []*big.Int
+	switch {
+	case algorithm == "newton":
+		form, err := lagrange.NewNewtonForm(points, opts...)
+		if err != nil {
+			return nil, nil, err
+		}
+		if withCoefficients {
+			if coefficients, err = form.Coefficients(); err != nil {
+				return nil, nil, err
+			}
+		}
+		at = form.EvaluateAt
+	case algorithm == "fast":
+		fast := append(opts[:len(opts):len(opts)], lagrange.WithFast(true))
+		at = func(x0 *big.Int) (*big.Int, error) { return lagrange.InterpolateAt(points, x0, fast...) }
+	case len(ats) > 1:
+		interpolator, err := lagrange.NewInterpolator(points, opts...)
+		if err != nil {
+			return nil, nil, err
+		}
+		at = interpolator.EvaluateAt
+	default:
+		at = func(x0 *big.Int) (*big.Int, error) { return lagrange.InterpolateAt(points, x0, opts...) }
+	}
+
+	values := make([]*big.Int, len(ats))
+	for i, x0 := range ats {
+		value, err := at(x0)
+		if err != nil {
+			return nil, nil, err
+		}
+		values[i] = value
+	}
+
+	if withCoefficients && coefficients == nil {
+		var err error
+		if coefficients, err = lagrange.Coefficients(points); err != nil {
+			return nil, nil, err
+		}
+	}
+	return values, coefficients, nil
+}
+
+// evaluateRational returns f(x0) with algorithm like evaluate, or the
+// fraction it failed on if f(x0) is not an integer.
+func evaluateRational(algorithm string, points []share.Point, x0 *big.Int, opts []lagrange.Option) (*big.Rat, error) {
+	values, _, err := evaluate(algorithm, points, []*big.Int{x0}, false, opts)
+	var fraction *lagrange.NonIntegerError
+	if errors.As(err, &fraction) {
+		return fraction.Value, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Rat).SetInt(values[0]), nil
+}
+
+// crossCheck reports the first value or coefficient on which the results of
+// two algorithms differ.
+func crossCheck(name, other string, ats, values, otherValues, coefficients, otherCoefficients []*big.Int) error {
+	for i, x0 := range ats {
+		if values[i].Cmp(otherValues[i]) != 0 {
+			return fmt.Errorf("cross-check failed: f(%s) is %s with %s but %s with %s", x0.String(), values[i].String(), name, otherValues[i].String(), other)
+		}
+	}
+	for d := range coefficients {
+		if coefficients[d].Cmp(otherCoefficients[d]) != 0 {
+			return fmt.Errorf("cross-check failed: coefficient of x^%d is %s with %s but %s with %s", d, coefficients[d].String(), name, otherCoefficients[d].String(), other)
+		}
+	}
+	return nil
+}
+
+// stringList collects the values of a repeatable string flag.
+type stringList []string
+
+func (l *stringList) String() string {
+	if l == nil {
+		return ""
+	}
+	return strings.Join(*l, ", ")
+}
+
+func (l *stringList) Set(s string) error {
+	*l = append(*l, s)
+	return nil
+}
+
+// intList collects the values of a repeatable integer flag.
+type intList []*big.Int
+
+func (l *intList) String() string {
+	if l == nil {
+		return ""
+	}
+	return joinInts(*l)
+}
+
+func (l *intList) Set(s string) error {
+	v, err := parseSecret(s)
+	if err != nil {
+		return err
+	}
+	*l = append(*l, v)
+	return nil
+}
+
+// traceInterpolation prints every term of the Lagrange interpolation of
+// points at each x in ats. Failures are left for the interpolation proper
+// to report.
+func traceInterpolation(w io.Writer, points []share.Point, ats []*big.Int, full bool, opts []lagrange.Option) {
+	number := func(s string) string {
+		if full {
+			return s
+		}
+		return elideNumber(s)
+	}
+	fraction := func(r *big.Rat) string {
+		if r.IsInt() {
+			return number(r.Num().String())
+		}
+		return number(r.Num().String()) + "/" + number(r.Denom().String())
+	}
+	for _, x0 := range ats {
+		fmt.Fprintf(w, "Terms of f(%s):\n", x0.String())
+		traced := append(opts[:len(opts):len(opts)], lagrange.WithTrace(func(t lagrange.Term) {
+			fmt.Fprintf(w, "  x=%s y=%s numerator=%s denominator=%s term=%s sum=%s\n",
+				number(t.X.String()), number(t.Y.String()), number(t.Numerator.String()), number(t.Denominator.String()), fraction(t.Term), fraction(t.Sum))
+		}))
+		lagrange.InterpolateAt(points, x0, traced...)
+	}
+}
+
+// showWeights prints a table of the basis coefficient ℓ_j(x0) of every
+// point and its contribution y_j·ℓ_j(x0) to f(x0), for each x0 in ats, and
+// checks that the coefficients sum to 1, as they interpolate the constant
+// polynomial 1. Modulo a prime the coefficients are reduced. Failures of
+// the interpolation itself are left for the interpolation proper to report.
+func showWeights(w io.Writer, points []share.Point, ats []*big.Int, modulus *big.Int, full bool, opts []lagrange.Option) error {
+	number := func(s string) string {
+		if full {
+			return s
+		}
+		return elideNumber(s)
+	}
+	fraction := func(r *big.Rat) string {
+		if r.IsInt() {
+			return number(r.Num().String())
+		}
+		return number(r.Num().String()) + "/" + number(r.Denom().String())
+	}
+	for _, x0 := range ats {
+		heading := fmt.Sprintf("Lagrange weights at x=%s", x0.String())
+		if modulus != nil {
+			heading += " modulo " + number(modulus.String())
+		}
+		fmt.Fprintf(w, "%s:\n", heading)
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintf(tw, "  X\tY\tℓ(%s)\tCONTRIBUTION\n", x0.String())
+		sum := new(big.Rat)
+		traced := append(opts[:len(opts):len(opts)], lagrange.WithTrace(func(t lagrange.Term) {
+			fmt.Fprintf(tw, "  %s\t%s\t%s\t%s\n", number(t.X.String()), number(t.Y.String()), fraction(t.Basis), fraction(t.Term))
+			sum.Add(sum, t.Basis)
+		}))
+		if _, err := lagrange.InterpolateAt(points, x0, traced...); err != nil {
+			return nil
+		}
+		tw.Flush()
+		if modulus != nil {
+			sum.SetInt(new(big.Int).Mod(sum.Num(), modulus))
+		}
+		if sum.Cmp(big.NewRat(1, 1)) != 0 {
+			return fmt.Errorf("the Lagrange weights at x=%s sum to %s instead of 1", x0.String(), sum.RatString())
+		}
+		fmt.Fprintln(w, "  The weights sum to 1")
+	}
+	return nil
+}
+
+// elideNumber shortens a decimal longer than 40 digits to its first and
+// last 12 digits and its length.
+func elideNumber(s string) string {
+	digits := strings.TrimPrefix(s, "-")
+	if len(digits) <= 40 {
+		return s
+	}
+	return fmt.Sprintf("%s%s…%s (%d digits)", s[:len(s)-len(digits)], digits[:12], digits[len(digits)-12:], len(digits))
+}
+
+func joinInts(values []*big.Int) string {
+	parts := make([]string, len(values))
+	for i, v := range values {
+		parts[i] = v.String()
+	}
+	return strings.Join(parts, ", ")
+}
+
+// runInspect prints the decoded entries of every JSON input to w without
+// reconstructing anything. It reports whether any entry has a problem.
+func (inv *invocation) runInspect(w io.Writer, paths []string, parseOpts []share.ParseOption, outputJSON bool) (bool, error) {
+	type entryResult struct {
+		Key      string   `json:"key"`
+		X        string   `json:"x,omitempty"`
+		Base     string   `json:"base"`
+		Value    string   `json:"value"`
+		Y        string   `json:"y,omitempty"`
+		Selected bool     `json:"selected"`
+		Problems []string `json:"problems,omitempty"`
+	}
+	type inspectResult struct {
+		Input       string        `json:"input"`
+		N           int           `json:"n"`
+		K           int           `json:"k"`
+		Participant string        `json:"participant,omitempty"`
+		Weight      int           `json:"weight,omitempty"`
+		Entries     []entryResult `json:"entries"`
+	}
+
+	problems := false
+	var results []inspectResult
+	var participants []share.Participant
+	held := make(map[string]int)
+	k := 0
+	for _, path := range paths {
+		input, inputName, err := inv.openInput(path)
+		if err != nil {
+			return problems, err
+		}
+		inspection, err := share.Inspect(input, parseOpts...)
+		input.Close()
+		if err != nil {
+			return problems, fmt.Errorf("%s: %w", inputName, err)
+		}
+
+		result := inspectResult{Input: inputName, N: inspection.N, K: inspection.K, Participant: inspection.Participant, Weight: inspection.Weight}
+		if inspection.Participant != "" && held[inspection.Participant] == 0 {
+			participants, k = inspection.Participants, inspection.K
+			held[inspection.Participant] = inspection.Weight
+		}
+		for _, entry := range inspection.Entries {
+			e := entryResult{Key: entry.Key, Base: entry.Base, Value: entry.Value, Selected: entry.Selected, Problems: entry.Problems}
+			if entry.X != nil {
+				e.X = entry.X.String()
+			}
+			if entry.Y != nil {
+				e.Y = entry.Y.String()
+			}
+			problems = problems || len(entry.Problems) > 0
+			result.Entries = append(result.Entries, e)
+		}
+		results = append(results, result)
+	}
+
+	if outputJSON {
+		printJSON(w, results)
+		return problems, nil
+	}
+	for i, result := range results {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintf(w, "%s: n=%d k=%d", result.Input, result.N, result.K)
+		if result.Participant != "" {
+			fmt.Fprintf(w, " participant=%s weight=%d", result.Participant, result.Weight)
+		}
+		fmt.Fprintln(w)
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "X\tBASE\tVALUE\tY\tUSED\tNOTES")
+		for _, e := range result.Entries {
+			x, used := e.X, ""
+			if x == "" {
+				x = e.Key
+			}
+			if e.Selected {
+				used = "yes"
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", x, e.Base, e.Value, e.Y, used, strings.Join(e.Problems, "; "))
+		}
+		tw.Flush()
+	}
+	if participants != nil {
+		fmt.Fprintf(w, "\nParticipants: %s\n", share.NewCoverage(participants, held, k))
+	}
+	return problems, nil
+}
+
+// createPrivate creates a file with mode 0600 for writing path. Without
+// force it is path itself and an existing file is an error; with force it is
+// a temporary file next to path that the caller renames over it once
+// complete, so a failed run leaves the old file in place.
+func createPrivate(path string, force bool) (*os.File, error) {
+	if force {
+		file, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp*")
+		if err != nil {
+			return nil, fmt.Errorf("failed to create output file: %w", err)
+		}
+		return file, nil
+	}
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if errors.Is(err, os.ErrExist) {
+		return nil, classify(exitIO, fmt.Errorf("output file %s already exists (use --force to overwrite)", path))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create output file: %w", err)
+	}
+	return file, nil
+}
+
+// shareSource names the input a share used was read from.
+type shareSource struct {
+	X      string `json:"x"`
+	Source string `json:"source"`
+}
+
+// reconstructResult is the document printed by --output json.
+type reconstructResult struct {
+	Secret       string        `json:"secret"`
+	SecretBase64 string        `json:"secret_base64,omitempty"`
+	At           string        `json:"at,omitempty"`
+	Derivative   bool          `json:"derivative,omitempty"`
+	Rational     bool          `json:"rational,omitempty"`
+	HashVerified bool          `json:"secret_hash_verified,omitempty"`
+	PointsParsed int           `json:"points_parsed"`
+	XUsed        []string      `json:"x_used"`
+	ShareSources []shareSource `json:"share_sources"`
+	K            int           `json:"k"`
+	N            int           `json:"n"`
+	Inputs       []string      `json:"inputs"`
+	Verified     int           `json:"verified,omitempty"`
+	VSSVerified  int           `json:"vss_verified,omitempty"`
+	Blinding     string        `json:"blinding,omitempty"`
+	Corrected    []string      `json:"corrected,omitempty"`
+	Suspects     []string      `json:"suspects,omitempty"`
+	Votes        int           `json:"votes,omitempty"`
+	Coefficients []string      `json:"coefficients,omitempty"`
+
+	// Values holds the results for every --at after the first, whose
+	// result is Secret.
+	Values []evaluation `json:"values,omitempty"`
+}
+
+type evaluation struct {
+	At    string `json:"at"`
+	Value string `json:"value"`
+}
+
+type errorResult struct {
+	Error     string           `json:"error"`
+	Conflicts []share.Conflict `json:"conflicts,omitempty"`
+}
+
+// printError prints err for a human to w, spreading the location carried by a
+// *share.ParseError, or the conflicts of a *share.ConflictError, over
+// several lines.
+func printError(w io.Writer, err error) {
+	var conflictErr *share.ConflictError
+	if errors.As(err, &conflictErr) && len(conflictErr.Conflicts) > 1 {
+		fmt.Fprintf(w, "Error: %d conflicts between share sources:\n", len(conflictErr.Conflicts))
+		for _, c := range conflictErr.Conflicts {
+			fmt.Fprintf(w, "  %s\n", c.Message)
+		}
+		return
+	}
+
+	var parseErr *share.ParseError
+	if !errors.As(err, &parseErr) {
+		fmt.Fprintln(w, "Error:", err)
+		return
+	}
+
+	fmt.Fprintln(w, "Error:", strings.TrimSuffix(err.Error(), parseErr.Error())+parseErr.Err.Error())
+	fmt.Fprintf(w, "  entry: %q\n", parseErr.Key)
+	if parseErr.Field != "" {
+		fmt.Fprintf(w, "  field: %s\n", parseErr.Field)
+	}
+	fmt.Fprintf(w, "  text:\n      %s\n", parseErr.Snippet)
+}
+
+func intStrings(values []*big.Int) []string {
+	parts := make([]string, len(values))
+	for i, v := range values {
+		parts[i] = v.String()
+	}
+	return parts
+}
+
+func printJSON(w io.Writer, v any) {
+	encoded, _ := json.MarshalIndent(v, "", "  ")
+	fmt.Fprintln(w, string(encoded))
+}
+
+// command is a subcommand of the tool.
+type command struct {
+	name     string
+	synopsis string
+	summary  string
+	run      func(inv *invocation, args []string, stdout, stderr io.Writer) int
+}
+
+// commands lists the subcommands, reconstruct first since it is also run
+// when the first argument names an input rather than a command.
+func commands() []command {
+	inputs := "[flags] <path_to_json_file | url | $variable | -> [more files...]"
+	return []command{
+		{"reconstruct", inputs, "recover the secret from shares", reconstructCommand("reconstruct")},
+		{"verify", inputs, "check that shares decode and agree with each other, without printing the secret", errorCommand(runVerify)},
+		{"eval", "--at <x> " + inputs, "evaluate the shared polynomial at x instead of 0", reconstructCommand("eval")},
+		{"inspect", inputs, "print a table of the decoded shares without reconstructing", reconstructCommand("inspect")},
+		{"split", "--n <n> --k <k> [flags]", "split a secret into shares", errorCommand(runSplit)},
+		{"migrate", "[--out <file> [--force]] <path_to_json_file>", "rewrite a share file at the current format version", errorCommand(runMigrate)},
+		{"convert", "--to-base <base>[,<base>...] [--force] <in.json> <out.json>", "rewrite the values of a share file in another base", errorCommand(runConvert)},
+		{"add", "[--out <file>] [--mod <prime>] <shares_a.json> <shares_b.json>", "add two share sets to get shares of the sum of their secrets", errorCommand(runAdd)},
+		{"diff", "[--strict] [--output json] <a.json> <b.json>", "compare the shares and keys two share files hold, ignoring the bases they are written in", errorCommand(runDiff)},
+		{"scale", "[--out <file>] [--mod <prime>] [--expected-result <value>] <shares.json> [--] <scalar>", "multiply every share by a constant, after -- if negative, to get shares of that multiple of the secret", errorCommand(runScale)},
+		{"reshare", "--n <n> --k <k> [--out <file>] [--mod <prime>] <share_files>...", "split at least k old shares into a fresh set for a new n and k without forming the secret", errorCommand(runReshare)},
+		{"serve", "[--addr <host:port>] [--grpc-addr <host:port>] [--mod <prime>] [--max-body-bytes <n>]", "serve reconstruction over HTTP, and split and reconstruct over gRPC", errorCommand(runServe)},
+		{"gen-testvectors", "[--out <dir>] [--digits <n>] [--seed <hex>]", "write a corpus of share files with their expected results", errorCommand(runGenTestVectors)},
+		{"selftest", "[--verbose]", "check this binary against the known-answer vectors built into it", errorCommand(runSelftest)},
+	}
+}
+
+func findCommand(name string) (command, bool) {
+	for _, c := range commands() {
+		if c.name == name {
+			return c, true
+		}
+	}
+	return command{}, false
+}
+
+// printCommands prints the list of subcommands to w.
+func printCommands(w io.Writer) {
+	fmt.Fprintln(w, "Usage: go run main.go <command> [flags] [arguments]")
+	fmt.Fprintln(w, "       go run main.go [flags] <inputs...>    (reconstruct)")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, c := range commands() {
+		fmt.Fprintf(tw, "  %s\t%s\n", c.name, c.summary)
+	}
+	tw.Flush()
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, `Run "go run main.go help <command>" for the flags of a command.`)
+}
+
+// run runs the command named by args[0] with the rest of args, or
+// reconstruct with all of them when args[0] is an input or flag, and
+// returns the exit code:
+//
+//	0  success
+//	1  a failure of no other class, such as some lines of a batch failing
+//	2  bad usage: unknown commands or flags, or flags that conflict
+//	3  an input that could not be read or an output that could not be written
+//	4  an input that does not parse or is not valid
+//	5  fewer shares than the threshold
+//	6  shares that are inconsistent, corrupted or conflicting
+//	7  a secret other than the --expected one
+//	8  a run stopped by --timeout
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	errOut = stderr
+	logger = slog.New(slog.NewTextHandler(stderr, &slog.HandlerOptions{Level: slog.LevelWarn}))
+	inv := newInvocation(stdin)
+	if len(args) == 0 {
+		return runReconstruct(inv, "reconstruct", args, stdout, stderr)
+	}
+	if c, ok := findCommand(args[0]); ok {
+		return c.run(inv, args[1:], stdout, stderr)
+	}
+	switch name := args[0]; {
+	case name == "help":
+		if len(args) > 1 {
+			if c, ok := findCommand(args[1]); ok {
+				return c.run(inv, []string{"-h"}, stdout, stdout)
+			}
+			fmt.Fprintf(stderr, "unknown command %q\n\n", args[1])
+			printCommands(stderr)
+			return exitUsage
+		}
+		printCommands(stdout)
+		return 0
+	case isInputArg(name):
+		return runReconstruct(inv, "reconstruct", args, stdout, stderr)
+	default:
+		fmt.Fprintf(stderr, "unknown command %q\n\n", name)
+		printCommands(stderr)
+		return exitUsage
+	}
+}
+
+// isInputArg reports whether arg, the first argument, is a flag or an
+// input of reconstruct rather than the name of a command, so that the
+// bare "main.go file.json" keeps working.
+func isInputArg(arg string) bool {
+	if strings.HasPrefix(arg, "-") || strings.HasPrefix(arg, "$") || strings.ContainsAny(arg, `./\:`) {
+		return true
+	}
+	_, err := os.Stat(arg)
+	return err == nil
+}
+
+// newFlagSet returns the flag set of the command name, which reports bad
+// usage to stderr with the usage text of the command.
+func newFlagSet(name string, stderr io.Writer) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		c, _ := findCommand(name)
+		fmt.Fprintf(fs.Output(), "Usage: go run main.go %s %s\n\n%s.\n\nFlags:\n", name, c.synopsis, strings.ToUpper(c.summary[:1])+c.summary[1:])
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parseArgs parses the flags of args into fs and returns the other
+// arguments. Unlike fs.Parse it also accepts flags after them, as in
+// "reconstruct shares.json -q"; everything after "--" is an argument.
+func parseArgs(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		rest := fs.Args()
+		if n := len(args) - len(rest); n > 0 && args[n-1] == "--" {
+			return append(positional, rest...), nil
+		}
+		if len(rest) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, rest[0])
+		args = rest[1:]
+	}
+}
+
+// usageError is a command line a command could not parse. Its flag set
+// has already reported it.
+type usageError struct {
+	err error
+}
+
+func (e usageError) Error() string {
+	return e.err.Error()
+}
+
+// Exit codes, stable for scripts. A failure of no other class exits with
+// exitFailure.
+const (
+	exitOK           = 0
+	exitFailure      = 1
+	exitUsage        = 2
+	exitIO           = 3
+	exitInvalid      = 4
+	exitInsufficient = 5
+	exitInconsistent = 6
+	exitMismatch     = 7
+	exitTimeout      = 8
 )
 
-type Point struct {
-	X *big.Int
-	Y *big.Int
+// classError gives err the exit code of its class.
+type classError struct {
+	code int
+	err  error
+}
+
+func (e *classError) Error() string { return e.err.Error() }
+
+func (e *classError) Unwrap() error { return e.err }
+
+// classify gives err the exit code code, unless err is nil.
+func classify(code int, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &classError{code: code, err: err}
+}
+
+// usagef returns an error in the usage of a command.
+func usagef(format string, args ...any) error {
+	return classify(exitUsage, fmt.Errorf(format, args...))
+}
+
+// errExpectedMismatch is a secret other than the expected one.
+var errExpectedMismatch = errors.New("mismatch")
+
+// exitCode returns the exit code of err. What went wrong with the shares
+// takes precedence over where it was found, and files that could not be
+// read over the class the error was given. -h asked for the usage, so it
+// succeeds.
+func exitCode(err error) int {
+	var usage usageError
+	var inconsistent *reconstruct.InconsistentError
+	var conflict *share.ConflictError
+	var pathErr *os.PathError
+	var class *classError
+	switch {
+	case err == nil:
+		return exitOK
+	case errors.As(err, &usage):
+		if errors.Is(usage.err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage
+	case errors.Is(err, share.ErrInsufficientShares):
+		return exitInsufficient
+	case errors.Is(err, share.ErrChecksumMismatch), errors.Is(err, share.ErrCommitmentMismatch),
+		errors.Is(err, lagrange.ErrNonIntegerSecret), errors.As(err, &inconsistent), errors.As(err, &conflict):
+		return exitInconsistent
+	case errors.Is(err, errExpectedMismatch), errors.Is(err, share.ErrSecretHashMismatch):
+		return exitMismatch
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, context.Canceled):
+		return exitTimeout
+	case errors.As(err, &pathErr):
+		return exitIO
+	case errors.As(err, &class):
+		return class.code
+	}
+	return exitFailure
+}
+
+// errorCommand adapts a command that returns an error, printing it and
+// exiting with the code of its class.
+func errorCommand(fn func(inv *invocation, args []string, stdout, stderr io.Writer) error) func(inv *invocation, args []string, stdout, stderr io.Writer) int {
+	return func(inv *invocation, args []string, stdout, stderr io.Writer) int {
+		err := fn(inv, args, stdout, stderr)
+		var usage usageError
+		if err != nil && !errors.As(err, &usage) {
+			printError(stderr, err)
+		}
+		return exitCode(err)
+	}
+}
+
+func reconstructCommand(name string) func(inv *invocation, args []string, stdout, stderr io.Writer) int {
+	return func(inv *invocation, args []string, stdout, stderr io.Writer) int {
+		return runReconstruct(inv, name, args, stdout, stderr)
+	}
+}
+
+// runVerify checks that the shares of its inputs decode, match their
+// checksums and commitments, and lie on one polynomial, without printing
+// the secret they share.
+func runVerify(inv *invocation, args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("verify", stderr)
+	modFlag := fs.String("mod", "", "interpolate modulo this prime (decimal or 0x hex), or auto for the one the keys object records, which is used when --mod is not given")
+	allowCompositeFlag := fs.Bool("allow-composite", false, "accept a modulus that is not prime")
+	formatFlag := fs.String("format", "auto", "input format: auto, json, csv, yaml, toml, cbor or msgpack")
+	kFlag := fs.Int("k", 0, "threshold for inputs that do not record one, such as CSV")
+	caseFlag := fs.String("case", "", "name of the test case to verify from a multi-case file")
+	noVSSFlag := fs.Bool("no-vss", false, "do not check the shares against the Feldman or Pedersen commitments of their file")
+	strictFlag := fs.Bool("strict", false, "reject unknown JSON fields and a share count that differs from the declared n")
+	lenientFlag := fs.Bool("lenient", false, "accept // and /* */ comments and trailing commas in JSON input")
+	fs.StringVar(&inv.passphraseFile, "passphrase-file", "", "read the passphrase of encrypted share files from the first line of this file")
+	installLogger := logFlags(fs, stderr)
+	paths, err := parseArgs(fs, args)
+	if err != nil {
+		return usageError{err}
+	}
+	if err := installLogger(); err != nil {
+		return err
+	}
+
+	var modulus *big.Int
+	if *modFlag != "" && *modFlag != "auto" {
+		if modulus, err = parseModulus(*modFlag); err != nil {
+			return err
+		}
+		if err := checkPrime(modulus, *allowCompositeFlag); err != nil {
+			return err
+		}
+	}
+	var parseOpts []share.ParseOption
+	if *strictFlag {
+		parseOpts = append(parseOpts, share.StrictFields())
+	}
+	if *lenientFlag {
+		parseOpts = append(parseOpts, share.Lenient())
+	}
+	if len(paths) == 0 {
+		paths = []string{"-"}
+	}
+	shares, cases, err := inv.loadShares(stdout, paths, *formatFlag, *kFlag, *caseFlag, *strictFlag, parseOpts)
+	if err != nil {
+		return err
+	}
+	if cases != nil {
+		return errors.New("the input holds several test cases; choose one with --case")
+	}
+	if modulus, err = shareModulus(modulus, *modFlag == "auto", *allowCompositeFlag, shares); err != nil {
+		return err
+	}
+	var opts []lagrange.Option
+	if modulus != nil {
+		opts = append(opts, lagrange.WithModulus(modulus))
+	}
+	result, err := reconstruct.Shares(shares, reconstruct.Options{NoVSS: *noVSSFlag, Lagrange: opts})
+	if err != nil {
+		return err
+	}
+
+	checks := []string{"decode"}
+	if shares.Checksummed {
+		checks = append(checks, "match their checksums")
+	}
+	if shares.Commitments != nil && !*noVSSFlag {
+		checks = append(checks, "match their "+commitmentName(shares.Commitments.Scheme)+" commitments")
+	}
+	if len(result.Extra) > 0 {
+		checks = append(checks, "lie on one polynomial")
+	}
+	if result.HashVerified {
+		checks = append(checks, "give the secret whose SHA-256 was recorded at split time")
+	}
+	fmt.Fprintf(stdout, "\n All %d shares %s\n", len(shares.Points), strings.Join(checks, ", "))
+	logger.Info("verified shares", "verified", len(shares.Points), "checks", strings.Join(checks, ", "))
+	if len(result.Extra) == 0 && (shares.Commitments == nil || *noVSSFlag) {
+		fmt.Fprintf(stdout, " With only k=%d shares there were none left to cross-check the others against\n", shares.K)
+	}
+	return nil
 }
 
-type tempRoot struct {
-	Base  string `json:"base"`
-	Value string `json:"value"`
+// reconstructFlags are the flags of reconstruct, eval and inspect.
+type reconstructFlags struct {
+	mod                string
+	allowComposite     bool
+	coefficients       bool
+	correctErrors      bool
+	vote               bool
+	noVerify           bool
+	noVSS              bool
+	blinding           bool
+	format             string
+	k                  int
+	use                string
+	exclude            string
+	verbose            bool
+	full               bool
+	showWeights        bool
+	quiet              bool
+	output             string
+	caseName           string
+	keepGoing          bool
+	encode             string
+	prefix             bool
+	asText             bool
+	allowDuplicateKeys bool
+	strictValues       bool
+	lenient            bool
+	strict             bool
+	dryRun             bool
+	at                 intList
+	allowRational      bool
+	derivative         bool
+	expected           string
+	out                string
+	force              bool
+	report             string
+	algorithm          string
+	crossCheck         bool
+	workers            int
+	field              string
+	poly               string
+	unsealKeys         stringList
+	byteLength         int
+	interactive        bool
+	watch              string
+	watchInterval      time.Duration
+	timeout            time.Duration
+	fromEnv            string
+}
+
+func reconstructFlagSet(fs *flag.FlagSet, inv *invocation) *reconstructFlags {
+	f := &reconstructFlags{}
+	fs.StringVar(&f.mod, "mod", "", "perform interpolation modulo this prime (decimal or 0x hex), or auto for the one the keys object records, which is used when --mod is not given")
+	fs.BoolVar(&f.allowComposite, "allow-composite", false, "accept a modulus that is not prime")
+	fs.BoolVar(&f.coefficients, "coefficients", false, "also print every polynomial coefficient, highest degree first")
+	fs.BoolVar(&f.correctErrors, "correct-errors", false, "use Berlekamp-Welch decoding to correct corrupted shares (requires --mod)")
+	fs.BoolVar(&f.vote, "vote", false, "interpolate every k-subset of shares and report the majority secret")
+	fs.BoolVar(&f.noVerify, "no-verify", false, "do not check the unused shares against the reconstructed polynomial")
+	fs.BoolVar(&f.noVSS, "no-vss", false, "do not check the shares against the Feldman or Pedersen commitments of their file")
+	fs.BoolVar(&f.blinding, "blinding", false, "also reconstruct the blinding value of shares with Pedersen commitments")
+	fs.StringVar(&f.format, "format", "auto", "input format: auto, json, jsonl, csv, yaml, toml, cbor, msgpack, vault or mnemonic")
+	fs.IntVar(&f.k, "k", 0, "threshold for inputs that do not record one, such as CSV")
+	fs.StringVar(&f.use, "use", "", "comma-separated x values of the shares to interpolate")
+	fs.StringVar(&f.exclude, "exclude", "", "comma-separated x values of shares to leave out")
+	fs.BoolVar(&f.verbose, "verbose", false, "print which input each used share came from and every term of the interpolation")
+	fs.BoolVar(&f.full, "full", false, "print the numbers of --verbose and --show-weights in full instead of eliding the middle of long ones")
+	fs.BoolVar(&f.showWeights, "show-weights", false, "print a table of each share's Lagrange basis coefficient ℓ_j(0) as an exact fraction and its contribution y_j·ℓ_j(0)")
+	fs.BoolVar(&f.quiet, "quiet", false, "print only the secret, or with --output json only the JSON result, and errors to stderr")
+	fs.BoolVar(&f.quiet, "q", false, "shorthand for --quiet")
+	fs.StringVar(&f.output, "output", "text", "result format: text or json")
+	fs.StringVar(&f.caseName, "case", "", "name of the test case to reconstruct from a multi-case file")
+	fs.BoolVar(&f.keepGoing, "keep-going", false, "in batch mode, continue after a line fails")
+	fs.StringVar(&f.encode, "encode", "dec", "encoding of the printed secret: "+strings.Join(secret.Encodings, ", "))
+	fs.BoolVar(&f.prefix, "prefix", false, "prefix hex secrets with 0x")
+	fs.BoolVar(&f.asText, "as-text", false, "print the secret's big-endian bytes as UTF-8 text")
+	fs.BoolVar(&f.allowDuplicateKeys, "allow-duplicate-keys", false, "accept JSON objects that repeat a key, keeping the last one")
+	fs.BoolVar(&f.strictValues, "strict-values", false, "reject share values containing whitespace or _ separators instead of removing them")
+	fs.BoolVar(&f.lenient, "lenient", false, "accept // and /* */ comments and trailing commas in JSON input")
+	fs.BoolVar(&f.strict, "strict", false, "reject unknown JSON fields and a share count that differs from the declared n")
+	fs.BoolVar(&f.dryRun, "dry-run", false, "only decode the JSON inputs and print a table of their shares")
+	fs.Var(&f.at, "at", "evaluate the polynomial at this x (decimal or 0x hex) instead of 0; repeat for several x")
+	fs.BoolVar(&f.allowRational, "allow-rational", false, "print a fractional result of exact interpolation as p/q instead of failing on it")
+	fs.BoolVar(&f.derivative, "derivative", false, "evaluate the derivative f'(x) at 0 or at every --at instead of f(x), from the shares without computing coefficients")
+	fs.StringVar(&f.expected, "expected", "", "fail unless the secret equals this value (decimal or 0x hex)")
+	fs.StringVar(&f.out, "out", "", "write the result to this file, created with mode 0600, instead of stdout; shares of a file split with split --in need it for the reassembled file")
+	fs.BoolVar(&f.force, "force", false, "let --out and --report overwrite an existing file")
+	fs.StringVar(&f.report, "report", "", "write a JSON audit record of the run, holding a hash of the secret but not the secret, to this file")
+	fs.StringVar(&f.algorithm, "algorithm", "lagrange", "interpolation algorithm: lagrange, newton or fast (product trees, for very large k; chosen by itself from 1024 shares with --mod)")
+	fs.BoolVar(&f.crossCheck, "cross-check", false, "interpolate with both algorithms and fail if they disagree")
+	fs.IntVar(&f.workers, "workers", 0, "goroutines computing interpolation terms; 0 uses every CPU, 1 forces serial")
+	fs.StringVar(&f.field, "field", "integer", "field the shares are over: integer, gf256 for byte-wise shares, gf2m for a binary field GF(2^m), a named prime field (secp256k1, p256, ed25519 or mersenne127), which the keys object of a split over one records, or list to print the named fields")
+	fs.StringVar(&f.poly, "poly", "", "with --field gf2m, the reduction polynomial (0x hex or decimal), used when the keys object records none and checked against the one it records")
+	fs.Var(&f.unsealKeys, "unseal-key", "a HashiCorp Vault unseal key in base64 or hex; repeat for every key (implies --format vault)")
+	fs.Var(&inv.fetch.headers, "header", "an HTTP header, Name: value, sent when fetching URL inputs; repeat for several")
+	fs.DurationVar(&inv.fetch.timeout, "url-timeout", inv.fetch.timeout, "time limit for fetching each URL input")
+	fs.Int64Var(&inv.fetch.maxBytes, "max-url-bytes", inv.fetch.maxBytes, "largest response accepted from a URL input")
+	fs.StringVar(&inv.passphraseFile, "passphrase-file", "", "read the passphrase of encrypted share files from the first line of this file")
+	fs.IntVar(&f.byteLength, "byte-length", 0, "left-pad the secret's bytes with zeros to this length for --as-text or --encode bip39")
+	fs.BoolVar(&f.interactive, "interactive", false, "prompt for k and then for each share, one per line; the default when run on a terminal without inputs")
+	fs.StringVar(&f.watch, "watch", "", "wait for share files to appear in this directory and reconstruct once k shares have arrived")
+	fs.DurationVar(&f.watchInterval, "watch-interval", 500*time.Millisecond, "how often --watch looks for new files")
+	fs.DurationVar(&f.timeout, "timeout", 0, "stop the run, exiting with code 8, if it takes longer than this; 0 means no limit")
+	fs.StringVar(&f.fromEnv, "from-env", "", "also read a share document from this environment variable, base64 or not; "+defaultSharesEnv+" is read when no input is given")
+	return f
+}
+
+// parseOptions returns the parse options the flags ask for.
+func (f *reconstructFlags) parseOptions() []share.ParseOption {
+	var parseOpts []share.ParseOption
+	if f.field == share.FieldGF2m {
+		parseOpts = append(parseOpts, share.BinaryField())
+	}
+	if f.strictValues {
+		parseOpts = append(parseOpts, share.StrictValues())
+	}
+	if f.strict {
+		parseOpts = append(parseOpts, share.StrictFields())
+	}
+	if f.allowDuplicateKeys {
+		parseOpts = append(parseOpts, share.AllowDuplicateKeys())
+	}
+	if f.lenient {
+		parseOpts = append(parseOpts, share.Lenient())
+	}
+	return parseOpts
 }
 
-type tempKeys struct {
-	N int `json:"n"`
-	K int `json:"k"`
+// reconstructRun is the state the steps of one run of reconstruct share:
+// where its output goes, the --out file and the audit record, which fail
+// and finish complete.
+type reconstructRun struct {
+	inv    *invocation
+	flags  *reconstructFlags
+	stderr io.Writer
+
+	// info receives the progress messages, discarded for --output json
+	// and --quiet; errorOut receives the errors; out receives the result.
+	info, errorOut, out io.Writer
+
+	outputJSON bool
+	// quiet prints the bare secret in place of the text report.
+	quiet bool
+
+	outFile *os.File
+	audit   *report.Report
 }
 
-func parseInputFile(filePath string) ([]Point, error) {
-	fileBytes, err := os.ReadFile(filePath)
+func runReconstruct(inv *invocation, name string, args []string, stdout, stderr io.Writer) int {
+	fs := newFlagSet(name, stderr)
+	f := reconstructFlagSet(fs, inv)
+	installLogger := logFlags(fs, stderr)
+	filePaths, err := parseArgs(fs, args)
+	if err != nil {
+		return exitCode(usageError{err})
+	}
+	if err := installLogger(); err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return exitCode(err)
+	}
+	if f.field == "list" {
+		listFields(stdout)
+		return exitOK
+	}
+	switch name {
+	case "eval":
+		if len(f.at) == 0 {
+			fmt.Fprintln(stderr, "eval requires --at")
+			fs.Usage()
+			return exitUsage
+		}
+	case "inspect":
+		f.dryRun = true
+	}
+
+	if f.fromEnv != "" {
+		filePaths = append([]string{"$" + f.fromEnv}, filePaths...)
+	}
+	if len(filePaths) == 0 && !stdinIsPiped() && f.watch == "" {
+		if _, ok := os.LookupEnv(defaultSharesEnv); ok {
+			filePaths = []string{"$" + defaultSharesEnv}
+		}
+	}
+
+	if len(filePaths) == 0 && !stdinIsPiped() && f.watch == "" && !f.interactive && fs.NFlag() > 0 {
+		fs.Usage()
+		return exitUsage
+	}
+	if len(f.unsealKeys) > 0 {
+		f.format = "vault"
+	}
+	vault := f.format == "vault"
+
+	interactive := f.interactive || len(filePaths) == 0 && !stdinIsPiped() && f.watch == "" && len(f.unsealKeys) == 0
+	if len(filePaths) == 0 && len(f.unsealKeys) == 0 && f.watch == "" && !interactive {
+		filePaths = []string{"-"}
+	}
+	if vault || f.format == "mnemonic" || f.format == "auto" && len(filePaths) > 0 && inv.isMnemonicInput(filePaths[0]) {
+		f.field = share.FieldGF256
+	}
+	fileShares := !f.dryRun && (f.format == "auto" || f.format == "json") && len(filePaths) > 0 && inv.isFileShareInput(filePaths[0])
+
+	r := &reconstructRun{inv: inv, flags: f, stderr: stderr, info: stdout, errorOut: stdout, out: stdout}
+	r.outputJSON = f.output == "json"
+	if r.outputJSON || f.quiet {
+		r.info = io.Discard
+	}
+	r.quiet = f.quiet && !r.outputJSON
+	if f.quiet {
+		r.errorOut = stderr
+	}
+
+	if f.output != "text" && !r.outputJSON {
+		return r.fail(usagef("unknown output format: %s", f.output))
+	}
+	if fileShares {
+		return r.fileShares(fs, filePaths)
+	}
+	encodeSet, err := checkFieldFlags(fs, f, vault)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read file: %w", err)
+		return r.fail(err)
 	}
 
-	var rawData map[string]json.RawMessage
-	if err := json.Unmarshal(fileBytes, &rawData); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal raw json: %w", err)
+	poly, modulus, err := fieldArithmetic(f)
+	if err != nil {
+		return r.fail(err)
 	}
+	modAuto := f.mod == "auto"
 
-	var keysData tempKeys
-	if err := json.Unmarshal(rawData["keys"], &keysData); err != nil {
-		return nil, fmt.Errorf("failed to parse 'keys' object: %w", err)
+	if f.workers < 0 {
+		return r.fail(usagef("invalid --workers %d: must not be negative", f.workers))
+	}
+	opts := []lagrange.Option{lagrange.WithWorkers(f.workers)}
+	if modulus != nil {
+		opts = append(opts, lagrange.WithModulus(modulus))
 	}
-	k := keysData.K
 
-	points := make([]Point, 0, k)
+	switch f.algorithm {
+	case "lagrange", "newton", "fast":
+	default:
+		return r.fail(usagef("unknown --algorithm: %s", f.algorithm))
+	}
+	if (f.algorithm != "lagrange" || f.crossCheck) && (f.correctErrors || f.vote) {
+		return r.fail(usagef("--algorithm and --cross-check are not supported together with --correct-errors or --vote"))
+	}
 
-	for key, rawValue := range rawData {
-		if key == "keys" {
-			continue
+	x0 := big.NewInt(0)
+	if len(f.at) > 0 {
+		if f.correctErrors || f.vote {
+			return r.fail(usagef("--at is not supported together with --correct-errors or --vote"))
+		}
+		x0 = f.at[0]
+	}
+	if f.report != "" {
+		if f.dryRun {
+			return r.fail(usagef("--report is not supported together with --dry-run"))
 		}
+		algorithm := f.algorithm
+		switch {
+		case f.correctErrors:
+			algorithm = "berlekamp-welch"
+		case f.vote:
+			algorithm = "vote"
+		}
+		r.audit = report.New(toolVersion(), algorithm, modulus)
+	}
 
-		if len(points) >= k {
-			break
+	parseOpts := f.parseOptions()
+	ctx := context.Background()
+	if f.timeout < 0 {
+		return r.fail(usagef("invalid --timeout %s: must not be negative", f.timeout))
+	}
+	if f.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, f.timeout)
+		defer cancel()
+		parseOpts = append(parseOpts, share.WithContext(ctx))
+		opts = append(opts, lagrange.WithContext(ctx))
+	}
+
+	// Progress is drawn on a terminal only, where it cannot end up mixed
+	// into a log or a result.
+	var progress *progressLine
+	if isTerminal(stderr) && !f.quiet {
+		progress = &progressLine{w: stderr}
+		parseOpts = append(parseOpts, share.WithProgress(progress.reporter("parsed %s shares")))
+		opts = append(opts, lagrange.WithProgress(progress.reporter("interpolation step %s")))
+	}
+
+	expect := expectation{modulus: modulus}
+	if f.expected != "" {
+		want, err := parseSecret(f.expected)
+		if err != nil {
+			return r.fail(usagef("invalid --expected: %w", err))
 		}
+		expect.want = want
+	}
+	if _, err := r.render(big.NewInt(0)); err != nil {
+		return r.fail(classify(exitUsage, err))
+	}
 
-		var root tempRoot
-		if err := json.Unmarshal(rawValue, &root); err != nil {
-			return nil, fmt.Errorf("failed to parse point '%s': %w", key, err)
+	if f.out != "" {
+		file, err := createPrivate(f.out, f.force)
+		if err != nil {
+			return r.fail(err)
 		}
+		r.outFile, r.out = file, file
+	}
 
-		x, err := strconv.ParseInt(key, 10, 64)
+	if f.field == share.FieldGF256 {
+		return r.gf256(filePaths, parseOpts, vault, encodeSet)
+	}
+	if f.format == "jsonl" || (f.format == "auto" && len(filePaths) == 1 && strings.EqualFold(inputExt(filePaths[0]), ".jsonl")) {
+		return r.batch(filePaths[0], parseOpts, opts, expect, modAuto)
+	}
+	if f.dryRun {
+		if f.format != "auto" && f.format != "json" {
+			return r.fail(usagef("--dry-run supports JSON input only"))
+		}
+		problems, err := inv.runInspect(r.out, filePaths, parseOpts, r.outputJSON)
 		if err != nil {
-			return nil, fmt.Errorf("invalid x value (key): %s", key)
+			return r.fail(err)
+		}
+		return r.finishFailing(problems)
+	}
+
+	switch {
+	case interactive && (len(filePaths) > 0 || f.watch != ""):
+		return r.fail(usagef("--interactive takes no other inputs"))
+	case interactive:
+	case f.watch != "" && (len(filePaths) > 0 || f.format != "auto" && f.format != "json"):
+		return r.fail(usagef("--watch reads the JSON share files of its directory and takes no other inputs"))
+	case f.watch != "" && f.watchInterval <= 0:
+		return r.fail(usagef("invalid --watch-interval %s: must be positive", f.watchInterval))
+	}
+	parseStart := time.Now()
+	shares, cases, filePaths, err := r.readShares(ctx, filePaths, interactive, parseOpts)
+	if progress != nil {
+		progress.clear()
+	}
+	if r.audit != nil {
+		r.audit.Timing.ParseMS = durationMillis(parseStart)
+		if !interactive {
+			for _, path := range filePaths {
+				r.audit.AddInput(path)
+			}
+		}
+	}
+	if err != nil {
+		return r.fail(err)
+	}
+	if cases != nil && r.audit != nil {
+		return r.fail(usagef("--report records a single reconstruction; choose a test case with --case"))
+	}
+	if cases != nil && modAuto {
+		return r.fail(usagef("--mod auto reads the modulus of a single share set; choose a test case with --case"))
+	}
+	if cases != nil && f.field == share.FieldGF2m {
+		return r.fail(usagef("--field gf2m reads a single share set, not test cases"))
+	}
+	if cases != nil {
+		return r.finishFailing(runCases(r.out, cases, r.outputJSON, f.noVerify, f.noVSS, opts, expect, r.render))
+	}
+
+	var binary *gf2m.Field
+	if f.field == share.FieldGF2m {
+		if binary, err = shareBinaryField(poly, shares); err != nil {
+			return r.fail(err)
+		}
+		opts = append(opts, lagrange.WithField(binary))
+		if r.audit != nil {
+			r.audit.Field, r.audit.Poly = share.FieldGF2m, fmt.Sprintf("%#x", binary.Poly())
+		}
+	}
+	recorded, err := shareModulus(modulus, modAuto, f.allowComposite, shares)
+	if err != nil {
+		return r.fail(err)
+	}
+	if modulus == nil && recorded != nil {
+		modulus = recorded
+		opts = append(opts, lagrange.WithModulus(modulus))
+		expect.modulus = modulus
+		if r.audit != nil {
+			r.audit.Field, r.audit.Modulus = "prime", modulus.String()
+		}
+		logger.Info("using the modulus the keys object records", "modulus", modulus.String())
+	}
+	return r.reconstructShares(shares, filePaths, x0, modulus, binary, opts, expect)
+}
+
+// fileShares reassembles the file the shares of a split --in came from.
+func (r *reconstructRun) fileShares(fs *flag.FlagSet, filePaths []string) int {
+	f := r.flags
+	var conflict string
+	fs.Visit(func(flag *flag.Flag) {
+		switch flag.Name {
+		case "out", "force", "no-verify", "field", "format", "quiet", "q", "passphrase-file", "from-env",
+			"header", "url-timeout", "max-url-bytes", "log-level", "log-format":
+		default:
+			conflict = flag.Name
 		}
+	})
+	switch {
+	case conflict != "":
+		return r.fail(usagef("shares of a file are not supported together with --%s", conflict))
+	case f.field != "integer" && f.field != share.FieldGF256:
+		return r.fail(usagef("shares of a file are over GF(256), not --field %s", f.field))
+	case f.out == "":
+		return r.fail(usagef("shares of a file split with split --in need --out to write the file to"))
+	}
+	if err := r.inv.reconstructFile(r.info, filePaths, f.out, f.force, f.noVerify); err != nil {
+		return r.fail(err)
+	}
+	return exitOK
+}
+
+// checkFieldFlags reports flags that the field of f does not support, and
+// whether --encode was given.
+func checkFieldFlags(fs *flag.FlagSet, f *reconstructFlags, vault bool) (bool, error) {
+	encodeSet := false
+	switch f.field {
+	case "integer":
+	case share.FieldGF256:
+		var conflict string
+		fs.Visit(func(flag *flag.Flag) {
+			switch flag.Name {
+			case "mod", "coefficients", "correct-errors", "vote", "at", "algorithm", "cross-check",
+				"case", "dry-run", "expected", "use", "exclude", "k", "keep-going", "report":
+				conflict = flag.Name
+			case "encode":
+				encodeSet = true
+			}
+		})
+		if conflict != "" {
+			return false, usagef("--field gf256, vault keys and mnemonics are not supported together with --%s", conflict)
+		}
+		if f.format != "auto" && f.format != "json" && f.format != "mnemonic" && !vault {
+			return false, usagef("--field gf256 only reads JSON share files and mnemonics")
+		}
+	case share.FieldGF2m:
+		var conflict string
+		fs.Visit(func(flag *flag.Flag) {
+			switch flag.Name {
+			case "mod", "coefficients", "correct-errors", "algorithm", "cross-check", "derivative", "allow-rational", "show-weights", "case":
+				conflict = flag.Name
+			}
+		})
+		if conflict != "" {
+			return false, usagef("--field gf2m is not supported together with --%s", conflict)
+		}
+	default:
+		if share.LookupField(f.field) == nil {
+			return false, usagef("unknown field: %s", f.field)
+		}
+		if f.mod != "" {
+			return false, usagef("--field %s already gives the modulus; drop --mod", f.field)
+		}
+	}
+	return encodeSet, nil
+}
 
-		base, err := strconv.Atoi(root.Base)
+// fieldArithmetic returns the reduction polynomial --poly gives and the
+// modulus --mod or a named --field gives, nil where there is none.
+func fieldArithmetic(f *reconstructFlags) (poly, modulus *big.Int, err error) {
+	if f.poly != "" {
+		if f.field != share.FieldGF2m {
+			return nil, nil, usagef("--poly requires --field gf2m")
+		}
+		if poly, err = share.ParsePoly(f.poly); err != nil {
+			return nil, nil, classify(exitUsage, err)
+		}
+	}
+	if preset := share.LookupField(f.field); preset != nil {
+		modulus = preset.Modulus
+	}
+	if f.mod != "" && f.mod != "auto" {
+		m, err := parseModulus(f.mod)
 		if err != nil {
-			return nil, fmt.Errorf("invalid base for x=%d: %s", x, root.Base)
+			return nil, nil, classify(exitUsage, err)
+		}
+		if err := checkPrime(m, f.allowComposite); err != nil {
+			return nil, nil, err
+		}
+		modulus = m
+	}
+	return poly, modulus, nil
+}
+
+// render formats a reconstructed value as --encode, --as-text and
+// --byte-length ask.
+func (r *reconstructRun) render(v *big.Int) (string, error) {
+	f := r.flags
+	if f.encode == "bip39" && !f.asText {
+		return secret.EncodeBIP39(v, f.byteLength)
+	}
+	if !f.asText {
+		return secret.Encode(v, f.encode, f.prefix)
+	}
+	data, err := secret.Bytes(v, f.byteLength)
+	if err != nil {
+		return "", err
+	}
+	if !utf8.Valid(data) {
+		logger.Warn("secret is not valid UTF-8, printing hex instead")
+		return hex.EncodeToString(data), nil
+	}
+	return string(data), nil
+}
+
+// writeAudit writes the audit record to the --report file.
+func (r *reconstructRun) writeAudit() error {
+	if err := writeReport(r.flags.report, r.flags.force, r.audit); err != nil {
+		return classify(exitIO, fmt.Errorf("failed to write %s: %w", r.flags.report, err))
+	}
+	fmt.Fprintf(r.info, "Wrote report to %s\n", r.flags.report)
+	return nil
+}
+
+// fail removes a partly written --out file, records err in the audit
+// record and prints it, returning its exit code.
+func (r *reconstructRun) fail(err error) int {
+	if r.outFile != nil {
+		r.outFile.Close()
+		os.Remove(r.outFile.Name())
+	}
+	var usage usageError
+	if errors.As(err, &usage) {
+		return exitCode(err)
+	}
+	if r.audit != nil {
+		if errors.Is(err, errExpectedMismatch) {
+			// The message holds the secret.
+			r.audit.Fail(errExpectedMismatch)
+		} else {
+			r.audit.Fail(err)
+		}
+		if reportErr := r.writeAudit(); reportErr != nil {
+			printError(r.stderr, reportErr)
+		}
+	}
+	if r.outputJSON {
+		result := errorResult{Error: err.Error()}
+		var conflictErr *share.ConflictError
+		if errors.As(err, &conflictErr) {
+			result.Conflicts = conflictErr.Conflicts
+		}
+		printJSON(r.errorOut, result)
+	} else {
+		printError(r.errorOut, err)
+	}
+	return exitCode(err)
+}
+
+// finish writes the audit record and moves the --out file into place once
+// the result has been written to it.
+func (r *reconstructRun) finish() int {
+	if r.audit != nil {
+		if err := r.writeAudit(); err != nil {
+			r.audit = nil
+			return r.fail(err)
+		}
+	}
+	if r.outFile == nil {
+		return 0
+	}
+	out := r.flags.out
+	if err := r.outFile.Close(); err != nil {
+		return r.fail(classify(exitIO, fmt.Errorf("failed to write %s: %w", out, err)))
+	}
+	if r.outFile.Name() != out {
+		if err := os.Rename(r.outFile.Name(), out); err != nil {
+			return r.fail(classify(exitIO, fmt.Errorf("failed to write %s: %w", out, err)))
+		}
+	}
+	fmt.Fprintf(r.info, "Wrote result to %s\n", out)
+	return 0
+}
+
+// finishFailing is finish for a run over several share sets, which exits
+// with exitFailure if some of them failed.
+func (r *reconstructRun) finishFailing(failed bool) int {
+	if status := r.finish(); status != 0 || !failed {
+		return status
+	}
+	return exitFailure
+}
+
+// gf256 reconstructs the bytes of shares over GF(256), vault unseal keys
+// or mnemonics.
+func (r *reconstructRun) gf256(filePaths []string, parseOpts []share.ParseOption, vault, encodeSet bool) int {
+	f := r.flags
+	data, result, err := r.inv.reconstructGF256(r.info, filePaths, f.format, f.unsealKeys, parseOpts, f.noVerify)
+	if err != nil {
+		return r.fail(err)
+	}
+	if vault && !encodeSet && !f.asText {
+		result.Secret = hex.EncodeToString(data)
+		result.SecretBase64 = base64.StdEncoding.EncodeToString(data)
+		switch {
+		case r.outputJSON:
+			printJSON(r.out, result)
+		case r.quiet:
+			fmt.Fprintln(r.out, result.Secret)
+		default:
+			fmt.Fprintf(r.out, "\n The recovered master key is:\n  hex:    %s\n  base64: %s\n", result.Secret, result.SecretBase64)
 		}
+		return r.finish()
+	}
 
-		y := new(big.Int)
-		_, success := y.SetString(root.Value, base)
-		if !success {
-			return nil, fmt.Errorf("failed to decode y value for x=%d", x)
+	encoding := f.encode
+	if !encodeSet {
+		encoding = "hex"
+	}
+	text, err := secret.EncodeBytes(data, encoding, f.prefix)
+	if f.asText {
+		text = string(data)
+		if !utf8.Valid(data) {
+			logger.Warn("secret is not valid UTF-8, printing hex instead")
+			text = hex.EncodeToString(data)
 		}
+	}
+	if err != nil {
+		return r.fail(err)
+	}
 
-		points = append(points, Point{X: big.NewInt(x), Y: y})
+	switch {
+	case r.outputJSON:
+		result.Secret = text
+		printJSON(r.out, result)
+	case r.quiet:
+		fmt.Fprintln(r.out, text)
+	default:
+		fmt.Fprintf(r.out, "\n The calculated secret (c) is: %s\n", text)
 	}
+	return r.finish()
+}
 
-	if len(points) < k {
-		return nil, fmt.Errorf("not enough points in file: found %d, need %d", len(points), k)
+// batch reconstructs every share document of the JSON Lines input at path.
+func (r *reconstructRun) batch(path string, parseOpts []share.ParseOption, opts []lagrange.Option, expect expectation, modAuto bool) int {
+	f := r.flags
+	input, _, err := r.inv.openInput(path)
+	if err != nil {
+		return r.fail(err)
+	}
+	defer input.Close()
+	if r.audit != nil {
+		return r.fail(usagef("--report records a single reconstruction, not a batch"))
+	}
+	if modAuto || f.field == share.FieldGF2m {
+		return r.fail(usagef("--mod auto and --field gf2m read the field of a single share set, not a batch"))
+	}
+	failed, err := runBatch(r.out, input, parseOpts, r.outputJSON, f.keepGoing, f.noVerify, f.noVSS, opts, expect, r.render)
+	if err != nil {
+		return r.fail(err)
 	}
+	return r.finishFailing(failed)
+}
 
-	return points, nil
+// readShares reads the shares of the run from the terminal, the --watch
+// directory or filePaths, returning the inputs they came from.
+func (r *reconstructRun) readShares(ctx context.Context, filePaths []string, interactive bool, parseOpts []share.ParseOption) (*share.Shares, []share.Case, []string, error) {
+	f := r.flags
+	switch {
+	case interactive:
+		shares, err := readInteractive(r.inv.stdin, r.stderr, parseOpts)
+		return shares, nil, []string{"stdin"}, err
+	case f.watch != "":
+		shares, paths, err := r.inv.watchShares(ctx, r.info, f.watch, f.watchInterval, parseOpts, f.noVSS)
+		return shares, nil, paths, err
+	}
+	shares, cases, err := r.inv.loadShares(r.info, filePaths, f.format, f.k, f.caseName, f.strict, parseOpts)
+	return shares, cases, filePaths, err
 }
 
-func findSecretC(points []Point) (*big.Int, error) {
-	if len(points) == 0 {
-		return nil, errors.New("cannot interpolate with zero points")
+// reconstructShares checks shares, interpolates the secret, or the values
+// --at asks for, and prints it.
+func (r *reconstructRun) reconstructShares(shares *share.Shares, filePaths []string, x0, modulus *big.Int, binary *gf2m.Field, opts []lagrange.Option, expect expectation) int {
+	f := r.flags
+	if f.use != "" || f.exclude != "" {
+		use, err := parseXList(f.use)
+		if err != nil {
+			return r.fail(usagef("invalid --use: %w", err))
+		}
+		exclude, err := parseXList(f.exclude)
+		if err != nil {
+			return r.fail(usagef("invalid --exclude: %w", err))
+		}
+		if shares, err = shares.Filter(use, exclude); err != nil {
+			return r.fail(err)
+		}
+	}
+
+	vssVerified := 0
+	if shares.Commitments != nil && !f.noVSS {
+		if err := shares.VerifyCommitments(); err != nil {
+			return r.fail(err)
+		}
+		vssVerified = len(shares.Points)
+		fmt.Fprintf(r.info, "Verified %d shares against the %s commitments\n", vssVerified, commitmentName(shares.Commitments.Scheme))
 	}
 
-	secretC := big.NewInt(0)
+	allPoints, k := shares.Points, shares.K
+	if r.audit != nil {
+		r.audit.SetShares(shares)
+		r.audit.Verification.Commitments = vssVerified
+	}
+	if coverage := shares.Coverage(); coverage != nil {
+		fmt.Fprintf(r.info, "Participants: %s\n", coverage)
+	}
+	points, extra, err := shares.Select()
+	if err != nil {
+		return r.fail(err)
+	}
 
-	numerator := new(big.Int)
-	denominator := new(big.Int)
-	termNumerator := new(big.Int)
-	termValue := new(big.Int)
-	negXi := new(big.Int)
-	denTerm := new(big.Int)
+	xs := make([]*big.Int, len(points))
+	for i, point := range points {
+		xs[i] = point.X
+	}
+	result := reconstructResult{
+		PointsParsed: len(allPoints),
+		XUsed:        intStrings(xs),
+		K:            k,
+		N:            shares.N,
+		Inputs:       filePaths,
+		VSSVerified:  vssVerified,
+	}
+	if r.audit != nil {
+		r.audit.XUsed = result.XUsed
+		r.audit.Verification.Unused = len(extra)
+	}
 
-	for j, pointJ := range points {
-		numerator.SetInt64(1)
-		denominator.SetInt64(1)
+	if f.use != "" || f.exclude != "" {
+		fmt.Fprintf(r.info, "Using shares x=%s\n", joinInts(xs))
+	}
+	for _, point := range points {
+		result.ShareSources = append(result.ShareSources, shareSource{X: point.X.String(), Source: point.Source})
+		if f.verbose || len(filePaths) > 1 {
+			fmt.Fprintf(r.info, "Using share %s from %s\n", point.Name(), point.Source)
+		}
+		logger.Debug("using share", "share_x", point.X.String(), "label", point.Label, "file", point.Source)
+	}
 
-		for i, pointI := range points {
-			if i == j {
-				continue
+	var secretC *big.Int
+	var values, coefficients []*big.Int
+	interpolationStart := time.Now()
+	switch {
+	case f.correctErrors:
+		if secretC, err = r.correctErrors(allPoints, k, modulus, &result); err != nil {
+			return r.fail(err)
+		}
+	case f.vote:
+		if secretC, err = r.vote(allPoints, k, opts, &result); err != nil {
+			return r.fail(err)
+		}
+	default:
+		if err := r.checkInterpolationFlags(modulus); err != nil {
+			return r.fail(err)
+		}
+		ats := []*big.Int{x0}
+		if len(f.at) > 0 {
+			ats = f.at
+		}
+		if f.verbose && binary == nil {
+			traceInterpolation(r.info, points, ats, f.full, opts)
+		}
+		if f.showWeights {
+			if err := showWeights(r.info, points, ats, modulus, f.full, opts); err != nil {
+				return r.fail(err)
+			}
+		}
+		values, coefficients, err = r.interpolate(points, ats, opts, &result)
+		if errors.Is(err, lagrange.ErrNonIntegerSecret) && f.allowRational {
+			return r.printRational(points, ats, shares.Expected, opts, &result)
+		}
+		if err != nil {
+			return r.fail(err)
+		}
+		secretC = values[0]
+
+		if !f.noVerify && len(extra) > 0 {
+			mismatches, err := lagrange.Verify(points, extra, opts...)
+			if err != nil {
+				return r.fail(err)
+			}
+			if len(mismatches) > 0 {
+				logger.Error("shares inconsistent with the reconstructed polynomial", "share_x", joinInts(mismatches))
+				return r.fail(&reconstruct.InconsistentError{X: mismatches})
 			}
-			numerator.Mul(numerator, negXi.Neg(pointI.X))
-			denominator.Mul(denominator, denTerm.Sub(pointJ.X, pointI.X))
+			fmt.Fprintf(r.info, "Verified %d additional shares against the reconstructed polynomial\n", len(extra))
+			logger.Info("verified shares", "verified", len(extra))
+			result.Verified = len(extra)
 		}
+	}
 
-		if denominator.Cmp(big.NewInt(0)) == 0 {
-			return nil, fmt.Errorf("interpolation failed: duplicate x-value detected leading to division by zero")
+	if r.audit != nil {
+		r.audit.Timing.InterpolationMS = durationMillis(interpolationStart)
+		r.audit.Verification.Verified = result.Verified
+	}
+	// The expected value a document records is that of its secret, not
+	// of the derivative.
+	documentExpected := shares.Expected
+	if f.derivative {
+		documentExpected = ""
+	}
+	if err := expect.check(secretC, documentExpected); err != nil {
+		return r.fail(err)
+	}
+	if shares.SecretSHA256 != "" && !f.derivative && x0.Sign() == 0 {
+		if err := shares.VerifySecret(secretC); err != nil {
+			return r.fail(err)
+		}
+		fmt.Fprintln(r.info, "Verified the secret against the SHA-256 recorded at split time")
+		result.HashVerified = true
+	}
+	if r.audit != nil {
+		r.audit.SecretSHA256 = report.SecretHash(secretC)
+	}
+	var blinding *big.Int
+	if f.blinding {
+		if blinding, err = reconstructBlinding(points, opts); err != nil {
+			return r.fail(err)
 		}
+		result.Blinding = blinding.String()
+	}
+	return r.printResult(secretC, blinding, x0, values, coefficients, &result)
+}
 
-		termNumerator.Mul(pointJ.Y, numerator)
-		termValue.Div(termNumerator, denominator)
+// correctErrors reconstructs the secret by Berlekamp-Welch decoding,
+// correcting up to (n-k)/2 corrupted shares.
+func (r *reconstructRun) correctErrors(points []share.Point, k int, modulus *big.Int, result *reconstructResult) (*big.Int, error) {
+	if modulus == nil {
+		return nil, usagef("--correct-errors requires --mod")
+	}
+	corrected, bad, err := lagrange.CorrectErrors(points, k, modulus)
+	if err != nil {
+		return nil, err
+	}
+	if len(bad) > 0 {
+		fmt.Fprintf(r.info, "Corrected corrupted shares at x=%s\n", joinInts(bad))
+	} else {
+		fmt.Fprintln(r.info, "No corrupted shares detected")
+	}
+	result.Corrected = intStrings(bad)
+	return corrected, nil
+}
 
-		secretC.Add(secretC, termValue)
+// vote reconstructs the secret most k-subsets of the shares agree on.
+func (r *reconstructRun) vote(points []share.Point, k int, opts []lagrange.Option, result *reconstructResult) (*big.Int, error) {
+	vote, err := lagrange.Vote(points, k, opts...)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Fprintf(r.info, "Winning secret received %d of %d subset votes\n", vote.Votes, vote.Subsets)
+	if len(vote.Suspects) > 0 {
+		fmt.Fprintf(r.info, "Suspect shares: x=%s\n", joinInts(vote.Suspects))
+	} else {
+		fmt.Fprintln(r.info, "Suspect shares: none")
 	}
+	result.Votes = vote.Votes
+	result.Suspects = intStrings(vote.Suspects)
+	return vote.Secret, nil
+}
 
-	return secretC, nil
+// checkInterpolationFlags reports flags of plain interpolation that do
+// not go together.
+func (r *reconstructRun) checkInterpolationFlags(modulus *big.Int) error {
+	f := r.flags
+	switch {
+	case f.coefficients && modulus != nil:
+		return usagef("--coefficients is not supported together with --mod")
+	case f.derivative && (f.coefficients || f.crossCheck || f.showWeights):
+		return usagef("--derivative is not supported together with --coefficients, --cross-check or --show-weights")
+	case f.allowRational && (modulus != nil || f.derivative || f.coefficients || f.encode != "dec" || f.asText || r.audit != nil):
+		return usagef("--allow-rational is not supported together with --mod, --derivative, --coefficients, --encode, --as-text or --report")
+	}
+	return nil
 }
 
-func main() {
-	if len(os.Args) < 2 {
-		fmt.Println("Usage: go run main.go <path_to_json_file>")
-		os.Exit(1)
+// interpolate evaluates the polynomial through points, or its derivative,
+// at every x of ats with the --algorithm, cross-checking it with another
+// one if asked. A result that is not an integer is an error wrapping
+// lagrange.ErrNonIntegerSecret.
+func (r *reconstructRun) interpolate(points []share.Point, ats []*big.Int, opts []lagrange.Option, result *reconstructResult) ([]*big.Int, []*big.Int, error) {
+	f := r.flags
+	start := time.Now()
+	if f.derivative {
+		values := make([]*big.Int, len(ats))
+		for i, at := range ats {
+			var err error
+			if values[i], err = lagrange.EvaluateDerivativeAt(points, at, opts...); err != nil {
+				return nil, nil, err
+			}
+		}
+		result.Derivative = true
+		return values, nil, nil
+	}
+
+	values, coefficients, err := evaluate(f.algorithm, points, ats, f.coefficients, opts)
+	if err != nil {
+		if errors.Is(err, lagrange.ErrNonIntegerSecret) && !f.allowRational {
+			return nil, nil, fmt.Errorf("%w; the shares are inconsistent, since shares of one integer polynomial always give an integer: rerun with --vote, or with --mod and --correct-errors, to find the bad shares, or with --allow-rational to print the fraction", err)
+		}
+		return nil, nil, err
+	}
+	logger.Info("interpolated", "algorithm", f.algorithm, "k", len(points), "duration_ms", durationMillis(start))
+	if f.crossCheck {
+		other, otherOpts := "newton", opts
+		switch f.algorithm {
+		case "newton":
+			other = "lagrange"
+		case "fast":
+			other, otherOpts = "lagrange", append(opts[:len(opts):len(opts)], lagrange.WithFast(false))
+		}
+		otherValues, otherCoefficients, err := evaluate(other, points, ats, f.coefficients, otherOpts)
+		if err != nil {
+			return nil, nil, fmt.Errorf("cross-check with %s failed: %w", other, err)
+		}
+		if err := crossCheck(f.algorithm, other, ats, values, otherValues, coefficients, otherCoefficients); err != nil {
+			return nil, nil, err
+		}
+		fmt.Fprintf(r.info, "Cross-checked the result with %s interpolation\n", other)
 	}
-	filePath := os.Args[1]
+	return values, coefficients, nil
+}
+
+// printRational prints the fractional values of exact interpolation that
+// --allow-rational accepts.
+func (r *reconstructRun) printRational(points []share.Point, ats []*big.Int, documentExpected string, opts []lagrange.Option, result *reconstructResult) int {
+	f := r.flags
+	logger.Warn("the shares do not lie on one integer polynomial; printing the fractional result")
+	result.Rational = true
+	fractions := make([]*big.Rat, len(ats))
+	for i, at := range ats {
+		var err error
+		if fractions[i], err = evaluateRational(f.algorithm, points, at, opts); err != nil {
+			return r.fail(err)
+		}
+	}
+	if want := cmp.Or(documentExpected, f.expected); want != "" {
+		return r.fail(fmt.Errorf("%w: got %s, want %s", errExpectedMismatch, fractions[0].RatString(), want))
+	}
+	for i, at := range ats {
+		text := fractions[i].RatString()
+		switch {
+		case r.outputJSON:
+		case r.quiet:
+			fmt.Fprintln(r.out, text)
+		case i == 0 && len(f.at) == 0:
+			fmt.Fprintf(r.out, "\n The calculated secret (c) is: %s\n", text)
+		case i == 0:
+			fmt.Fprintf(r.out, "\n The value f(%s) is: %s\n", at.String(), text)
+		default:
+			fmt.Fprintf(r.out, " The value f(%s) is: %s\n", at.String(), text)
+		}
+		if i == 0 {
+			result.Secret = text
+		} else {
+			result.Values = append(result.Values, evaluation{At: at.String(), Value: text})
+		}
+	}
+	if len(f.at) > 0 {
+		result.At = ats[0].String()
+	}
+	if r.outputJSON {
+		printJSON(r.out, result)
+	}
+	return r.finish()
+}
 
-	points, err := parseInputFile(filePath)
+// printResult prints the secret, or the value at the first --at, then the
+// blinding value, the values at the other --at and the coefficients.
+func (r *reconstructRun) printResult(secretC, blinding, x0 *big.Int, values, coefficients []*big.Int, result *reconstructResult) int {
+	f := r.flags
+	text, err := r.render(secretC)
 	if err != nil {
-		fmt.Println("Error:", err)
-		os.Exit(1)
+		return r.fail(err)
+	}
+	switch {
+	case r.outputJSON:
+	case r.quiet:
+		fmt.Fprintln(r.out, text)
+	case f.derivative:
+		fmt.Fprintf(r.out, "\n The value f'(%s) is: %s\n", x0.String(), text)
+	case len(f.at) > 0:
+		fmt.Fprintf(r.out, "\n The value f(%s) is: %s\n", x0.String(), text)
+	default:
+		fmt.Fprintf(r.out, "\n The calculated secret (c) is: %s\n", text)
+	}
+	switch {
+	case blinding == nil || r.outputJSON:
+	case r.quiet:
+		fmt.Fprintln(r.out, blinding.String())
+	default:
+		fmt.Fprintf(r.out, " The blinding value is: %s\n", blinding.String())
+	}
+	result.Secret = text
+	if len(f.at) > 0 {
+		result.At = x0.String()
+	}
+	for i := 1; i < len(values); i++ {
+		text, err := r.render(values[i])
+		if err != nil {
+			return r.fail(err)
+		}
+		switch {
+		case r.outputJSON:
+		case r.quiet:
+			fmt.Fprintln(r.out, text)
+		case f.derivative:
+			fmt.Fprintf(r.out, " The value f'(%s) is: %s\n", f.at[i].String(), text)
+		default:
+			fmt.Fprintf(r.out, " The value f(%s) is: %s\n", f.at[i].String(), text)
+		}
+		result.Values = append(result.Values, evaluation{At: f.at[i].String(), Value: text})
+	}
+
+	for d := len(coefficients) - 1; d >= 0; d-- {
+		switch {
+		case r.outputJSON:
+		case r.quiet:
+			fmt.Fprintln(r.out, coefficients[d].String())
+		default:
+			if d == len(coefficients)-1 {
+				fmt.Fprintf(r.out, "\n The polynomial coefficients (highest degree first) are:\n")
+			}
+			fmt.Fprintf(r.out, "  a_%d = %s\n", d, coefficients[d].String())
+		}
+		result.Coefficients = append(result.Coefficients, coefficients[d].String())
 	}
 
-	fmt.Printf("Successfully parsed %d points from %s\n", len(points), filePath)
+	if r.outputJSON {
+		printJSON(r.out, result)
+	}
+	return r.finish()
+}
 
-	secretC, err := findSecretC(points)
+// writeReport writes r to path, created with mode 0600 unless force lets
+// it replace an existing file.
+func writeReport(path string, force bool, r *report.Report) error {
+	file, err := createPrivate(path, force)
 	if err != nil {
-		fmt.Println("Error:", err)
-		os.Exit(1)
+		return err
 	}
+	if err := r.Write(file); err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	if file.Name() != path {
+		return os.Rename(file.Name(), path)
+	}
+	return nil
+}
 
-	fmt.Printf("\n The calculated secret (c) is: %s\n", secretC.String())
+// toolVersion returns the module version the binary was built from, or
+// "(devel)" for a build from a working tree.
+func toolVersion() string {
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" {
+		return info.Main.Version
+	}
+	return "(devel)"
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
 }
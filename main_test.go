@@ -0,0 +1,1345 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/OmSingh2003/CATALOG-ASSIGNMENT/pkg/lagrange"
+	"github.com/OmSingh2003/CATALOG-ASSIGNMENT/pkg/reconstruct"
+	"github.com/OmSingh2003/CATALOG-ASSIGNMENT/pkg/share"
+	"github.com/OmSingh2003/CATALOG-ASSIGNMENT/pkg/terminal"
+)
+
+func TestWriteSplitOutputPermissions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "shares.json")
+	if err := newInvocation(strings.NewReader("")).writeSplitOutput(nil, path, []byte("first"), false, false); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mode := info.Mode().Perm(); mode != 0o600 {
+		t.Errorf("mode = %o, want 600", mode)
+	}
+}
+
+func TestWriteSplitOutputOverwrite(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		force bool
+		want  string
+	}{
+		{"refused", false, "old"},
+		{"forced", true, "new"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, "shares.json")
+			if err := os.WriteFile(path, []byte("old"), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			err := newInvocation(strings.NewReader("")).writeSplitOutput(nil, path, []byte("new"), false, tc.force)
+			if tc.force && err != nil {
+				t.Fatal(err)
+			}
+			if !tc.force {
+				if code := exitCode(err); err == nil || code != exitIO {
+					t.Fatalf("err = %v (exit %d), want an I/O error", err, code)
+				}
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != tc.want {
+				t.Errorf("file holds %q, want %q", data, tc.want)
+			}
+			if tc.force {
+				info, err := os.Stat(path)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if mode := info.Mode().Perm(); mode != 0o600 {
+					t.Errorf("mode = %o, want 600", mode)
+				}
+			}
+			entries, err := os.ReadDir(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(entries) != 1 {
+				t.Errorf("%d files left in the directory, want 1", len(entries))
+			}
+		})
+	}
+}
+
+func TestWriteSplitOutputStdout(t *testing.T) {
+	var stdout bytes.Buffer
+	if err := newInvocation(strings.NewReader("")).writeSplitOutput(&stdout, "", []byte("shares"), false, false); err != nil {
+		t.Fatal(err)
+	}
+	if stdout.String() != "shares" {
+		t.Errorf("stdout = %q, want %q", stdout.String(), "shares")
+	}
+}
+
+func TestRunSplitOutRefusesOverwrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "shares.json")
+	args := []string{"split", "--secret", "42", "--n", "3", "--k", "2", "--out", path}
+	var stdout, stderr bytes.Buffer
+	if code := run(args, strings.NewReader(""), &stdout, &stderr); code != 0 {
+		t.Fatalf("first split exited %d: %s%s", code, stdout.String(), stderr.String())
+	}
+	first, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if code := run(args, strings.NewReader(""), &stdout, &stderr); code != exitIO {
+		t.Errorf("second split exited %d, want %d", code, exitIO)
+	}
+	if again, _ := os.ReadFile(path); !bytes.Equal(again, first) {
+		t.Error("second split without --force changed the file")
+	}
+	if code := run(append(args, "--force"), strings.NewReader(""), &stdout, &stderr); code != 0 {
+		t.Errorf("split --force exited %d: %s", code, stdout.String())
+	}
+}
+
+// captureLog points the package logger at the returned buffer until the
+// test ends.
+func captureLog(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	saved := logger
+	t.Cleanup(func() { logger = saved })
+	var log bytes.Buffer
+	logger = slog.New(slog.NewTextHandler(&log, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	return &log
+}
+
+func TestWatchSharesRedactsParseErrors(t *testing.T) {
+	dir := t.TempDir()
+	bad := `{"keys": {"n": 2, "k": 2}, "1": {"base": "10", "value": "9876zz54321"}}`
+	if err := os.WriteFile(filepath.Join(dir, "bad.json"), []byte(bad), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	log := captureLog(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if _, _, err := newInvocation(strings.NewReader("")).watchShares(ctx, io.Discard, dir, 10*time.Millisecond, nil, false); err == nil {
+		t.Fatal("watchShares returned no error for a directory without enough shares")
+	}
+	if !strings.Contains(log.String(), "skipping file") {
+		t.Fatalf("log does not report the skipped file:\n%s", log.String())
+	}
+	if strings.Contains(log.String(), "9876zz54321") {
+		t.Errorf("log holds the share value:\n%s", log.String())
+	}
+}
+
+func TestWatchSharesRejectsModulusMismatch(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"a.json": `{"keys": {"n": 3, "k": 2, "modulus": "101"}, "1": {"base": "10", "value": "5"}}`,
+		"b.json": `{"keys": {"n": 3, "k": 2, "modulus": "103"}, "2": {"base": "10", "value": "7"}}`,
+	}
+	for name, document := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(document), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	log := captureLog(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if merged, _, err := newInvocation(strings.NewReader("")).watchShares(ctx, io.Discard, dir, 10*time.Millisecond, nil, true); err == nil {
+		t.Fatalf("watchShares merged shares over different moduli: %+v", merged)
+	}
+	if !strings.Contains(log.String(), "modulus mismatch") || !strings.Contains(log.String(), "b.json") {
+		t.Errorf("log does not report the modulus mismatch of b.json:\n%s", log.String())
+	}
+}
+
+// TestGolden checks the reconstruct output the output*.txt files record:
+// the stdout of each run in turn, with its exit code.
+func TestGolden(t *testing.T) {
+	type golden struct {
+		args []string
+		code int
+	}
+	for _, tc := range []struct {
+		file string
+		runs []golden
+	}{
+		{"output1.txt", []golden{{[]string{"testcase1.json"}, exitOK}}},
+		{"output2.txt", []golden{{[]string{"testcase2.json"}, exitInconsistent}}},
+		{"output1_corrupted.txt", []golden{
+			{[]string{"testcase1_corrupted.json"}, exitInconsistent},
+			{[]string{"--allow-rational", "testcase1_corrupted.json"}, exitOK},
+		}},
+		{"output1_derivative.txt", []golden{{[]string{"--derivative", "--at", "0", "--at", "2", "--at", "5", "testcase1.json"}, exitOK}}},
+		{"output1_tampered.txt", []golden{{[]string{"testcase1_tampered.json"}, exitMismatch}}},
+		{"output1_weights.txt", []golden{{[]string{"--show-weights", "--use", "1,3,6", "testcase1.json"}, exitOK}}},
+	} {
+		t.Run(tc.file, func(t *testing.T) {
+			want, err := os.ReadFile(tc.file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var stdout bytes.Buffer
+			for _, r := range tc.runs {
+				var stderr bytes.Buffer
+				if code := run(r.args, strings.NewReader(""), &stdout, &stderr); code != r.code {
+					t.Errorf("%q exited %d, want %d: %s", r.args, code, r.code, stderr.String())
+				}
+			}
+			if stdout.String() != string(want) {
+				t.Errorf("stdout differs from %s:\n%s\nwant:\n%s", tc.file, stdout.String(), want)
+			}
+		})
+	}
+}
+
+func TestDebugLogRedactsShares(t *testing.T) {
+	for _, path := range []string{"testcase_field_secp256k1.json", "testcase_vss.json", "testcase_pedersen.json"} {
+		t.Run(path, func(t *testing.T) {
+			s, err := share.ParseShares(openFile(t, path))
+			if err != nil {
+				t.Fatal(err)
+			}
+			secret, err := lagrange.Interpolate(s.Points[:s.K], lagrange.WithModulus(s.Modulus))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var stdout, stderr bytes.Buffer
+			args := []string{"reconstruct", "--log-level", "debug", "--verbose", path}
+			if code := run(args, strings.NewReader(""), &stdout, &stderr); code != exitOK {
+				t.Fatalf("%q exited %d: %s", args, code, stderr.String())
+			}
+			if !strings.Contains(stderr.String(), "level=DEBUG") {
+				t.Fatalf("no debug records logged:\n%s", stderr.String())
+			}
+			if !strings.Contains(stdout.String(), secret.String()) {
+				t.Fatalf("stdout does not hold the secret %s:\n%s", secret, stdout.String())
+			}
+			secrets := map[string]string{"secret": secret.String()}
+			for _, p := range s.Points {
+				secrets["y of x="+p.X.String()] = p.Y.String()
+				if p.Blinding != nil {
+					secrets["blinding of x="+p.X.String()] = p.Blinding.String()
+				}
+			}
+			for name, value := range secrets {
+				if strings.Contains(stderr.String(), value) {
+					t.Errorf("log holds the %s:\n%s", name, stderr.String())
+				}
+			}
+		})
+	}
+}
+
+func openFile(t *testing.T, path string) *os.File {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { f.Close() })
+	return f
+}
+
+func TestRunDoesNotCarryHeadersOver(t *testing.T) {
+	document, err := os.ReadFile("testcase1.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.Header.Get("X-Token"))
+		w.Write(document)
+	}))
+	defer srv.Close()
+
+	for _, args := range [][]string{
+		{"reconstruct", "--header", "X-Token: first", srv.URL + "/shares.json"},
+		{"reconstruct", srv.URL + "/shares.json"},
+	} {
+		var stdout, stderr bytes.Buffer
+		if code := run(args, strings.NewReader(""), &stdout, &stderr); code != 0 {
+			t.Fatalf("%v exited %d: %s%s", args, code, stdout.String(), stderr.String())
+		}
+	}
+	if want := []string{"first", ""}; len(got) != 2 || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("X-Token headers sent = %q, want %q", got, want)
+	}
+}
+
+func TestReadPassphraseNoTerminal(t *testing.T) {
+	inv := newInvocation(strings.NewReader(""))
+	inv.prompt = func(string) ([]byte, error) { return nil, terminal.ErrNoTerminal }
+	_, err := inv.readPassphrase("Passphrase: ", false)
+	if err == nil {
+		t.Fatal("readPassphrase returned no error without a terminal")
+	}
+	flags := regexp.MustCompile(`--[a-z][a-z-]*`).FindAllString(err.Error(), -1)
+	if len(flags) == 0 {
+		t.Fatalf("error %q names no flag to use instead", err)
+	}
+	for _, command := range []string{"split", "reconstruct", "verify"} {
+		var usage bytes.Buffer
+		run([]string{"help", command}, strings.NewReader(""), &usage, &usage)
+		for _, flag := range flags {
+			if !strings.Contains(usage.String(), "\n  -"+strings.TrimPrefix(flag, "--")+" ") {
+				t.Errorf("error %q names %s, which %s does not have", err, flag, command)
+			}
+		}
+	}
+}
+
+func TestReadPassphrase(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		typed   []string
+		confirm bool
+		want    string
+		wantErr string
+	}{
+		{name: "typed", typed: []string{"secret"}, want: "secret"},
+		{name: "confirmed", typed: []string{"secret", "secret"}, confirm: true, want: "secret"},
+		{name: "mismatch", typed: []string{"secret", "other"}, confirm: true, wantErr: "passphrases do not match"},
+		{name: "empty", typed: []string{""}, wantErr: "empty passphrase"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			inv := newInvocation(strings.NewReader(""))
+			var prompts []string
+			inv.prompt = func(prompt string) ([]byte, error) {
+				prompts = append(prompts, prompt)
+				typed := tc.typed[0]
+				tc.typed = tc.typed[1:]
+				return []byte(typed), nil
+			}
+			got, err := inv.readPassphrase("Passphrase: ", tc.confirm)
+			if tc.wantErr != "" {
+				if err == nil || err.Error() != tc.wantErr {
+					t.Fatalf("err = %v, want %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tc.want {
+				t.Errorf("passphrase = %q, want %q", got, tc.want)
+			}
+			if tc.confirm && prompts[1] != "Repeat the passphrase: " {
+				t.Errorf("confirmation prompt = %q", prompts[1])
+			}
+			// The passphrase is asked for once per run.
+			if again, _ := inv.readPassphrase("Passphrase: ", tc.confirm); string(again) != tc.want || len(tc.typed) != 0 {
+				t.Errorf("second read = %q after %d prompts", again, len(prompts))
+			}
+		})
+	}
+}
+
+func TestReadPassphraseFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "passphrase")
+	if err := os.WriteFile(path, []byte("from file\r\nignored\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	inv := newInvocation(strings.NewReader(""))
+	inv.passphraseFile = path
+	inv.prompt = func(string) ([]byte, error) { t.Fatal("prompted despite --passphrase-file"); return nil, nil }
+	got, err := inv.readPassphrase("Passphrase: ", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "from file" {
+		t.Errorf("passphrase = %q, want %q", got, "from file")
+	}
+
+	inv = newInvocation(strings.NewReader(""))
+	inv.passphraseFile = filepath.Join(t.TempDir(), "missing")
+	if _, err := inv.readPassphrase("Passphrase: ", false); err == nil || !strings.HasPrefix(err.Error(), "failed to read passphrase file: ") {
+		t.Errorf("err = %v, want a failure to read the passphrase file", err)
+	}
+}
+
+func TestRunRouting(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		args       []string
+		code       int
+		stdout     string
+		stderr     string
+		emptyOut   bool
+		emptyError bool
+	}{
+		{name: "subcommand", args: []string{"reconstruct", "testcase1.json"}, stdout: "The calculated secret (c) is: 3"},
+		{name: "bare input", args: []string{"testcase1.json"}, stdout: "The calculated secret (c) is: 3"},
+		{name: "flag first", args: []string{"-q", "testcase1.json"}, stdout: "3"},
+		{name: "flag after input", args: []string{"reconstruct", "testcase1.json", "-q"}, stdout: "3"},
+		{name: "eval", args: []string{"eval", "--at", "2", "-q", "testcase1.json"}, stdout: "7"},
+		{name: "unknown command", args: []string{"recnostruct"}, code: exitUsage, stderr: `unknown command "recnostruct"`, emptyOut: true},
+		{name: "help", args: []string{"help"}, stdout: "Commands:", emptyError: true},
+		{name: "help command", args: []string{"help", "split"}, stdout: "Usage: go run main.go split", emptyError: true},
+		{name: "help unknown command", args: []string{"help", "nope"}, code: exitUsage, stderr: `unknown command "nope"`, emptyOut: true},
+		{name: "--help", args: []string{"--help"}, stderr: "Usage: go run main.go reconstruct", emptyOut: true},
+		{name: "command -h", args: []string{"split", "-h"}, stderr: "Usage: go run main.go split", emptyOut: true},
+		{name: "unknown flag", args: []string{"split", "--nope"}, code: exitUsage, stderr: "flag provided but not defined: -nope", emptyOut: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			code := run(tc.args, strings.NewReader(""), &stdout, &stderr)
+			if code != tc.code {
+				t.Errorf("exit %d, want %d\nstdout: %s\nstderr: %s", code, tc.code, stdout.String(), stderr.String())
+			}
+			if !strings.Contains(stdout.String(), tc.stdout) {
+				t.Errorf("stdout %q does not contain %q", stdout.String(), tc.stdout)
+			}
+			if !strings.Contains(stderr.String(), tc.stderr) {
+				t.Errorf("stderr %q does not contain %q", stderr.String(), tc.stderr)
+			}
+			if tc.emptyOut && stdout.Len() > 0 {
+				t.Errorf("stdout = %q, want nothing", stdout.String())
+			}
+			if tc.emptyError && stderr.Len() > 0 {
+				t.Errorf("stderr = %q, want nothing", stderr.String())
+			}
+		})
+	}
+}
+
+func TestHelpListsEveryCommand(t *testing.T) {
+	var stdout bytes.Buffer
+	run([]string{"help"}, strings.NewReader(""), &stdout, io.Discard)
+	for _, c := range commands() {
+		if !strings.Contains(stdout.String(), "  "+c.name+" ") {
+			t.Errorf("help does not list %s", c.name)
+		}
+		var usage bytes.Buffer
+		if code := run([]string{"help", c.name}, strings.NewReader(""), &usage, &usage); code != 0 {
+			t.Errorf("help %s exited %d: %s", c.name, code, usage.String())
+		}
+		if !strings.HasPrefix(usage.String(), "Usage: go run main.go "+c.name+" ") {
+			t.Errorf("help %s = %q, want its usage", c.name, usage.String())
+		}
+	}
+}
+
+func TestNoPositionalArguments(t *testing.T) {
+	for _, args := range [][]string{
+		{"split", "--text", "hello world", "--n", "3", "--k", "2"},
+		{"split", "--n", "3", "--k", "2", "42"},
+		{"serve", "localhost:8080"},
+		{"gen-testvectors", "out"},
+		{"selftest", "vectors"},
+	} {
+		var stdout, stderr bytes.Buffer
+		// The split would block reading its secret from stdin if it
+		// went ahead.
+		if code := run(args, blockingReader{t}, &stdout, &stderr); code != exitUsage {
+			t.Errorf("%q exited %d, want %d: %s", args, code, exitUsage, stderr.String())
+		}
+		if !strings.Contains(stderr.String(), "takes no arguments") {
+			t.Errorf("%q: stderr does not say the arguments are not taken:\n%s", args, stderr.String())
+		}
+		if stdout.Len() > 0 {
+			t.Errorf("%q wrote to stdout:\n%s", args, stdout.String())
+		}
+	}
+}
+
+func TestExitCode(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"nil", nil, exitOK},
+		{"help", usageError{flag.ErrHelp}, exitOK},
+		{"flag", usageError{errors.New("flag provided but not defined: -z")}, exitUsage},
+		{"usage", usagef("invalid --k %d: must be at least 1", 0), exitUsage},
+		{"io", classify(exitIO, errors.New("output file exists")), exitIO},
+		{"path", fmt.Errorf("read: %w", &os.PathError{Op: "open", Path: "x", Err: os.ErrNotExist}), exitIO},
+		{"invalid", classify(exitInvalid, errors.New("bad share")), exitInvalid},
+		{"insufficient", &share.InsufficientSharesError{Found: 1, Needed: 2}, exitInsufficient},
+		{"checksum", fmt.Errorf("share 1: %w", share.ErrChecksumMismatch), exitInconsistent},
+		{"commitment", share.ErrCommitmentMismatch, exitInconsistent},
+		{"inconsistent", &reconstruct.InconsistentError{X: []*big.Int{big.NewInt(3)}}, exitInconsistent},
+		{"conflict", &share.ConflictError{Conflicts: []share.Conflict{{Kind: share.ConflictThreshold}}}, exitInconsistent},
+		{"expected", errExpectedMismatch, exitMismatch},
+		{"secret hash", share.ErrSecretHashMismatch, exitMismatch},
+		{"timeout", fmt.Errorf("fetch: %w", context.DeadlineExceeded), exitTimeout},
+		{"other", errors.New("something failed"), exitFailure},
+	} {
+		if got := exitCode(tc.err); got != tc.want {
+			t.Errorf("%s: exitCode(%v) = %d, want %d", tc.name, tc.err, got, tc.want)
+		}
+	}
+}
+
+func TestSplitReshareValidation(t *testing.T) {
+	inconsistent := filepath.Join(t.TempDir(), "inconsistent.json")
+	document := `{"keys": {"n": 3, "k": 2}, "1": {"base": "10", "value": "3"}, "2": {"base": "10", "value": "5"}, "3": {"base": "10", "value": "8"}}`
+	if err := os.WriteFile(inconsistent, []byte(document), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		args []string
+		want int
+	}{
+		{[]string{"split", "--secret", "42", "--n", "3", "--k", "0"}, exitUsage},
+		{[]string{"split", "--secret", "42", "--n", "1", "--k", "2"}, exitUsage},
+		{[]string{"split", "--secret", "0xzz", "--n", "3", "--k", "2"}, exitUsage},
+		{[]string{"split", "--secret", "42", "--n", "3", "--k", "2", "--base", "10,16"}, exitUsage},
+		{[]string{"split", "--secret", "42", "--n", "3", "--k", "2", "--mod", "23"}, exitUsage},
+		{[]string{"split", "--field", "gf256", "--secret", "0x42", "--n", "300", "--k", "2"}, exitUsage},
+		{[]string{"reshare", "--n", "3", "--k", "4", inconsistent}, exitUsage},
+		{[]string{"reshare", "--n", "3", "--k", "2", inconsistent}, exitInconsistent},
+	} {
+		var stdout, stderr bytes.Buffer
+		if code := run(tc.args, strings.NewReader(""), &stdout, &stderr); code != tc.want {
+			t.Errorf("%q exited %d, want %d: %s", tc.args, code, tc.want, stderr.String())
+		}
+		if !strings.Contains(stderr.String(), "Error:") || strings.Contains(stdout.String(), "Error:") {
+			t.Errorf("%q: want the error on stderr only, got stdout:\n%s\nstderr:\n%s", tc.args, stdout.String(), stderr.String())
+		}
+	}
+}
+
+// blockingReader fails the test on a read, which would otherwise wait for
+// input that never comes.
+type blockingReader struct{ t *testing.T }
+
+func (r blockingReader) Read([]byte) (int, error) {
+	r.t.Error("stdin read")
+	return 0, io.EOF
+}
+
+// runArgs runs the command line args with stdin and returns what it wrote
+// and its exit code.
+func runArgs(stdin string, args ...string) (stdout, stderr string, code int) {
+	var out, errs bytes.Buffer
+	code = run(args, strings.NewReader(stdin), &out, &errs)
+	return out.String(), errs.String(), code
+}
+
+// writeFile writes data to name in a new temporary directory and returns
+// its path.
+func writeFile(t *testing.T, name, data string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestReconstructUsesSmallestShares(t *testing.T) {
+	// The shares of testcase1.json, largest x first.
+	path := writeFile(t, "reversed.json", `{"keys": {"n": 4, "k": 3},
+		"6": {"base": "10", "value": "39"}, "3": {"base": "10", "value": "12"},
+		"2": {"base": "10", "value": "7"}, "1": {"base": "10", "value": "4"}}`)
+	first, stderr, code := runArgs("", "--verbose", path)
+	if code != exitOK {
+		t.Fatalf("exited %d: %s", code, stderr)
+	}
+	for _, x := range []string{"1", "2", "3"} {
+		if !strings.Contains(first, "Using share x="+x+" ") {
+			t.Errorf("share x=%s is not used:\n%s", x, first)
+		}
+	}
+	if strings.Contains(first, "Using share x=6 ") {
+		t.Errorf("share x=6 is used instead of a smaller one:\n%s", first)
+	}
+	if again, _, _ := runArgs("", "--verbose", path); again != first {
+		t.Errorf("a second run differs:\n%s\nfirst:\n%s", again, first)
+	}
+}
+
+func TestReconstructMod(t *testing.T) {
+	// f(x) = 5 + 3x + 4x² mod 11.
+	path := writeFile(t, "mod.json", `{"keys": {"n": 3, "k": 3},
+		"1": {"base": "10", "value": "1"}, "2": {"base": "10", "value": "5"}, "3": {"base": "10", "value": "6"}}`)
+	stdout, stderr, code := runArgs("", "-q", "--mod", "11", path)
+	if code != exitOK || stdout != "5\n" {
+		t.Errorf("--mod 11 printed %q and exited %d, want 5: %s", stdout, code, stderr)
+	}
+	if _, _, code := runArgs("", "-q", "--mod", "12", path); code != exitUsage {
+		t.Errorf("--mod 12 exited %d, want %d for a composite modulus", code, exitUsage)
+	}
+	if stdout, _, code := runArgs("", "-q", "--mod", "12", "--allow-composite", path); code == exitUsage {
+		t.Errorf("--mod 12 --allow-composite exited %d: %s", code, stdout)
+	}
+}
+
+func TestReconstructStdin(t *testing.T) {
+	document, err := os.ReadFile("testcase1.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout, stderr, code := runArgs(string(document), "-q", "-")
+	if code != exitOK || stdout != "3\n" {
+		t.Errorf("reading - printed %q and exited %d, want 3: %s", stdout, code, stderr)
+	}
+	if _, _, code := runArgs("", "-q", "-"); code == exitOK {
+		t.Error("reading an empty stdin succeeded")
+	}
+}
+
+func TestReconstructMergesFiles(t *testing.T) {
+	files := []string{"testcase_single_1.json", "testcase_single_2.json", "testcase_single_3.json"}
+	stdout, stderr, code := runArgs("", append([]string{"-q"}, files...)...)
+	if code != exitOK || stdout != "3\n" {
+		t.Errorf("%v printed %q and exited %d, want 3: %s", files, stdout, code, stderr)
+	}
+	if _, _, code := runArgs("", append([]string{"-q"}, files[:2]...)...); code != exitInsufficient {
+		t.Errorf("%v exited %d, want %d", files[:2], code, exitInsufficient)
+	}
+}
+
+func TestReconstructOutputJSON(t *testing.T) {
+	stdout, stderr, code := runArgs("", "--output", "json", "testcase1.json")
+	if code != exitOK {
+		t.Fatalf("exited %d: %s", code, stderr)
+	}
+	var result struct {
+		Secret   string   `json:"secret"`
+		XUsed    []string `json:"x_used"`
+		K        int      `json:"k"`
+		Verified int      `json:"verified"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &result); err != nil {
+		t.Fatalf("stdout is not JSON: %v\n%s", err, stdout)
+	}
+	if result.Secret != "3" || result.K != 3 || len(result.XUsed) != 3 || result.Verified != 1 {
+		t.Errorf("result = %+v, want secret 3 from 3 shares with 1 verified", result)
+	}
+
+	stdout, _, code = runArgs("", "--output", "json", "testcase2.json")
+	var failure struct {
+		Error string `json:"error"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &failure); err != nil || code != exitInconsistent {
+		t.Fatalf("failure exited %d with %v:\n%s", code, err, stdout)
+	}
+	if !strings.Contains(failure.Error, "x=8") {
+		t.Errorf("error = %q, want the inconsistent share x=8", failure.Error)
+	}
+}
+
+func TestReconstructEncode(t *testing.T) {
+	for encoding, want := range map[string]string{"dec": "3\n", "hex": "3\n", "base64": "Aw==\n", "base58": "4\n"} {
+		stdout, stderr, code := runArgs("", "-q", "--encode", encoding, "testcase1.json")
+		if code != exitOK || stdout != want {
+			t.Errorf("--encode %s printed %q and exited %d, want %q: %s", encoding, stdout, code, want, stderr)
+		}
+	}
+	if _, _, code := runArgs("", "-q", "--encode", "base32", "testcase1.json"); code != exitUsage {
+		t.Errorf("--encode base32 exited %d, want %d", code, exitUsage)
+	}
+}
+
+func TestReconstructExpected(t *testing.T) {
+	if stdout, stderr, code := runArgs("", "-q", "--expected", "3", "testcase1.json"); code != exitOK || stdout != "3\n" {
+		t.Errorf("--expected 3 printed %q and exited %d: %s", stdout, code, stderr)
+	}
+	if _, stderr, code := runArgs("", "-q", "--expected", "4", "testcase1.json"); code != exitMismatch {
+		t.Errorf("--expected 4 exited %d, want %d: %s", code, exitMismatch, stderr)
+	}
+
+	// An "expected" entry in the document is checked the same way.
+	for expected, want := range map[string]int{`"3"`: exitOK, `4`: exitMismatch} {
+		path := writeFile(t, "expected.json", `{"keys": {"n": 1, "k": 1}, "expected": `+expected+`, "1": {"base": "10", "value": "3"}}`)
+		if _, stderr, code := runArgs("", "-q", path); code != want {
+			t.Errorf("expected %s exited %d, want %d: %s", expected, code, want, stderr)
+		}
+	}
+}
+
+func TestReconstructShareCount(t *testing.T) {
+	path := writeFile(t, "short.json", `{"keys": {"n": 5, "k": 1}, "1": {"base": "10", "value": "4"}}`)
+	stdout, stderr, code := runArgs("", "-q", path)
+	if code != exitOK || stdout != "4\n" {
+		t.Errorf("printed %q and exited %d, want 4", stdout, code)
+	}
+	if !strings.Contains(stderr, "declares n=5 but contains 1 shares") {
+		t.Errorf("no warning about the share count:\n%s", stderr)
+	}
+	if _, stderr, code := runArgs("", "-q", "--strict", path); code != exitInvalid {
+		t.Errorf("--strict exited %d, want %d: %s", code, exitInvalid, stderr)
+	}
+}
+
+func TestReshareCommand(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "reshared.json")
+	if _, stderr, code := runArgs("", "reshare", "--n", "3", "--k", "2", "--out", out, "testcase_reshare.json"); code != exitOK {
+		t.Fatalf("reshare exited %d: %s", code, stderr)
+	}
+	if stdout, stderr, code := runArgs("", "-q", "--strict", out); code != exitOK || stdout != "123456789\n" {
+		t.Errorf("reconstructing the new shares printed %q and exited %d: %s", stdout, code, stderr)
+	}
+	if _, stderr, code := runArgs("", "reshare", "--n", "3", "--k", "2", "--vss", "pedersen", "testcase_reshare.json"); code != exitUsage {
+		t.Errorf("--vss pedersen exited %d, want %d: %s", code, exitUsage, stderr)
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	original, err := os.ReadFile("testcase1.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := writeFile(t, "shares.json", string(original))
+	stdout, stderr, code := runArgs("", "migrate", path)
+	if code != exitOK || !strings.Contains(stdout, "from version 1 to version 2") {
+		t.Fatalf("migrate printed %q and exited %d: %s", stdout, code, stderr)
+	}
+	migrated, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(migrated), `"version": 2`) || !strings.Contains(string(migrated), `"checksum"`) {
+		t.Errorf("migrated file has no version or checksums:\n%s", migrated)
+	}
+	if stdout, stderr, code := runArgs("", "-q", path); code != exitOK || stdout != "3\n" {
+		t.Errorf("reconstructing the migrated file printed %q and exited %d: %s", stdout, code, stderr)
+	}
+	if stdout, _, code := runArgs("", "migrate", path); code != exitOK || !strings.Contains(stdout, "already at version 2") {
+		t.Errorf("migrating again printed %q and exited %d", stdout, code)
+	}
+}
+
+func TestSplitSeed(t *testing.T) {
+	args := []string{"split", "--n", "3", "--k", "2", "--secret", "42", "--vss", "none", "--seed", "00ff", "--insecure-deterministic"}
+	first, stderr, code := runArgs("", args...)
+	if code != exitOK {
+		t.Fatalf("split exited %d: %s", code, stderr)
+	}
+	if !strings.Contains(stderr, "predictable") {
+		t.Errorf("no warning about the seed:\n%s", stderr)
+	}
+	if second, _, _ := runArgs("", args...); second != first {
+		t.Errorf("splits from the same seed differ:\n%s\n%s", first, second)
+	}
+
+	path := writeFile(t, "seeded.json", first)
+	stdout, stderr, code := runArgs("", "-q", path)
+	if code != exitOK || stdout != "42\n" {
+		t.Errorf("reconstructing printed %q and exited %d: %s", stdout, code, stderr)
+	}
+	if !strings.Contains(stderr, "fixed seed") {
+		t.Errorf("no warning that the shares are deterministic:\n%s", stderr)
+	}
+
+	for _, args := range [][]string{
+		{"split", "--n", "3", "--k", "2", "--secret", "42", "--seed", "00ff"},
+		{"split", "--n", "3", "--k", "2", "--secret", "42", "--insecure-deterministic"},
+		{"split", "--n", "3", "--k", "2", "--secret", "42", "--seed", "xyz", "--insecure-deterministic"},
+	} {
+		if _, stderr, code := runArgs("", args...); code != exitUsage {
+			t.Errorf("%v exited %d, want %d: %s", args, code, exitUsage, stderr)
+		}
+	}
+}
+
+func TestURLInput(t *testing.T) {
+	document, err := os.ReadFile("testcase1.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/slow.json":
+			select {
+			case <-r.Context().Done():
+			case <-time.After(5 * time.Second):
+			}
+		case r.Header.Get("Authorization") != "Bearer token":
+			http.Error(w, "no token", http.StatusUnauthorized)
+		case r.URL.Path == "/shares.json":
+			w.Write(document)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	url := server.URL + "/shares.json"
+	if stdout, stderr, code := runArgs("", "-q", "--header", "Authorization: Bearer token", url); code != exitOK || stdout != "3\n" {
+		t.Errorf("fetching %s printed %q and exited %d: %s", url, stdout, code, stderr)
+	}
+	for _, tc := range []struct {
+		args []string
+		want string
+	}{
+		{[]string{url}, "401 Unauthorized"},
+		{[]string{"--header", "Authorization: Bearer token", server.URL + "/missing.json"}, "404 Not Found"},
+		{[]string{"--header", "Authorization: Bearer token", "--max-url-bytes", "10", url}, "larger than 10 bytes"},
+		{[]string{"--url-timeout", "50ms", server.URL + "/slow.json"}, "timed out after 50ms"},
+		{[]string{"--header", "no colon", url}, "invalid --header"},
+	} {
+		if _, stderr, code := runArgs("", append([]string{"-q"}, tc.args...)...); code == exitOK || !strings.Contains(stderr, tc.want) {
+			t.Errorf("%v exited %d, want an error containing %q:\n%s", tc.args, code, tc.want, stderr)
+		}
+	}
+}
+
+func TestFromEnv(t *testing.T) {
+	document, err := os.ReadFile("testcase1.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("TEST_SHARES", string(document))
+	t.Setenv("TEST_SHARES_BASE64", base64.StdEncoding.EncodeToString(document))
+	t.Setenv("TEST_SHARES_EMPTY", "")
+	for _, args := range [][]string{
+		{"-q", "--from-env", "TEST_SHARES"},
+		{"-q", "--from-env", "TEST_SHARES_BASE64"},
+		{"-q", "$TEST_SHARES"},
+	} {
+		if stdout, stderr, code := runArgs("", args...); code != exitOK || stdout != "3\n" {
+			t.Errorf("%v printed %q and exited %d: %s", args, stdout, code, stderr)
+		}
+	}
+	for variable, want := range map[string]string{
+		"TEST_SHARES_UNSET": "environment variable TEST_SHARES_UNSET is not set",
+		"TEST_SHARES_EMPTY": "environment variable TEST_SHARES_EMPTY is empty",
+	} {
+		if _, stderr, code := runArgs("", "-q", "--from-env", variable); code == exitOK || !strings.Contains(stderr, want) {
+			t.Errorf("--from-env %s exited %d, want an error containing %q:\n%s", variable, code, want, stderr)
+		}
+	}
+}
+
+func TestInteractive(t *testing.T) {
+	input := strings.Join([]string{
+		"zero",
+		"3",
+		"1 10 4",
+		"# a comment",
+		"1 10 4",
+		"2 2 102",
+		`"2": {"base": "2", "value": "111"},`,
+		"",
+		"6 10 39",
+	}, "\n") + "\n"
+	stdout, stderr, code := runArgs(input, "-q", "--interactive")
+	if code != exitOK || stdout != "3\n" {
+		t.Fatalf("printed %q and exited %d:\n%s", stdout, code, stderr)
+	}
+	for _, want := range []string{
+		`invalid k "zero"`,
+		"Share 1 of 3: ",
+		"ok: x=1",
+		"already have a share with x=1",
+		"type the share again",
+		"Share 3 of 3: ",
+		"ok: x=6",
+	} {
+		if !strings.Contains(stderr, want) {
+			t.Errorf("prompts do not contain %q:\n%s", want, stderr)
+		}
+	}
+
+	if _, stderr, code := runArgs("2\n1 10 4\n", "-q", "--interactive"); code != exitInsufficient {
+		t.Errorf("input ending after one of two shares exited %d, want %d:\n%s", code, exitInsufficient, stderr)
+	}
+	if _, stderr, code := runArgs("", "-q", "--interactive", "testcase1.json"); code != exitUsage {
+		t.Errorf("--interactive with a file exited %d, want %d:\n%s", code, exitUsage, stderr)
+	}
+}
+
+func TestSplitPerShareFiles(t *testing.T) {
+	dir := t.TempDir()
+	args := []string{"split", "--n", "3", "--k", "2", "--secret", "42", "--vss", "none", "--per-share-files", "--out", dir, "--name-template", "part-{x}.json", "--manifest", "manifest.json"}
+	if _, stderr, code := runArgs("", args...); code != exitOK || !strings.Contains(stderr, "Wrote 3 share files") {
+		t.Fatalf("split exited %d: %s", code, stderr)
+	}
+	for _, name := range []string{"part-1.json", "part-2.json", "part-3.json"} {
+		info, err := os.Stat(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if mode := info.Mode().Perm(); mode != 0o600 {
+			t.Errorf("%s has mode %o, want 600", name, mode)
+		}
+	}
+	manifest, err := os.ReadFile(filepath.Join(dir, "manifest.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(manifest), `"part-2.json"`) || strings.Contains(string(manifest), `"value"`) {
+		t.Errorf("manifest does not list the files without their values:\n%s", manifest)
+	}
+	if stdout, stderr, code := runArgs("", "-q", filepath.Join(dir, "part-1.json"), filepath.Join(dir, "part-3.json")); code != exitOK || stdout != "42\n" {
+		t.Errorf("reconstructing two share files printed %q and exited %d: %s", stdout, code, stderr)
+	}
+	if _, stderr, code := runArgs("", "-q", filepath.Join(dir, "part-2.json")); code != exitInsufficient {
+		t.Errorf("reconstructing one share file exited %d, want %d: %s", code, exitInsufficient, stderr)
+	}
+
+	// Nothing is overwritten.
+	if _, stderr, code := runArgs("", args...); code != exitIO || !strings.Contains(stderr, "already exists") {
+		t.Errorf("splitting into the same directory again exited %d, want %d: %s", code, exitIO, stderr)
+	}
+	for _, template := range []string{"share.json", "dir/share_{x}.json"} {
+		if _, stderr, code := runArgs("", "split", "--n", "3", "--k", "2", "--secret", "42", "--per-share-files", "--out", t.TempDir(), "--name-template", template); code != exitUsage {
+			t.Errorf("--name-template %s exited %d, want %d: %s", template, code, exitUsage, stderr)
+		}
+	}
+}
+
+func TestQuiet(t *testing.T) {
+	for _, flag := range []string{"-q", "--quiet"} {
+		stdout, stderr, code := runArgs("", flag, "testcase1.json")
+		if code != exitOK || stdout != "3\n" {
+			t.Errorf("%s printed %q and exited %d, want exactly \"3\\n\"", flag, stdout, code)
+		}
+		if strings.Contains(stderr, "Successfully parsed") || strings.Contains(stderr, "Verified") {
+			t.Errorf("%s printed informational output:\n%s", flag, stderr)
+		}
+	}
+	path := writeFile(t, "ff.json", `{"keys": {"n": 1, "k": 1}, "1": {"base": "10", "value": "255"}}`)
+	if stdout, _, _ := runArgs("", "-q", "--encode", "hex", path); stdout != "ff\n" {
+		t.Errorf("-q --encode hex printed %q, want \"ff\\n\"", stdout)
+	}
+
+	stdout, _, code := runArgs("", "-q", "--output", "json", "testcase1.json")
+	var result map[string]any
+	decoder := json.NewDecoder(strings.NewReader(stdout))
+	if err := decoder.Decode(&result); err != nil || code != exitOK {
+		t.Fatalf("-q --output json printed %q and exited %d: %v", stdout, code, err)
+	}
+	if result["secret"] != "3" || decoder.More() {
+		t.Errorf("-q --output json printed %q, want only the JSON object", stdout)
+	}
+
+	stdout, stderr, code := runArgs("", "-q", "missing.json")
+	if code != exitIO || stdout != "" || !strings.HasPrefix(stderr, "Error: ") {
+		t.Errorf("-q with a missing file printed %q to stdout and %q to stderr and exited %d", stdout, stderr, code)
+	}
+}
+
+func TestProgressLine(t *testing.T) {
+	var out bytes.Buffer
+	p := &progressLine{w: &out}
+	report := p.reporter("parsed %s shares")
+	report(1, 4)
+	if got := out.String(); got != "\r\033[Kparsed 1/4 shares (ETA 0s)" {
+		t.Errorf("first line = %q", got)
+	}
+	// Lines are redrawn at most every progressInterval.
+	report(2, 4)
+	if strings.Contains(out.String(), "2/4") {
+		t.Errorf("redrawn before progressInterval: %q", out.String())
+	}
+	out.Reset()
+	report(4, 4)
+	if got := out.String(); got != "\r\033[K" {
+		t.Errorf("finishing wrote %q, want the line erased", got)
+	}
+	out.Reset()
+	p.clear()
+	if out.Len() != 0 {
+		t.Errorf("clearing an erased line wrote %q", out.String())
+	}
+
+	(&progressLine{w: &out}).reporter("step %s")(3, 0)
+	if got := out.String(); got != "\r\033[Kstep 3" {
+		t.Errorf("with no total wrote %q, want the count alone", got)
+	}
+	if isTerminal(&out) {
+		t.Error("a buffer is a terminal")
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	if _, stderr, code := runArgs("", "-q", "--timeout", "1ns", "testcase1.json"); code != exitTimeout || !strings.Contains(stderr, "deadline exceeded") {
+		t.Errorf("--timeout 1ns exited %d, want %d: %s", code, exitTimeout, stderr)
+	}
+	if stdout, stderr, code := runArgs("", "-q", "--timeout", "1m", "testcase1.json"); code != exitOK || stdout != "3\n" {
+		t.Errorf("--timeout 1m printed %q and exited %d: %s", stdout, code, stderr)
+	}
+	if _, stderr, code := runArgs("", "-q", "--timeout", "-1s", "testcase1.json"); code != exitUsage {
+		t.Errorf("--timeout -1s exited %d, want %d: %s", code, exitUsage, stderr)
+	}
+	// --watch stops waiting for shares too.
+	if _, stderr, code := runArgs("", "-q", "--timeout", "100ms", "--watch", t.TempDir(), "--watch-interval", "10ms"); code != exitTimeout || !strings.Contains(stderr, "stopped watching") {
+		t.Errorf("--watch with --timeout exited %d, want %d: %s", code, exitTimeout, stderr)
+	}
+}
+
+func TestStdinBOM(t *testing.T) {
+	document, err := os.ReadFile("testcase1.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stdout, stderr, code := runArgs("\xef\xbb\xbf"+string(document), "-q", "-"); code != exitOK || stdout != "3\n" {
+		t.Errorf("stdin with a byte-order mark printed %q and exited %d: %s", stdout, code, stderr)
+	}
+}
+
+func TestSplitParticipants(t *testing.T) {
+	participants := writeFile(t, "participants.json", `[{"name": "alice", "weight": 2}, {"name": "bob"}, {"name": "carol"}]`)
+	dir := t.TempDir()
+	if _, stderr, code := runArgs("", "split", "--n", "4", "--k", "3", "--secret", "42", "--vss", "none", "--participants", participants, "--per-share-files", "--out", dir); code != exitOK {
+		t.Fatalf("split exited %d: %s", code, stderr)
+	}
+	alice, bob := filepath.Join(dir, "share_alice.json"), filepath.Join(dir, "share_bob.json")
+	if _, stderr, code := runArgs("", "-q", alice); code != exitInsufficient || !strings.Contains(stderr, "not enough weight") || !strings.Contains(stderr, "missing bob (weight 1), carol (weight 1)") {
+		t.Errorf("alice alone exited %d, want %d naming the missing participants: %s", code, exitInsufficient, stderr)
+	}
+	stdout, stderr, code := runArgs("", alice, bob)
+	if code != exitOK || !strings.Contains(stdout, "is: 42") {
+		t.Errorf("alice and bob printed %q and exited %d: %s", stdout, code, stderr)
+	}
+	if !strings.Contains(stdout+stderr, "hold weight 3 of the 3 needed; missing carol (weight 1)") {
+		t.Errorf("no coverage report:\n%s%s", stdout, stderr)
+	}
+}
+
+func TestReconstructFraction(t *testing.T) {
+	if _, stderr, code := runArgs("", "-q", "testcase1_corrupted.json"); code != exitInconsistent || !strings.Contains(stderr, "f(0) is not an integer (10/3)") {
+		t.Errorf("exited %d, want %d reporting the fraction: %s", code, exitInconsistent, stderr)
+	}
+	for _, tc := range []struct {
+		flag string
+		want string
+	}{
+		{"--allow-rational", "10/3\n"},
+		{"--vote", "3\n"},
+	} {
+		if stdout, stderr, code := runArgs("", "-q", tc.flag, "testcase1_corrupted.json"); code != exitOK || stdout != tc.want {
+			t.Errorf("%s printed %q and exited %d, want %q: %s", tc.flag, stdout, code, tc.want, stderr)
+		}
+	}
+}
+
+func TestReconstructThresholdOne(t *testing.T) {
+	stdout, stderr, code := runArgs("", "--verbose", "testcase_k1.json")
+	if code != exitOK || !strings.Contains(stdout, "is: 7") {
+		t.Fatalf("printed %q and exited %d: %s", stdout, code, stderr)
+	}
+	if !strings.Contains(stdout+stderr, "Using share x=1 from testcase_k1.json") || !strings.Contains(stdout, "Verified 2 additional shares") {
+		t.Errorf("k=1 did not use x=1 and check the other two:\n%s%s", stdout, stderr)
+	}
+}
+
+func TestModAuto(t *testing.T) {
+	for _, args := range [][]string{
+		{"-q", "testcase_mod_auto.json"},
+		{"-q", "--mod", "auto", "testcase_mod_auto.json"},
+		{"-q", "--mod", "9223372036854775837", "testcase_mod_auto.json"},
+	} {
+		if stdout, stderr, code := runArgs("", args...); code != exitOK || stdout != "123456789\n" {
+			t.Errorf("%v printed %q and exited %d: %s", args, stdout, code, stderr)
+		}
+	}
+	for _, tc := range []struct {
+		args []string
+		want string
+	}{
+		{[]string{"--mod", "101", "testcase_mod_auto.json"}, "differs from the modulus 9223372036854775837"},
+		{[]string{"--mod", "auto", "testcase1.json"}, "--mod auto needs shares whose keys object records their modulus"},
+		{[]string{"--mod", "91", "testcase1.json"}, "not a prime"},
+	} {
+		if _, stderr, code := runArgs("", append([]string{"-q"}, tc.args...)...); code != exitUsage || !strings.Contains(stderr, tc.want) {
+			t.Errorf("%v exited %d, want %d with %q: %s", tc.args, code, exitUsage, tc.want, stderr)
+		}
+	}
+	if stdout, stderr, code := runArgs("", "-q", "--mod", "91", "--allow-composite", "testcase1.json"); code != exitOK || stdout != "3\n" {
+		t.Errorf("--allow-composite printed %q and exited %d: %s", stdout, code, stderr)
+	}
+
+	// split --mod auto records the prime it picked.
+	stdout, stderr, code := runArgs("", "split", "--n", "3", "--k", "2", "--secret", "42", "--mod", "auto")
+	if code != exitOK || !strings.Contains(stdout, `"modulus": "9223372036854775837"`) {
+		t.Fatalf("split --mod auto printed %q and exited %d: %s", stdout, code, stderr)
+	}
+	if got, stderr, code := runArgs("", "-q", writeFile(t, "auto.json", stdout)); code != exitOK || got != "42\n" {
+		t.Errorf("reconstructing the --mod auto split printed %q and exited %d: %s", got, code, stderr)
+	}
+}
+
+func TestFieldPresetCommands(t *testing.T) {
+	stdout, _, code := runArgs("", "--field", "list")
+	if code != exitOK {
+		t.Fatalf("--field list exited %d", code)
+	}
+	for _, p := range share.FieldPresets() {
+		if !strings.Contains(stdout, p.Name) || !strings.Contains(stdout, p.Modulus.String()) {
+			t.Errorf("--field list does not show %s and its modulus:\n%s", p.Name, stdout)
+		}
+		if _, stderr, code := runArgs("", "-q", "testcase_field_"+p.Name+".json"); code != exitOK {
+			t.Errorf("testcase_field_%s.json exited %d: %s", p.Name, code, stderr)
+		}
+	}
+
+	stdout, stderr, code := runArgs("", "split", "--n", "3", "--k", "2", "--secret", "42", "--field", "ed25519")
+	if code != exitOK || !strings.Contains(stdout, `"field": "ed25519"`) {
+		t.Fatalf("split --field ed25519 printed %q and exited %d: %s", stdout, code, stderr)
+	}
+	if got, stderr, code := runArgs("", "-q", writeFile(t, "ed25519.json", stdout)); code != exitOK || got != "42\n" {
+		t.Errorf("reconstructing the ed25519 split printed %q and exited %d: %s", got, code, stderr)
+	}
+	if _, stderr, code := runArgs("", "split", "--n", "3", "--k", "2", "--secret", "42", "--field", "p384"); code != exitUsage {
+		t.Errorf("--field p384 exited %d, want %d: %s", code, exitUsage, stderr)
+	}
+}
+
+func TestBinaryField(t *testing.T) {
+	if _, stderr, code := runArgs("", "-q", "testcase_gf2m.json"); code != exitInvalid || !strings.Contains(stderr, "--field gf2m") {
+		t.Errorf("GF(2^m) shares without --field gf2m exited %d, want %d: %s", code, exitInvalid, stderr)
+	}
+	if _, stderr, code := runArgs("", "-q", "--field", "gf2m", "testcase_gf2m.json"); code != exitOK {
+		t.Errorf("--field gf2m exited %d: %s", code, stderr)
+	}
+
+	stdout, stderr, code := runArgs("", "split", "--n", "3", "--k", "2", "--secret", "171", "--field", "gf2m", "--poly", "0x11b")
+	if code != exitOK || !strings.Contains(stdout, `"poly": "0x11b"`) {
+		t.Fatalf("split --field gf2m printed %q and exited %d: %s", stdout, code, stderr)
+	}
+	if got, stderr, code := runArgs("", "-q", "--field", "gf2m", writeFile(t, "gf2m.json", stdout)); code != exitOK || got != "171\n" {
+		t.Errorf("reconstructing the GF(2^8) split printed %q and exited %d: %s", got, code, stderr)
+	}
+	for _, poly := range []string{"0x11a", "1"} {
+		if _, stderr, code := runArgs("", "split", "--n", "3", "--k", "2", "--secret", "171", "--field", "gf2m", "--poly", poly); code != exitUsage {
+			t.Errorf("--poly %s exited %d, want %d: %s", poly, code, exitUsage, stderr)
+		}
+	}
+}
+
+func TestAddCommand(t *testing.T) {
+	stdout, stderr, code := runArgs("", "add", "testcase_add_a.json", "testcase_add_b.json")
+	if code != exitOK {
+		t.Fatalf("add exited %d: %s", code, stderr)
+	}
+	if got, stderr, code := runArgs("", "-q", writeFile(t, "sum.json", stdout)); code != exitOK || got != "1111111110111111111011111111100\n" {
+		t.Errorf("reconstructing the sum printed %q and exited %d: %s", got, code, stderr)
+	}
+
+	out := filepath.Join(t.TempDir(), "sum.json")
+	if _, stderr, code := runArgs("", "add", "--out", out, "testcase_add_a.json", "testcase_add_b.json"); code != exitOK {
+		t.Fatalf("add --out exited %d: %s", code, stderr)
+	}
+	if got, stderr, code := runArgs("", "-q", out); code != exitOK || got != "1111111110111111111011111111100\n" {
+		t.Errorf("reconstructing the --out sum printed %q and exited %d: %s", got, code, stderr)
+	}
+
+	if _, stderr, code := runArgs("", "add", "testcase_add_a.json"); code != exitUsage {
+		t.Errorf("add with one file exited %d, want %d: %s", code, exitUsage, stderr)
+	}
+	if _, stderr, code := runArgs("", "add", "testcase_add_a.json", "testcase1.json"); code == exitOK || !strings.Contains(stderr, "testcase1.json") {
+		t.Errorf("adding mismatched share files exited %d: %s", code, stderr)
+	}
+}
+
+func TestFileSplit(t *testing.T) {
+	want, err := os.ReadFile("testcase_file.bin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := filepath.Join(t.TempDir(), "key.pem")
+	if _, stderr, code := runArgs("", "--out", out, "testcase_file_1.json", "testcase_file_2.json", "testcase_file_3.json"); code != exitOK {
+		t.Fatalf("reconstructing testcase_file.bin exited %d: %s", code, stderr)
+	}
+	if got, err := os.ReadFile(out); err != nil || !bytes.Equal(got, want) {
+		t.Errorf("reconstructed %x (%v), want %x", got, err, want)
+	}
+	if _, stderr, code := runArgs("", "testcase_file_1.json", "testcase_file_2.json"); code != exitUsage {
+		t.Errorf("file shares without --out exited %d, want %d: %s", code, exitUsage, stderr)
+	}
+
+	// Empty files, leading zero bytes and more than one chunk round trip.
+	large := bytes.Repeat([]byte("0123456789abcdef"), 1<<12)
+	for _, data := range [][]byte{nil, {0, 0, 0, 1}, large} {
+		in := writeFile(t, "secret.bin", string(data))
+		dir := t.TempDir()
+		if _, stderr, code := runArgs("", "split", "--in", in, "--n", "3", "--k", "2", "--out", dir, "--chunk-size", "1000"); code != exitOK {
+			t.Fatalf("split --in of %d bytes exited %d: %s", len(data), code, stderr)
+		}
+		out := filepath.Join(t.TempDir(), "out.bin")
+		if _, stderr, code := runArgs("", "--out", out, filepath.Join(dir, "share_1.json"), filepath.Join(dir, "share_3.json")); code != exitOK {
+			t.Fatalf("reconstructing %d bytes exited %d: %s", len(data), code, stderr)
+		}
+		if got, err := os.ReadFile(out); err != nil || !bytes.Equal(got, data) {
+			t.Errorf("round trip of %d bytes gave %d bytes (%v)", len(data), len(got), err)
+		}
+	}
+
+	// A tampered chunk is caught by the additional share, and without it by
+	// the recorded hash; either way nothing is left at --out.
+	tampered, err := os.ReadFile("testcase_file_2.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tamperedPath := writeFile(t, "tampered.json", strings.Replace(string(tampered), `"c988`, `"c989`, 1))
+	for _, paths := range [][]string{
+		{"testcase_file_1.json", tamperedPath, "testcase_file_3.json"},
+		{"testcase_file_1.json", tamperedPath},
+	} {
+		out := filepath.Join(t.TempDir(), "key.pem")
+		if _, stderr, code := runArgs("", append([]string{"--out", out}, paths...)...); code == exitOK {
+			t.Errorf("reconstructing from %v passed: %s", paths, stderr)
+		}
+		if _, err := os.Stat(out); !os.IsNotExist(err) {
+			t.Errorf("reconstructing from %v left %s behind", paths, out)
+		}
+	}
+}
+
+func TestScaleCommand(t *testing.T) {
+	const scaled = "864197523086419752308641975230"
+	stdout, stderr, code := runArgs("", "scale", "--expected-result", scaled, "testcase_add_a.json", "7")
+	if code != exitOK {
+		t.Fatalf("scale exited %d: %s", code, stderr)
+	}
+	secret, _ := new(big.Int).SetString(scaled, 10)
+	if !strings.Contains(stdout, `"secret_sha256": "`+share.SecretSHA256(secret)+`"`) {
+		t.Errorf("scale --expected-result recorded no hash of the scaled secret:\n%s", stdout)
+	}
+	if got, stderr, code := runArgs("", "-q", writeFile(t, "scaled.json", stdout)); code != exitOK || got != scaled+"\n" {
+		t.Errorf("reconstructing the scaled shares printed %q and exited %d: %s", got, code, stderr)
+	}
+	if got, stderr, code := runArgs("", "-q", "testcase_scale.json"); code != exitOK || got != scaled+"\n" {
+		t.Errorf("testcase_scale.json printed %q and exited %d: %s", got, code, stderr)
+	}
+
+	if stdout, stderr, code := runArgs("", "scale", "testcase1.json", "--", "-7"); code != exitOK {
+		t.Errorf("scale by -7 exited %d: %s", code, stderr)
+	} else if got, stderr, code := runArgs("", "-q", writeFile(t, "negative.json", stdout)); code != exitOK || got != "-21\n" {
+		t.Errorf("reconstructing testcase1.json scaled by -7 printed %q and exited %d: %s", got, code, stderr)
+	}
+
+	for _, tc := range []struct {
+		args []string
+		code int
+	}{
+		{[]string{"testcase_add_a.json", "7", "--expected-result", "5"}, exitMismatch},
+		{[]string{"testcase1.json", "2", "--expected-result", "7"}, exitMismatch},
+		{[]string{"testcase_add_a.json"}, exitUsage},
+		{[]string{"testcase_add_a.json", "seven"}, exitUsage},
+	} {
+		if _, stderr, code := runArgs("", append([]string{"scale"}, tc.args...)...); code != tc.code {
+			t.Errorf("scale %v exited %d, want %d: %s", tc.args, code, tc.code, stderr)
+		}
+	}
+}
+
+func TestConvert(t *testing.T) {
+	dir := t.TempDir()
+	in, steps := "testcase_checksum.json", []string{"16", "7", "62", "64", "2", "85", "15,16,10,36", "10"}
+	for i, base := range steps {
+		out := filepath.Join(dir, fmt.Sprintf("step%d.json", i))
+		stdout, stderr, code := runArgs("", "convert", "--to-base", base, in, out)
+		if code != exitOK || !strings.Contains(stdout, "to base "+base) {
+			t.Fatalf("convert --to-base %s printed %q and exited %d: %s", base, stdout, code, stderr)
+		}
+		if got, stderr, code := runArgs("", "-q", out); code != exitOK || got != "987654321\n" {
+			t.Errorf("after converting to base %s the file printed %q and exited %d: %s", base, got, code, stderr)
+		}
+		data, err := os.ReadFile(out)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if base != "15,16,10,36" && strings.Count(string(data), `"base": "`+base+`"`) != 4 {
+			t.Errorf("base %s: converted file has other bases:\n%s", base, data)
+		}
+		if !strings.Contains(string(data), `"checksum"`) {
+			t.Errorf("base %s: converted file dropped its checksums:\n%s", base, data)
+		}
+		in = out
+	}
+
+	for _, tc := range []struct {
+		args []string
+		code int
+	}{
+		{[]string{"testcase1.json", in}, exitUsage},
+		{[]string{"--to-base", "16", "testcase1.json"}, exitUsage},
+		{[]string{"--to-base", "63", "testcase1.json", filepath.Join(dir, "out.json")}, exitUsage},
+		{[]string{"--to-base", "16,10", "testcase1.json", filepath.Join(dir, "out.json")}, exitUsage},
+		{[]string{"--to-base", "16", "testcase1.json", in}, exitIO},
+		{[]string{"--to-base", "16", "testcase_encrypted.json", filepath.Join(dir, "out.json")}, exitFailure},
+	} {
+		if _, stderr, code := runArgs("", append([]string{"convert"}, tc.args...)...); code != tc.code {
+			t.Errorf("convert %v exited %d, want %d: %s", tc.args, code, tc.code, stderr)
+		}
+	}
+	if _, stderr, code := runArgs("", "convert", "--force", "--to-base", "16", in, in); code != exitOK {
+		t.Errorf("convert --force in place exited %d: %s", code, stderr)
+	}
+	if got, stderr, code := runArgs("", "-q", in); code != exitOK || got != "987654321\n" {
+		t.Errorf("after converting in place the file printed %q and exited %d: %s", got, code, stderr)
+	}
+}
+
+func TestDiffCommand(t *testing.T) {
+	hexPath := filepath.Join(t.TempDir(), "hex.json")
+	if _, stderr, code := runArgs("", "convert", "--to-base", "16", "testcase_checksum.json", hexPath); code != exitOK {
+		t.Fatalf("convert exited %d: %s", code, stderr)
+	}
+	decPath := filepath.Join(t.TempDir(), "dec.json")
+	if _, stderr, code := runArgs("", "convert", "--to-base", "10", hexPath, decPath); code != exitOK {
+		t.Fatalf("convert exited %d: %s", code, stderr)
+	}
+	data, err := os.ReadFile("testcase1.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	changed := writeFile(t, "changed.json", strings.Replace(string(data), `"value": "4"`, `"value": "5"`, 1))
+	var doc map[string]any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	delete(doc, "6")
+	missingData, _ := json.Marshal(doc)
+	missing := writeFile(t, "missing.json", string(missingData))
+
+	for _, tc := range []struct {
+		args []string
+		code int
+		want string
+	}{
+		{[]string{"testcase1.json", "testcase1.json"}, exitOK, "hold the same shares\n"},
+		{[]string{decPath, hexPath}, exitOK, "Representation only:\n  x=1: base 10 in " + decPath + ", 16 in " + hexPath},
+		{[]string{"testcase1.json", changed}, exitMismatch, "x=1: y 4 in testcase1.json, 5 in " + changed},
+		{[]string{"testcase1.json", missing}, exitMismatch, "x=6: only in testcase1.json"},
+		{[]string{"testcase1.json", "testcase1_labeled.json"}, exitOK, "Metadata:"},
+		{[]string{"--strict", "testcase1.json", "testcase1_labeled.json"}, exitMismatch, "Metadata:"},
+		{[]string{"--strict", decPath, hexPath}, exitOK, "described differently"},
+		{[]string{"testcase_checksum.json", hexPath}, exitOK, "version: 1 in testcase_checksum.json, 2 in " + hexPath},
+		{[]string{"testcase1.json"}, exitUsage, ""},
+	} {
+		stdout, stderr, code := runArgs("", append([]string{"diff"}, tc.args...)...)
+		if code != tc.code || !strings.Contains(stdout, tc.want) {
+			t.Errorf("diff %v printed %q and exited %d, want %q and %d: %s", tc.args, stdout, code, tc.want, tc.code, stderr)
+		}
+	}
+
+	stdout, stderr, code := runArgs("", "diff", "--output", "json", "testcase1.json", changed)
+	var result struct {
+		Differences []share.Difference `json:"differences"`
+		Material    bool               `json:"material"`
+	}
+	if code != exitMismatch || json.Unmarshal([]byte(stdout), &result) != nil || !result.Material || len(result.Differences) != 1 {
+		t.Errorf("diff --output json printed %q and exited %d: %s", stdout, code, stderr)
+	}
+}
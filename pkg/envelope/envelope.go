@@ -0,0 +1,150 @@
+// Package envelope encrypts share files under a passphrase: the file is
+// sealed with AES-256-GCM under a key derived from the passphrase with
+// scrypt, and wrapped in a JSON envelope recording the scrypt parameters.
+package envelope
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// Cipher names the only cipher envelopes use.
+const Cipher = "aes-256-gcm"
+
+// The scrypt parameters Seal uses.
+const (
+	DefaultN = 1 << 15
+	DefaultR = 8
+	DefaultP = 1
+)
+
+// maxScryptMemory bounds the memory the scrypt parameters of an envelope
+// may ask Open for.
+const maxScryptMemory = 1 << 30
+
+// ErrWrongPassphrase is returned by Open when the ciphertext does not
+// authenticate, because the passphrase is wrong or the envelope was
+// altered.
+var ErrWrongPassphrase = errors.New("wrong passphrase, or the encrypted file was altered")
+
+// KDF holds the scrypt parameters and salt of an envelope.
+type KDF struct {
+	Name string `json:"name"`
+	Salt []byte `json:"salt"`
+	N    int    `json:"n"`
+	R    int    `json:"r"`
+	P    int    `json:"p"`
+}
+
+// Envelope is the JSON document an encrypted file holds. Byte fields are
+// base64.
+type Envelope struct {
+	Cipher     string `json:"cipher"`
+	KDF        KDF    `json:"kdf"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// Seal encrypts plaintext under passphrase and returns the envelope.
+func Seal(plaintext, passphrase []byte) ([]byte, error) {
+	kdf := KDF{Name: "scrypt", Salt: make([]byte, 16), N: DefaultN, R: DefaultR, P: DefaultP}
+	if _, err := rand.Read(kdf.Salt); err != nil {
+		return nil, err
+	}
+	aead, err := newAEAD(passphrase, kdf)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	env := Envelope{Cipher: Cipher, KDF: kdf, Nonce: nonce, Ciphertext: aead.Seal(nil, nonce, plaintext, nil)}
+	data, err := json.MarshalIndent(env, "", "    ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// Open decrypts the envelope data with passphrase.
+func Open(data, passphrase []byte) ([]byte, error) {
+	var env Envelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		return nil, fmt.Errorf("invalid encrypted file: %w", err)
+	}
+	if env.Cipher != Cipher {
+		return nil, fmt.Errorf("invalid encrypted file: unknown cipher %q", env.Cipher)
+	}
+	if err := checkKDF(env.KDF); err != nil {
+		return nil, err
+	}
+	aead, err := newAEAD(passphrase, env.KDF)
+	if err != nil {
+		return nil, err
+	}
+	if len(env.Nonce) != aead.NonceSize() {
+		return nil, fmt.Errorf("invalid encrypted file: nonce has %d bytes, want %d", len(env.Nonce), aead.NonceSize())
+	}
+	plaintext, err := aead.Open(nil, env.Nonce, env.Ciphertext, nil)
+	if err != nil {
+		return nil, ErrWrongPassphrase
+	}
+	return plaintext, nil
+}
+
+// IsEnvelope reports whether head, the start of a file, begins an
+// envelope rather than a share document: a JSON object whose first key
+// is one only envelopes have.
+func IsEnvelope(head []byte) bool {
+	dec := json.NewDecoder(bytes.NewReader(head))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return false
+	}
+	tok, err := dec.Token()
+	if err != nil {
+		return false
+	}
+	switch tok {
+	case "cipher", "kdf", "nonce", "ciphertext":
+		return true
+	}
+	return false
+}
+
+// checkKDF rejects scrypt parameters that are invalid or would need more
+// than maxScryptMemory.
+func checkKDF(kdf KDF) error {
+	if kdf.Name != "scrypt" {
+		return fmt.Errorf("invalid encrypted file: unknown key derivation %q", kdf.Name)
+	}
+	if kdf.N < 2 || kdf.N&(kdf.N-1) != 0 || kdf.R < 1 || kdf.P < 1 {
+		return errors.New("invalid encrypted file: invalid scrypt parameters")
+	}
+	if kdf.N > maxScryptMemory/128/kdf.R || kdf.P > maxScryptMemory/128/kdf.R {
+		return errors.New("invalid encrypted file: scrypt parameters need too much memory")
+	}
+	if len(kdf.Salt) == 0 {
+		return errors.New("invalid encrypted file: empty salt")
+	}
+	return nil
+}
+
+func newAEAD(passphrase []byte, kdf KDF) (cipher.AEAD, error) {
+	key, err := scrypt.Key(passphrase, kdf.Salt, kdf.N, kdf.R, kdf.P, 32)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
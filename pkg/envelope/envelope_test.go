@@ -0,0 +1,110 @@
+package envelope
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestSealOpen(t *testing.T) {
+	plaintext := []byte(`{"keys": {"n": 2, "k": 2}}`)
+	data, err := Seal(plaintext, []byte("correct horse"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsEnvelope(data) {
+		t.Error("IsEnvelope of a sealed envelope is false")
+	}
+	got, err := Open(data, []byte("correct horse"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(plaintext) {
+		t.Errorf("Open = %q, want %q", got, plaintext)
+	}
+	if _, err := Open(data, []byte("battery staple")); !errors.Is(err, ErrWrongPassphrase) {
+		t.Errorf("Open with the wrong passphrase: err = %v, want %v", err, ErrWrongPassphrase)
+	}
+}
+
+func TestOpenTestcase(t *testing.T) {
+	data, err := os.ReadFile("../../testcase_encrypted.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	passphrase, err := os.ReadFile("../../testcase_encrypted.passphrase")
+	if err != nil {
+		t.Fatal(err)
+	}
+	plaintext, err := Open(data, []byte(strings.TrimRight(string(passphrase), "\n")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !json.Valid(plaintext) {
+		t.Errorf("Open = %q, want a JSON share file", plaintext)
+	}
+}
+
+func TestOpenTampered(t *testing.T) {
+	data, err := Seal([]byte("secret shares"), []byte("pass"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		name   string
+		tamper func(*Envelope)
+	}{
+		{"ciphertext", func(env *Envelope) { env.Ciphertext[0] ^= 1 }},
+		{"tag", func(env *Envelope) { env.Ciphertext[len(env.Ciphertext)-1] ^= 1 }},
+		{"nonce", func(env *Envelope) { env.Nonce[0] ^= 1 }},
+		{"salt", func(env *Envelope) { env.KDF.Salt[0] ^= 1 }},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var env Envelope
+			if err := json.Unmarshal(data, &env); err != nil {
+				t.Fatal(err)
+			}
+			tc.tamper(&env)
+			tampered, err := json.Marshal(env)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := Open(tampered, []byte("pass")); !errors.Is(err, ErrWrongPassphrase) {
+				t.Errorf("err = %v, want %v", err, ErrWrongPassphrase)
+			}
+		})
+	}
+}
+
+func TestCheckKDF(t *testing.T) {
+	salt := []byte("0123456789abcdef")
+	for _, tc := range []struct {
+		name string
+		kdf  KDF
+		want string
+	}{
+		{"default", KDF{Name: "scrypt", Salt: salt, N: DefaultN, R: DefaultR, P: DefaultP}, ""},
+		{"largest", KDF{Name: "scrypt", Salt: salt, N: 1 << 20, R: 8, P: 1}, ""},
+		{"unknown name", KDF{Name: "argon2id", Salt: salt, N: DefaultN, R: DefaultR, P: DefaultP}, "unknown key derivation"},
+		{"n of 1", KDF{Name: "scrypt", Salt: salt, N: 1, R: DefaultR, P: DefaultP}, "invalid scrypt parameters"},
+		{"n not a power of two", KDF{Name: "scrypt", Salt: salt, N: 3 << 10, R: DefaultR, P: DefaultP}, "invalid scrypt parameters"},
+		{"r of 0", KDF{Name: "scrypt", Salt: salt, N: DefaultN, R: 0, P: DefaultP}, "invalid scrypt parameters"},
+		{"p of 0", KDF{Name: "scrypt", Salt: salt, N: DefaultN, R: DefaultR, P: 0}, "invalid scrypt parameters"},
+		{"n too large", KDF{Name: "scrypt", Salt: salt, N: 1 << 21, R: 8, P: 1}, "too much memory"},
+		{"r too large", KDF{Name: "scrypt", Salt: salt, N: DefaultN, R: 1 << 20, P: 1}, "too much memory"},
+		{"p too large", KDF{Name: "scrypt", Salt: salt, N: DefaultN, R: 8, P: 1 << 21}, "too much memory"},
+		{"empty salt", KDF{Name: "scrypt", N: DefaultN, R: DefaultR, P: DefaultP}, "empty salt"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := checkKDF(tc.kdf)
+			switch {
+			case tc.want == "" && err != nil:
+				t.Errorf("err = %v, want nil", err)
+			case tc.want != "" && (err == nil || !strings.Contains(err.Error(), tc.want)):
+				t.Errorf("err = %v, want one saying %q", err, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,249 @@
+// Package fileshare shares the bytes of a file of any size over GF(256), a
+// chunk at a time, as the share documents of share.FileShareWriter, and
+// reassembles the file from them. Neither direction holds more than a
+// chunk of the file or its shares in memory.
+package fileshare
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/OmSingh2003/CATALOG-ASSIGNMENT/pkg/gf256"
+	"github.com/OmSingh2003/CATALOG-ASSIGNMENT/pkg/share"
+)
+
+// Options configures Split.
+type Options struct {
+	// ChunkSize is the number of bytes of the file in each chunk.
+	ChunkSize int
+
+	// Base is the base of the chunks: "16", "64", "64url" or "85".
+	Base string
+
+	// Random is the source of the coefficients, crypto/rand if nil.
+	Random io.Reader
+
+	// Deterministic marks the shares insecure_deterministic, for a Random
+	// such as share.SeededReader that anyone can replay.
+	Deterministic bool
+}
+
+// ReadError is a failure to read the file being split.
+type ReadError struct {
+	Err error
+}
+
+func (e *ReadError) Error() string { return "failed to read the file: " + e.Err.Error() }
+
+func (e *ReadError) Unwrap() error { return e.Err }
+
+// WriteError is a failure to write the share document at X, or with X 0
+// the reassembled file.
+type WriteError struct {
+	X   byte
+	Err error
+}
+
+func (e *WriteError) Error() string {
+	if e.X == 0 {
+		return "failed to write the file: " + e.Err.Error()
+	}
+	return fmt.Sprintf("failed to write the share at x=%d: %v", e.X, e.Err)
+}
+
+func (e *WriteError) Unwrap() error { return e.Err }
+
+// InvalidError is a share document that does not parse, or that does not
+// fit together with the others.
+type InvalidError struct {
+	Err error
+}
+
+func (e *InvalidError) Error() string { return e.Err.Error() }
+
+func (e *InvalidError) Unwrap() error { return e.Err }
+
+// Split reads r to its end and writes the share at x = i+1 of the file to
+// ws[i], so that any k of the len(ws) documents reassemble it. Each records
+// the size and SHA-256 of the whole file. Split returns the size and does
+// not close ws.
+func Split(r io.Reader, ws []io.Writer, k int, opts Options) (int64, error) {
+	n := len(ws)
+	if err := gf256.CheckThreshold(n, k); err != nil {
+		return 0, err
+	}
+	if opts.ChunkSize < 1 {
+		return 0, fmt.Errorf("invalid chunk size %d: must be positive", opts.ChunkSize)
+	}
+	random := opts.Random
+	if random == nil {
+		random = rand.Reader
+	}
+	writers := make([]*share.FileShareWriter, n)
+	for i, w := range ws {
+		var err error
+		if writers[i], err = share.NewFileShareWriter(w, n, k, byte(i+1), opts.ChunkSize, opts.Base, opts.Deterministic); err != nil {
+			return 0, err
+		}
+	}
+
+	hash := sha256.New()
+	var size int64
+	chunk := make([]byte, opts.ChunkSize)
+	for {
+		read, err := io.ReadFull(r, chunk)
+		if read > 0 {
+			hash.Write(chunk[:read])
+			size += int64(read)
+			shares, err := gf256.SplitFrom(random, chunk[:read], n, k)
+			if err != nil {
+				return 0, err
+			}
+			for i, s := range shares {
+				if err := writers[i].WriteChunk(s.Y); err != nil {
+					return 0, &WriteError{X: s.X, Err: err}
+				}
+			}
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			break
+		}
+		if err != nil {
+			return 0, &ReadError{Err: err}
+		}
+	}
+
+	sum := hex.EncodeToString(hash.Sum(nil))
+	for i, w := range writers {
+		if err := w.Close(size, sum); err != nil {
+			return 0, &WriteError{X: byte(i + 1), Err: err}
+		}
+	}
+	return size, nil
+}
+
+// Result is a reassembled file.
+type Result struct {
+	// Size is the number of bytes written.
+	Size int64
+
+	// Verified is the number of shares beyond the first k checked against
+	// the polynomials of every chunk.
+	Verified int
+}
+
+// Check reports share documents that cannot be combined: fewer than the k
+// they record, or documents that repeat an x or disagree on k or the chunk
+// size. names label rs in errors.
+func Check(rs []*share.FileShareReader, names []string) error {
+	if len(rs) == 0 {
+		return share.ErrInsufficientShares
+	}
+	for i, r := range rs {
+		for j, prev := range rs[:i] {
+			switch {
+			case prev.X == r.X:
+				return &InvalidError{Err: &share.DuplicateXError{X: big.NewInt(int64(r.X)), Entries: []string{names[j], names[i]}}}
+			case prev.K != r.K:
+				return fmt.Errorf("threshold mismatch: %s has k=%d but %s has k=%d", names[j], prev.K, names[i], r.K)
+			case prev.ChunkSize != r.ChunkSize:
+				return fmt.Errorf("chunk size mismatch: %s has chunks of %d bytes but %s has %d", names[j], prev.ChunkSize, names[i], r.ChunkSize)
+			}
+		}
+	}
+	if k := rs[0].K; len(rs) < k {
+		return &share.InsufficientSharesError{Found: len(rs), Needed: k}
+	}
+	return nil
+}
+
+// Combine reassembles the file of the share documents rs, read in step a
+// chunk at a time, and writes it to w, after checking them with Check. The
+// shares
+// beyond the first k are checked against the polynomials of every chunk
+// unless noVerify is set. What has been written to w is only the file if
+// Combine returns no error: it checks the size and SHA-256 every document
+// used records once the last chunk is written.
+func Combine(w io.Writer, rs []*share.FileShareReader, names []string, noVerify bool) (*Result, error) {
+	if err := Check(rs, names); err != nil {
+		return nil, err
+	}
+	k := rs[0].K
+	if noVerify {
+		rs, names = rs[:k], names[:k]
+	}
+
+	hash := sha256.New()
+	var size int64
+	shares := make([]gf256.Share, len(rs))
+	mismatched := make(map[byte]bool)
+	for chunk := 0; ; chunk++ {
+		var ended []string
+		for i, r := range rs {
+			y, err := r.Next()
+			if err == io.EOF {
+				ended = append(ended, names[i])
+				continue
+			}
+			if err != nil {
+				return nil, &InvalidError{Err: fmt.Errorf("%s: %w", names[i], err)}
+			}
+			shares[i] = gf256.Share{X: r.X, Y: y}
+		}
+		if len(ended) == len(rs) {
+			break
+		}
+		if len(ended) > 0 {
+			return nil, &InvalidError{Err: fmt.Errorf("%s end after %d chunks, but the other share files go on", strings.Join(ended, ", "), chunk)}
+		}
+		for i, s := range shares {
+			if len(s.Y) != len(shares[0].Y) {
+				return nil, &InvalidError{Err: fmt.Errorf("chunk %d of %s has %d bytes, but that of %s has %d", chunk, names[i], len(s.Y), names[0], len(shares[0].Y))}
+			}
+		}
+
+		data, err := gf256.Combine(shares[:k])
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range shares[k:] {
+			y, err := gf256.InterpolateAt(shares[:k], s.X)
+			if err != nil {
+				return nil, err
+			}
+			if !bytes.Equal(y, s.Y) {
+				mismatched[s.X] = true
+			}
+		}
+		hash.Write(data)
+		size += int64(len(data))
+		if _, err := w.Write(data); err != nil {
+			return nil, &WriteError{Err: err}
+		}
+	}
+	if len(mismatched) > 0 {
+		var xs []string
+		for _, s := range shares[k:] {
+			if mismatched[s.X] {
+				xs = append(xs, strconv.Itoa(int(s.X)))
+			}
+		}
+		return nil, errors.New("shares inconsistent with the reconstructed polynomials at x=" + strings.Join(xs, ", "))
+	}
+
+	sum := hex.EncodeToString(hash.Sum(nil))
+	for i, r := range rs {
+		if r.Size != size || r.SHA256 != sum {
+			return nil, fmt.Errorf("%w: %s records a file of %d bytes with SHA-256 %s, but the reassembled file has %d bytes with SHA-256 %s", share.ErrSecretHashMismatch, names[i], r.Size, r.SHA256, size, sum)
+		}
+	}
+	return &Result{Size: size, Verified: len(rs) - k}, nil
+}
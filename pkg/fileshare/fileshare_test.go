@@ -0,0 +1,165 @@
+package fileshare
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/OmSingh2003/CATALOG-ASSIGNMENT/pkg/share"
+)
+
+// split shares data into n documents with threshold k.
+func split(t *testing.T, data []byte, n, k, chunkSize int) []*bytes.Buffer {
+	t.Helper()
+	bufs := make([]*bytes.Buffer, n)
+	ws := make([]io.Writer, n)
+	for i := range bufs {
+		bufs[i] = new(bytes.Buffer)
+		ws[i] = bufs[i]
+	}
+	size, err := Split(bytes.NewReader(data), ws, k, Options{ChunkSize: chunkSize, Base: "16", Random: share.SeededReader([]byte("fileshare")), Deterministic: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if size != int64(len(data)) {
+		t.Fatalf("Split returned size %d, want %d", size, len(data))
+	}
+	return bufs
+}
+
+// readers opens the share documents docs.
+func readers(t *testing.T, docs ...[]byte) ([]*share.FileShareReader, []string) {
+	t.Helper()
+	rs := make([]*share.FileShareReader, len(docs))
+	names := make([]string, len(docs))
+	for i, doc := range docs {
+		r, err := share.NewFileShareReader(bytes.NewReader(doc))
+		if err != nil {
+			t.Fatal(err)
+		}
+		rs[i], names[i] = r, "share"+string(rune('a'+i))
+	}
+	return rs, names
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, data := range [][]byte{nil, {0, 0, 0, 1}, bytes.Repeat([]byte("0123456789"), 1000)} {
+		bufs := split(t, data, 5, 3, 64)
+		rs, names := readers(t, bufs[4].Bytes(), bufs[0].Bytes(), bufs[2].Bytes(), bufs[1].Bytes())
+		var out bytes.Buffer
+		result, err := Combine(&out, rs, names, false)
+		if err != nil {
+			t.Fatalf("%d bytes: %v", len(data), err)
+		}
+		if !bytes.Equal(out.Bytes(), data) || result.Size != int64(len(data)) || result.Verified != 1 {
+			t.Errorf("%d bytes: reassembled %d bytes with %d verified", len(data), out.Len(), result.Verified)
+		}
+	}
+}
+
+func TestCombineTestcase(t *testing.T) {
+	want, err := os.ReadFile("../../testcase_file.bin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var docs [][]byte
+	for _, name := range []string{"1", "2", "3"} {
+		doc, err := os.ReadFile("../../testcase_file_" + name + ".json")
+		if err != nil {
+			t.Fatal(err)
+		}
+		docs = append(docs, doc)
+	}
+	rs, names := readers(t, docs...)
+	var out bytes.Buffer
+	if _, err := Combine(&out, rs, names, false); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(out.Bytes(), want) {
+		t.Errorf("reassembled %x, want %x", out.Bytes(), want)
+	}
+}
+
+func TestCombineErrors(t *testing.T) {
+	data := bytes.Repeat([]byte("secret"), 20)
+	bufs := split(t, data, 3, 2, 16)
+	doc := func(i int) []byte { return bufs[i].Bytes() }
+	other := split(t, data, 3, 3, 16)
+	chunks := split(t, data, 3, 2, 32)
+	// The share at x=3 with the first digit of its first chunk changed.
+	flipped := bytes.Clone(doc(2))
+	i := bytes.Index(flipped, []byte(`"chunks": [`)) + len(`"chunks": [`) + len("\n        \"")
+	if flipped[i] == '0' {
+		flipped[i] = '1'
+	} else {
+		flipped[i] = '0'
+	}
+
+	for _, tc := range []struct {
+		name string
+		docs [][]byte
+		test func(error) bool
+	}{
+		{"too few", [][]byte{doc(0)}, func(err error) bool { return errors.Is(err, share.ErrInsufficientShares) }},
+		{"duplicate x", [][]byte{doc(0), doc(0)}, func(err error) bool {
+			var invalid *InvalidError
+			var dup *share.DuplicateXError
+			return errors.As(err, &invalid) && errors.As(err, &dup)
+		}},
+		{"threshold", [][]byte{doc(0), other[1].Bytes()}, func(err error) bool { return strings.Contains(err.Error(), "threshold mismatch") }},
+		{"chunk size", [][]byte{doc(0), chunks[1].Bytes()}, func(err error) bool { return strings.Contains(err.Error(), "chunk size mismatch") }},
+		{"inconsistent", [][]byte{doc(0), doc(1), flipped}, func(err error) bool {
+			return strings.Contains(err.Error(), "inconsistent with the reconstructed polynomials at x=3")
+		}},
+		{"hash", [][]byte{doc(0), flipped}, func(err error) bool { return errors.Is(err, share.ErrSecretHashMismatch) }},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			rs, names := readers(t, tc.docs...)
+			if _, err := Combine(io.Discard, rs, names, false); err == nil || !tc.test(err) {
+				t.Errorf("err = %v", err)
+			}
+		})
+	}
+
+	// Without verifying, the share beyond k is not read.
+	rs, names := readers(t, doc(0), doc(1), flipped)
+	var out bytes.Buffer
+	if result, err := Combine(&out, rs, names, true); err != nil || result.Verified != 0 || !bytes.Equal(out.Bytes(), data) {
+		t.Errorf("Combine without verifying = %+v, %v", result, err)
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) { return 0, errors.New("disk full") }
+
+func TestIOErrors(t *testing.T) {
+	ws := []io.Writer{io.Discard, failingWriter{}, io.Discard}
+	var writeErr *WriteError
+	if _, err := Split(bytes.NewReader(make([]byte, 10000)), ws, 2, Options{ChunkSize: 16, Base: "16"}); !errors.As(err, &writeErr) || writeErr.X != 2 {
+		t.Errorf("Split to a failing writer: err = %v, want a WriteError at x=2", err)
+	}
+	var readErr *ReadError
+	if _, err := Split(io.MultiReader(strings.NewReader("abc"), failingReader{}), []io.Writer{io.Discard, io.Discard}, 2, Options{ChunkSize: 2, Base: "16"}); !errors.As(err, &readErr) {
+		t.Errorf("Split of a failing reader: err = %v, want a ReadError", err)
+	}
+	if _, err := Split(strings.NewReader("abc"), []io.Writer{io.Discard}, 2, Options{ChunkSize: 2, Base: "16"}); err == nil {
+		t.Error("Split with n < k passed")
+	}
+	if _, err := Split(strings.NewReader("abc"), []io.Writer{io.Discard, io.Discard}, 2, Options{ChunkSize: 2, Base: "10"}); err == nil {
+		t.Error("Split in base 10 passed")
+	}
+
+	bufs := split(t, []byte("secret"), 2, 2, 4)
+	rs, names := readers(t, bufs[0].Bytes(), bufs[1].Bytes())
+	if _, err := Combine(failingWriter{}, rs, names, false); !errors.As(err, &writeErr) || writeErr.X != 0 {
+		t.Errorf("Combine to a failing writer: err = %v, want a WriteError", err)
+	}
+}
+
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) { return 0, errors.New("device gone") }
@@ -0,0 +1,161 @@
+// Package gf256 implements byte-wise Shamir secret sharing over GF(2^8), the
+// scheme used by HashiCorp Vault and most practical libraries: every byte of
+// the secret gets its own random polynomial, and a share holds the values of
+// all of them at one x. The field is the AES field, reduced by
+// x^8 + x^4 + x^3 + x + 1.
+package gf256
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/OmSingh2003/CATALOG-ASSIGNMENT/pkg/share"
+)
+
+// MaxShares is the number of distinct non-zero x coordinates in GF(2^8).
+const MaxShares = 255
+
+// Share is one byte-wise share: the values at X of the polynomials of every
+// byte position of the secret.
+type Share struct {
+	X byte
+	Y []byte
+}
+
+// expTable[i] is 3^i and logTable[v] the i with 3^i = v; 3 generates the
+// multiplicative group. expTable repeats once so that the sum of two logs
+// needs no reduction.
+var (
+	expTable [2 * MaxShares]byte
+	logTable [256]byte
+)
+
+func init() {
+	v := byte(1)
+	for i := 0; i < MaxShares; i++ {
+		expTable[i], expTable[i+MaxShares] = v, v
+		logTable[v] = byte(i)
+		// Multiply by 3: v*2 with reduction, plus v.
+		double := v << 1
+		if v&0x80 != 0 {
+			double ^= 0x1b
+		}
+		v ^= double
+	}
+}
+
+func mul(a, b byte) byte {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	return expTable[int(logTable[a])+int(logTable[b])]
+}
+
+// div returns a/b for a non-zero b.
+func div(a, b byte) byte {
+	if a == 0 {
+		return 0
+	}
+	return expTable[int(logTable[a])+MaxShares-int(logTable[b])]
+}
+
+// Split shares secret among n shares with threshold k, at x = 1 to n.
+func Split(secret []byte, n, k int) ([]Share, error) {
+	return SplitFrom(rand.Reader, secret, n, k)
+}
+
+// SplitFrom is Split drawing the coefficients from random, which must be
+// crypto/rand.Reader unless the shares are only test fixtures.
+func SplitFrom(random io.Reader, secret []byte, n, k int) ([]Share, error) {
+	if len(secret) == 0 {
+		return nil, errors.New("cannot split an empty secret")
+	}
+	if err := CheckThreshold(n, k); err != nil {
+		return nil, err
+	}
+
+	// coefficients[c][i] is the coefficient of x^(c+1) for byte i.
+	coefficients := make([][]byte, k-1)
+	for c := range coefficients {
+		coefficients[c] = make([]byte, len(secret))
+		if _, err := io.ReadFull(random, coefficients[c]); err != nil {
+			return nil, fmt.Errorf("failed to generate coefficient: %w", err)
+		}
+	}
+
+	shares := make([]Share, n)
+	for s := range shares {
+		x := byte(s + 1)
+		y := make([]byte, len(secret))
+		for i := range secret {
+			var v byte
+			for c := k - 2; c >= 0; c-- {
+				v = mul(v, x) ^ coefficients[c][i]
+			}
+			y[i] = mul(v, x) ^ secret[i]
+		}
+		shares[s] = Share{X: x, Y: y}
+	}
+	return shares, nil
+}
+
+// CheckThreshold reports whether n shares with threshold k can be made in
+// GF(256).
+func CheckThreshold(n, k int) error {
+	if k < 1 {
+		return fmt.Errorf("invalid k=%d: must be at least 1", k)
+	}
+	if n < k {
+		return fmt.Errorf("invalid n=%d: must be at least k=%d", n, k)
+	}
+	if n > MaxShares {
+		return fmt.Errorf("invalid n=%d: at most %d shares fit in GF(256)", n, MaxShares)
+	}
+	return nil
+}
+
+// Combine recovers the secret from at least k shares, all of which are
+// used.
+func Combine(shares []Share) ([]byte, error) {
+	return InterpolateAt(shares, 0)
+}
+
+// InterpolateAt evaluates the polynomials through shares at x0, which gives
+// the secret for x0 = 0 and the share at x0 otherwise.
+func InterpolateAt(shares []Share, x0 byte) ([]byte, error) {
+	if len(shares) == 0 {
+		return nil, &share.InsufficientSharesError{Found: 0, Needed: 1, Context: "to interpolate"}
+	}
+	size := len(shares[0].Y)
+	for j, s := range shares {
+		if s.X == 0 {
+			return nil, errors.New("invalid share: x=0 would hold the secret itself")
+		}
+		if len(s.Y) != size {
+			return nil, fmt.Errorf("share x=%d has %d bytes, but share x=%d has %d", s.X, len(s.Y), shares[0].X, size)
+		}
+		for _, other := range shares[:j] {
+			if other.X == s.X {
+				return nil, &share.DuplicateXError{X: big.NewInt(int64(s.X))}
+			}
+		}
+	}
+
+	// In characteristic 2 subtraction is addition, which is xor.
+	out := make([]byte, size)
+	for j, sj := range shares {
+		basis := byte(1)
+		for i, si := range shares {
+			if i != j {
+				basis = mul(basis, div(x0^si.X, sj.X^si.X))
+			}
+		}
+		for b, y := range sj.Y {
+			out[b] ^= mul(basis, y)
+		}
+	}
+	return out, nil
+}
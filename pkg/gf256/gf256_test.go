@@ -0,0 +1,109 @@
+package gf256
+
+import (
+	"bytes"
+	"errors"
+	"math/rand"
+	"testing"
+
+	"github.com/OmSingh2003/CATALOG-ASSIGNMENT/pkg/share"
+)
+
+func TestMulDiv(t *testing.T) {
+	// 0x53 and 0xca are inverses in the AES field.
+	if got := mul(0x53, 0xca); got != 1 {
+		t.Errorf("mul(0x53, 0xca) = %#x, want 1", got)
+	}
+	for a := 1; a < 256; a++ {
+		for b := 1; b < 256; b++ {
+			if got := div(mul(byte(a), byte(b)), byte(b)); got != byte(a) {
+				t.Fatalf("div(mul(%#x, %#x), %#x) = %#x", a, b, b, got)
+			}
+		}
+	}
+}
+
+func TestCombineEverySubset(t *testing.T) {
+	secret := []byte("correct horse battery staple")
+	const n, k = 6, 3
+	shares, err := SplitFrom(rand.New(rand.NewSource(1)), secret, n, k)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for mask := 0; mask < 1<<n; mask++ {
+		var subset []Share
+		for i := range shares {
+			if mask&(1<<i) != 0 {
+				subset = append(subset, shares[i])
+			}
+		}
+		if len(subset) == 0 {
+			continue
+		}
+		got, err := Combine(subset)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if recovered := bytes.Equal(got, secret); recovered != (len(subset) >= k) {
+			t.Errorf("%d shares (mask %06b) recovered the secret: %v", len(subset), mask, recovered)
+		}
+	}
+}
+
+func TestInterpolateAtShare(t *testing.T) {
+	shares, err := Split([]byte{0x00, 0x7f, 0xff}, 5, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range shares[3:] {
+		got, err := InterpolateAt(shares[:3], s.X)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, s.Y) {
+			t.Errorf("InterpolateAt(x=%d) = %x, want %x", s.X, got, s.Y)
+		}
+	}
+}
+
+func TestCheckThreshold(t *testing.T) {
+	for _, tc := range []struct {
+		n, k    int
+		wantErr bool
+	}{
+		{n: 1, k: 1},
+		{n: 5, k: 3},
+		{n: MaxShares, k: MaxShares},
+		{n: 3, k: 0, wantErr: true},
+		{n: 2, k: 3, wantErr: true},
+		{n: MaxShares + 1, k: 2, wantErr: true},
+	} {
+		if err := CheckThreshold(tc.n, tc.k); (err != nil) != tc.wantErr {
+			t.Errorf("CheckThreshold(%d, %d) = %v, want error %v", tc.n, tc.k, err, tc.wantErr)
+		}
+	}
+}
+
+func TestInterpolateAtErrors(t *testing.T) {
+	var insufficient *share.InsufficientSharesError
+	if _, err := InterpolateAt(nil, 0); !errors.As(err, &insufficient) {
+		t.Errorf("no shares: err = %v, want an InsufficientSharesError", err)
+	}
+	if _, err := Combine(nil); !errors.Is(err, share.ErrInsufficientShares) {
+		t.Errorf("Combine of no shares: err = %v, want %v", err, share.ErrInsufficientShares)
+	}
+	for _, tc := range []struct {
+		name   string
+		shares []Share
+	}{
+		{"x=0", []Share{{X: 0, Y: []byte{1}}}},
+		{"lengths differ", []Share{{X: 1, Y: []byte{1}}, {X: 2, Y: []byte{1, 2}}}},
+	} {
+		if _, err := InterpolateAt(tc.shares, 0); err == nil {
+			t.Errorf("%s: InterpolateAt returned no error", tc.name)
+		}
+	}
+	if _, err := InterpolateAt([]Share{{X: 1, Y: []byte{1}}, {X: 1, Y: []byte{2}}}, 0); !errors.Is(err, share.ErrDuplicateX) {
+		t.Errorf("duplicate x: err = %v, want %v", err, share.ErrDuplicateX)
+	}
+}
@@ -0,0 +1,193 @@
+// Package gf2m implements the binary extension fields GF(2^m) some hardware
+// tokens share secrets over. An element is a polynomial over GF(2) of degree
+// below m, held as a big.Int whose bit i is the coefficient of x^i; sums are
+// xor and products are carry-less multiplications reduced by a fixed
+// irreducible polynomial of degree m, such as x^233 + x^74 + 1 for
+// GF(2^233).
+package gf2m
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/OmSingh2003/CATALOG-ASSIGNMENT/pkg/share"
+)
+
+// Field is GF(2^m) reduced by an irreducible polynomial of degree m. It is
+// safe for concurrent use.
+type Field struct {
+	poly *big.Int
+	m    int
+}
+
+var one = big.NewInt(1)
+
+// New returns the field reduced by poly, which must be irreducible over
+// GF(2) and of degree at least 1.
+func New(poly *big.Int) (*Field, error) {
+	if poly.Cmp(big.NewInt(2)) < 0 {
+		return nil, fmt.Errorf("invalid polynomial %#x: must have degree at least 1", poly)
+	}
+	if !Irreducible(poly) {
+		return nil, fmt.Errorf("invalid polynomial %#x: not irreducible over GF(2)", poly)
+	}
+	return &Field{poly: new(big.Int).Set(poly), m: poly.BitLen() - 1}, nil
+}
+
+// Degree returns m.
+func (f *Field) Degree() int {
+	return f.m
+}
+
+// Poly returns the reduction polynomial.
+func (f *Field) Poly() *big.Int {
+	return new(big.Int).Set(f.poly)
+}
+
+func (f *Field) String() string {
+	return fmt.Sprintf("GF(2^%d)", f.m)
+}
+
+// Contains reports whether a is an element of the field, a polynomial of
+// degree below m.
+func (f *Field) Contains(a *big.Int) bool {
+	return a.Sign() >= 0 && a.BitLen() <= f.m
+}
+
+// Add returns a+b, which in characteristic 2 is a xor b.
+func (f *Field) Add(a, b *big.Int) *big.Int {
+	return new(big.Int).Xor(a, b)
+}
+
+// Sub returns a-b, the same as a+b.
+func (f *Field) Sub(a, b *big.Int) *big.Int {
+	return new(big.Int).Xor(a, b)
+}
+
+// Mul returns a·b.
+func (f *Field) Mul(a, b *big.Int) *big.Int {
+	return polyMod(clmul(a, b), f.poly)
+}
+
+// Inverse returns 1/a, found by the extended Euclidean algorithm for
+// polynomials over GF(2). a is reduced modulo the polynomial first, so a
+// multiple of it is zero, which has no inverse.
+func (f *Field) Inverse(a *big.Int) (*big.Int, error) {
+	if a.Sign() < 0 {
+		return nil, fmt.Errorf("%s is not an element of %s", a, f)
+	}
+	// Invariants: g1·a ≡ u and g2·a ≡ v modulo poly.
+	u, v := polyMod(a, f.poly), new(big.Int).Set(f.poly)
+	if u.Sign() == 0 {
+		return nil, errors.New("zero has no inverse")
+	}
+	g1, g2 := big.NewInt(1), new(big.Int)
+	shifted := new(big.Int)
+	for u.Cmp(one) != 0 {
+		j := u.BitLen() - v.BitLen()
+		if j < 0 {
+			u, v = v, u
+			g1, g2 = g2, g1
+			j = -j
+		}
+		u.Xor(u, shifted.Lsh(v, uint(j)))
+		g1.Xor(g1, shifted.Lsh(g2, uint(j)))
+	}
+	return polyMod(g1, f.poly), nil
+}
+
+// Split shares secret, an element of the field, among n shares with
+// threshold k from a polynomial of degree k-1 over the field with
+// coefficients drawn from random, which must be crypto/rand.Reader unless
+// the shares are only test fixtures. The shares are at x = 1 to n.
+func (f *Field) Split(random io.Reader, secret *big.Int, n, k int) ([]share.Point, error) {
+	if !f.Contains(secret) {
+		return nil, fmt.Errorf("secret must be an element of %s: a non-negative integer of at most %d bits", f, f.m)
+	}
+	if k < 1 {
+		return nil, fmt.Errorf("invalid k=%d: must be at least 1", k)
+	}
+	if n < k {
+		return nil, fmt.Errorf("invalid n=%d: must be at least k=%d", n, k)
+	}
+	if !f.Contains(big.NewInt(int64(n))) {
+		return nil, fmt.Errorf("invalid n=%d: at most %d shares fit in %s", n, new(big.Int).Sub(new(big.Int).Lsh(one, uint(f.m)), one), f)
+	}
+
+	coefficients := make([]*big.Int, k)
+	coefficients[0] = new(big.Int).Set(secret)
+	buf := make([]byte, (f.m+7)/8)
+	mask := new(big.Int).Sub(new(big.Int).Lsh(one, uint(f.m)), one)
+	for i := 1; i < k; i++ {
+		if _, err := io.ReadFull(random, buf); err != nil {
+			return nil, fmt.Errorf("failed to generate coefficient: %w", err)
+		}
+		coefficients[i] = new(big.Int).And(new(big.Int).SetBytes(buf), mask)
+	}
+
+	points := make([]share.Point, n)
+	for s := range points {
+		x := big.NewInt(int64(s + 1))
+		y := new(big.Int)
+		for c := k - 1; c >= 0; c-- {
+			y = f.Add(f.Mul(y, x), coefficients[c])
+		}
+		points[s] = share.Point{X: x, Y: y}
+	}
+	return points, nil
+}
+
+// Irreducible reports whether poly, of degree m, has no factor over GF(2)
+// of degree between 1 and m-1, by Ben-Or's test: it has none of degree i
+// exactly when gcd(x^(2^i) - x, poly) = 1, and a reducible polynomial has a
+// factor of degree at most m/2.
+func Irreducible(poly *big.Int) bool {
+	m := poly.BitLen() - 1
+	if m < 1 {
+		return false
+	}
+	x := big.NewInt(2)
+	h := polyMod(x, poly)
+	for i := 1; i <= m/2; i++ {
+		h = polyMod(clmul(h, h), poly)
+		if polyGCD(new(big.Int).Xor(h, x), poly).Cmp(one) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// clmul returns the carry-less product of a and b.
+func clmul(a, b *big.Int) *big.Int {
+	product := new(big.Int)
+	shifted := new(big.Int)
+	for i := b.BitLen() - 1; i >= 0; i-- {
+		if b.Bit(i) == 1 {
+			product.Xor(product, shifted.Lsh(a, uint(i)))
+		}
+	}
+	return product
+}
+
+// polyMod returns the remainder of a divided by the non-zero b as
+// polynomials over GF(2).
+func polyMod(a, b *big.Int) *big.Int {
+	r := new(big.Int).Set(a)
+	shifted := new(big.Int)
+	for d := b.BitLen(); r.BitLen() >= d; {
+		r.Xor(r, shifted.Lsh(b, uint(r.BitLen()-d)))
+	}
+	return r
+}
+
+// polyGCD returns the greatest common divisor of a and b as polynomials
+// over GF(2).
+func polyGCD(a, b *big.Int) *big.Int {
+	a, b = new(big.Int).Set(a), new(big.Int).Set(b)
+	for b.Sign() != 0 {
+		a, b = b, polyMod(a, b)
+	}
+	return a
+}
@@ -0,0 +1,133 @@
+package gf2m
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/OmSingh2003/CATALOG-ASSIGNMENT/pkg/lagrange"
+	"github.com/OmSingh2003/CATALOG-ASSIGNMENT/pkg/share"
+)
+
+func TestInverseOfMultipleOfPoly(t *testing.T) {
+	f, err := New(big.NewInt(0x11b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, a := range []*big.Int{
+		big.NewInt(0),
+		big.NewInt(0x11b),
+		clmul(big.NewInt(0x11b), big.NewInt(0x5)),
+	} {
+		if inverse, err := f.Inverse(a); err == nil {
+			t.Errorf("Inverse(%#x) = %#x, want an error", a, inverse)
+		}
+	}
+}
+
+func TestInverse(t *testing.T) {
+	f, err := New(big.NewInt(0x11b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for a := int64(1); a < 256; a++ {
+		inverse, err := f.Inverse(big.NewInt(a))
+		if err != nil {
+			t.Fatalf("Inverse(%#x): %v", a, err)
+		}
+		if product := f.Mul(big.NewInt(a), inverse); product.Cmp(one) != 0 {
+			t.Errorf("%#x · Inverse(%#x) = %#x, want 1", a, a, product)
+		}
+	}
+	// An unreduced a has the inverse of its remainder.
+	reduced, _ := f.Inverse(big.NewInt(0x53))
+	unreduced, err := f.Inverse(new(big.Int).Xor(big.NewInt(0x53), big.NewInt(0x11b)))
+	if err != nil || unreduced.Cmp(reduced) != 0 {
+		t.Errorf("Inverse(0x53 + poly) = %v, %v, want %#x", unreduced, err, reduced)
+	}
+}
+
+func TestNew(t *testing.T) {
+	for _, tc := range []struct {
+		poly *big.Int
+		m    int
+		ok   bool
+	}{
+		{big.NewInt(0x11b), 8, true},
+		{new(big.Int).SetBit(new(big.Int).SetBit(big.NewInt(1), 74, 1), 233, 1), 233, true},
+		{big.NewInt(0b11), 1, true},
+		// x^8 + x^4 + x^3 + x is divisible by x.
+		{big.NewInt(0x11a), 0, false},
+		// x^2 + 1 = (x + 1)^2.
+		{big.NewInt(0b101), 0, false},
+		{big.NewInt(1), 0, false},
+		{big.NewInt(0), 0, false},
+	} {
+		f, err := New(tc.poly)
+		if !tc.ok {
+			if err == nil {
+				t.Errorf("New(%#x) returned no error", tc.poly)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("New(%#x): %v", tc.poly, err)
+			continue
+		}
+		if f.Degree() != tc.m || f.Poly().Cmp(tc.poly) != 0 {
+			t.Errorf("New(%#x) has degree %d, want %d", tc.poly, f.Degree(), tc.m)
+		}
+	}
+}
+
+func TestMul(t *testing.T) {
+	f, err := New(big.NewInt(0x11b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The worked example of FIPS 197, section 4.2.
+	if got := f.Mul(big.NewInt(0x57), big.NewInt(0x83)); got.Int64() != 0xc1 {
+		t.Errorf("0x57 · 0x83 = %#x, want 0xc1", got)
+	}
+	if got := f.Add(big.NewInt(0x57), big.NewInt(0x83)); got.Int64() != 0xd4 {
+		t.Errorf("0x57 + 0x83 = %#x, want 0xd4", got)
+	}
+	if !f.Contains(big.NewInt(0xff)) || f.Contains(big.NewInt(0x100)) || f.Contains(big.NewInt(-1)) {
+		t.Error("Contains does not hold exactly the elements below x^8")
+	}
+}
+
+func TestSplit(t *testing.T) {
+	f, err := New(big.NewInt(0x11b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	points, err := f.Split(share.SeededReader([]byte("gf2m")), big.NewInt(0xab), 5, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(points) != 5 {
+		t.Fatalf("%d shares, want 5", len(points))
+	}
+	for _, subset := range [][]share.Point{points[:3], points[2:], {points[0], points[2], points[4]}} {
+		secret, err := lagrange.Interpolate(subset, lagrange.WithField(f))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if secret.Int64() != 0xab {
+			t.Errorf("secret = %#x, want 0xab", secret)
+		}
+	}
+	for _, tc := range []struct {
+		secret *big.Int
+		n, k   int
+	}{
+		{big.NewInt(0x100), 5, 3},
+		{big.NewInt(1), 5, 0},
+		{big.NewInt(1), 2, 3},
+		{big.NewInt(1), 256, 3},
+	} {
+		if _, err := f.Split(share.SeededReader(nil), tc.secret, tc.n, tc.k); err == nil {
+			t.Errorf("Split(%#x, n=%d, k=%d) returned no error", tc.secret, tc.n, tc.k)
+		}
+	}
+}
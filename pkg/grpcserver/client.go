@@ -0,0 +1,54 @@
+package grpcserver
+
+import (
+	"context"
+	"math/big"
+
+	"google.golang.org/grpc"
+
+	"github.com/OmSingh2003/CATALOG-ASSIGNMENT/pkg/share"
+	shamirv1 "github.com/OmSingh2003/CATALOG-ASSIGNMENT/proto/shamir/v1"
+)
+
+// Client calls the Shamir service with the types of pkg/share. Errors are
+// the status errors of the server; status.Code tells them apart.
+type Client struct {
+	c shamirv1.ShamirClient
+}
+
+// NewClient returns a client calling the service over conn.
+func NewClient(conn grpc.ClientConnInterface) *Client {
+	return &Client{c: shamirv1.NewShamirClient(conn)}
+}
+
+// Reconstruct returns the secret of s and the shares the server
+// interpolated.
+func (c *Client) Reconstruct(ctx context.Context, s *share.Shares) (*big.Int, []share.Point, error) {
+	secret, err := c.c.Reconstruct(ctx, ToProto(s))
+	if err != nil {
+		return nil, nil, err
+	}
+	used := make([]share.Point, len(secret.GetPointsUsed()))
+	for i, point := range secret.GetPointsUsed() {
+		used[i] = share.Point{
+			X:    fromSigned(point.GetXNegative(), point.GetX()),
+			Y:    fromSigned(point.GetYNegative(), point.GetY()),
+			Base: point.GetBase(),
+		}
+	}
+	return fromSigned(secret.GetNegative(), secret.GetValue()), used, nil
+}
+
+// Split returns n shares of the non-negative secret with threshold k, over
+// the prime field of modulus unless it is nil, written in base.
+func (c *Client) Split(ctx context.Context, secret *big.Int, n, k int, modulus *big.Int, base string) (*share.Shares, error) {
+	req := &shamirv1.SplitRequest{Secret: secret.Bytes(), N: uint32(n), K: uint32(k), Base: base}
+	if modulus != nil {
+		req.Modulus = modulus.Bytes()
+	}
+	m, err := c.c.Split(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return FromProto(m)
+}
@@ -0,0 +1,58 @@
+package grpcserver
+
+import (
+	"math/big"
+
+	"github.com/OmSingh2003/CATALOG-ASSIGNMENT/pkg/share"
+	shamirv1 "github.com/OmSingh2003/CATALOG-ASSIGNMENT/proto/shamir/v1"
+)
+
+// FromProto decodes a Shares message with the checks the share parsers
+// make. An empty modulus means shares over the integers.
+func FromProto(m *shamirv1.Shares) (*share.Shares, error) {
+	points := make([]share.Point, len(m.GetShares()))
+	for i, s := range m.GetShares() {
+		points[i] = share.Point{
+			X:    fromSigned(s.GetXNegative(), s.GetX()),
+			Y:    fromSigned(s.GetYNegative(), s.GetY()),
+			Base: s.GetBase(),
+		}
+	}
+	var modulus *big.Int
+	if len(m.GetModulus()) > 0 {
+		modulus = new(big.Int).SetBytes(m.GetModulus())
+	}
+	return share.NewShares(int(m.GetN()), int(m.GetK()), points, modulus)
+}
+
+// ToProto encodes s as a Shares message. Its commitments and metadata,
+// which the message has no fields for, are dropped.
+func ToProto(s *share.Shares) *shamirv1.Shares {
+	m := &shamirv1.Shares{N: uint32(s.N), K: uint32(s.K)}
+	for _, point := range s.Points {
+		m.Shares = append(m.Shares, shareProto(point))
+	}
+	if s.Modulus != nil {
+		m.Modulus = s.Modulus.Bytes()
+	}
+	return m
+}
+
+func shareProto(point share.Point) *shamirv1.Share {
+	xNegative, x := signed(point.X)
+	yNegative, y := signed(point.Y)
+	return &shamirv1.Share{X: x, XNegative: xNegative, Y: y, YNegative: yNegative, Base: point.Base}
+}
+
+// signed splits v into its sign and big-endian magnitude.
+func signed(v *big.Int) (negative bool, magnitude []byte) {
+	return v.Sign() < 0, v.Bytes()
+}
+
+func fromSigned(negative bool, magnitude []byte) *big.Int {
+	v := new(big.Int).SetBytes(magnitude)
+	if negative {
+		v.Neg(v)
+	}
+	return v
+}
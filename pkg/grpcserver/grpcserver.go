@@ -0,0 +1,138 @@
+// Package grpcserver serves the Shamir gRPC service of proto/shamir/v1: it
+// splits with pkg/share and reconstructs with pkg/reconstruct, as
+// pkg/server reconstructs over HTTP, and maps their errors to status codes.
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"math/big"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/OmSingh2003/CATALOG-ASSIGNMENT/pkg/lagrange"
+	"github.com/OmSingh2003/CATALOG-ASSIGNMENT/pkg/reconstruct"
+	"github.com/OmSingh2003/CATALOG-ASSIGNMENT/pkg/share"
+	shamirv1 "github.com/OmSingh2003/CATALOG-ASSIGNMENT/proto/shamir/v1"
+)
+
+// Option configures New.
+type Option func(*config)
+
+type config struct {
+	modulus *big.Int
+}
+
+// WithModulus reconstructs modulo the prime p shares whose request gives
+// no modulus. Without it they are reconstructed over the integers.
+func WithModulus(p *big.Int) Option {
+	return func(c *config) {
+		c.modulus = p
+	}
+}
+
+// Server implements shamirv1.ShamirServer. It keeps no state between
+// calls, so concurrent calls are safe.
+type Server struct {
+	shamirv1.UnimplementedShamirServer
+	c *config
+}
+
+// New returns the server, to be registered with
+// shamirv1.RegisterShamirServer.
+func New(opts ...Option) *Server {
+	c := &config{}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return &Server{c: c}
+}
+
+// Reconstruct interpolates the first k shares, checks the others against
+// the polynomial through them and returns the secret.
+func (s *Server) Reconstruct(ctx context.Context, req *shamirv1.Shares) (*shamirv1.Secret, error) {
+	shares, err := FromProto(req)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err := checkPrime(shares.Modulus); err != nil {
+		return nil, err
+	}
+
+	opts := reconstruct.Options{Lagrange: []lagrange.Option{lagrange.WithContext(ctx)}}
+	if shares.Modulus == nil && s.c.modulus != nil {
+		opts.Lagrange = append(opts.Lagrange, lagrange.WithModulus(s.c.modulus))
+	}
+	result, err := reconstruct.Shares(shares, opts)
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	sign, magnitude := signed(result.Secret)
+	used := make([]*shamirv1.Share, len(result.Used))
+	for i, point := range result.Used {
+		used[i] = shareProto(point)
+	}
+	return &shamirv1.Secret{Value: magnitude, Negative: sign, PointsUsed: used}, nil
+}
+
+// Split shares the secret among n shares with threshold k, over the prime
+// field of the request's modulus if it gives one. The shares carry no
+// commitments, which the Shares message cannot hold.
+func (s *Server) Split(ctx context.Context, req *shamirv1.SplitRequest) (*shamirv1.Shares, error) {
+	base := req.GetBase()
+	if base == "" {
+		base = "10"
+	}
+	if !share.ValidBase(base) {
+		return nil, status.Errorf(codes.InvalidArgument, "%v: %s", share.ErrInvalidBase, base)
+	}
+	var modulus *big.Int
+	if len(req.GetModulus()) > 0 {
+		modulus = new(big.Int).SetBytes(req.GetModulus())
+		if err := checkPrime(modulus); err != nil {
+			return nil, err
+		}
+	}
+	secret := new(big.Int).SetBytes(req.GetSecret())
+	shares, err := share.Split(secret, int(req.GetN()), int(req.GetK()), modulus, share.WithCommitments(""))
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	for i := range shares.Points {
+		shares.Points[i].Base = base
+	}
+	return ToProto(shares), nil
+}
+
+// checkPrime reports a modulus that is not prime as an InvalidArgument
+// status: interpolation divides by differences of x values, which a
+// composite modulus may not invert.
+func checkPrime(modulus *big.Int) error {
+	if modulus != nil && !modulus.ProbablyPrime(20) {
+		return status.Errorf(codes.InvalidArgument, "modulus %s is not prime", modulus)
+	}
+	return nil
+}
+
+// statusError maps an error of reconstruct.Shares to a status:
+// FailedPrecondition for too few shares or a missing modulus and DataLoss
+// for shares that fail their commitments or checksums or do not lie on one
+// polynomial.
+func statusError(err error) error {
+	code := codes.Unknown
+	var inconsistent *reconstruct.InconsistentError
+	switch {
+	case errors.Is(err, context.Canceled):
+		code = codes.Canceled
+	case errors.Is(err, context.DeadlineExceeded):
+		code = codes.DeadlineExceeded
+	case errors.Is(err, share.ErrInsufficientShares), errors.Is(err, reconstruct.ErrNoModulus):
+		code = codes.FailedPrecondition
+	case errors.Is(err, share.ErrCommitmentMismatch), errors.Is(err, share.ErrChecksumMismatch),
+		errors.As(err, &inconsistent), errors.Is(err, lagrange.ErrNonIntegerSecret):
+		code = codes.DataLoss
+	}
+	return status.Error(code, err.Error())
+}
@@ -0,0 +1,213 @@
+package grpcserver_test
+
+import (
+	"context"
+	"math/big"
+	"net"
+	"os"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/OmSingh2003/CATALOG-ASSIGNMENT/pkg/grpcserver"
+	"github.com/OmSingh2003/CATALOG-ASSIGNMENT/pkg/share"
+	shamirv1 "github.com/OmSingh2003/CATALOG-ASSIGNMENT/proto/shamir/v1"
+)
+
+// dial starts the server with opts on an in-process listener and returns a
+// client connected to it.
+func dial(t *testing.T, opts ...grpcserver.Option) (*grpcserver.Client, shamirv1.ShamirClient) {
+	t.Helper()
+	listener := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer()
+	shamirv1.RegisterShamirServer(srv, grpcserver.New(opts...))
+	go srv.Serve(listener)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return grpcserver.NewClient(conn), shamirv1.NewShamirClient(conn)
+}
+
+func readShares(t *testing.T, path string) *share.Shares {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	shares, err := share.ParseShares(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return shares
+}
+
+func TestReconstruct(t *testing.T) {
+	client, _ := dial(t)
+	for _, tc := range []struct {
+		path string
+		want string
+	}{
+		{"../../testcase1.json", "3"},
+		{"../../testcase_negative.json", "-7"},
+		{"../../testcase_vss.json", "123456789"},
+	} {
+		t.Run(tc.path, func(t *testing.T) {
+			shares := readShares(t, tc.path)
+			got, used, err := client.Reconstruct(context.Background(), shares)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.String() != tc.want {
+				t.Errorf("secret = %s, want %s", got, tc.want)
+			}
+			if len(used) != shares.K {
+				t.Errorf("%d shares used, want k=%d", len(used), shares.K)
+			}
+			for i, point := range used {
+				if point.X.Cmp(shares.Points[i].X) != 0 || point.Y.Cmp(shares.Points[i].Y) != 0 {
+					t.Errorf("used share %d = (%s, %s), want (%s, %s)", i, point.X, point.Y, shares.Points[i].X, shares.Points[i].Y)
+				}
+			}
+		})
+	}
+}
+
+func TestReconstructNegativeX(t *testing.T) {
+	// f(x) = 7 + 2x through x = -2, -1.
+	shares := &share.Shares{N: 2, K: 2, Points: []share.Point{
+		{X: big.NewInt(-2), Y: big.NewInt(3)},
+		{X: big.NewInt(-1), Y: big.NewInt(5)},
+	}}
+	client, _ := dial(t)
+	got, used, err := client.Reconstruct(context.Background(), shares)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Int64() != 7 {
+		t.Errorf("secret = %s, want 7", got)
+	}
+	if len(used) != 2 || used[0].X.Int64() != -2 || used[1].X.Int64() != -1 {
+		t.Errorf("used shares %v, want x = -2, -1", used)
+	}
+}
+
+func TestSplitRoundTrip(t *testing.T) {
+	client, _ := dial(t)
+	secret, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+	for _, tc := range []struct {
+		name    string
+		modulus *big.Int
+		base    string
+	}{
+		{"integers", nil, "16"},
+		{"prime field", share.LookupField("mersenne127").Modulus, "62"},
+		{"default base", nil, ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			shares, err := client.Split(context.Background(), secret, 5, 3, tc.modulus, tc.base)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(shares.Points) != 5 || shares.K != 3 {
+				t.Fatalf("got %d shares with k=%d, want 5 with k=3", len(shares.Points), shares.K)
+			}
+			wantBase := tc.base
+			if wantBase == "" {
+				wantBase = "10"
+			}
+			if shares.Points[0].Base != wantBase {
+				t.Errorf("base = %q, want %q", shares.Points[0].Base, wantBase)
+			}
+			if (shares.Modulus == nil) != (tc.modulus == nil) {
+				t.Errorf("modulus = %v, want %v", shares.Modulus, tc.modulus)
+			}
+			shares.Points = shares.Points[2:]
+			got, _, err := client.Reconstruct(context.Background(), shares)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Cmp(secret) != 0 {
+				t.Errorf("reconstructed %s, want %s", got, secret)
+			}
+		})
+	}
+}
+
+func TestStatusCodes(t *testing.T) {
+	_, raw := dial(t)
+	one, two, three := []byte{1}, []byte{2}, []byte{3}
+	for _, tc := range []struct {
+		name string
+		call func(context.Context) error
+		want codes.Code
+	}{
+		{"k larger than n", func(ctx context.Context) error {
+			_, err := raw.Reconstruct(ctx, &shamirv1.Shares{N: 2, K: 3})
+			return err
+		}, codes.InvalidArgument},
+		{"duplicate x", func(ctx context.Context) error {
+			_, err := raw.Reconstruct(ctx, &shamirv1.Shares{N: 2, K: 2, Shares: []*shamirv1.Share{{X: one, Y: two}, {X: one, Y: three}}})
+			return err
+		}, codes.InvalidArgument},
+		{"invalid base", func(ctx context.Context) error {
+			_, err := raw.Reconstruct(ctx, &shamirv1.Shares{N: 1, K: 1, Shares: []*shamirv1.Share{{X: one, Y: two, Base: "99"}}})
+			return err
+		}, codes.InvalidArgument},
+		{"composite modulus", func(ctx context.Context) error {
+			_, err := raw.Reconstruct(ctx, &shamirv1.Shares{N: 1, K: 1, Shares: []*shamirv1.Share{{X: one, Y: two}}, Modulus: []byte{15}})
+			return err
+		}, codes.InvalidArgument},
+		{"insufficient shares", func(ctx context.Context) error {
+			_, err := raw.Reconstruct(ctx, &shamirv1.Shares{N: 3, K: 3, Shares: []*shamirv1.Share{{X: one, Y: two}, {X: two, Y: three}}})
+			return err
+		}, codes.FailedPrecondition},
+		{"inconsistent shares", func(ctx context.Context) error {
+			_, err := raw.Reconstruct(ctx, &shamirv1.Shares{N: 3, K: 2, Shares: []*shamirv1.Share{{X: one, Y: one}, {X: two, Y: two}, {X: three, Y: one}}})
+			return err
+		}, codes.DataLoss},
+		{"split with k larger than n", func(ctx context.Context) error {
+			_, err := raw.Split(ctx, &shamirv1.SplitRequest{Secret: one, N: 2, K: 3})
+			return err
+		}, codes.InvalidArgument},
+		{"split in an invalid base", func(ctx context.Context) error {
+			_, err := raw.Split(ctx, &shamirv1.SplitRequest{Secret: one, N: 3, K: 2, Base: "1"})
+			return err
+		}, codes.InvalidArgument},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.call(context.Background())
+			if got := status.Code(err); got != tc.want {
+				t.Errorf("status = %s (%v), want %s", got, err, tc.want)
+			}
+		})
+	}
+}
+
+func TestWithModulus(t *testing.T) {
+	// f(x) = 5 + 3x mod 7 through x = 1, 2.
+	shares := &share.Shares{N: 2, K: 2, Points: []share.Point{
+		{X: big.NewInt(1), Y: big.NewInt(1)},
+		{X: big.NewInt(2), Y: big.NewInt(4)},
+	}}
+	client, _ := dial(t, grpcserver.WithModulus(big.NewInt(7)))
+	got, _, err := client.Reconstruct(context.Background(), shares)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Int64() != 5 {
+		t.Errorf("secret = %s, want 5", got)
+	}
+}
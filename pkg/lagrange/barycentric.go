@@ -0,0 +1,177 @@
+package lagrange
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/OmSingh2003/CATALOG-ASSIGNMENT/pkg/share"
+)
+
+// Interpolator evaluates the polynomial through a fixed set of points at
+// many x values. It precomputes the barycentric weights once, in O(k²),
+// after which each evaluation takes O(k) arithmetic operations instead of
+// the O(k²) of InterpolateAt. Results are exact and equal to those of
+// InterpolateAt with the same options.
+type Interpolator struct {
+	points  []share.Point
+	modulus *big.Int
+	field   Field
+
+	// scaled and scaledMod hold y_j·w_j for the barycentric weight
+	// w_j = 1/∏_{i≠j}(x_j-x_i), over the rationals or modulo the modulus,
+	// and scaledField in the field.
+	scaled      []*big.Rat
+	scaledMod   []*big.Int
+	scaledField []*big.Int
+}
+
+// NewInterpolator prepares the evaluation of the polynomial through points.
+// Only WithModulus and WithField affect it.
+func NewInterpolator(points []share.Point, opts ...Option) (*Interpolator, error) {
+	if len(points) == 0 {
+		return nil, errNoPoints()
+	}
+	c := newConfig(opts)
+	if c.modulus != nil && c.modulus.Cmp(big.NewInt(2)) < 0 {
+		return nil, fmt.Errorf("invalid modulus %s: must be at least 2", c.modulus.String())
+	}
+
+	if c.field != nil {
+		return newFieldInterpolator(points, c.field)
+	}
+
+	ip := &Interpolator{points: points, modulus: c.modulus}
+	s := newTermScratch()
+	for j, pointJ := range points {
+		s.denominatorOf(points, j, c.modulus)
+
+		if c.modulus != nil {
+			if s.inverse.ModInverse(s.denominator, c.modulus) == nil {
+				return nil, fmt.Errorf("interpolation failed: denominator for x=%s is not invertible modulo %s", pointJ.X.String(), c.modulus.String())
+			}
+			scaled := new(big.Int).Mul(pointJ.Y, s.inverse)
+			ip.scaledMod = append(ip.scaledMod, scaled.Mod(scaled, c.modulus))
+			continue
+		}
+
+		if s.denominator.Sign() == 0 {
+			return nil, fmt.Errorf("interpolation failed: %w leads to division by zero", &share.DuplicateXError{X: pointJ.X})
+		}
+		ip.scaled = append(ip.scaled, new(big.Rat).SetFrac(pointJ.Y, s.denominator))
+	}
+	return ip, nil
+}
+
+// EvaluateAt returns f(x0) using the barycentric formula
+// f(x0) = ∏_i(x0-x_i) · Σ_j y_j·w_j/(x0-x_j).
+func (ip *Interpolator) EvaluateAt(x0 *big.Int) (*big.Int, error) {
+	if ip.field != nil {
+		return ip.evaluateField(x0)
+	}
+	if ip.modulus != nil {
+		return ip.evaluateMod(x0)
+	}
+
+	for _, point := range ip.points {
+		if point.X.Cmp(x0) == 0 {
+			return new(big.Int).Set(point.Y), nil
+		}
+	}
+
+	node := new(big.Int).SetInt64(1)
+	diff := new(big.Int)
+	term := new(big.Rat)
+	sum := new(big.Rat)
+	for j, point := range ip.points {
+		diff.Sub(x0, point.X)
+		node.Mul(node, diff)
+		term.Quo(ip.scaled[j], term.SetInt(diff))
+		sum.Add(sum, term)
+	}
+	sum.Mul(sum, term.SetInt(node))
+
+	if !sum.IsInt() {
+		return nil, &NonIntegerError{What: "f(" + x0.String() + ")", Value: sum}
+	}
+	return new(big.Int).Set(sum.Num()), nil
+}
+
+func (ip *Interpolator) evaluateMod(x0 *big.Int) (*big.Int, error) {
+	modulus := ip.modulus
+	diffs := make([]*big.Int, len(ip.points))
+	for j, point := range ip.points {
+		diffs[j] = new(big.Int).Sub(x0, point.X)
+		diffs[j].Mod(diffs[j], modulus)
+		if diffs[j].Sign() == 0 {
+			return new(big.Int).Mod(point.Y, modulus), nil
+		}
+	}
+
+	node := big.NewInt(1)
+	inverse := new(big.Int)
+	term := new(big.Int)
+	sum := new(big.Int)
+	for j, diff := range diffs {
+		node.Mul(node, diff)
+		node.Mod(node, modulus)
+		if inverse.ModInverse(diff, modulus) == nil {
+			return nil, fmt.Errorf("interpolation failed: x=%s minus x=%s is not invertible modulo %s", x0.String(), ip.points[j].X.String(), modulus.String())
+		}
+		term.Mul(ip.scaledMod[j], inverse)
+		sum.Add(sum, term)
+		sum.Mod(sum, modulus)
+	}
+	sum.Mul(sum, node)
+	return sum.Mod(sum, modulus), nil
+}
+
+func newFieldInterpolator(points []share.Point, f Field) (*Interpolator, error) {
+	if err := checkFieldPoints(points, nil, f); err != nil {
+		return nil, err
+	}
+	ip := &Interpolator{points: points, field: f}
+	for j, pointJ := range points {
+		denominator := big.NewInt(1)
+		for i, pointI := range points {
+			if i == j {
+				continue
+			}
+			diff := f.Sub(pointJ.X, pointI.X)
+			if diff.Sign() == 0 {
+				return nil, fmt.Errorf("interpolation failed: %w leads to division by zero", &share.DuplicateXError{X: pointJ.X})
+			}
+			denominator = f.Mul(denominator, diff)
+		}
+		inverse, err := f.Inverse(denominator)
+		if err != nil {
+			return nil, fmt.Errorf("interpolation failed: %w", err)
+		}
+		ip.scaledField = append(ip.scaledField, f.Mul(pointJ.Y, inverse))
+	}
+	return ip, nil
+}
+
+func (ip *Interpolator) evaluateField(x0 *big.Int) (*big.Int, error) {
+	f := ip.field
+	if !f.Contains(x0) {
+		return nil, fmt.Errorf("x=%s is not an element of %s", x0.String(), f)
+	}
+	diffs := make([]*big.Int, len(ip.points))
+	for j, point := range ip.points {
+		if diffs[j] = f.Sub(x0, point.X); diffs[j].Sign() == 0 {
+			return new(big.Int).Set(point.Y), nil
+		}
+	}
+
+	node := big.NewInt(1)
+	sum := new(big.Int)
+	for j, diff := range diffs {
+		node = f.Mul(node, diff)
+		inverse, err := f.Inverse(diff)
+		if err != nil {
+			return nil, fmt.Errorf("interpolation failed: %w", err)
+		}
+		sum = f.Add(sum, f.Mul(ip.scaledField[j], inverse))
+	}
+	return f.Mul(sum, node), nil
+}
@@ -0,0 +1,43 @@
+package lagrange
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/OmSingh2003/CATALOG-ASSIGNMENT/pkg/share"
+)
+
+// BenchmarkInterpolate runs the term-by-term path on one goroutine, where
+// allocs/op counts the temporaries of the terms, at several k.
+func BenchmarkInterpolate(b *testing.B) {
+	modulus := share.LookupField("secp256k1").Modulus
+	for _, k := range []int{10, 100, 1000, 5000} {
+		rng := rand.New(rand.NewSource(int64(k)))
+		for _, bc := range []struct {
+			name   string
+			points []share.Point
+			opts   []Option
+		}{
+			{"rational", randomPoints(rng, k, 256, nil), nil},
+			{"modular", randomPoints(rng, k, 256, modulus), []Option{WithModulus(modulus)}},
+		} {
+			if bc.opts == nil && k > 1000 {
+				// Rational sums of 5000 terms take tens of seconds.
+				continue
+			}
+			b.Run(fmt.Sprintf("%s/k=%d", bc.name, k), func(b *testing.B) {
+				b.ReportAllocs()
+				opts := append(bc.opts, WithFast(false), WithWorkers(1))
+				for i := 0; i < b.N; i++ {
+					// Random values rarely lie on an integer polynomial.
+					if _, err := InterpolateAt(bc.points, big.NewInt(0), opts...); err != nil && !errors.Is(err, ErrNonIntegerSecret) {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package lagrange
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/OmSingh2003/CATALOG-ASSIGNMENT/pkg/share"
+)
+
+// CorrectErrors decodes the shares as a Reed-Solomon codeword over GF(p),
+// tolerating up to (n-k)/2 corrupted shares. It returns the secret and the x
+// values of the shares that were found to be wrong.
+func CorrectErrors(points []share.Point, k int, modulus *big.Int) (*big.Int, []*big.Int, error) {
+	n := len(points)
+	if k < 1 {
+		return nil, nil, errNoPoints()
+	}
+	if n < k {
+		return nil, nil, &share.InsufficientSharesError{Found: n, Needed: k}
+	}
+	e := (n - k) / 2
+
+	// Unknowns are the k+e coefficients of Q followed by the e low
+	// coefficients of the monic error locator E. Each share gives the row
+	// Q(x_i) - y_i*E_low(x_i) = y_i*x_i^e.
+	unknowns := k + 2*e
+	matrix := make([][]*big.Int, n)
+	for r, point := range points {
+		x := new(big.Int).Mod(point.X, modulus)
+		y := new(big.Int).Mod(point.Y, modulus)
+
+		powers := make([]*big.Int, k+e+1)
+		powers[0] = big.NewInt(1)
+		for c := 1; c < len(powers); c++ {
+			powers[c] = new(big.Int).Mul(powers[c-1], x)
+			powers[c].Mod(powers[c], modulus)
+		}
+
+		row := make([]*big.Int, unknowns+1)
+		for c := 0; c < k+e; c++ {
+			row[c] = powers[c]
+		}
+		for c := 0; c < e; c++ {
+			row[k+e+c] = new(big.Int).Mul(y, powers[c])
+			row[k+e+c].Neg(row[k+e+c]).Mod(row[k+e+c], modulus)
+		}
+		row[unknowns] = new(big.Int).Mul(y, powers[e])
+		row[unknowns].Mod(row[unknowns], modulus)
+		matrix[r] = row
+	}
+
+	solution, err := solveLinearSystemMod(matrix, unknowns, modulus)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	q := solution[:k+e]
+	locator := make([]*big.Int, e+1)
+	copy(locator, solution[k+e:])
+	locator[e] = big.NewInt(1)
+
+	message, remainder := dividePolynomialMod(q, locator, modulus)
+	for _, c := range remainder {
+		if c.Sign() != 0 {
+			return nil, nil, fmt.Errorf("too many corrupted shares to correct: at most %d can be corrected with n=%d, k=%d", e, n, k)
+		}
+	}
+
+	var bad []*big.Int
+	for _, point := range points {
+		y := evaluatePolynomial(message, point.X, modulus)
+		if y.Cmp(new(big.Int).Mod(point.Y, modulus)) != 0 {
+			bad = append(bad, point.X)
+		}
+	}
+	if len(bad) > e {
+		return nil, nil, fmt.Errorf("too many corrupted shares to correct: at most %d can be corrected with n=%d, k=%d", e, n, k)
+	}
+
+	return new(big.Int).Mod(message[0], modulus), bad, nil
+}
+
+// solveLinearSystemMod solves an augmented matrix over GF(p) by Gaussian
+// elimination. Free variables are set to zero.
+func solveLinearSystemMod(matrix [][]*big.Int, unknowns int, modulus *big.Int) ([]*big.Int, error) {
+	pivotCols := make([]int, 0, unknowns)
+	inverse := new(big.Int)
+	scratch := new(big.Int)
+
+	row := 0
+	for col := 0; col < unknowns && row < len(matrix); col++ {
+		pivot := -1
+		for r := row; r < len(matrix); r++ {
+			if matrix[r][col].Sign() != 0 {
+				pivot = r
+				break
+			}
+		}
+		if pivot < 0 {
+			continue
+		}
+		matrix[row], matrix[pivot] = matrix[pivot], matrix[row]
+
+		if inverse.ModInverse(matrix[row][col], modulus) == nil {
+			return nil, fmt.Errorf("modulus %s is not prime: %s has no inverse", modulus.String(), matrix[row][col].String())
+		}
+		for c := col; c <= unknowns; c++ {
+			matrix[row][c].Mul(matrix[row][c], inverse).Mod(matrix[row][c], modulus)
+		}
+
+		for r := range matrix {
+			if r == row || matrix[r][col].Sign() == 0 {
+				continue
+			}
+			factor := new(big.Int).Set(matrix[r][col])
+			for c := col; c <= unknowns; c++ {
+				matrix[r][c].Sub(matrix[r][c], scratch.Mul(factor, matrix[row][c])).Mod(matrix[r][c], modulus)
+			}
+		}
+
+		pivotCols = append(pivotCols, col)
+		row++
+	}
+
+	for r := row; r < len(matrix); r++ {
+		if matrix[r][unknowns].Sign() != 0 {
+			return nil, errors.New("too many corrupted shares to correct: the decoding system is inconsistent")
+		}
+	}
+
+	solution := make([]*big.Int, unknowns)
+	for c := range solution {
+		solution[c] = big.NewInt(0)
+	}
+	for r, col := range pivotCols {
+		solution[col].Set(matrix[r][unknowns])
+	}
+	return solution, nil
+}
+
+// dividePolynomialMod divides numerator by the monic divisor over GF(p).
+// Coefficients are stored lowest degree first.
+func dividePolynomialMod(numerator, divisor []*big.Int, modulus *big.Int) ([]*big.Int, []*big.Int) {
+	remainder := make([]*big.Int, len(numerator))
+	for i, c := range numerator {
+		remainder[i] = new(big.Int).Set(c)
+	}
+
+	degree := len(divisor) - 1
+	if len(numerator) <= degree {
+		return []*big.Int{big.NewInt(0)}, remainder
+	}
+
+	quotient := make([]*big.Int, len(numerator)-degree)
+	scratch := new(big.Int)
+	for i := len(quotient) - 1; i >= 0; i-- {
+		factor := new(big.Int).Set(remainder[i+degree])
+		quotient[i] = factor
+		for j := 0; j <= degree; j++ {
+			remainder[i+j].Sub(remainder[i+j], scratch.Mul(factor, divisor[j])).Mod(remainder[i+j], modulus)
+		}
+	}
+	return quotient, remainder[:degree]
+}
+
+func evaluatePolynomial(coefficients []*big.Int, x *big.Int, modulus *big.Int) *big.Int {
+	y := big.NewInt(0)
+	for i := len(coefficients) - 1; i >= 0; i-- {
+		y.Mul(y, x)
+		y.Add(y, coefficients[i])
+		if modulus != nil {
+			y.Mod(y, modulus)
+		}
+	}
+	return y
+}
@@ -0,0 +1,118 @@
+package lagrange
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/OmSingh2003/CATALOG-ASSIGNMENT/pkg/share"
+)
+
+// EvaluateDerivativeAt returns f'(x0) for the polynomial f through points,
+// summing y_j·ℓ_j'(x0) over the differentiated Lagrange basis instead of
+// computing the coefficients of f. Away from the share x values
+//
+//	ℓ_j'(x0) = ℓ_j(x0) · Σ_{m≠j} 1/(x0-x_m)
+//
+// and at x0 = x_i, where that sum divides by zero,
+//
+//	ℓ_i'(x_i) = Σ_{m≠i} 1/(x_i-x_m)
+//	ℓ_j'(x_i) = ∏_{m≠i,j}(x_i-x_m) / ∏_{m≠j}(x_j-x_m)  for j ≠ i.
+//
+// The arithmetic is exact over the rationals. With WithModulus the
+// coordinates are first reduced modulo p and the result is reduced into
+// [0, p); the other options are ignored.
+func EvaluateDerivativeAt(points []share.Point, x0 *big.Int, opts ...Option) (*big.Int, error) {
+	if len(points) == 0 {
+		return nil, errNoPoints()
+	}
+	c := newConfig(opts)
+	if c.field != nil {
+		return nil, errFieldUnsupported("the derivative", c.field)
+	}
+	modulus := c.modulus
+	if modulus != nil && modulus.Cmp(big.NewInt(2)) < 0 {
+		return nil, fmt.Errorf("invalid modulus %s: must be at least 2", modulus.String())
+	}
+
+	xs := make([]*big.Int, len(points))
+	ys := make([]*big.Int, len(points))
+	at := x0
+	for j, point := range points {
+		xs[j], ys[j] = point.X, point.Y
+		if modulus != nil {
+			xs[j] = new(big.Int).Mod(point.X, modulus)
+			ys[j] = new(big.Int).Mod(point.Y, modulus)
+		}
+	}
+	if modulus != nil {
+		at = new(big.Int).Mod(x0, modulus)
+	}
+
+	// node is the index of the share at x0, or -1.
+	node := -1
+	for j, x := range xs {
+		if x.Cmp(at) == 0 {
+			node = j
+			break
+		}
+	}
+
+	sum := new(big.Rat)
+	diff := new(big.Int)
+	for j := range xs {
+		denominator := big.NewInt(1)
+		for m := range xs {
+			if m == j {
+				continue
+			}
+			if diff.Sub(xs[j], xs[m]).Sign() == 0 {
+				return nil, fmt.Errorf("interpolation failed: %w leads to division by zero", &share.DuplicateXError{X: points[j].X})
+			}
+			denominator.Mul(denominator, diff)
+		}
+
+		basis := new(big.Rat)
+		switch {
+		case node == j:
+			for m := range xs {
+				if m != j {
+					basis.Add(basis, new(big.Rat).SetFrac(big.NewInt(1), diff.Sub(at, xs[m])))
+				}
+			}
+		case node >= 0:
+			numerator := big.NewInt(1)
+			for m := range xs {
+				if m != j && m != node {
+					numerator.Mul(numerator, diff.Sub(at, xs[m]))
+				}
+			}
+			basis.SetFrac(numerator, denominator)
+		default:
+			numerator := big.NewInt(1)
+			reciprocals := new(big.Rat)
+			for m := range xs {
+				if m != j {
+					diff.Sub(at, xs[m])
+					numerator.Mul(numerator, diff)
+					reciprocals.Add(reciprocals, new(big.Rat).SetFrac(big.NewInt(1), diff))
+				}
+			}
+			basis.SetFrac(numerator, denominator)
+			basis.Mul(basis, reciprocals)
+		}
+		sum.Add(sum, basis.Mul(basis, new(big.Rat).SetInt(ys[j])))
+	}
+
+	if modulus != nil {
+		inverse := new(big.Int).ModInverse(sum.Denom(), modulus)
+		if inverse == nil {
+			return nil, fmt.Errorf("interpolation failed: the derivative at x=%s divides by %s, which is not invertible modulo %s", x0.String(), sum.Denom().String(), modulus.String())
+		}
+		result := new(big.Int).Mul(sum.Num(), inverse)
+		return result.Mod(result, modulus), nil
+	}
+	if !sum.IsInt() {
+		return nil, &NonIntegerError{What: "f'(" + x0.String() + ")", Value: sum}
+	}
+	return new(big.Int).Set(sum.Num()), nil
+}
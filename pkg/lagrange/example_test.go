@@ -0,0 +1,64 @@
+package lagrange_test
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/OmSingh2003/CATALOG-ASSIGNMENT/pkg/lagrange"
+	"github.com/OmSingh2003/CATALOG-ASSIGNMENT/pkg/share"
+)
+
+func ExampleInterpolate() {
+	// f(x) = 3 + x^2 through x = 1, 2, 3.
+	points := []share.Point{
+		{X: big.NewInt(1), Y: big.NewInt(4)},
+		{X: big.NewInt(2), Y: big.NewInt(7)},
+		{X: big.NewInt(3), Y: big.NewInt(12)},
+	}
+	secret, err := lagrange.Interpolate(points)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(secret)
+	// Output: 3
+}
+
+func ExampleInterpolateAt() {
+	// f(x) = 5 + 3x mod 7 through x = 1, 2, evaluated at the next share.
+	points := []share.Point{
+		{X: big.NewInt(1), Y: big.NewInt(1)},
+		{X: big.NewInt(2), Y: big.NewInt(4)},
+	}
+	y, err := lagrange.InterpolateAt(points, big.NewInt(3), lagrange.WithModulus(big.NewInt(7)))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(y)
+	// Output: 0
+}
+
+func ExampleCorrectErrors() {
+	// f(x) = 5 + 3x mod 7, with the share at x = 3 corrupted.
+	shares, err := share.ParseShares(strings.NewReader(`{
+		"keys": {"n": 5, "k": 2},
+		"1": {"base": "10", "value": "1"},
+		"2": {"base": "10", "value": "4"},
+		"3": {"base": "10", "value": "9"},
+		"4": {"base": "10", "value": "3"},
+		"5": {"base": "10", "value": "6"}
+	}`))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	secret, bad, err := lagrange.CorrectErrors(shares.Points, shares.K, big.NewInt(7))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(secret, bad)
+	// Output: 5 [3]
+}
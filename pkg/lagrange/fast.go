@@ -0,0 +1,503 @@
+package lagrange
+
+import (
+	"math/big"
+
+	"github.com/OmSingh2003/CATALOG-ASSIGNMENT/pkg/share"
+)
+
+// FastThreshold is the number of points from which InterpolateAt switches
+// to the product-tree algorithm modulo a prime, unless WithFast says
+// otherwise. Over the rationals it is never chosen by itself: math/big
+// multiplies by Karatsuba, not FFT, and with coefficients that grow with
+// every level of the trees the term-by-term path stays faster.
+const FastThreshold = 1024
+
+// WithFast forces the product-tree algorithm on, or off, whatever the
+// number of points and field. Both algorithms give the same results and
+// errors. The product-tree algorithm runs on one goroutine.
+func WithFast(enabled bool) Option {
+	return func(c *config) {
+		c.fast = &enabled
+	}
+}
+
+func (c *config) useFast(n int) bool {
+	if c.fast != nil {
+		return *c.fast
+	}
+	return c.modulus != nil && n >= FastThreshold
+}
+
+// The product-tree algorithm evaluates f(x0) as
+//
+//	f(x0) = M(x0) · Σ_j y_j / ((x0-x_j)·M'(x_j)),  M(x) = ∏_i(x-x_i),
+//
+// where M'(x_j) = ∏_{i≠j}(x_j-x_i) is the denominator of term j. M is built
+// by a product tree of the linear factors and M' is evaluated at every x_j
+// by a remainder tree, with polynomial products done as single big-integer
+// products by Kronecker substitution and divisions by Newton inversion, so
+// that the cost grows quasi-linearly with the size of the denominators
+// instead of with k times it. Over the rationals the terms are summed as
+// fractions by a tree as well, and divided once at the end.
+//
+// Inputs the term-by-term path rejects, duplicate x values, denominators
+// that are not invertible and a modulus below 2, are handed to that path,
+// so that the errors are the same too.
+
+// polyBaseCase is the size below which polynomials are multiplied and
+// divided by the schoolbook methods.
+const polyBaseCase = 64
+
+// leafSize is the number of points below which the remainder tree stops
+// and evaluates the remainder at each point directly.
+const leafSize = 32
+
+// poly is a polynomial with integer coefficients, lowest degree first.
+type poly []*big.Int
+
+// interpolateFast returns f(x0) by the product-tree algorithm, and false
+// if the points are ones it leaves to the term-by-term path.
+func interpolateFast(points []share.Point, x0, modulus *big.Int, progress *progress) (*big.Int, bool, error) {
+	if len(points) == 0 || modulus != nil && modulus.Cmp(big.NewInt(2)) < 0 {
+		return nil, false, nil
+	}
+	xs := make([]*big.Int, len(points))
+	for i, point := range points {
+		xs[i] = point.X
+		if modulus != nil {
+			xs[i] = new(big.Int).Mod(point.X, modulus)
+		}
+	}
+	at := x0
+	if modulus != nil {
+		at = new(big.Int).Mod(x0, modulus)
+	}
+
+	if err := progress.check(); err != nil {
+		return nil, true, err
+	}
+	tree := productTree(xs, modulus)
+	root := tree[len(tree)-1][0]
+	denominators := make([]*big.Int, len(points))
+	if err := remainderTree(derivative(root, modulus), tree, len(tree)-1, 0, xs, denominators, modulus, progress); err != nil {
+		return nil, true, err
+	}
+	var inverses []*big.Int
+	if modulus != nil {
+		if inverses = batchInverse(denominators, modulus); inverses == nil {
+			return nil, false, nil
+		}
+	} else {
+		for _, d := range denominators {
+			if d.Sign() == 0 {
+				return nil, false, nil
+			}
+		}
+	}
+
+	// At a node the polynomial is the y of that node.
+	for i, x := range xs {
+		if x.Cmp(at) == 0 {
+			y := new(big.Int).Set(points[i].Y)
+			if modulus != nil {
+				y.Mod(y, modulus)
+			}
+			return y, true, nil
+		}
+	}
+
+	// factors[j] = x0-x_j, and their product is M(x0).
+	factors := make([]*big.Int, len(points))
+	for j, x := range xs {
+		factors[j] = new(big.Int).Sub(at, x)
+	}
+
+	if modulus != nil {
+		// The numerator of term j, ∏_{i≠j}(x0-x_i), is the product of the
+		// factors before j and those after it, so no factor is inverted.
+		suffix := make([]*big.Int, len(points)+1)
+		suffix[len(points)] = big.NewInt(1)
+		for j := len(points) - 1; j >= 0; j-- {
+			suffix[j] = mulMod(suffix[j+1], factors[j], modulus)
+		}
+		prefix := big.NewInt(1)
+		sum := new(big.Int)
+		term := new(big.Int)
+		for j, point := range points {
+			if err := progress.check(); err != nil {
+				return nil, true, err
+			}
+			term.Mul(point.Y, mulMod(prefix, suffix[j+1], modulus))
+			term.Mod(term, modulus)
+			sum.Add(sum, term.Mul(term, inverses[j]))
+			sum.Mod(sum, modulus)
+			prefix = mulMod(prefix, factors[j], modulus)
+		}
+		return sum, true, nil
+	}
+
+	dens := make([]*big.Int, len(points))
+	for j := range points {
+		dens[j] = new(big.Int).Mul(factors[j], denominators[j])
+		progress.step()
+	}
+	if err := progress.check(); err != nil {
+		return nil, true, err
+	}
+	ys := make([]*big.Int, len(points))
+	for j, point := range points {
+		ys[j] = point.Y
+	}
+	num, den := sumFractions(ys, dens)
+	num.Mul(num, productOf(factors, nil))
+	if den.Sign() < 0 {
+		num.Neg(num)
+		den.Neg(den)
+	}
+	secretC, remainder := new(big.Int).QuoRem(num, den, new(big.Int))
+	if remainder.Sign() != 0 {
+		return nil, true, nonInteger(x0, num, den)
+	}
+	return secretC, true, nil
+}
+
+// productTree returns the levels of the product tree of the factors x-x_i:
+// level 0 holds each factor, and every polynomial of a level above is the
+// product of two neighbours below it, or a copy of the last one if a level
+// has an odd length. The last level holds M alone.
+func productTree(xs []*big.Int, modulus *big.Int) [][]poly {
+	level := make([]poly, len(xs))
+	for i, x := range xs {
+		c := new(big.Int).Neg(x)
+		if modulus != nil {
+			c.Mod(c, modulus)
+		}
+		level[i] = poly{c, big.NewInt(1)}
+	}
+	tree := [][]poly{level}
+	for len(level) > 1 {
+		next := make([]poly, (len(level)+1)/2)
+		for i := range next {
+			if 2*i+1 < len(level) {
+				next[i] = polyMul(level[2*i], level[2*i+1], modulus)
+			} else {
+				next[i] = level[2*i]
+			}
+		}
+		tree = append(tree, next)
+		level = next
+	}
+	return tree
+}
+
+// remainderTree evaluates f at the points below node i of level l of the
+// tree, writing f(x_j) to values[j]. f has a lower degree than the
+// polynomial of the node.
+func remainderTree(f poly, tree [][]poly, l, i int, xs, values []*big.Int, modulus *big.Int, progress *progress) error {
+	if err := progress.check(); err != nil {
+		return err
+	}
+	// The node covers the points [lo, hi).
+	lo, hi := i<<l, min((i+1)<<l, len(xs))
+	if hi-lo <= leafSize || l == 0 {
+		for j := lo; j < hi; j++ {
+			values[j] = evalPoly(f, xs[j], modulus)
+			progress.step()
+		}
+		return nil
+	}
+	below := tree[l-1]
+	for c := 2 * i; c <= 2*i+1 && c < len(below); c++ {
+		if err := remainderTree(polyMod(f, below[c], modulus), tree, l-1, c, xs, values, modulus, progress); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// derivative returns f'.
+func derivative(f poly, modulus *big.Int) poly {
+	d := make(poly, max(1, len(f)-1))
+	d[0] = new(big.Int)
+	for i := 1; i < len(f); i++ {
+		d[i-1] = new(big.Int).Mul(f[i], big.NewInt(int64(i)))
+		if modulus != nil {
+			d[i-1].Mod(d[i-1], modulus)
+		}
+	}
+	return d
+}
+
+// evalPoly returns f(x) by Horner's rule.
+func evalPoly(f poly, x, modulus *big.Int) *big.Int {
+	v := new(big.Int)
+	for i := len(f) - 1; i >= 0; i-- {
+		v.Mul(v, x)
+		v.Add(v, f[i])
+		if modulus != nil {
+			v.Mod(v, modulus)
+		}
+	}
+	return v
+}
+
+// polyMod returns f mod g for a monic g of lower degree than f, or f when
+// its degree is already lower.
+func polyMod(f, g poly, modulus *big.Int) poly {
+	m := len(g) - 1
+	if len(f) <= m {
+		return f
+	}
+	if m < polyBaseCase || len(f)-m < polyBaseCase {
+		return polyModSchool(f, g, modulus)
+	}
+
+	// With rev(p) the coefficients of p reversed, the quotient q of f by g
+	// satisfies rev(q) = rev(f)·rev(g)⁻¹ mod x^(n-m), where rev(g) has
+	// constant term 1 since g is monic.
+	s := len(f) - m
+	inverse := seriesInverse(reverse(g), s, modulus)
+	revQ := truncate(polyMul(truncate(reverse(f), s), inverse, modulus), s)
+	q := make(poly, s)
+	for i := range q {
+		if s-1-i < len(revQ) {
+			q[i] = revQ[s-1-i]
+		} else {
+			q[i] = new(big.Int)
+		}
+	}
+	qg := polyMul(q, g, modulus)
+	r := make(poly, m)
+	for i := range r {
+		r[i] = new(big.Int).Sub(f[i], coefficient(qg, i))
+		if modulus != nil {
+			r[i].Mod(r[i], modulus)
+		}
+	}
+	return r
+}
+
+func polyModSchool(f, g poly, modulus *big.Int) poly {
+	m := len(g) - 1
+	r := make(poly, len(f))
+	for i, c := range f {
+		r[i] = new(big.Int).Set(c)
+	}
+	t := new(big.Int)
+	for d := len(r) - 1; d >= m; d-- {
+		lead := r[d]
+		if lead.Sign() == 0 {
+			continue
+		}
+		for i := 0; i < m; i++ {
+			r[d-m+i].Sub(r[d-m+i], t.Mul(lead, g[i]))
+			if modulus != nil {
+				r[d-m+i].Mod(r[d-m+i], modulus)
+			}
+		}
+	}
+	return r[:m]
+}
+
+// seriesInverse returns h with f·h = 1 mod x^s, for f with constant term
+// 1, by the Newton iteration h ← h·(2 - f·h), which doubles the number of
+// correct coefficients each step.
+func seriesInverse(f poly, s int, modulus *big.Int) poly {
+	h := poly{big.NewInt(1)}
+	for l := 1; l < s; {
+		l = min(2*l, s)
+		e := truncate(polyMul(truncate(f, l), h, modulus), l)
+		for i := range e {
+			e[i].Neg(e[i])
+		}
+		e[0].Add(e[0], big.NewInt(2))
+		h = truncate(polyMul(h, e, modulus), l)
+	}
+	return h
+}
+
+func reverse(f poly) poly {
+	r := make(poly, len(f))
+	for i, c := range f {
+		r[len(f)-1-i] = c
+	}
+	return r
+}
+
+// truncate returns f mod x^n, sharing the coefficients of f.
+func truncate(f poly, n int) poly {
+	if len(f) > n {
+		return f[:n]
+	}
+	return f
+}
+
+func coefficient(f poly, i int) *big.Int {
+	if i < len(f) {
+		return f[i]
+	}
+	return new(big.Int)
+}
+
+// polyMul returns a·b, with coefficients reduced modulo modulus if it is
+// non-nil.
+func polyMul(a, b poly, modulus *big.Int) poly {
+	if len(a) == 0 || len(b) == 0 {
+		return poly{}
+	}
+	if min(len(a), len(b)) < polyBaseCase {
+		return polyMulSchool(a, b, modulus)
+	}
+	return polyMulKronecker(a, b, modulus)
+}
+
+func polyMulSchool(a, b poly, modulus *big.Int) poly {
+	c := make(poly, len(a)+len(b)-1)
+	for i := range c {
+		c[i] = new(big.Int)
+	}
+	t := new(big.Int)
+	for i, x := range a {
+		if x.Sign() == 0 {
+			continue
+		}
+		for j, y := range b {
+			c[i+j].Add(c[i+j], t.Mul(x, y))
+		}
+	}
+	if modulus != nil {
+		for _, v := range c {
+			v.Mod(v, modulus)
+		}
+	}
+	return c
+}
+
+// polyMulKronecker multiplies a and b as the integers a(2^w) and b(2^w),
+// with w wide enough that no coefficient of the product overlaps the next.
+// Coefficients may be negative; they are packed and unpacked as signed
+// digits.
+func polyMulKronecker(a, b poly, modulus *big.Int) poly {
+	bits := maxBits(a) + maxBits(b) + big.NewInt(int64(min(len(a), len(b)))).BitLen() + 2
+	width := (bits + 7) / 8
+	product := new(big.Int).Mul(pack(a, width), pack(b, width))
+	c := unpack(product, len(a)+len(b)-1, width)
+	if modulus != nil {
+		for _, v := range c {
+			v.Mod(v, modulus)
+		}
+	}
+	return c
+}
+
+func maxBits(f poly) int {
+	bits := 0
+	for _, c := range f {
+		bits = max(bits, c.BitLen())
+	}
+	return bits
+}
+
+// pack returns f(2^(8·width)) for coefficients of fewer than 8·width-1 bits.
+func pack(f poly, width int) *big.Int {
+	positive := make([]byte, len(f)*width)
+	negative := make([]byte, len(f)*width)
+	abs := new(big.Int)
+	for i, c := range f {
+		slot := positive
+		if c.Sign() < 0 {
+			slot = negative
+		}
+		end := (len(f) - i) * width
+		abs.Abs(c).FillBytes(slot[end-width : end])
+	}
+	v := new(big.Int).SetBytes(positive)
+	return v.Sub(v, new(big.Int).SetBytes(negative))
+}
+
+// unpack splits v into n signed digits of 8·width bits, lowest first.
+func unpack(v *big.Int, n, width int) poly {
+	negative := v.Sign() < 0
+	buf := new(big.Int).Abs(v).FillBytes(make([]byte, (n+1)*width))
+	half := new(big.Int).Lsh(big.NewInt(1), uint(8*width-1))
+	full := new(big.Int).Lsh(half, 1)
+	c := make(poly, n)
+	carry := false
+	for i := range c {
+		end := len(buf) - i*width
+		d := new(big.Int).SetBytes(buf[end-width : end])
+		if carry {
+			d.Add(d, big.NewInt(1))
+		}
+		carry = d.Cmp(half) >= 0
+		if carry {
+			d.Sub(d, full)
+		}
+		if negative {
+			d.Neg(d)
+		}
+		c[i] = d
+	}
+	return c
+}
+
+// productOf returns the product of values, by a balanced tree so that the
+// operands of each multiplication have similar sizes.
+func productOf(values []*big.Int, modulus *big.Int) *big.Int {
+	switch len(values) {
+	case 0:
+		return big.NewInt(1)
+	case 1:
+		v := new(big.Int).Set(values[0])
+		if modulus != nil {
+			v.Mod(v, modulus)
+		}
+		return v
+	}
+	mid := len(values) / 2
+	v := new(big.Int).Mul(productOf(values[:mid], modulus), productOf(values[mid:], modulus))
+	if modulus != nil {
+		v.Mod(v, modulus)
+	}
+	return v
+}
+
+// sumFractions returns the numerator and denominator of Σ_j a_j/b_j,
+// adding neighbours pairwise so that the operands stay balanced.
+func sumFractions(a, b []*big.Int) (*big.Int, *big.Int) {
+	if len(a) == 1 {
+		return new(big.Int).Set(a[0]), new(big.Int).Set(b[0])
+	}
+	mid := len(a) / 2
+	n1, d1 := sumFractions(a[:mid], b[:mid])
+	n2, d2 := sumFractions(a[mid:], b[mid:])
+	n1.Mul(n1, d2)
+	n1.Add(n1, n2.Mul(n2, d1))
+	return n1, d1.Mul(d1, d2)
+}
+
+func mulMod(a, b, modulus *big.Int) *big.Int {
+	v := new(big.Int).Mul(a, b)
+	return v.Mod(v, modulus)
+}
+
+// batchInverse returns the inverses of values modulo modulus with one
+// modular inversion, or nil if any is not invertible.
+func batchInverse(values []*big.Int, modulus *big.Int) []*big.Int {
+	prefix := make([]*big.Int, len(values)+1)
+	prefix[0] = big.NewInt(1)
+	for i, v := range values {
+		prefix[i+1] = mulMod(prefix[i], v, modulus)
+	}
+	inverse := new(big.Int).ModInverse(prefix[len(values)], modulus)
+	if inverse == nil {
+		return nil
+	}
+	inverses := make([]*big.Int, len(values))
+	for i := len(values) - 1; i >= 0; i-- {
+		inverses[i] = mulMod(inverse, prefix[i], modulus)
+		inverse = mulMod(inverse, values[i], modulus)
+	}
+	return inverses
+}
@@ -0,0 +1,138 @@
+package lagrange
+
+import (
+	"fmt"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/OmSingh2003/CATALOG-ASSIGNMENT/pkg/share"
+)
+
+// TestFastMatchesNaive compares the product-tree algorithm with the
+// term-by-term path on random shares, up to k=2000 over a prime field and
+// k=300 over the rationals, at 0, at a random x0 and at a node. Over the
+// rationals the product tree is slower than the terms, seconds at k=1000,
+// which is why it is never chosen there unless forced.
+func TestFastMatchesNaive(t *testing.T) {
+	rng := rand.New(rand.NewSource(84))
+	modulus := share.LookupField("secp256k1").Modulus
+	sizes := []int{1, 2, 3, leafSize, leafSize + 1, polyBaseCase + 1, 300, FastThreshold, 2000}
+	rationalSizes := []int{1, 2, 3, leafSize + 1, polyBaseCase + 1, 300}
+	if testing.Short() {
+		sizes = sizes[:7]
+	}
+	for _, tc := range []struct {
+		name  string
+		sizes []int
+		gen   func(k int) []share.Point
+		opts  []Option
+	}{
+		{"modular", sizes, func(k int) []share.Point { return randomPoints(rng, k, 256, modulus) }, []Option{WithModulus(modulus)}},
+		{"rational", rationalSizes, func(k int) []share.Point { return randomPoints(rng, k, 64, nil) }, nil},
+		{"integer secret", rationalSizes, func(k int) []share.Point {
+			ps, _ := polynomialPoints(rng, k, 64)
+			return ps
+		}, nil},
+	} {
+		for _, k := range tc.sizes {
+			ps := tc.gen(k)
+			for _, x0 := range []*big.Int{big.NewInt(0), big.NewInt(int64(rng.Intn(1000) - 500)), ps[rng.Intn(k)].X} {
+				naive, naiveErr := InterpolateAt(ps, x0, append(tc.opts, WithFast(false))...)
+				fast, fastErr := InterpolateAt(ps, x0, append(tc.opts, WithFast(true))...)
+				checkSame(t, fmt.Sprintf("%s k=%d f(%s)", tc.name, k, x0), naive, fast, naiveErr, fastErr)
+			}
+		}
+	}
+}
+
+// TestFastFallsBack covers the inputs the product-tree algorithm hands to
+// the term-by-term path, which must then give its errors.
+func TestFastFallsBack(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		points  []share.Point
+		modulus int64
+	}{
+		{"duplicate x", points(1, 2, 1, 3), 0},
+		{"duplicate x modulo p", points(1, 2, 8, 3), 7},
+		{"modulus below 2", points(1, 2, 2, 3), 1},
+	} {
+		var opts []Option
+		if tc.modulus != 0 {
+			opts = append(opts, WithModulus(big.NewInt(tc.modulus)))
+		}
+		naive, naiveErr := Interpolate(tc.points, append(opts, WithFast(false))...)
+		fast, fastErr := Interpolate(tc.points, append(opts, WithFast(true))...)
+		if naiveErr == nil {
+			t.Errorf("%s: naive path returned %s, want an error", tc.name, naive)
+		}
+		checkSame(t, tc.name, naive, fast, naiveErr, fastErr)
+	}
+}
+
+func TestPolyArithmetic(t *testing.T) {
+	rng := rand.New(rand.NewSource(840))
+	modulus := share.LookupField("mersenne127").Modulus
+	random := func(n int, m *big.Int) poly {
+		f := make(poly, n)
+		for i := range f {
+			f[i] = new(big.Int).Rand(rng, modulus)
+			if m == nil && rng.Intn(2) == 0 {
+				f[i].Neg(f[i])
+			}
+		}
+		return f
+	}
+	for _, m := range []*big.Int{modulus, nil} {
+		for _, n := range []int{1, polyBaseCase - 1, polyBaseCase + 1, 3 * polyBaseCase} {
+			a, b := random(n, m), random(n/2+1, m)
+			if got, want := polyMulKronecker(a, b, m), polyMulSchool(a, b, m); !polyEqual(got, want) {
+				t.Errorf("modulus %v, n=%d: Kronecker product differs from the schoolbook product", m, n)
+			}
+			if m == nil {
+				continue
+			}
+			// polyMod divides by monic polynomials, as the tree's are.
+			b[len(b)-1] = big.NewInt(1)
+			if got, want := polyMod(a, b, m), polyModSchool(a, b, m); !polyEqual(got, want) {
+				t.Errorf("n=%d: remainder differs from the schoolbook remainder", n)
+			}
+		}
+	}
+}
+
+// polyEqual reports whether f and g are the same polynomial, ignoring
+// zero leading coefficients.
+func polyEqual(f, g poly) bool {
+	for i := 0; i < max(len(f), len(g)); i++ {
+		if coefficient(f, i).Cmp(coefficient(g, i)) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// BenchmarkFast compares the product-tree algorithm with the term-by-term
+// path over a prime field as k doubles. The terms grow quadratically and
+// the product tree more slowly, overtaking them near FastThreshold.
+func BenchmarkFast(b *testing.B) {
+	modulus := share.LookupField("secp256k1").Modulus
+	for _, k := range []int{500, 1000, 2000, 4000} {
+		ps := randomPoints(rand.New(rand.NewSource(int64(k))), k, 256, modulus)
+		for _, fast := range []bool{true, false} {
+			name := "naive"
+			if fast {
+				name = "fast"
+			}
+			b.Run(fmt.Sprintf("%s/k=%d", name, k), func(b *testing.B) {
+				opts := []Option{WithModulus(modulus), WithFast(fast), WithWorkers(1)}
+				for i := 0; i < b.N; i++ {
+					if _, err := Interpolate(ps, opts...); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
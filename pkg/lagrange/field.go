@@ -0,0 +1,80 @@
+package lagrange
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/OmSingh2003/CATALOG-ASSIGNMENT/pkg/share"
+)
+
+// Field is the arithmetic of a finite field other than the integers modulo
+// a prime, such as the binary fields of package gf2m, whose elements are
+// held as big.Ints. The operations return new values.
+type Field interface {
+	fmt.Stringer
+	Contains(a *big.Int) bool
+	Add(a, b *big.Int) *big.Int
+	Sub(a, b *big.Int) *big.Int
+	Mul(a, b *big.Int) *big.Int
+	Inverse(a *big.Int) (*big.Int, error)
+}
+
+// WithField performs all arithmetic in f instead of over the rationals.
+// InterpolateAt, with Interpolate and Verify, NewInterpolator and Vote
+// support it; the other algorithms fail with it, as do WithModulus and
+// WithTrace.
+func WithField(f Field) Option {
+	return func(c *config) {
+		c.field = f
+	}
+}
+
+// errFieldUnsupported reports an algorithm unavailable over f.
+func errFieldUnsupported(what string, f Field) error {
+	return fmt.Errorf("%s is not supported over %s", what, f)
+}
+
+// checkFieldPoints reports the first coordinate of points or x0 that is not
+// an element of f.
+func checkFieldPoints(points []share.Point, x0 *big.Int, f Field) error {
+	if x0 != nil && !f.Contains(x0) {
+		return fmt.Errorf("x=%s is not an element of %s", x0.String(), f)
+	}
+	for _, point := range points {
+		if !f.Contains(point.X) || !f.Contains(point.Y) {
+			return fmt.Errorf("share x=%s is not an element of %s", point.X.String(), f)
+		}
+	}
+	return nil
+}
+
+func interpolateField(points []share.Point, x0 *big.Int, f Field, progress *progress) (*big.Int, error) {
+	if err := checkFieldPoints(points, x0, f); err != nil {
+		return nil, err
+	}
+	sum := new(big.Int)
+	for j, pointJ := range points {
+		if err := progress.check(); err != nil {
+			return nil, err
+		}
+		numerator, denominator := big.NewInt(1), big.NewInt(1)
+		for i, pointI := range points {
+			if i == j {
+				continue
+			}
+			diff := f.Sub(pointJ.X, pointI.X)
+			if diff.Sign() == 0 {
+				return nil, fmt.Errorf("interpolation failed: %w leads to division by zero", &share.DuplicateXError{X: pointJ.X})
+			}
+			numerator = f.Mul(numerator, f.Sub(x0, pointI.X))
+			denominator = f.Mul(denominator, diff)
+		}
+		inverse, err := f.Inverse(denominator)
+		if err != nil {
+			return nil, fmt.Errorf("interpolation failed: %w", err)
+		}
+		sum = f.Add(sum, f.Mul(pointJ.Y, f.Mul(numerator, inverse)))
+		progress.step()
+	}
+	return sum, nil
+}
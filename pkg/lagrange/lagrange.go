@@ -0,0 +1,467 @@
+// Package lagrange reconstructs Shamir secrets by Lagrange interpolation,
+// either exactly over the rationals, over a prime field, or over another
+// Field such as GF(2^m). Share
+// coordinates may be negative; modular results are always reduced into
+// [0, p).
+package lagrange
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/OmSingh2003/CATALOG-ASSIGNMENT/pkg/share"
+)
+
+// ErrNonIntegerSecret is matched by the errors returned when exact
+// interpolation yields a fraction, which means the shares do not lie on one
+// integer polynomial.
+var ErrNonIntegerSecret = errors.New("not an integer")
+
+// NonIntegerError reports the fraction exact interpolation yielded for
+// What, as in "f(0)" or "coefficient of x^2". It matches
+// ErrNonIntegerSecret.
+type NonIntegerError struct {
+	What  string
+	Value *big.Rat
+}
+
+func (e *NonIntegerError) Error() string {
+	return fmt.Sprintf("interpolation failed: %s is %v (%s)", e.What, ErrNonIntegerSecret, e.Value.RatString())
+}
+
+func (e *NonIntegerError) Is(target error) bool {
+	return target == ErrNonIntegerSecret
+}
+
+// Option configures an interpolation.
+type Option func(*config)
+
+type config struct {
+	modulus *big.Int
+	field   Field
+	workers int
+	trace   func(Term)
+	fast    *bool
+
+	progress func(done, total int)
+	ctx      context.Context
+}
+
+// WithModulus performs all arithmetic modulo the prime p instead of over the
+// rationals.
+func WithModulus(p *big.Int) Option {
+	return func(c *config) {
+		c.modulus = p
+	}
+}
+
+// WithWorkers computes the terms of an interpolation on up to n goroutines.
+// The default, n <= 0, uses GOMAXPROCS; n = 1 forces the serial loop. The
+// result does not depend on n.
+func WithWorkers(n int) Option {
+	return func(c *config) {
+		c.workers = n
+	}
+}
+
+// WithProgress makes InterpolateAt call fn as it works, with the number of
+// steps done and the total: one per term modulo a prime, and over the
+// rationals one per denominator and one per term. The calls never overlap,
+// even when the terms are computed on several goroutines.
+func WithProgress(fn func(done, total int)) Option {
+	return func(c *config) {
+		c.progress = fn
+	}
+}
+
+// WithContext makes InterpolateAt check ctx before every step counted by
+// WithProgress and, once ctx is done, return its error wrapped with how
+// many steps were done.
+func WithContext(ctx context.Context) Option {
+	return func(c *config) {
+		c.ctx = ctx
+	}
+}
+
+// Arithmetic returns the modulus of WithModulus and the field of WithField
+// that opts set, both nil for arithmetic over the rationals.
+func Arithmetic(opts ...Option) (*big.Int, Field) {
+	c := newConfig(opts)
+	return c.modulus, c.field
+}
+
+func newConfig(opts []Option) *config {
+	c := &config{}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Interpolate returns the secret f(0) of the polynomial through points.
+// A single point, the share of a 1-of-n split, is the secret itself.
+func Interpolate(points []share.Point, opts ...Option) (*big.Int, error) {
+	return InterpolateAt(points, big.NewInt(0), opts...)
+}
+
+// InterpolateAt returns f(x0) for the polynomial through points. A single
+// point determines the constant polynomial f = y, so its y is returned for
+// every x0, reduced modulo a prime. No points at all, as a threshold of 0
+// would select, is an InsufficientSharesError.
+func InterpolateAt(points []share.Point, x0 *big.Int, opts ...Option) (*big.Int, error) {
+	c := newConfig(opts)
+	if len(points) == 0 {
+		return nil, errNoPoints()
+	}
+	if c.field != nil {
+		if c.modulus != nil || c.trace != nil {
+			return nil, errFieldUnsupported("WithModulus or WithTrace", c.field)
+		}
+		return interpolateField(points, x0, c.field, c.newProgress(len(points)))
+	}
+	if c.trace != nil {
+		if err := trace(points, x0, c.modulus, c.trace); err != nil {
+			return nil, err
+		}
+	}
+	if len(points) == 1 {
+		if c.modulus == nil {
+			return new(big.Int).Set(points[0].Y), nil
+		}
+		if c.modulus.Cmp(big.NewInt(2)) < 0 {
+			return nil, fmt.Errorf("invalid modulus %s: must be at least 2", c.modulus.String())
+		}
+		return new(big.Int).Mod(points[0].Y, c.modulus), nil
+	}
+	if c.useFast(len(points)) {
+		total := 2 * len(points)
+		if c.modulus != nil {
+			total = len(points)
+		}
+		if secret, ok, err := interpolateFast(points, x0, c.modulus, c.newProgress(total)); ok {
+			return secret, err
+		}
+	}
+	if c.modulus != nil {
+		return interpolateMod(points, x0, c.modulus, c.termWorkers(len(points)), c.newProgress(len(points)))
+	}
+	return interpolateRational(points, x0, c.termWorkers(len(points)), c.newProgress(2*len(points)))
+}
+
+func interpolateRational(points []share.Point, x0 *big.Int, workers int, progress *progress) (*big.Int, error) {
+	if len(points) == 0 {
+		return nil, errNoPoints()
+	}
+
+	// Over the integers the numerators grow with every factor, so they are
+	// all derived from a single product. The modular path keeps its
+	// products reduced and does not need this.
+	product := nodeProduct(points, x0)
+
+	scratch := make([]termScratch, workers)
+	for w := range scratch {
+		scratch[w] = newTermScratch()
+	}
+
+	// Rather than adding k fractions, each normalised by a GCD, the terms
+	// are brought over the common denominator lcm_j ∏_{i≠j}(x_j-x_i) and
+	// divided once at the end.
+	denominators := make([]*big.Int, len(points))
+	store := make([]big.Int, len(points))
+	err := forEachTerm(len(points), workers, func(w, j int) error {
+		if err := progress.check(); err != nil {
+			return err
+		}
+		s := &scratch[w]
+		s.denominatorOf(points, j, nil)
+		if s.denominator.Sign() == 0 {
+			return fmt.Errorf("interpolation failed: %w leads to division by zero", &share.DuplicateXError{X: points[j].X})
+		}
+		denominators[j] = store[j].Set(s.denominator)
+		progress.step()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	common := big.NewInt(1)
+	gcd := new(big.Int)
+	magnitude := new(big.Int)
+	s := &scratch[0]
+	for _, d := range denominators {
+		magnitude.Abs(d)
+		gcd.GCD(nil, nil, common, magnitude)
+		s.quotient.QuoRem(common, gcd, s.spare)
+		common.Mul(s.quotient, magnitude)
+	}
+
+	// Every worker sums its own terms; integer addition makes the total
+	// independent of how the terms were split.
+	sums := make([]*big.Int, workers)
+	for w := range sums {
+		sums[w] = new(big.Int)
+	}
+	err = forEachTerm(len(points), workers, func(w, j int) error {
+		if err := progress.check(); err != nil {
+			return err
+		}
+		s := &scratch[w]
+		s.numeratorOf(points, j, x0, product, nil)
+		s.cofactor.QuoRem(common, denominators[j], s.spare)
+		s.termNumerator.Mul(points[j].Y, s.numerator)
+		s.mul(&s.termNumerator, s.cofactor)
+		sums[w].Add(sums[w], s.termNumerator)
+		progress.step()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	total := new(big.Int)
+	for _, sum := range sums {
+		total.Add(total, sum)
+	}
+
+	secretC, remainder := new(big.Int).QuoRem(total, common, new(big.Int))
+	if remainder.Sign() != 0 {
+		return nil, nonInteger(x0, total, common)
+	}
+	return secretC, nil
+}
+
+// errNoPoints is returned for an interpolation without points, which
+// leaves the polynomial undetermined.
+func errNoPoints() error {
+	return &share.InsufficientSharesError{Found: 0, Needed: 1, Context: "to interpolate"}
+}
+
+func nonInteger(x0, numerator, denominator *big.Int) error {
+	return &NonIntegerError{What: "f(" + x0.String() + ")", Value: new(big.Rat).SetFrac(numerator, denominator)}
+}
+
+func interpolateMod(points []share.Point, x0 *big.Int, modulus *big.Int, workers int, progress *progress) (*big.Int, error) {
+	if len(points) == 0 {
+		return nil, errNoPoints()
+	}
+	if modulus.Cmp(big.NewInt(2)) < 0 {
+		return nil, fmt.Errorf("invalid modulus %s: must be at least 2", modulus.String())
+	}
+
+	scratch := make([]termScratch, workers)
+	sums := make([]*big.Int, workers)
+	for w := range scratch {
+		scratch[w] = newTermScratch()
+		sums[w] = new(big.Int)
+	}
+
+	err := forEachTerm(len(points), workers, func(w, j int) error {
+		if err := progress.check(); err != nil {
+			return err
+		}
+		s := &scratch[w]
+		pointJ := points[j]
+		s.basis(points, j, x0, nil, modulus)
+
+		if s.inverse.ModInverse(s.denominator, modulus) == nil {
+			return fmt.Errorf("interpolation failed: denominator for x=%s is not invertible modulo %s", pointJ.X.String(), modulus.String())
+		}
+
+		s.termNumerator.Mul(pointJ.Y, s.numerator)
+		s.mul(&s.termNumerator, s.inverse)
+		sums[w].Add(sums[w], s.termNumerator)
+		s.reduce(&sums[w], modulus)
+		progress.step()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	secretC := big.NewInt(0)
+	for _, sum := range sums {
+		secretC.Add(secretC, sum)
+	}
+	return secretC.Mod(secretC, modulus), nil
+}
+
+// termScratch holds the temporaries of one Lagrange term, so that a worker
+// can reuse them across the terms it computes.
+type termScratch struct {
+	numerator, denominator *big.Int
+	termNumerator, inverse *big.Int
+	numTerm, denTerm       *big.Int
+	cofactor               *big.Int
+
+	// spare and quotient let mul and reduce write their results to a
+	// buffer other than their operands; math/big allocates a fresh one
+	// whenever a product or quotient aliases an operand.
+	spare, quotient *big.Int
+}
+
+func newTermScratch() termScratch {
+	return termScratch{
+		numerator:     new(big.Int),
+		denominator:   new(big.Int),
+		termNumerator: new(big.Int),
+		inverse:       new(big.Int),
+		numTerm:       new(big.Int),
+		denTerm:       new(big.Int),
+		cofactor:      new(big.Int),
+		spare:         new(big.Int),
+		quotient:      new(big.Int),
+	}
+}
+
+// mul sets *z to *z·x.
+func (s *termScratch) mul(z **big.Int, x *big.Int) {
+	s.spare.Mul(*z, x)
+	*z, s.spare = s.spare, *z
+}
+
+// reduce sets *z to *z mod m, in [0, m), for a positive m.
+func (s *termScratch) reduce(z **big.Int, m *big.Int) {
+	s.quotient.QuoRem(*z, m, s.spare)
+	if s.spare.Sign() < 0 {
+		s.spare.Add(s.spare, m)
+	}
+	*z, s.spare = s.spare, *z
+}
+
+// lazyBits is the size a product modulo m may grow to before it is
+// reduced. The factors are usually much smaller than m, so reducing after
+// every multiplication would spend most of the time dividing.
+func lazyBits(m *big.Int) int {
+	return 2 * m.BitLen()
+}
+
+// basis sets s.numerator and s.denominator to the numerator and denominator
+// of the j-th Lagrange basis polynomial at x0, reduced modulo modulus when it
+// is non-nil. product, if non-nil, is the precomputed ∏_i(x0-x_i), from
+// which the numerator is derived by one exact division instead of k-1
+// multiplications.
+func (s *termScratch) basis(points []share.Point, j int, x0, product, modulus *big.Int) {
+	s.denominatorOf(points, j, modulus)
+	s.numeratorOf(points, j, x0, product, modulus)
+}
+
+// numeratorOf sets s.numerator to ∏_{i≠j}(x0-x_i), as basis does.
+func (s *termScratch) numeratorOf(points []share.Point, j int, x0, product, modulus *big.Int) {
+	if product != nil {
+		s.numerator.QuoRem(product, s.numTerm.Sub(x0, points[j].X), s.spare)
+		return
+	}
+
+	s.numerator.SetInt64(1)
+	for i, pointI := range points {
+		if i == j {
+			continue
+		}
+		s.mul(&s.numerator, s.numTerm.Sub(x0, pointI.X))
+		if modulus != nil && s.numerator.BitLen() > lazyBits(modulus) {
+			s.reduce(&s.numerator, modulus)
+		}
+	}
+	if modulus != nil {
+		s.reduce(&s.numerator, modulus)
+	}
+}
+
+// denominatorOf sets s.denominator to ∏_{i≠j}(x_j-x_i), reduced modulo
+// modulus when it is non-nil.
+func (s *termScratch) denominatorOf(points []share.Point, j int, modulus *big.Int) {
+	s.denominator.SetInt64(1)
+	for i, pointI := range points {
+		if i == j {
+			continue
+		}
+		s.mul(&s.denominator, s.denTerm.Sub(points[j].X, pointI.X))
+		if modulus != nil && s.denominator.BitLen() > lazyBits(modulus) {
+			s.reduce(&s.denominator, modulus)
+		}
+	}
+	if modulus != nil {
+		s.reduce(&s.denominator, modulus)
+	}
+}
+
+// nodeProduct returns ∏_i(x0-x_i), or nil when x0 is one of the x_i and
+// the product is zero, so that it cannot be divided back out.
+func nodeProduct(points []share.Point, x0 *big.Int) *big.Int {
+	product := big.NewInt(1)
+	factor := new(big.Int)
+	for _, point := range points {
+		if factor.Sub(x0, point.X).Sign() == 0 {
+			return nil
+		}
+		product.Mul(product, factor)
+	}
+	return product
+}
+
+// Coefficients recovers every coefficient of the integer polynomial through
+// points, lowest degree first.
+func Coefficients(points []share.Point) ([]*big.Int, error) {
+	if len(points) == 0 {
+		return nil, errNoPoints()
+	}
+
+	k := len(points)
+	sums := make([]*big.Rat, k)
+	for d := range sums {
+		sums[d] = new(big.Rat)
+	}
+
+	basis := make([]*big.Int, k)
+	for d := range basis {
+		basis[d] = new(big.Int)
+	}
+	denominator := new(big.Int)
+	denTerm := new(big.Int)
+	scratch := new(big.Int)
+	term := new(big.Rat)
+
+	for j, pointJ := range points {
+		// basis holds the coefficients of prod_{i != j} (x - x_i), lowest degree first.
+		basis[0].SetInt64(1)
+		for d := 1; d < k; d++ {
+			basis[d].SetInt64(0)
+		}
+		denominator.SetInt64(1)
+
+		degree := 0
+		for i, pointI := range points {
+			if i == j {
+				continue
+			}
+			degree++
+			for d := degree; d > 0; d-- {
+				basis[d].Sub(basis[d-1], scratch.Mul(basis[d], pointI.X))
+			}
+			basis[0].Mul(basis[0], scratch.Neg(pointI.X))
+			denominator.Mul(denominator, denTerm.Sub(pointJ.X, pointI.X))
+		}
+
+		if denominator.Sign() == 0 {
+			return nil, fmt.Errorf("interpolation failed: %w leads to division by zero", &share.DuplicateXError{X: pointJ.X})
+		}
+
+		for d := 0; d < k; d++ {
+			term.SetFrac(scratch.Mul(pointJ.Y, basis[d]), denominator)
+			sums[d].Add(sums[d], term)
+		}
+	}
+
+	coefficients := make([]*big.Int, k)
+	for d, sum := range sums {
+		if !sum.IsInt() {
+			return nil, &NonIntegerError{What: fmt.Sprintf("coefficient of x^%d", d), Value: sum}
+		}
+		coefficients[d] = new(big.Int).Set(sum.Num())
+	}
+
+	return coefficients, nil
+}
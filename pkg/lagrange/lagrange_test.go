@@ -0,0 +1,468 @@
+package lagrange
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/OmSingh2003/CATALOG-ASSIGNMENT/pkg/gf2m"
+	"github.com/OmSingh2003/CATALOG-ASSIGNMENT/pkg/share"
+)
+
+// points returns the shares (xy[0], xy[1]), (xy[2], xy[3]), ...
+func points(xy ...int64) []share.Point {
+	ps := make([]share.Point, len(xy)/2)
+	for i := range ps {
+		ps[i] = share.Point{X: big.NewInt(xy[2*i]), Y: big.NewInt(xy[2*i+1])}
+	}
+	return ps
+}
+
+// evaluators are the algorithms that evaluate the polynomial through a set
+// of points, which must all give the same results.
+var evaluators = []struct {
+	name string
+	eval func(points []share.Point, x0 *big.Int, opts ...Option) (*big.Int, error)
+}{
+	{"lagrange", func(ps []share.Point, x0 *big.Int, opts ...Option) (*big.Int, error) {
+		return InterpolateAt(ps, x0, append(opts, WithFast(false))...)
+	}},
+	{"serial", func(ps []share.Point, x0 *big.Int, opts ...Option) (*big.Int, error) {
+		return InterpolateAt(ps, x0, append(opts, WithFast(false), WithWorkers(1))...)
+	}},
+	{"fast", func(ps []share.Point, x0 *big.Int, opts ...Option) (*big.Int, error) {
+		return InterpolateAt(ps, x0, append(opts, WithFast(true))...)
+	}},
+	{"newton", func(ps []share.Point, x0 *big.Int, opts ...Option) (*big.Int, error) {
+		f, err := NewNewtonForm(ps, opts...)
+		if err != nil {
+			return nil, err
+		}
+		return f.EvaluateAt(x0)
+	}},
+	{"barycentric", func(ps []share.Point, x0 *big.Int, opts ...Option) (*big.Int, error) {
+		ip, err := NewInterpolator(ps, opts...)
+		if err != nil {
+			return nil, err
+		}
+		return ip.EvaluateAt(x0)
+	}},
+}
+
+func TestInterpolateAt(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		points  []share.Point
+		x0      int64
+		modulus int64
+		want    int64
+		wantErr error
+	}{
+		// f(x) = x² + 3, the polynomial of testcase1.json.
+		{name: "secret", points: points(1, 4, 2, 7, 3, 12), want: 3},
+		{name: "at a share", points: points(1, 4, 2, 7, 3, 12), x0: 2, want: 7},
+		{name: "beyond the shares", points: points(1, 4, 2, 7, 3, 12), x0: 6, want: 39},
+		{name: "unsorted", points: points(6, 39, 1, 4, 3, 12), want: 3},
+		// The Lagrange weights at 0 of x=1, 3, 6 are 9/5, -1 and 1/5, so
+		// only their sum is an integer.
+		{name: "fractional terms", points: points(1, 4, 3, 12, 6, 39), want: 3},
+		// f(x) = -7 + 2x - x².
+		{name: "negative coordinates", points: points(-2, -15, 1, -6, 5, -22), want: -7},
+		{name: "negative x0", points: points(-2, -15, 1, -6, 5, -22), x0: -1, want: -10},
+		{name: "single share", points: points(4, 9), want: 9},
+		// f(x) = 5 + 3x + 4x² mod 11.
+		{name: "modular", points: points(1, 1, 2, 5, 3, 6), modulus: 11, want: 5},
+		{name: "modular at x0", points: points(1, 1, 2, 5, 3, 6), x0: 4, modulus: 11, want: 4},
+		{name: "modular unreduced", points: points(1, 12, 2, 27, 3, -5), modulus: 11, want: 5},
+		{name: "modular single share", points: points(1, 13), modulus: 11, want: 2},
+		{name: "fraction", points: points(1, 0, 3, 1), wantErr: ErrNonIntegerSecret},
+		{name: "duplicate x", points: points(1, 4, 1, 5), wantErr: share.ErrDuplicateX},
+		{name: "no shares", wantErr: share.ErrInsufficientShares},
+	} {
+		for _, e := range evaluators {
+			t.Run(tc.name+"/"+e.name, func(t *testing.T) {
+				var opts []Option
+				if tc.modulus != 0 {
+					opts = append(opts, WithModulus(big.NewInt(tc.modulus)))
+				}
+				got, err := e.eval(tc.points, big.NewInt(tc.x0), opts...)
+				if tc.wantErr != nil {
+					if !errors.Is(err, tc.wantErr) {
+						t.Fatalf("err = %v, want %v", err, tc.wantErr)
+					}
+					return
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got.Int64() != tc.want {
+					t.Errorf("f(%d) = %s, want %d", tc.x0, got, tc.want)
+				}
+			})
+		}
+	}
+}
+
+func TestInterpolateField(t *testing.T) {
+	field, err := gf2m.New(big.NewInt(0x11b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	secret, a1, a2 := big.NewInt(0x57), big.NewInt(0x13), big.NewInt(0xca)
+	f := func(x *big.Int) *big.Int {
+		return field.Add(secret, field.Add(field.Mul(a1, x), field.Mul(a2, field.Mul(x, x))))
+	}
+	var ps []share.Point
+	for x := int64(1); x <= 4; x++ {
+		ps = append(ps, share.Point{X: big.NewInt(x), Y: f(big.NewInt(x))})
+	}
+
+	got, err := Interpolate(ps[:3], WithField(field))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Cmp(secret) != 0 {
+		t.Errorf("secret = %#x, want %#x", got, secret)
+	}
+	got, err = InterpolateAt(ps[1:], big.NewInt(9), WithField(field))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := f(big.NewInt(9)); got.Cmp(want) != 0 {
+		t.Errorf("f(9) = %#x, want %#x", got, want)
+	}
+	if _, err := NewNewtonForm(ps, WithField(field)); err == nil {
+		t.Error("NewNewtonForm accepted WithField")
+	}
+	if _, err := Interpolate(ps, WithField(field), WithModulus(big.NewInt(7))); err == nil {
+		t.Error("Interpolate accepted WithField together with WithModulus")
+	}
+}
+
+func TestCoefficients(t *testing.T) {
+	// f(x) = 3 + 2x + x².
+	ps := points(1, 6, 2, 11, 3, 18)
+	want := []int64{3, 2, 1}
+	got, err := Coefficients(ps)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := NewNewtonForm(ps)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fromNewton, err := f.Coefficients()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, coefficients := range map[string][]*big.Int{"Coefficients": got, "NewtonForm.Coefficients": fromNewton} {
+		if len(coefficients) != len(want) {
+			t.Fatalf("%s returned %d coefficients, want %d", name, len(coefficients), len(want))
+		}
+		for d := range want {
+			if coefficients[d].Int64() != want[d] {
+				t.Errorf("%s: a_%d = %s, want %d", name, d, coefficients[d], want[d])
+			}
+		}
+	}
+	if _, err := Coefficients(points(1, 0, 3, 1)); !errors.Is(err, ErrNonIntegerSecret) {
+		t.Errorf("Coefficients of a fractional polynomial: err = %v, want %v", err, ErrNonIntegerSecret)
+	}
+}
+
+func TestEvaluateDerivativeAt(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		points  []share.Point
+		x0      int64
+		modulus int64
+		want    int64
+	}{
+		// f(x) = 3 + 2x + x², f'(x) = 2 + 2x.
+		{name: "at zero", points: points(1, 6, 2, 11, 3, 18), want: 2},
+		{name: "away from the shares", points: points(1, 6, 2, 11, 3, 18), x0: 4, want: 10},
+		{name: "at a share", points: points(1, 6, 2, 11, 3, 18), x0: 2, want: 6},
+		{name: "negative x0", points: points(1, 6, 2, 11, 3, 18), x0: -3, want: -4},
+		{name: "single share", points: points(5, 8), want: 0},
+		{name: "modular", points: points(1, 6, 2, 11, 3, 18), x0: 4, modulus: 7, want: 3},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var opts []Option
+			if tc.modulus != 0 {
+				opts = append(opts, WithModulus(big.NewInt(tc.modulus)))
+			}
+			got, err := EvaluateDerivativeAt(tc.points, big.NewInt(tc.x0), opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Int64() != tc.want {
+				t.Errorf("f'(%d) = %s, want %d", tc.x0, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestEvaluateDerivativeAtErrors(t *testing.T) {
+	// f(x) = (x-1)/2 has a fractional slope.
+	_, err := EvaluateDerivativeAt(points(1, 0, 3, 1), big.NewInt(0))
+	var nonInteger *NonIntegerError
+	if !errors.As(err, &nonInteger) || nonInteger.What != "f'(0)" || nonInteger.Value.RatString() != "1/2" {
+		t.Errorf("fractional slope: err = %v, want f'(0) = 1/2 reported", err)
+	}
+	for _, tc := range []struct {
+		name    string
+		points  []share.Point
+		modulus int64
+		want    string
+	}{
+		{"no shares", nil, 0, ""},
+		{"duplicate x", points(1, 6, 1, 7), 0, "division by zero"},
+		{"modulus 1", points(1, 6, 2, 11), 1, "must be at least 2"},
+		{"not invertible", points(1, 0, 3, 1), 4, "not invertible modulo 4"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var opts []Option
+			if tc.modulus != 0 {
+				opts = append(opts, WithModulus(big.NewInt(tc.modulus)))
+			}
+			if _, err := EvaluateDerivativeAt(tc.points, big.NewInt(0), opts...); err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("err = %v, want %q", err, tc.want)
+			}
+		})
+	}
+}
+
+// modularShares returns the shares at x = 1..n of f(x) = 5 + 3x + 7x² mod
+// 101 with the shares at the x of corrupt given a wrong value.
+func modularShares(n int, corrupt ...int64) []share.Point {
+	var ps []share.Point
+	for x := int64(1); x <= int64(n); x++ {
+		y := (5 + 3*x + 7*x*x) % 101
+		for _, c := range corrupt {
+			if x == c {
+				y = (y + 42) % 101
+			}
+		}
+		ps = append(ps, share.Point{X: big.NewInt(x), Y: big.NewInt(y)})
+	}
+	return ps
+}
+
+func TestCorrectErrors(t *testing.T) {
+	modulus := big.NewInt(101)
+	for _, tc := range []struct {
+		name    string
+		n       int
+		corrupt []int64
+		wantErr bool
+	}{
+		{name: "no errors", n: 5},
+		{name: "one error", n: 5, corrupt: []int64{2}},
+		{name: "two errors", n: 7, corrupt: []int64{1, 6}},
+		{name: "k shares", n: 3},
+		{name: "too many errors", n: 5, corrupt: []int64{1, 2}, wantErr: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			secret, bad, err := CorrectErrors(modularShares(tc.n, tc.corrupt...), 3, modulus)
+			if tc.wantErr {
+				if err == nil && secret.Int64() == 5 {
+					t.Fatalf("corrected more errors than (n-k)/2")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if secret.Int64() != 5 {
+				t.Errorf("secret = %s, want 5", secret)
+			}
+			if len(bad) != len(tc.corrupt) {
+				t.Fatalf("corrected x=%v, want %v", bad, tc.corrupt)
+			}
+			for i, x := range bad {
+				if x.Int64() != tc.corrupt[i] {
+					t.Errorf("corrected x=%v, want %v", bad, tc.corrupt)
+				}
+			}
+		})
+	}
+	if _, _, err := CorrectErrors(modularShares(2), 3, modulus); !errors.Is(err, share.ErrInsufficientShares) {
+		t.Errorf("CorrectErrors of too few shares: err = %v, want %v", err, share.ErrInsufficientShares)
+	}
+}
+
+func TestVote(t *testing.T) {
+	for _, tc := range []struct {
+		name         string
+		points       []share.Point
+		opts         []Option
+		want         int64
+		wantSuspects []int64
+	}{
+		// f(x) = x² + 3 with the share at x=2 corrupted.
+		{name: "integers", points: points(1, 4, 2, 8, 3, 12, 4, 19, 5, 28), want: 3, wantSuspects: []int64{2}},
+		{name: "all agree", points: points(1, 4, 2, 7, 3, 12, 4, 19), want: 3},
+		{name: "modular", points: modularShares(6, 4), opts: []Option{WithModulus(big.NewInt(101))}, want: 5, wantSuspects: []int64{4}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := Vote(tc.points, 3, tc.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.Secret.Int64() != tc.want {
+				t.Errorf("secret = %s, want %d", result.Secret, tc.want)
+			}
+			if len(result.Suspects) != len(tc.wantSuspects) {
+				t.Fatalf("suspects x=%v, want %v", result.Suspects, tc.wantSuspects)
+			}
+			for i, x := range result.Suspects {
+				if x.Int64() != tc.wantSuspects[i] {
+					t.Errorf("suspects x=%v, want %v", result.Suspects, tc.wantSuspects)
+				}
+			}
+		})
+	}
+	if _, err := Vote(points(1, 4, 2, 7), 3); !errors.Is(err, share.ErrInsufficientShares) {
+		t.Errorf("Vote of too few shares: err = %v, want %v", err, share.ErrInsufficientShares)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	selected := points(1, 4, 2, 7, 3, 12)
+	for _, tc := range []struct {
+		name  string
+		extra []share.Point
+		want  []int64
+	}{
+		{name: "none", extra: nil},
+		{name: "consistent", extra: points(4, 19, 6, 39)},
+		{name: "one inconsistent", extra: points(4, 19, 6, 40), want: []int64{6}},
+		{name: "single inconsistent", extra: points(4, 20), want: []int64{4}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mismatches, err := Verify(selected, tc.extra)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(mismatches) != len(tc.want) {
+				t.Fatalf("mismatches x=%v, want %v", mismatches, tc.want)
+			}
+			for i, x := range mismatches {
+				if x.Int64() != tc.want[i] {
+					t.Errorf("mismatches x=%v, want %v", mismatches, tc.want)
+				}
+			}
+		})
+	}
+}
+
+func TestNegativeFixture(t *testing.T) {
+	f, err := os.Open("../../testcase_negative.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	s, err := share.ParseShares(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := Interpolate(s.Points[:s.K])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Int64() != -7 {
+		t.Errorf("secret = %s, want -7", got)
+	}
+	// The share left out lies on the same polynomial.
+	for _, p := range s.Points[s.K:] {
+		y, err := InterpolateAt(s.Points[:s.K], p.X)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if y.Cmp(p.Y) != 0 {
+			t.Errorf("f(%s) = %s, want %s", p.X, y, p.Y)
+		}
+	}
+}
+
+func TestWithProgress(t *testing.T) {
+	ps := points(1, 4, 2, 7, 3, 12, 4, 19, 5, 28, 6, 39)
+	for _, tc := range []struct {
+		name  string
+		opts  []Option
+		total int
+	}{
+		{"rational", nil, 2 * len(ps)},
+		{"rational on workers", []Option{WithWorkers(3)}, 2 * len(ps)},
+		{"modular", []Option{WithModulus(big.NewInt(101))}, len(ps)},
+		{"modular on workers", []Option{WithModulus(big.NewInt(101)), WithWorkers(3)}, len(ps)},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var calls []int
+			opts := append(tc.opts, WithFast(false), WithProgress(func(done, total int) {
+				if total != tc.total {
+					t.Errorf("total = %d, want %d", total, tc.total)
+				}
+				calls = append(calls, done)
+			}))
+			y, err := InterpolateAt(ps, big.NewInt(0), opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if y.Int64() != 3 {
+				t.Errorf("secret = %s, want 3", y)
+			}
+			if len(calls) != tc.total {
+				t.Fatalf("progress called %d times, want %d", len(calls), tc.total)
+			}
+			for i, done := range calls {
+				if done != i+1 {
+					t.Fatalf("progress calls = %v, want 1 to %d in order", calls, tc.total)
+				}
+			}
+		})
+	}
+}
+
+func TestWithContext(t *testing.T) {
+	ps := points(1, 4, 2, 7, 3, 12, 4, 19, 5, 28, 6, 39)
+	for _, modular := range []bool{false, true} {
+		ctx, cancel := context.WithCancel(context.Background())
+		opts := []Option{WithFast(false), WithContext(ctx), WithProgress(func(done, total int) {
+			if done == 2 {
+				cancel()
+			}
+		})}
+		if modular {
+			opts = append(opts, WithModulus(big.NewInt(101)))
+		}
+		_, err := InterpolateAt(ps, big.NewInt(0), opts...)
+		if !errors.Is(err, context.Canceled) || !strings.Contains(err.Error(), "stopped after 2 of") {
+			t.Errorf("modular %t: err = %v, want one stopping after 2 steps", modular, err)
+		}
+		cancel()
+	}
+
+	// A context that is not done changes nothing.
+	y, err := InterpolateAt(ps, big.NewInt(0), WithContext(context.Background()))
+	if err != nil || y.Int64() != 3 {
+		t.Errorf("InterpolateAt = %v, %v, want 3", y, err)
+	}
+}
+
+func TestThresholdBelowOne(t *testing.T) {
+	for _, k := range []int{0, -1} {
+		if _, err := Vote(points(1, 4, 2, 7), k); !errors.Is(err, share.ErrInsufficientShares) {
+			t.Errorf("Vote with k=%d: err = %v, want %v", k, err, share.ErrInsufficientShares)
+		}
+		if _, _, err := CorrectErrors(modularShares(3), k, big.NewInt(101)); !errors.Is(err, share.ErrInsufficientShares) {
+			t.Errorf("CorrectErrors with k=%d: err = %v, want %v", k, err, share.ErrInsufficientShares)
+		}
+	}
+	if _, err := EvaluateDerivativeAt(nil, big.NewInt(0)); !errors.Is(err, share.ErrInsufficientShares) {
+		t.Errorf("EvaluateDerivativeAt of no shares: err = %v, want %v", err, share.ErrInsufficientShares)
+	}
+}
@@ -0,0 +1,157 @@
+package lagrange
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/OmSingh2003/CATALOG-ASSIGNMENT/pkg/share"
+)
+
+// NewtonForm is the polynomial through a set of points written in Newton's
+// form
+//
+//	f(x) = c_0 + c_1(x-x_0) + c_2(x-x_0)(x-x_1) + ...
+//
+// with the c_j found by divided differences. It shares no code with the
+// Lagrange interpolation, so the two can check each other.
+type NewtonForm struct {
+	xs      []*big.Int
+	modulus *big.Int
+
+	// coefficients and coefficientsMod hold the c_j over the rationals or
+	// modulo the modulus.
+	coefficients    []*big.Rat
+	coefficientsMod []*big.Int
+}
+
+// NewNewtonForm computes the divided differences of points in O(k²). Only
+// WithModulus affects it.
+func NewNewtonForm(points []share.Point, opts ...Option) (*NewtonForm, error) {
+	if len(points) == 0 {
+		return nil, errNoPoints()
+	}
+	c := newConfig(opts)
+	if c.field != nil {
+		return nil, errFieldUnsupported("Newton interpolation", c.field)
+	}
+
+	f := &NewtonForm{xs: make([]*big.Int, len(points)), modulus: c.modulus}
+	for i, point := range points {
+		f.xs[i] = point.X
+	}
+	if c.modulus != nil {
+		return f, f.divideMod(points)
+	}
+	return f, f.divide(points)
+}
+
+func (f *NewtonForm) divide(points []share.Point) error {
+	table := make([]*big.Rat, len(points))
+	for i, point := range points {
+		table[i] = new(big.Rat).SetInt(point.Y)
+	}
+
+	step := new(big.Int)
+	width := new(big.Rat)
+	for level := 1; level < len(points); level++ {
+		for i := len(points) - 1; i >= level; i-- {
+			step.Sub(f.xs[i], f.xs[i-level])
+			if step.Sign() == 0 {
+				return fmt.Errorf("interpolation failed: %w leads to division by zero", &share.DuplicateXError{X: f.xs[i]})
+			}
+			table[i].Sub(table[i], table[i-1])
+			table[i].Quo(table[i], width.SetInt(step))
+		}
+	}
+	f.coefficients = table
+	return nil
+}
+
+func (f *NewtonForm) divideMod(points []share.Point) error {
+	modulus := f.modulus
+	if modulus.Cmp(big.NewInt(2)) < 0 {
+		return fmt.Errorf("invalid modulus %s: must be at least 2", modulus.String())
+	}
+
+	table := make([]*big.Int, len(points))
+	for i, point := range points {
+		table[i] = new(big.Int).Mod(point.Y, modulus)
+	}
+
+	step := new(big.Int)
+	for level := 1; level < len(points); level++ {
+		for i := len(points) - 1; i >= level; i-- {
+			step.Sub(f.xs[i], f.xs[i-level])
+			if step.ModInverse(step.Mod(step, modulus), modulus) == nil {
+				return fmt.Errorf("interpolation failed: denominator for x=%s is not invertible modulo %s", f.xs[i].String(), modulus.String())
+			}
+			table[i].Sub(table[i], table[i-1])
+			table[i].Mul(table[i], step)
+			table[i].Mod(table[i], modulus)
+		}
+	}
+	f.coefficientsMod = table
+	return nil
+}
+
+// EvaluateAt returns f(x0) by Horner's rule on the Newton form.
+func (f *NewtonForm) EvaluateAt(x0 *big.Int) (*big.Int, error) {
+	last := len(f.xs) - 1
+	diff := new(big.Int)
+
+	if f.modulus != nil {
+		value := new(big.Int).Set(f.coefficientsMod[last])
+		for i := last - 1; i >= 0; i-- {
+			value.Mul(value, diff.Sub(x0, f.xs[i]))
+			value.Add(value, f.coefficientsMod[i])
+			value.Mod(value, f.modulus)
+		}
+		return value, nil
+	}
+
+	value := new(big.Rat).Set(f.coefficients[last])
+	factor := new(big.Rat)
+	for i := last - 1; i >= 0; i-- {
+		value.Mul(value, factor.SetInt(diff.Sub(x0, f.xs[i])))
+		value.Add(value, f.coefficients[i])
+	}
+	if !value.IsInt() {
+		return nil, &NonIntegerError{What: "f(" + x0.String() + ")", Value: value}
+	}
+	return new(big.Int).Set(value.Num()), nil
+}
+
+// Coefficients expands the Newton form into the coefficients of the integer
+// polynomial, lowest degree first, as Coefficients does for the Lagrange
+// form. It is not available in modular mode.
+func (f *NewtonForm) Coefficients() ([]*big.Int, error) {
+	if f.modulus != nil {
+		return nil, errors.New("coefficients are not supported in modular mode")
+	}
+
+	// Expand c_0 + (x-x_0)(c_1 + (x-x_1)(c_2 + ...)) from the inside out.
+	last := len(f.xs) - 1
+	poly := []*big.Rat{new(big.Rat).Set(f.coefficients[last])}
+	scratch := new(big.Rat)
+	for i := last - 1; i >= 0; i-- {
+		root := new(big.Rat).SetInt(f.xs[i])
+		next := make([]*big.Rat, len(poly)+1)
+		next[len(poly)] = new(big.Rat).Set(poly[len(poly)-1])
+		for d := len(poly) - 1; d > 0; d-- {
+			next[d] = new(big.Rat).Sub(poly[d-1], scratch.Mul(poly[d], root))
+		}
+		next[0] = new(big.Rat).Neg(scratch.Mul(poly[0], root))
+		next[0].Add(next[0], f.coefficients[i])
+		poly = next
+	}
+
+	coefficients := make([]*big.Int, len(poly))
+	for d, c := range poly {
+		if !c.IsInt() {
+			return nil, &NonIntegerError{What: fmt.Sprintf("coefficient of x^%d", d), Value: c}
+		}
+		coefficients[d] = new(big.Int).Set(c.Num())
+	}
+	return coefficients, nil
+}
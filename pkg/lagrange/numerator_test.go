@@ -0,0 +1,114 @@
+package lagrange
+
+import (
+	"fmt"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/OmSingh2003/CATALOG-ASSIGNMENT/pkg/share"
+)
+
+// TestNumeratorFromProduct checks that dividing the node product by x0-x_j
+// gives the numerator the k-1 multiplications of the naive loop do.
+func TestNumeratorFromProduct(t *testing.T) {
+	rng := rand.New(rand.NewSource(48))
+	for trial := 0; trial < 50; trial++ {
+		k := 1 + rng.Intn(60)
+		ps := randomPoints(rng, k, 8, nil)
+		if trial%2 == 0 {
+			// Half the trials hold a share at x=0.
+			ps[rng.Intn(k)].X.SetInt64(0)
+			ps = dedupe(ps)
+		}
+		for _, x0 := range []*big.Int{big.NewInt(0), big.NewInt(int64(rng.Intn(1000) - 500)), big.NewInt(int64(8*k + 1))} {
+			product := nodeProduct(ps, x0)
+			if product == nil {
+				continue
+			}
+			fromProduct, naive := newTermScratch(), newTermScratch()
+			for j := range ps {
+				fromProduct.numeratorOf(ps, j, x0, product, nil)
+				naive.numeratorOf(ps, j, x0, nil, nil)
+				if fromProduct.numerator.Cmp(naive.numerator) != 0 {
+					t.Fatalf("trial %d: numerator of term %d at x0=%s = %s, want %s", trial, j, x0, fromProduct.numerator, naive.numerator)
+				}
+			}
+		}
+	}
+}
+
+// dedupe drops the shares whose x repeats an earlier one.
+func dedupe(ps []share.Point) []share.Point {
+	seen := make(map[string]bool, len(ps))
+	out := ps[:0]
+	for _, p := range ps {
+		if !seen[p.X.String()] {
+			seen[p.X.String()] = true
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// TestNodeProductAtShare covers x0 equal to the x of a share, where the
+// node product is zero and every numerator comes from the naive loop.
+func TestNodeProductAtShare(t *testing.T) {
+	rng := rand.New(rand.NewSource(480))
+	ps, _ := polynomialPoints(rng, 12, 32)
+	ps[rng.Intn(len(ps))].X.SetInt64(0)
+	ps = dedupe(ps)
+	for _, p := range ps {
+		if nodeProduct(ps, p.X) != nil {
+			t.Fatalf("nodeProduct at x0=%s is not nil", p.X)
+		}
+	}
+	// A share at x=0 holds the secret, whatever the others.
+	for _, p := range ps {
+		if p.X.Sign() != 0 {
+			continue
+		}
+		got, err := Interpolate(ps, WithFast(false))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Cmp(p.Y) != 0 {
+			t.Errorf("secret = %s, want the y of the share at x=0, %s", got, p.Y)
+		}
+	}
+	for _, p := range ps {
+		got, err := InterpolateAt(ps, p.X, WithFast(false))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Cmp(p.Y) != 0 {
+			t.Errorf("f(%s) = %s, want %s", p.X, got, p.Y)
+		}
+	}
+}
+
+// BenchmarkNumerators computes the numerators of all k=1000 terms from the
+// node product and by the naive loop.
+func BenchmarkNumerators(b *testing.B) {
+	const k = 1000
+	ps := randomPoints(rand.New(rand.NewSource(1000)), k, 1, nil)
+	x0 := big.NewInt(8*k + 1)
+	for _, bc := range []struct {
+		name    string
+		product func() *big.Int
+	}{
+		{"product", func() *big.Int { return nodeProduct(ps, x0) }},
+		{"naive", func() *big.Int { return nil }},
+	} {
+		b.Run(fmt.Sprintf("%s/k=%d", bc.name, k), func(b *testing.B) {
+			b.ReportAllocs()
+			s := newTermScratch()
+			for i := 0; i < b.N; i++ {
+				product := bc.product()
+				for j := range ps {
+					s.numeratorOf(ps, j, x0, product, nil)
+				}
+			}
+		})
+	}
+}
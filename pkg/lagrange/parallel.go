@@ -0,0 +1,107 @@
+package lagrange
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sync"
+)
+
+// progress counts the steps of an interpolation, for WithProgress and
+// WithContext. A nil *progress counts nothing.
+type progress struct {
+	mu          sync.Mutex
+	done, total int
+	fn          func(done, total int)
+	ctx         context.Context
+}
+
+func (c *config) newProgress(total int) *progress {
+	if c.progress == nil && c.ctx == nil {
+		return nil
+	}
+	return &progress{total: total, fn: c.progress, ctx: c.ctx}
+}
+
+// step counts one step done; workers may call it concurrently.
+func (p *progress) step() {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.done++
+	if p.fn != nil {
+		p.fn(p.done, p.total)
+	}
+}
+
+// check returns the error of a context that is done, saying how far the
+// interpolation got.
+func (p *progress) check() error {
+	if p == nil || p.ctx == nil {
+		return nil
+	}
+	if err := p.ctx.Err(); err != nil {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		return fmt.Errorf("interpolation stopped after %d of %d steps: %w", p.done, p.total, err)
+	}
+	return nil
+}
+
+// minTermsPerWorker keeps small interpolations, where starting goroutines
+// costs more than it saves, on the serial loop.
+const minTermsPerWorker = 64
+
+// termWorkers returns how many goroutines should compute the n terms of an
+// interpolation.
+func (c *config) termWorkers(n int) int {
+	workers := c.workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	return max(1, min(workers, n/minTermsPerWorker))
+}
+
+// forEachTerm calls term(w, j) for every j in [0, n), spreading the calls
+// over workers goroutines numbered w. Calls with the same w never run
+// concurrently, so term may keep scratch state per worker. If several
+// terms fail, the error of the lowest j is returned, as the serial loop
+// would.
+func forEachTerm(n, workers int, term func(w, j int) error) error {
+	if workers <= 1 {
+		for j := 0; j < n; j++ {
+			if err := term(0, j); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	failedAt := make([]int, workers)
+	errs := make([]error, workers)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := w; j < n; j += workers {
+				if err := term(w, j); err != nil {
+					failedAt[w], errs[w] = j, err
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	var err error
+	first := n
+	for w, e := range errs {
+		if e != nil && failedAt[w] < first {
+			first, err = failedAt[w], e
+		}
+	}
+	return err
+}
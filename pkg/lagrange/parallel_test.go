@@ -0,0 +1,153 @@
+package lagrange
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"math/rand"
+	"runtime"
+	"testing"
+
+	"github.com/OmSingh2003/CATALOG-ASSIGNMENT/pkg/share"
+)
+
+// m4423 is the Mersenne prime 2^4423 - 1, a modulus for 4096-bit shares.
+var m4423 = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 4423), big.NewInt(1))
+
+// randomPoints returns k shares with distinct x in [-4k, 4k], which may
+// include 0, and y of up to bits bits with a random sign, or reduced
+// modulo modulus when it is non-nil.
+func randomPoints(rng *rand.Rand, k, bits int, modulus *big.Int) []share.Point {
+	limit := new(big.Int).Lsh(big.NewInt(1), uint(bits))
+	seen := make(map[int64]bool, k)
+	ps := make([]share.Point, 0, k)
+	for len(ps) < k {
+		x := rng.Int63n(int64(8*k+1)) - int64(4*k)
+		if seen[x] {
+			continue
+		}
+		seen[x] = true
+		y := new(big.Int).Rand(rng, limit)
+		if modulus != nil {
+			y.Mod(y, modulus)
+		} else if rng.Intn(2) == 0 {
+			y.Neg(y)
+		}
+		ps = append(ps, share.Point{X: big.NewInt(x), Y: y})
+	}
+	return ps
+}
+
+// polynomialPoints returns the shares at k distinct random x of a random
+// polynomial of degree k-1 with coefficients of up to bits bits of either
+// sign, so that f(0), its constant term, is an integer.
+func polynomialPoints(rng *rand.Rand, k, bits int) ([]share.Point, *big.Int) {
+	coefficients := randomPoints(rng, k, bits, nil)
+	ps := randomPoints(rng, k, 1, nil)
+	for i := range ps {
+		y := new(big.Int)
+		for c := k - 1; c >= 0; c-- {
+			y.Mul(y, ps[i].X).Add(y, coefficients[c].Y)
+		}
+		ps[i].Y = y
+	}
+	return ps, coefficients[0].Y
+}
+
+// checkSame fails unless two interpolations gave the same value or the same
+// error.
+func checkSame(t *testing.T, what string, want, got *big.Int, wantErr, gotErr error) {
+	t.Helper()
+	switch {
+	case wantErr != nil || gotErr != nil:
+		if fmt.Sprint(wantErr) != fmt.Sprint(gotErr) {
+			t.Errorf("%s: err = %v, want %v", what, gotErr, wantErr)
+		}
+	case got.Cmp(want) != 0:
+		t.Errorf("%s = %s, want %s", what, got, want)
+	}
+}
+
+func TestParallelMatchesSerial(t *testing.T) {
+	rng := rand.New(rand.NewSource(45))
+	// At least minTermsPerWorker terms per worker, so that the terms are
+	// really spread over several goroutines.
+	k := 4 * minTermsPerWorker
+	for trial := 0; trial < 8; trial++ {
+		x0 := big.NewInt(int64(trial - 4))
+		integer, secret := polynomialPoints(rng, k, 64)
+		for _, tc := range []struct {
+			name   string
+			points []share.Point
+			opts   []Option
+		}{
+			{"integer secret", integer, nil},
+			{"rational", randomPoints(rng, k, 512, nil), nil},
+			{"modular", randomPoints(rng, k, 4096, m4423), []Option{WithModulus(m4423)}},
+		} {
+			serial, serialErr := InterpolateAt(tc.points, x0, append(tc.opts, WithFast(false), WithWorkers(1))...)
+			for _, workers := range []int{2, 3, 4, runtime.GOMAXPROCS(0)} {
+				parallel, parallelErr := InterpolateAt(tc.points, x0, append(tc.opts, WithFast(false), WithWorkers(workers))...)
+				checkSame(t, fmt.Sprintf("trial %d %s f(%s) with %d workers", trial, tc.name, x0, workers), serial, parallel, serialErr, parallelErr)
+			}
+		}
+
+		got, err := Interpolate(integer, WithFast(false), WithWorkers(4))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Cmp(secret) != 0 {
+			t.Errorf("trial %d: secret = %s, want %s", trial, got, secret)
+		}
+	}
+}
+
+func TestTermWorkers(t *testing.T) {
+	for _, tc := range []struct {
+		workers, n, want int
+	}{
+		{workers: 1, n: 10000, want: 1},
+		{workers: 8, n: 10, want: 1},
+		{workers: 8, n: 3 * minTermsPerWorker, want: 3},
+		{workers: 8, n: 100 * minTermsPerWorker, want: 8},
+	} {
+		c := &config{workers: tc.workers}
+		if got := c.termWorkers(tc.n); got != tc.want {
+			t.Errorf("termWorkers(%d) with %d workers = %d, want %d", tc.n, tc.workers, got, tc.want)
+		}
+	}
+}
+
+// BenchmarkInterpolateWorkers compares the serial loop with 4 workers and
+// one per core at k=2000 with 4096-bit values. Random values rarely lie on an
+// integer polynomial, so the rational runs end in ErrNonIntegerSecret, but
+// only after the single division all the terms lead up to.
+func BenchmarkInterpolateWorkers(b *testing.B) {
+	const k = 2000
+	rng := rand.New(rand.NewSource(2000))
+	rational := randomPoints(rng, k, 4096, nil)
+	modular := randomPoints(rng, k, 4096, m4423)
+	for _, bc := range []struct {
+		name   string
+		points []share.Point
+		opts   []Option
+	}{
+		{"rational", rational, nil},
+		{"modular", modular, []Option{WithModulus(m4423)}},
+	} {
+		counts := []int{1, 4}
+		if cores := runtime.GOMAXPROCS(0); cores > 4 {
+			counts = append(counts, cores)
+		}
+		for _, workers := range counts {
+			b.Run(fmt.Sprintf("%s/workers=%d", bc.name, workers), func(b *testing.B) {
+				opts := append(bc.opts, WithFast(false), WithWorkers(workers))
+				for i := 0; i < b.N; i++ {
+					if _, err := Interpolate(bc.points, opts...); err != nil && !errors.Is(err, ErrNonIntegerSecret) {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package lagrange
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/OmSingh2003/CATALOG-ASSIGNMENT/pkg/share"
+)
+
+// ratInterpolateAt is the textbook interpolation in big.Rat, reducing every
+// term, which interpolateRational must agree with.
+func ratInterpolateAt(ps []share.Point, x0 *big.Int) *big.Rat {
+	sum := new(big.Rat)
+	for j, pj := range ps {
+		term := new(big.Rat).SetInt(pj.Y)
+		for i, pi := range ps {
+			if i != j {
+				factor := new(big.Rat).SetFrac(new(big.Int).Sub(x0, pi.X), new(big.Int).Sub(pj.X, pi.X))
+				term.Mul(term, factor)
+			}
+		}
+		sum.Add(sum, term)
+	}
+	return sum
+}
+
+func TestCommonDenominatorMatchesRat(t *testing.T) {
+	rng := rand.New(rand.NewSource(49))
+	for trial := 0; trial < 200; trial++ {
+		k := 1 + rng.Intn(40)
+		// About half the x and y are negative.
+		ps := randomPoints(rng, k, 1+rng.Intn(128), nil)
+		if trial%4 == 0 {
+			ps, _ = polynomialPoints(rng, k, 1+rng.Intn(128))
+		}
+		x0 := big.NewInt(int64(rng.Intn(9) - 4))
+		want := ratInterpolateAt(ps, x0)
+		got, err := interpolateRational(ps, x0, 1+rng.Intn(4), nil)
+		if want.IsInt() {
+			if err != nil || got.Cmp(want.Num()) != 0 {
+				t.Errorf("trial %d: f(%s) = %v, %v, want %s", trial, x0, got, err, want.Num())
+			}
+			continue
+		}
+		var nonInteger *NonIntegerError
+		if !errors.As(err, &nonInteger) {
+			t.Errorf("trial %d: f(%s) = %v, %v, want the fraction %s", trial, x0, got, err, want)
+		} else if nonInteger.Value.Cmp(want) != 0 {
+			t.Errorf("trial %d: f(%s) = %s, want %s", trial, x0, nonInteger.Value, want)
+		}
+	}
+}
+
+// BenchmarkRational compares the sum over a common denominator with the
+// big.Rat sum that reduces every term, at k=500; the big.Rat sum takes
+// seconds there and most of a minute at k=1000.
+func BenchmarkRational(b *testing.B) {
+	const k = 500
+	ps, _ := polynomialPoints(rand.New(rand.NewSource(k)), k, 256)
+	x0 := big.NewInt(0)
+	b.Run(fmt.Sprintf("common/k=%d", k), func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := interpolateRational(ps, x0, 1, nil); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run(fmt.Sprintf("rat/k=%d", k), func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			ratInterpolateAt(ps, x0)
+		}
+	})
+}
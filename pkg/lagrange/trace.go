@@ -0,0 +1,71 @@
+package lagrange
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/OmSingh2003/CATALOG-ASSIGNMENT/pkg/share"
+)
+
+// Term is one term of a traced interpolation at x0: the share (X, Y), the
+// numerator ∏_{i≠j}(x0-x_i) and denominator ∏_{i≠j}(x_j-x_i) of its basis
+// polynomial, the basis coefficient ℓ_j(x0) = Numerator/Denominator in
+// lowest terms, the term Y·ℓ_j(x0) and the sum of the terms so far. Modulo
+// a prime the numerator and denominator are reduced, and the basis
+// coefficient, term and sum are integers in [0, p).
+type Term struct {
+	X, Y                   *big.Int
+	Numerator, Denominator *big.Int
+	Basis, Term, Sum       *big.Rat
+}
+
+// WithTrace calls fn with every term of InterpolateAt, in the order of the
+// points, before the result is computed. The terms are exact, so a trace
+// also shows how a fractional result came about. Tracing computes the
+// terms a second time, serially; the other interpolators ignore it.
+func WithTrace(fn func(Term)) Option {
+	return func(c *config) {
+		c.trace = fn
+	}
+}
+
+func trace(points []share.Point, x0, modulus *big.Int, fn func(Term)) error {
+	// Invalid inputs are left for the interpolation to report.
+	if modulus != nil && modulus.Cmp(big.NewInt(2)) < 0 {
+		return nil
+	}
+	s := newTermScratch()
+	sum := new(big.Rat)
+	for j, point := range points {
+		s.basis(points, j, x0, nil, modulus)
+		basis, term := new(big.Rat), new(big.Rat)
+		if modulus == nil {
+			if s.denominator.Sign() == 0 {
+				return fmt.Errorf("interpolation failed: %w leads to division by zero", &share.DuplicateXError{X: point.X})
+			}
+			basis.SetFrac(s.numerator, s.denominator)
+			term.SetFrac(new(big.Int).Mul(point.Y, s.numerator), s.denominator)
+			sum.Add(sum, term)
+		} else {
+			if s.inverse.ModInverse(s.denominator, modulus) == nil {
+				return fmt.Errorf("interpolation failed: denominator for x=%s is not invertible modulo %s", point.X.String(), modulus.String())
+			}
+			coefficient := new(big.Int).Mul(s.numerator, s.inverse)
+			basis.SetInt(coefficient.Mod(coefficient, modulus))
+			value := new(big.Int).Mul(point.Y, coefficient)
+			value.Mod(value, modulus)
+			term.SetInt(value)
+			sum.SetInt(value.Add(value, sum.Num()).Mod(value, modulus))
+		}
+		fn(Term{
+			X:           point.X,
+			Y:           point.Y,
+			Numerator:   new(big.Int).Set(s.numerator),
+			Denominator: new(big.Int).Set(s.denominator),
+			Basis:       basis,
+			Term:        term,
+			Sum:         new(big.Rat).Set(sum),
+		})
+	}
+	return nil
+}
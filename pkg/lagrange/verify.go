@@ -0,0 +1,40 @@
+package lagrange
+
+import (
+	"math/big"
+
+	"github.com/OmSingh2003/CATALOG-ASSIGNMENT/pkg/share"
+)
+
+// Verify checks the shares left over after selection against the polynomial
+// defined by the selected ones and returns the x values that disagree.
+// Several shares are checked with one Interpolator, so each costs O(k).
+func Verify(selected, extra []share.Point, opts ...Option) ([]*big.Int, error) {
+	modulus := newConfig(opts).modulus
+
+	at := func(x0 *big.Int) (*big.Int, error) { return InterpolateAt(selected, x0, opts...) }
+	if len(extra) > 1 {
+		interpolator, err := NewInterpolator(selected, opts...)
+		if err != nil {
+			return nil, err
+		}
+		at = interpolator.EvaluateAt
+	}
+
+	var mismatches []*big.Int
+	for _, point := range extra {
+		y, err := at(point.X)
+		if err != nil {
+			return nil, err
+		}
+
+		want := point.Y
+		if modulus != nil {
+			want = new(big.Int).Mod(point.Y, modulus)
+		}
+		if y.Cmp(want) != 0 {
+			mismatches = append(mismatches, point.X)
+		}
+	}
+	return mismatches, nil
+}
@@ -0,0 +1,107 @@
+package lagrange
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/OmSingh2003/CATALOG-ASSIGNMENT/pkg/share"
+)
+
+// MaxVoteSubsets bounds the number of k-subsets Vote is willing to try.
+const MaxVoteSubsets = 100000
+
+// VoteResult is the outcome of Vote.
+type VoteResult struct {
+	Secret   *big.Int
+	Votes    int
+	Subsets  int
+	Suspects []*big.Int
+}
+
+// Vote interpolates every k-subset of points and returns the secret
+// that the most subsets agree on, along with the shares that never took part
+// in an agreeing subset.
+func Vote(points []share.Point, k int, opts ...Option) (*VoteResult, error) {
+	n := len(points)
+	if k < 1 {
+		return nil, errNoPoints()
+	}
+	if n < k {
+		return nil, &share.InsufficientSharesError{Found: n, Needed: k}
+	}
+	total := new(big.Int).Binomial(int64(n), int64(k))
+	if total.Cmp(big.NewInt(MaxVoteSubsets)) > 0 {
+		return nil, fmt.Errorf("refusing to vote over %s subsets: the limit is %d", total.String(), MaxVoteSubsets)
+	}
+
+	type tally struct {
+		secret  *big.Int
+		votes   int
+		members []bool
+	}
+	tallies := make(map[string]*tally)
+	var order []string
+
+	indices := make([]int, k)
+	for i := range indices {
+		indices[i] = i
+	}
+	subset := make([]share.Point, k)
+
+	subsets := 0
+	for {
+		for i, idx := range indices {
+			subset[i] = points[idx]
+		}
+
+		secret, err := Interpolate(subset, opts...)
+		subsets++
+
+		if err == nil {
+			key := secret.String()
+			t, ok := tallies[key]
+			if !ok {
+				t = &tally{secret: secret, members: make([]bool, n)}
+				tallies[key] = t
+				order = append(order, key)
+			}
+			t.votes++
+			for _, idx := range indices {
+				t.members[idx] = true
+			}
+		}
+
+		// Advance to the next combination in lexicographic order.
+		i := k - 1
+		for i >= 0 && indices[i] == n-k+i {
+			i--
+		}
+		if i < 0 {
+			break
+		}
+		indices[i]++
+		for j := i + 1; j < k; j++ {
+			indices[j] = indices[j-1] + 1
+		}
+	}
+
+	if len(order) == 0 {
+		return nil, errors.New("no subset of shares produced an integer secret")
+	}
+
+	var winner *tally
+	for _, key := range order {
+		if winner == nil || tallies[key].votes > winner.votes {
+			winner = tallies[key]
+		}
+	}
+
+	result := &VoteResult{Secret: winner.secret, Votes: winner.votes, Subsets: subsets}
+	for idx, member := range winner.members {
+		if !member {
+			result.Suspects = append(result.Suspects, points[idx].X)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,119 @@
+// Package reconstruct recovers the secret of a share set: it checks the
+// shares against their commitments, interpolates the first k of them and
+// checks the others against the polynomial through those.
+package reconstruct
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/OmSingh2003/CATALOG-ASSIGNMENT/pkg/gf2m"
+	"github.com/OmSingh2003/CATALOG-ASSIGNMENT/pkg/lagrange"
+	"github.com/OmSingh2003/CATALOG-ASSIGNMENT/pkg/share"
+)
+
+// Options configures Shares.
+type Options struct {
+	// NoVerify skips checking the unused shares against the polynomial.
+	NoVerify bool
+
+	// NoVSS skips checking the shares against their commitments.
+	NoVSS bool
+
+	// Lagrange configures the interpolation. Without WithModulus or
+	// WithField it is over the field the document records, if any.
+	Lagrange []lagrange.Option
+}
+
+// ErrNoModulus is a document with commitments that records no modulus and
+// was given none, so that its polynomial, over a prime field, cannot be
+// interpolated.
+var ErrNoModulus = errors.New("shares with commitments record no modulus")
+
+// Result is a reconstructed secret and the shares it came from.
+type Result struct {
+	Secret *big.Int
+
+	// Used holds the k shares interpolated and Extra the others, which
+	// were checked against them unless Options.NoVerify was set.
+	Used, Extra []share.Point
+
+	// HashVerified reports that the secret matched the SHA-256 its
+	// document recorded at split time.
+	HashVerified bool
+}
+
+// InconsistentError reports unused shares that do not lie on the
+// reconstructed polynomial.
+type InconsistentError struct {
+	X []*big.Int
+}
+
+func (e *InconsistentError) Error() string {
+	parts := make([]string, len(e.X))
+	for i, x := range e.X {
+		parts[i] = x.String()
+	}
+	return fmt.Sprintf("shares inconsistent with the reconstructed polynomial at x=%s", strings.Join(parts, ", "))
+}
+
+// Shares reconstructs the secret of s. It keeps no state, so it is safe
+// for concurrent use.
+func Shares(s *share.Shares, opts Options) (*Result, error) {
+	if modulus, field := lagrange.Arithmetic(opts.Lagrange...); modulus == nil && field == nil {
+		recorded, err := recordedArithmetic(s)
+		if err != nil {
+			return nil, err
+		}
+		if recorded != nil {
+			opts.Lagrange = append([]lagrange.Option{recorded}, opts.Lagrange...)
+		}
+	}
+	if !opts.NoVSS {
+		if err := s.VerifyCommitments(); err != nil {
+			return nil, err
+		}
+	}
+	points, extra, err := s.Select()
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := lagrange.Interpolate(points, opts.Lagrange...)
+	if err != nil {
+		return nil, err
+	}
+	if !opts.NoVerify && len(extra) > 0 {
+		mismatches, err := lagrange.Verify(points, extra, opts.Lagrange...)
+		if err != nil {
+			return nil, err
+		}
+		if len(mismatches) > 0 {
+			return nil, &InconsistentError{X: mismatches}
+		}
+	}
+	if err := s.VerifySecret(secret); err != nil {
+		return nil, err
+	}
+	return &Result{Secret: secret, Used: points, Extra: extra, HashVerified: s.SecretSHA256 != ""}, nil
+}
+
+// recordedArithmetic returns the option interpolating over the field s
+// records, nil if it records none.
+func recordedArithmetic(s *share.Shares) (lagrange.Option, error) {
+	switch {
+	case s.Field == share.FieldGF2m && s.Poly != nil:
+		f, err := gf2m.New(s.Poly)
+		if err != nil {
+			return nil, err
+		}
+		return lagrange.WithField(f), nil
+	case s.Modulus != nil:
+		return lagrange.WithModulus(s.Modulus), nil
+	case s.Commitments != nil:
+		return nil, fmt.Errorf("%w; reconstruct them with the modulus they were split with", ErrNoModulus)
+	}
+	return nil, nil
+}
@@ -0,0 +1,169 @@
+package reconstruct_test
+
+import (
+	"errors"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/OmSingh2003/CATALOG-ASSIGNMENT/pkg/lagrange"
+	"github.com/OmSingh2003/CATALOG-ASSIGNMENT/pkg/reconstruct"
+	"github.com/OmSingh2003/CATALOG-ASSIGNMENT/pkg/share"
+)
+
+func readShares(t *testing.T, path string, opts ...share.ParseOption) *share.Shares {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	s, err := share.ParseShares(f, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestShares(t *testing.T) {
+	for _, tc := range []struct {
+		path         string
+		opts         []share.ParseOption
+		want         string
+		hashVerified bool
+	}{
+		{path: "../../testcase1.json", want: "3"},
+		{path: "../../testcase_vss.json", want: "123456789"},
+		{path: "../../testcase_pedersen.json"},
+		{path: "../../testcase_field_p256.json", hashVerified: true},
+		{path: "../../testcase_gf2m.json", opts: []share.ParseOption{share.BinaryField()}, hashVerified: true},
+	} {
+		t.Run(tc.path, func(t *testing.T) {
+			s := readShares(t, tc.path, tc.opts...)
+			result, err := reconstruct.Shares(s, reconstruct.Options{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tc.want != "" && result.Secret.String() != tc.want {
+				t.Errorf("secret = %s, want %s", result.Secret, tc.want)
+			}
+			if len(result.Used) != s.K || len(result.Used)+len(result.Extra) != len(s.Points) {
+				t.Errorf("%d shares used and %d extra, want k=%d of %d", len(result.Used), len(result.Extra), s.K, len(s.Points))
+			}
+			if result.HashVerified != tc.hashVerified {
+				t.Errorf("HashVerified = %v, want %v", result.HashVerified, tc.hashVerified)
+			}
+		})
+	}
+}
+
+func TestInconsistent(t *testing.T) {
+	s := readShares(t, "../../testcase1.json")
+	s.Points[3].Y = big.NewInt(40)
+
+	_, err := reconstruct.Shares(s, reconstruct.Options{})
+	var inconsistent *reconstruct.InconsistentError
+	if !errors.As(err, &inconsistent) {
+		t.Fatalf("err = %v, want an InconsistentError", err)
+	}
+	if len(inconsistent.X) != 1 || inconsistent.X[0].Int64() != 6 {
+		t.Errorf("inconsistent shares at x=%v, want x=6", inconsistent.X)
+	}
+
+	result, err := reconstruct.Shares(s, reconstruct.Options{NoVerify: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Secret.Int64() != 3 {
+		t.Errorf("secret without verifying = %s, want 3", result.Secret)
+	}
+}
+
+func TestCommitments(t *testing.T) {
+	s := readShares(t, "../../testcase_vss.json")
+	s.Points[0].Y = new(big.Int).Add(s.Points[0].Y, big.NewInt(1))
+	if _, err := reconstruct.Shares(s, reconstruct.Options{}); !errors.Is(err, share.ErrCommitmentMismatch) {
+		t.Errorf("err = %v, want %v", err, share.ErrCommitmentMismatch)
+	}
+	if _, err := reconstruct.Shares(s, reconstruct.Options{NoVSS: true, NoVerify: true}); err != nil {
+		t.Errorf("NoVSS: %v", err)
+	}
+
+	s.Modulus = nil
+	if _, err := reconstruct.Shares(s, reconstruct.Options{}); !errors.Is(err, reconstruct.ErrNoModulus) {
+		t.Errorf("no modulus: err = %v, want %v", err, reconstruct.ErrNoModulus)
+	}
+	modulus := share.LookupField("mersenne127").Modulus
+	if _, err := reconstruct.Shares(s, reconstruct.Options{NoVSS: true, NoVerify: true, Lagrange: []lagrange.Option{lagrange.WithModulus(modulus)}}); err != nil {
+		t.Errorf("WithModulus: %v", err)
+	}
+}
+
+func TestSecretHash(t *testing.T) {
+	s := readShares(t, "../../testcase1_tampered.json")
+	if _, err := reconstruct.Shares(s, reconstruct.Options{}); !errors.Is(err, share.ErrSecretHashMismatch) {
+		t.Errorf("err = %v, want %v", err, share.ErrSecretHashMismatch)
+	}
+}
+
+func TestInsufficient(t *testing.T) {
+	s := readShares(t, "../../testcase1.json")
+	s.Points = s.Points[:2]
+	if _, err := reconstruct.Shares(s, reconstruct.Options{}); !errors.Is(err, share.ErrInsufficientShares) {
+		t.Errorf("err = %v, want %v", err, share.ErrInsufficientShares)
+	}
+}
+
+func TestReshare(t *testing.T) {
+	old := readShares(t, "../../testcase_reshare.json")
+	reshared, err := reconstruct.Reshare(old, 5, 3, nil, share.WithSeed([]byte("reshare")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := reconstruct.Shares(reshared, reconstruct.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reshared.N != 5 || reshared.K != 3 || result.Secret.Int64() != 123456789 {
+		t.Errorf("reshared %d-of-%d with secret %s, want 3-of-5 with 123456789", reshared.K, reshared.N, result.Secret)
+	}
+
+	// Over the integers the secret is formed and split again.
+	old = readShares(t, "../../testcase1.json")
+	reshared, err = reconstruct.Reshare(old, 3, 2, nil, share.WithCommitments(""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if secret, err := lagrange.Interpolate(reshared.Points[1:]); err != nil || secret.Int64() != 3 {
+		t.Errorf("reshared testcase1.json has secret %v, %v, want 3", secret, err)
+	}
+}
+
+func TestReshareErrors(t *testing.T) {
+	corrupted := readShares(t, "../../testcase1.json")
+	corrupted.Points[3].Y = big.NewInt(40)
+	var inconsistent *reconstruct.InconsistentError
+	if _, err := reconstruct.Reshare(corrupted, 3, 2, nil); !errors.As(err, &inconsistent) || len(inconsistent.X) != 1 || inconsistent.X[0].Int64() != 6 {
+		t.Errorf("resharing an inconsistent share: err = %v, want one at x=6", err)
+	}
+
+	few := readShares(t, "../../testcase1.json")
+	few.Points = few.Points[:2]
+	if _, err := reconstruct.Reshare(few, 3, 2, nil); !errors.Is(err, share.ErrInsufficientShares) {
+		t.Errorf("resharing 2 shares of k=3: err = %v, want %v", err, share.ErrInsufficientShares)
+	}
+
+	if _, err := reconstruct.Reshare(readShares(t, "../../testcase1_tampered.json"), 3, 2, nil); !errors.Is(err, share.ErrSecretHashMismatch) {
+		t.Errorf("resharing tampered shares: err = %v, want %v", err, share.ErrSecretHashMismatch)
+	}
+
+	vss := readShares(t, "../../testcase_vss.json")
+	vss.Points[0].Y = new(big.Int).Add(vss.Points[0].Y, big.NewInt(1))
+	if _, err := reconstruct.Reshare(vss, 5, 3, nil); !errors.Is(err, share.ErrCommitmentMismatch) {
+		t.Errorf("resharing a share that fails its commitment: err = %v, want %v", err, share.ErrCommitmentMismatch)
+	}
+	vss.Modulus = nil
+	if _, err := reconstruct.Reshare(vss, 5, 3, nil); !errors.Is(err, reconstruct.ErrNoModulus) {
+		t.Errorf("resharing commitments without a modulus: err = %v, want %v", err, reconstruct.ErrNoModulus)
+	}
+}
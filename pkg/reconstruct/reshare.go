@@ -0,0 +1,69 @@
+package reconstruct
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/OmSingh2003/CATALOG-ASSIGNMENT/pkg/lagrange"
+	"github.com/OmSingh2003/CATALOG-ASSIGNMENT/pkg/share"
+)
+
+// Reshare returns a fresh set of n shares with threshold k of the secret of
+// old, after checking old against its commitments and the shares beyond
+// the first k against the polynomial through those. Over the prime modulus
+// old records, or modulus when it records none, the secret is not formed:
+// see share.Reshare. Over the integers the Lagrange coefficients are
+// fractions and the old shares cannot each be split, so the secret is
+// reconstructed in memory, checked against the hash old records and split
+// again; the new shares keep the expected value of old and record a secret
+// hash if it does.
+func Reshare(old *share.Shares, n, k int, modulus *big.Int, opts ...share.SplitOption) (*share.Shares, error) {
+	if len(old.Points) < old.K {
+		return nil, &share.InsufficientSharesError{Found: len(old.Points), Needed: old.K}
+	}
+	if modulus == nil {
+		modulus = old.Modulus
+	}
+	if old.Commitments != nil {
+		if modulus == nil {
+			return nil, fmt.Errorf("%w; reshare them with the modulus they were split with", ErrNoModulus)
+		}
+		if err := old.VerifyCommitments(); err != nil {
+			return nil, err
+		}
+	}
+	var lagrangeOpts []lagrange.Option
+	if modulus != nil {
+		lagrangeOpts = append(lagrangeOpts, lagrange.WithModulus(modulus))
+	}
+	var mismatches []*big.Int
+	for _, point := range old.Points[old.K:] {
+		y, err := lagrange.InterpolateAt(old.Points[:old.K], point.X, lagrangeOpts...)
+		if err != nil {
+			return nil, err
+		}
+		if y.Cmp(point.Y) != 0 {
+			mismatches = append(mismatches, point.X)
+		}
+	}
+	if len(mismatches) > 0 {
+		return nil, &InconsistentError{X: mismatches}
+	}
+
+	if modulus != nil {
+		return share.Reshare(old, n, k, modulus, opts...)
+	}
+	secret, err := lagrange.Interpolate(old.Points[:old.K])
+	if err != nil {
+		return nil, err
+	}
+	if err := old.VerifySecret(secret); err != nil {
+		return nil, err
+	}
+	reshared, err := share.Split(secret, n, k, nil, append(opts[:len(opts):len(opts)], share.WithSecretHash(old.SecretSHA256 != ""))...)
+	if err != nil {
+		return nil, err
+	}
+	reshared.Expected = old.Expected
+	return reshared, nil
+}
@@ -0,0 +1,138 @@
+// Package report records how a secret was reconstructed, for audit: the
+// inputs and their hashes, the shares interpolated, the checks made, how
+// long each step took and a hash of the secret. The secret itself and the
+// share values are never part of a report.
+package report
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"math/big"
+	"os"
+	"time"
+
+	"github.com/OmSingh2003/CATALOG-ASSIGNMENT/pkg/reconstruct"
+	"github.com/OmSingh2003/CATALOG-ASSIGNMENT/pkg/share"
+)
+
+// Input is an input of a reconstruction. SHA256 is the hash of its bytes
+// as stored, before any decryption; it is empty for inputs, such as stdin,
+// that cannot be read a second time.
+type Input struct {
+	Path   string `json:"path"`
+	SHA256 string `json:"sha256,omitempty"`
+}
+
+// Verification is what was checked besides interpolating.
+type Verification struct {
+	// Commitments is the number of shares checked against Feldman or
+	// Pedersen commitments.
+	Commitments int `json:"commitments"`
+
+	// Unused is the number of shares beyond the threshold and Verified
+	// how many of them were checked against the polynomial; Inconsistent
+	// lists the x of those that do not lie on it.
+	Unused       int      `json:"unused"`
+	Verified     int      `json:"verified"`
+	Inconsistent []string `json:"inconsistent,omitempty"`
+}
+
+// Timing holds when a reconstruction started and ended, and how long
+// parsing and interpolating took, in milliseconds.
+type Timing struct {
+	Start           time.Time `json:"start"`
+	End             time.Time `json:"end"`
+	ParseMS         float64   `json:"parse_ms"`
+	InterpolationMS float64   `json:"interpolation_ms"`
+}
+
+// Report is the audit record of one reconstruction. Error is set instead
+// of SecretSHA256 if it failed.
+type Report struct {
+	ToolVersion  string       `json:"tool_version"`
+	Inputs       []Input      `json:"inputs"`
+	N            int          `json:"n"`
+	K            int          `json:"k"`
+	XUsed        []string     `json:"x_used"`
+	Algorithm    string       `json:"algorithm"`
+	Field        string       `json:"field"`
+	Modulus      string       `json:"modulus,omitempty"`
+	Poly         string       `json:"poly,omitempty"`
+	Verification Verification `json:"verification"`
+	Timing       Timing       `json:"timing"`
+	SecretSHA256 string       `json:"secret_sha256,omitempty"`
+	Error        string       `json:"error,omitempty"`
+}
+
+// New returns a report of a reconstruction starting now. A nil modulus
+// means exact interpolation over the integers.
+func New(version, algorithm string, modulus *big.Int) *Report {
+	r := &Report{ToolVersion: version, Algorithm: algorithm, Field: "integer", Timing: Timing{Start: time.Now()}}
+	if modulus != nil {
+		r.Field, r.Modulus = "prime", modulus.String()
+	}
+	return r
+}
+
+// AddInput records the input at path, hashing the file if it is one.
+func (r *Report) AddInput(path string) {
+	input := Input{Path: path}
+	if data, err := os.ReadFile(path); err == nil {
+		sum := sha256.Sum256(data)
+		input.SHA256 = hex.EncodeToString(sum[:])
+	}
+	r.Inputs = append(r.Inputs, input)
+}
+
+// SetShares records the threshold and share count of shares.
+func (r *Report) SetShares(shares *share.Shares) {
+	r.N, r.K = shares.N, shares.K
+}
+
+// SetResult records the shares used and checked and the hash of the secret
+// of result. unverified says whether the unused shares were left unchecked.
+func (r *Report) SetResult(result *reconstruct.Result, unverified bool) {
+	r.XUsed = make([]string, len(result.Used))
+	for i, point := range result.Used {
+		r.XUsed[i] = point.X.String()
+	}
+	r.Verification.Unused = len(result.Extra)
+	if !unverified {
+		r.Verification.Verified = len(result.Extra)
+	}
+	r.SecretSHA256 = SecretHash(result.Secret)
+}
+
+// Fail records the error a reconstruction failed with, and the x of any
+// inconsistent shares.
+func (r *Report) Fail(err error) {
+	r.Error = err.Error()
+	r.SecretSHA256 = ""
+	var inconsistent *reconstruct.InconsistentError
+	if errors.As(err, &inconsistent) {
+		for _, x := range inconsistent.X {
+			r.Verification.Inconsistent = append(r.Verification.Inconsistent, x.String())
+		}
+	}
+}
+
+// Write sets the end time of the report and writes it to w as JSON.
+func (r *Report) Write(w io.Writer) error {
+	r.Timing.End = time.Now()
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+// SecretHash returns the hex SHA-256 of the decimal form of secret, so
+// that a report can be matched against a known secret without holding it.
+func SecretHash(secret *big.Int) string {
+	sum := sha256.Sum256([]byte(secret.String()))
+	return hex.EncodeToString(sum[:])
+}
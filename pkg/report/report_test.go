@@ -0,0 +1,86 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/OmSingh2003/CATALOG-ASSIGNMENT/pkg/reconstruct"
+	"github.com/OmSingh2003/CATALOG-ASSIGNMENT/pkg/share"
+)
+
+func TestReport(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "shares.json")
+	if err := os.WriteFile(path, []byte("shares"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	r := New("v1.2.3", "lagrange", big.NewInt(101))
+	if r.Field != "prime" || r.Modulus != "101" {
+		t.Errorf("field %q modulus %q, want prime 101", r.Field, r.Modulus)
+	}
+	r.AddInput(path)
+	r.AddInput("stdin")
+	r.SetShares(&share.Shares{N: 4, K: 2})
+	secret := big.NewInt(987654321)
+	r.SetResult(&reconstruct.Result{
+		Secret: secret,
+		Used:   []share.Point{{X: big.NewInt(1), Y: big.NewInt(11111)}, {X: big.NewInt(2), Y: big.NewInt(22222)}},
+		Extra:  []share.Point{{X: big.NewInt(3), Y: big.NewInt(33333)}},
+	}, false)
+
+	var buf bytes.Buffer
+	if err := r.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	for _, value := range []string{"987654321", "11111", "22222", "33333"} {
+		if strings.Contains(buf.String(), value) {
+			t.Errorf("report holds the secret or a share value %s:\n%s", value, buf.String())
+		}
+	}
+	var got Report
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	// The SHA-256 of "shares".
+	if want := "4a64609f7db9facca39eaf9f66d77f8f030236e4a826d9fabd09f6b27c465c54"; got.Inputs[0].SHA256 != want {
+		t.Errorf("input hash = %s, want %s", got.Inputs[0].SHA256, want)
+	}
+	if got.Inputs[1].SHA256 != "" {
+		t.Errorf("stdin has hash %s, want none", got.Inputs[1].SHA256)
+	}
+	if got.N != 4 || got.K != 2 || strings.Join(got.XUsed, ",") != "1,2" {
+		t.Errorf("n=%d k=%d x_used=%v, want n=4 k=2 x_used=[1 2]", got.N, got.K, got.XUsed)
+	}
+	if got.Verification.Unused != 1 || got.Verification.Verified != 1 {
+		t.Errorf("verification = %+v, want 1 unused and verified", got.Verification)
+	}
+	if got.SecretSHA256 != SecretHash(secret) || got.Timing.End.Before(got.Timing.Start) {
+		t.Errorf("secret hash %s, timing %+v", got.SecretSHA256, got.Timing)
+	}
+}
+
+func TestReportFail(t *testing.T) {
+	r := New("v1.2.3", "lagrange", nil)
+	if r.Field != "integer" || r.Modulus != "" {
+		t.Errorf("field %q modulus %q, want integer and none", r.Field, r.Modulus)
+	}
+	r.SetResult(&reconstruct.Result{Secret: big.NewInt(3)}, true)
+	r.Fail(&reconstruct.InconsistentError{X: []*big.Int{big.NewInt(4), big.NewInt(6)}})
+	if r.SecretSHA256 != "" {
+		t.Error("a failed report keeps the secret hash")
+	}
+	if !strings.Contains(r.Error, "x=4, 6") || strings.Join(r.Verification.Inconsistent, ",") != "4,6" {
+		t.Errorf("error %q, inconsistent %v, want x=4 and 6", r.Error, r.Verification.Inconsistent)
+	}
+}
+
+func TestSecretHash(t *testing.T) {
+	// The SHA-256 of "3".
+	if got, want := SecretHash(big.NewInt(3)), "4e07408562bedb8b60ce05c1decfe3ad16b72230967de01f640b7e4729b49fce"; got != want {
+		t.Errorf("SecretHash(3) = %s, want %s", got, want)
+	}
+}
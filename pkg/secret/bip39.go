@@ -0,0 +1,73 @@
+package secret
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// bip39Lengths are the entropy sizes, in bytes, of 12- to 24-word BIP-39
+// mnemonics.
+var bip39Lengths = []int{16, 20, 24, 28, 32}
+
+// BIP39 writes entropy as its English BIP-39 mnemonic. entropy must be 16,
+// 20, 24, 28 or 32 bytes long.
+func BIP39(entropy []byte) (string, error) {
+	if !validBIP39Length(len(entropy)) {
+		return "", fmt.Errorf("a %d-byte secret has no BIP-39 mnemonic: entropy must be 16, 20, 24, 28 or 32 bytes", len(entropy))
+	}
+
+	checksumBits := len(entropy) / 4
+	digest := sha256.Sum256(entropy)
+	bits := new(big.Int).SetBytes(entropy)
+	bits.Lsh(bits, uint(checksumBits))
+	bits.Or(bits, big.NewInt(int64(digest[0]>>(8-checksumBits))))
+
+	words := make([]string, (8*len(entropy)+checksumBits)/11)
+	mask := big.NewInt(2047)
+	index := new(big.Int)
+	for i := len(words) - 1; i >= 0; i-- {
+		words[i] = bip39Words[index.And(bits, mask).Int64()]
+		bits.Rsh(bits, 11)
+	}
+	return strings.Join(words, " "), nil
+}
+
+// EncodeBIP39 writes v as a BIP-39 mnemonic, left-padding its bytes with
+// zeros to length, or to the shortest valid entropy size when length is
+// zero.
+func EncodeBIP39(v *big.Int, length int) (string, error) {
+	if v.Sign() < 0 {
+		return "", fmt.Errorf("cannot encode negative secret %s as bip39", v.String())
+	}
+	size := len(v.Bytes())
+	if length == 0 {
+		for _, n := range bip39Lengths {
+			if n >= size {
+				length = n
+				break
+			}
+		}
+		if length == 0 {
+			return "", fmt.Errorf("secret needs %d bytes, more than the 32 bytes of BIP-39 entropy", size)
+		}
+	} else if !validBIP39Length(length) {
+		return "", fmt.Errorf("--byte-length %d is not a BIP-39 entropy length (16, 20, 24, 28 or 32)", length)
+	}
+
+	data, err := Bytes(v, length)
+	if err != nil {
+		return "", err
+	}
+	return BIP39(data)
+}
+
+func validBIP39Length(n int) bool {
+	for _, length := range bip39Lengths {
+		if n == length {
+			return true
+		}
+	}
+	return false
+}
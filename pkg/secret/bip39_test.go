@@ -0,0 +1,71 @@
+package secret
+
+import (
+	"bytes"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestBIP39(t *testing.T) {
+	// Vectors from the BIP-39 reference test set.
+	for _, tc := range []struct {
+		entropy []byte
+		want    string
+	}{
+		{bytes.Repeat([]byte{0x00}, 16), strings.Repeat("abandon ", 11) + "about"},
+		{bytes.Repeat([]byte{0x7f}, 16), "legal winner thank year wave sausage worth useful legal winner thank yellow"},
+		{bytes.Repeat([]byte{0x80}, 16), "letter advice cage absurd amount doctor acoustic avoid letter advice cage above"},
+		{bytes.Repeat([]byte{0xff}, 16), strings.Repeat("zoo ", 11) + "wrong"},
+		{bytes.Repeat([]byte{0x00}, 32), strings.Repeat("abandon ", 23) + "art"},
+		{bytes.Repeat([]byte{0xff}, 32), strings.Repeat("zoo ", 23) + "vote"},
+	} {
+		got, err := BIP39(tc.entropy)
+		if err != nil {
+			t.Errorf("BIP39(%x): %v", tc.entropy, err)
+			continue
+		}
+		if got != tc.want {
+			t.Errorf("BIP39(%x) = %q, want %q", tc.entropy, got, tc.want)
+		}
+	}
+	for _, n := range []int{0, 15, 17, 33} {
+		if _, err := BIP39(make([]byte, n)); err == nil {
+			t.Errorf("BIP39 of %d bytes returned no error", n)
+		}
+	}
+}
+
+func TestEncodeBIP39(t *testing.T) {
+	for _, tc := range []struct {
+		v      *big.Int
+		length int
+		words  int
+	}{
+		{big.NewInt(0), 0, 12},
+		{new(big.Int).Lsh(big.NewInt(1), 127), 0, 12},
+		{new(big.Int).Lsh(big.NewInt(1), 128), 0, 15},
+		{big.NewInt(1), 32, 24},
+	} {
+		got, err := EncodeBIP39(tc.v, tc.length)
+		if err != nil {
+			t.Errorf("EncodeBIP39(%s, %d): %v", tc.v, tc.length, err)
+			continue
+		}
+		if words := len(strings.Fields(got)); words != tc.words {
+			t.Errorf("EncodeBIP39(%s, %d) has %d words, want %d", tc.v, tc.length, words, tc.words)
+		}
+	}
+	for _, tc := range []struct {
+		v      *big.Int
+		length int
+	}{
+		{big.NewInt(-1), 0},
+		{new(big.Int).Lsh(big.NewInt(1), 256), 0},
+		{big.NewInt(1), 17},
+	} {
+		if _, err := EncodeBIP39(tc.v, tc.length); err == nil {
+			t.Errorf("EncodeBIP39(%s, %d) returned no error", tc.v, tc.length)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+// Package secret renders reconstructed secrets for output.
+package secret
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+)
+
+// Encodings lists the names accepted by Encode.
+var Encodings = []string{"dec", "hex", "base58", "base64", "base64url", "bip39"}
+
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// Encode renders v in the named encoding. Hex is lowercase and, with prefix
+// set, starts with 0x; negative values keep a leading minus sign in dec and
+// hex and are rejected by the byte-oriented encodings, which treat zero as a
+// single zero byte. bip39 pads v as EncodeBIP39 does with no length.
+func Encode(v *big.Int, encoding string, prefix bool) (string, error) {
+	switch encoding {
+	case "", "dec":
+		return v.String(), nil
+	case "hex":
+		digits := new(big.Int).Abs(v).Text(16)
+		if prefix {
+			digits = "0x" + digits
+		}
+		if v.Sign() < 0 {
+			digits = "-" + digits
+		}
+		return digits, nil
+	case "base58", "base64", "base64url":
+		if v.Sign() < 0 {
+			return "", fmt.Errorf("cannot encode negative secret %s as %s", v.String(), encoding)
+		}
+		data := v.Bytes()
+		if len(data) == 0 {
+			data = []byte{0}
+		}
+		return EncodeBytes(data, encoding, prefix)
+	case "bip39":
+		return EncodeBIP39(v, 0)
+	}
+	return "", fmt.Errorf("unknown encoding: %s", encoding)
+}
+
+// EncodeBytes renders a secret that is a byte string rather than a number.
+// Unlike Encode, hex keeps every leading zero byte; dec is the big-endian
+// value of the bytes.
+func EncodeBytes(data []byte, encoding string, prefix bool) (string, error) {
+	switch encoding {
+	case "", "dec":
+		return new(big.Int).SetBytes(data).String(), nil
+	case "hex":
+		digits := hex.EncodeToString(data)
+		if prefix {
+			digits = "0x" + digits
+		}
+		return digits, nil
+	case "base58":
+		return base58(data), nil
+	case "base64":
+		return base64.StdEncoding.EncodeToString(data), nil
+	case "base64url":
+		return base64.RawURLEncoding.EncodeToString(data), nil
+	case "bip39":
+		return BIP39(data)
+	}
+	return "", fmt.Errorf("unknown encoding: %s", encoding)
+}
+
+// base58 encodes data with the Bitcoin alphabet, writing each leading zero
+// byte as '1'.
+func base58(data []byte) string {
+	n := new(big.Int).SetBytes(data)
+	radix := big.NewInt(58)
+	mod := new(big.Int)
+
+	var out []byte
+	for n.Sign() > 0 {
+		n.DivMod(n, radix, mod)
+		out = append(out, base58Alphabet[mod.Int64()])
+	}
+	for _, b := range data {
+		if b != 0 {
+			break
+		}
+		out = append(out, base58Alphabet[0])
+	}
+
+	for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+		out[i], out[j] = out[j], out[i]
+	}
+	return string(out)
+}
@@ -0,0 +1,59 @@
+package secret
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestEncode(t *testing.T) {
+	for _, tc := range []struct {
+		v        int64
+		encoding string
+		prefix   bool
+		want     string
+	}{
+		{255, "dec", false, "255"},
+		{255, "hex", false, "ff"},
+		{255, "hex", true, "0xff"},
+		{-255, "hex", true, "-0xff"},
+		{-255, "dec", false, "-255"},
+		{0, "base64", false, "AA=="},
+		{0xfbff, "base64", false, "+/8="},
+		{0xfbff, "base64url", false, "-_8"},
+		{0x287fb4cd, "base58", false, "233QC4"},
+	} {
+		got, err := Encode(big.NewInt(tc.v), tc.encoding, tc.prefix)
+		if err != nil {
+			t.Errorf("Encode(%d, %s): %v", tc.v, tc.encoding, err)
+			continue
+		}
+		if got != tc.want {
+			t.Errorf("Encode(%d, %s, %t) = %q, want %q", tc.v, tc.encoding, tc.prefix, got, tc.want)
+		}
+	}
+	for _, encoding := range []string{"base58", "base64", "base64url"} {
+		if _, err := Encode(big.NewInt(-1), encoding, false); err == nil {
+			t.Errorf("Encode(-1, %s) returned no error", encoding)
+		}
+	}
+	if _, err := Encode(big.NewInt(1), "base32", false); err == nil {
+		t.Error("Encode with an unknown encoding returned no error")
+	}
+}
+
+func TestEncodeBytes(t *testing.T) {
+	data := []byte{0, 0, 0x28, 0x7f, 0xb4, 0xcd}
+	for encoding, want := range map[string]string{
+		"hex":    "0000287fb4cd",
+		"base58": "11233QC4",
+		"dec":    "679457997",
+	} {
+		got, err := EncodeBytes(data, encoding, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("EncodeBytes(%x, %s) = %q, want %q", data, encoding, got, want)
+		}
+	}
+}
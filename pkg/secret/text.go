@@ -0,0 +1,26 @@
+package secret
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// Bytes returns the big-endian bytes of v, left-padded with zeros to length
+// when length is positive. Zero with no length is a single zero byte.
+func Bytes(v *big.Int, length int) ([]byte, error) {
+	if v.Sign() < 0 {
+		return nil, fmt.Errorf("cannot convert negative secret %s to bytes", v.String())
+	}
+
+	data := v.Bytes()
+	if length <= 0 {
+		if len(data) == 0 {
+			data = []byte{0}
+		}
+		return data, nil
+	}
+	if len(data) > length {
+		return nil, fmt.Errorf("secret needs %d bytes, more than --byte-length %d", len(data), length)
+	}
+	return append(make([]byte, length-len(data)), data...), nil
+}
@@ -0,0 +1,127 @@
+// Package selftest checks the shipped binary against known-answer vectors
+// embedded in it: share files, each with a sidecar stating the secret it
+// reconstructs to or a substring of the error it must fail with, run
+// through the same parsing and interpolation as any other input, and
+// products and inverses in binary fields.
+package selftest
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/OmSingh2003/CATALOG-ASSIGNMENT/pkg/gf2m"
+	"github.com/OmSingh2003/CATALOG-ASSIGNMENT/pkg/lagrange"
+	"github.com/OmSingh2003/CATALOG-ASSIGNMENT/pkg/reconstruct"
+	"github.com/OmSingh2003/CATALOG-ASSIGNMENT/pkg/share"
+)
+
+//go:embed vectors
+var vectors embed.FS
+
+// expectation is the sidecar <name>.expected.json of a vector. Mod is the
+// prime of a vector over a prime field, and Binary marks one over the
+// GF(2^m) its keys object records.
+type expectation struct {
+	Secret string `json:"secret"`
+	Error  string `json:"error"`
+	Mod    string `json:"mod"`
+	Binary bool   `json:"binary"`
+}
+
+// Result is the outcome of one vector; Err is nil if it passed.
+type Result struct {
+	Name string
+	Err  error
+}
+
+// Run runs every embedded vector, in name order, and then the arithmetic
+// vectors.
+func Run() []Result {
+	paths, _ := fs.Glob(vectors, "vectors/*.expected.json")
+	sort.Strings(paths)
+	results := make([]Result, 0, len(paths)+len(arithmeticVectors))
+	for _, path := range paths {
+		name := strings.TrimSuffix(strings.TrimPrefix(path, "vectors/"), ".expected.json")
+		results = append(results, Result{Name: name, Err: check(name)})
+	}
+	for _, v := range arithmeticVectors {
+		results = append(results, Result{Name: v.name, Err: v.check()})
+	}
+	return results
+}
+
+func check(name string) error {
+	sidecar, err := vectors.ReadFile("vectors/" + name + ".expected.json")
+	if err != nil {
+		return err
+	}
+	var want expectation
+	if err := json.Unmarshal(sidecar, &want); err != nil {
+		return fmt.Errorf("invalid sidecar: %w", err)
+	}
+	if (want.Secret == "") == (want.Error == "") {
+		return fmt.Errorf("invalid sidecar: exactly one of secret and error must be set")
+	}
+	data, err := vectors.ReadFile("vectors/" + name + ".json")
+	if err != nil {
+		return err
+	}
+
+	got, err := reconstructVector(data, want.Mod, want.Binary)
+	if want.Error != "" {
+		switch {
+		case err == nil:
+			return fmt.Errorf("reconstructed %s, want an error containing %q", got, want.Error)
+		case !strings.Contains(err.Error(), want.Error):
+			return fmt.Errorf("failed with %q, want an error containing %q", err, want.Error)
+		}
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if got.String() != want.Secret {
+		return fmt.Errorf("reconstructed %s, want %s", got, want.Secret)
+	}
+	return nil
+}
+
+func reconstructVector(data []byte, mod string, binary bool) (*big.Int, error) {
+	var opts reconstruct.Options
+	parseOpts := []share.ParseOption{share.RequireThreshold()}
+	if binary {
+		parseOpts = append(parseOpts, share.BinaryField())
+	}
+	if mod != "" {
+		p, ok := new(big.Int).SetString(mod, 10)
+		if !ok {
+			return nil, fmt.Errorf("invalid sidecar: mod %q is not a decimal integer", mod)
+		}
+		opts.Lagrange = append(opts.Lagrange, lagrange.WithModulus(p))
+	}
+	shares, err := share.ParseShares(bytes.NewReader(data), parseOpts...)
+	if err != nil {
+		return nil, err
+	}
+	if binary {
+		if shares.Poly == nil {
+			return nil, fmt.Errorf("invalid vector: the keys object records no poly")
+		}
+		f, err := gf2m.New(shares.Poly)
+		if err != nil {
+			return nil, err
+		}
+		opts.Lagrange = append(opts.Lagrange, lagrange.WithField(f))
+	}
+	result, err := reconstruct.Shares(shares, opts)
+	if err != nil {
+		return nil, err
+	}
+	return result.Secret, nil
+}
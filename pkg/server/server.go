@@ -0,0 +1,176 @@
+// Package server exposes reconstruction over HTTP. POST /reconstruct takes
+// a JSON share document as its body and answers with the secret, or with a
+// structured error and a 4xx status.
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"math/big"
+	"net/http"
+	"time"
+
+	"github.com/OmSingh2003/CATALOG-ASSIGNMENT/pkg/lagrange"
+	"github.com/OmSingh2003/CATALOG-ASSIGNMENT/pkg/reconstruct"
+	"github.com/OmSingh2003/CATALOG-ASSIGNMENT/pkg/share"
+)
+
+// DefaultMaxBodyBytes is the largest request body accepted unless
+// WithMaxBodyBytes says otherwise.
+const DefaultMaxBodyBytes = 1 << 20
+
+// Option configures Handler.
+type Option func(*config)
+
+type config struct {
+	maxBodyBytes int64
+	modulus      *big.Int
+	logger       *log.Logger
+}
+
+// WithMaxBodyBytes sets the largest request body accepted; larger ones
+// get 413.
+func WithMaxBodyBytes(n int64) Option {
+	return func(c *config) {
+		c.maxBodyBytes = n
+	}
+}
+
+// WithModulus reconstructs modulo the prime p. Without it a document is
+// reconstructed modulo the prime its keys object records, if any.
+func WithModulus(p *big.Int) Option {
+	return func(c *config) {
+		c.modulus = p
+	}
+}
+
+// WithLogger logs one line per request to l: the method, path, status and
+// duration. Bodies, and so share values and secrets, are never logged.
+func WithLogger(l *log.Logger) Option {
+	return func(c *config) {
+		c.logger = l
+	}
+}
+
+// Result is the body of a successful reconstruction.
+type Result struct {
+	Secret     string   `json:"secret"`
+	PointsUsed []string `json:"pointsUsed"`
+}
+
+// Error is the body of a failed request.
+type Error struct {
+	Error ErrorDetail `json:"error"`
+}
+
+// ErrorDetail says why a request failed. Code is stable for callers to
+// branch on; Message is for people.
+type ErrorDetail struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// Handler returns the HTTP handler of the server. It keeps no state
+// between requests, so concurrent requests are safe.
+func Handler(opts ...Option) http.Handler {
+	c := &config{maxBodyBytes: DefaultMaxBodyBytes}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/reconstruct", c.reconstruct)
+	if c.logger == nil {
+		return mux
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		mux.ServeHTTP(rec, r)
+		c.logger.Printf("%s %s %d %s", r.Method, r.URL.Path, rec.status, time.Since(start).Round(time.Microsecond))
+	})
+}
+
+func (c *config) reconstruct(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeError(w, http.StatusMethodNotAllowed, "method_not_allowed", "use POST")
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, c.maxBodyBytes))
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		writeError(w, http.StatusRequestEntityTooLarge, "body_too_large", "request body exceeds the size limit")
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "unreadable_body", err.Error())
+		return
+	}
+
+	shares, err := share.ParseShares(bytes.NewReader(body), share.RequireThreshold())
+	if err != nil {
+		status, code := http.StatusBadRequest, "invalid_document"
+		switch {
+		case errors.Is(err, share.ErrInsufficientShares):
+			status, code = http.StatusUnprocessableEntity, "insufficient_shares"
+		case errors.Is(err, share.ErrChecksumMismatch):
+			status, code = http.StatusUnprocessableEntity, "checksum_mismatch"
+		}
+		writeError(w, status, code, err.Error())
+		return
+	}
+
+	opts := reconstruct.Options{}
+	if c.modulus != nil {
+		opts.Lagrange = append(opts.Lagrange, lagrange.WithModulus(c.modulus))
+	}
+	result, err := reconstruct.Shares(shares, opts)
+	if err != nil {
+		code := "reconstruction_failed"
+		var inconsistent *reconstruct.InconsistentError
+		switch {
+		case errors.Is(err, share.ErrCommitmentMismatch):
+			code = "commitment_mismatch"
+		case errors.As(err, &inconsistent):
+			code = "inconsistent_shares"
+		case errors.Is(err, share.ErrInsufficientShares):
+			code = "insufficient_shares"
+		case errors.Is(err, reconstruct.ErrNoModulus):
+			code = "missing_modulus"
+		}
+		writeError(w, http.StatusUnprocessableEntity, code, err.Error())
+		return
+	}
+
+	used := make([]string, len(result.Used))
+	for i, point := range result.Used {
+		used[i] = point.X.String()
+	}
+	writeJSON(w, http.StatusOK, Result{Secret: result.Secret.String(), PointsUsed: used})
+}
+
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	writeJSON(w, status, Error{Error: ErrorDetail{Code: code, Message: message}})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// statusRecorder remembers the status a handler wrote, for the log.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
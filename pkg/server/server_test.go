@@ -0,0 +1,170 @@
+package server_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/OmSingh2003/CATALOG-ASSIGNMENT/pkg/server"
+)
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+// post sends body to POST /reconstruct of a server with opts and returns
+// the response and its decoded body.
+func post(t *testing.T, body string, opts ...server.Option) (*http.Response, []byte) {
+	t.Helper()
+	srv := httptest.NewServer(server.Handler(opts...))
+	t.Cleanup(srv.Close)
+	resp, err := http.Post(srv.URL+"/reconstruct", "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var buf bytes.Buffer
+	if _, err := buf.ReadFrom(resp.Body); err != nil {
+		t.Fatal(err)
+	}
+	if got := resp.Header.Get("Content-Type"); got != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", got)
+	}
+	return resp, buf.Bytes()
+}
+
+func TestReconstruct(t *testing.T) {
+	for _, tc := range []struct {
+		path string
+		want string
+		used []string
+	}{
+		{"../../testcase1.json", "3", []string{"1", "2", "3"}},
+		{"../../testcase_negative.json", "-7", nil},
+		{"../../testcase_vss.json", "123456789", nil},
+	} {
+		t.Run(tc.path, func(t *testing.T) {
+			resp, body := post(t, readFile(t, tc.path))
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("status %d: %s", resp.StatusCode, body)
+			}
+			var result server.Result
+			if err := json.Unmarshal(body, &result); err != nil {
+				t.Fatal(err)
+			}
+			if result.Secret != tc.want {
+				t.Errorf("secret = %s, want %s", result.Secret, tc.want)
+			}
+			if tc.used != nil && strings.Join(result.PointsUsed, ",") != strings.Join(tc.used, ",") {
+				t.Errorf("pointsUsed = %v, want %v", result.PointsUsed, tc.used)
+			}
+		})
+	}
+}
+
+func TestErrors(t *testing.T) {
+	testcase1 := readFile(t, "../../testcase1.json")
+	vss := readFile(t, "../../testcase_vss.json")
+	for _, tc := range []struct {
+		name   string
+		body   string
+		opts   []server.Option
+		status int
+		code   string
+	}{
+		{"malformed json", `{"keys": {"n": 1,`, nil, http.StatusBadRequest, "invalid_document"},
+		{"invalid base", `{"keys": {"n": 1, "k": 1}, "1": {"base": "99", "value": "4"}}`, nil, http.StatusBadRequest, "invalid_document"},
+		{"oversized body", testcase1, []server.Option{server.WithMaxBodyBytes(16)}, http.StatusRequestEntityTooLarge, "body_too_large"},
+		{"insufficient shares", `{"keys": {"n": 3, "k": 3}, "1": {"base": "10", "value": "4"}, "2": {"base": "10", "value": "7"}}`, nil, http.StatusUnprocessableEntity, "insufficient_shares"},
+		{"checksum mismatch", strings.Replace(readFile(t, "../../testcase_checksum.json"), "49825890389260245365", "49825890389260245366", 1), nil, http.StatusUnprocessableEntity, "checksum_mismatch"},
+		{"inconsistent shares", strings.Replace(testcase1, `"value": "213"`, `"value": "220"`, 1), nil, http.StatusUnprocessableEntity, "inconsistent_shares"},
+		{"commitment mismatch", tamperFirstValue(t, vss), nil, http.StatusUnprocessableEntity, "commitment_mismatch"},
+		{"missing modulus", strings.Replace(vss, `"modulus": "170141183460469231731687303715884105727",`, "", 1), nil, http.StatusUnprocessableEntity, "missing_modulus"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, body := post(t, tc.body, tc.opts...)
+			if resp.StatusCode != tc.status {
+				t.Errorf("status = %d, want %d: %s", resp.StatusCode, tc.status, body)
+			}
+			var e server.Error
+			if err := json.Unmarshal(body, &e); err != nil {
+				t.Fatal(err)
+			}
+			if e.Error.Code != tc.code {
+				t.Errorf("code = %q, want %q (%s)", e.Error.Code, tc.code, e.Error.Message)
+			}
+		})
+	}
+}
+
+// tamperFirstValue changes the last digit of the first share value of a
+// document in base 10.
+func tamperFirstValue(t *testing.T, document string) string {
+	t.Helper()
+	const marker = `"value": "`
+	i := strings.Index(document, marker)
+	if i < 0 {
+		t.Fatal("document has no value")
+	}
+	end := i + len(marker) + strings.IndexByte(document[i+len(marker):], '"') - 1
+	digit := '0' + (document[end]-'0'+1)%10
+	return document[:end] + string(rune(digit)) + document[end+1:]
+}
+
+func TestMethodNotAllowed(t *testing.T) {
+	srv := httptest.NewServer(server.Handler())
+	defer srv.Close()
+	resp, err := http.Get(srv.URL + "/reconstruct")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusMethodNotAllowed)
+	}
+	if got := resp.Header.Get("Allow"); got != http.MethodPost {
+		t.Errorf("Allow = %q, want POST", got)
+	}
+}
+
+func TestWithModulus(t *testing.T) {
+	// f(x) = 5 + 3x mod 7 through x = 1, 2.
+	body := `{"keys": {"n": 2, "k": 2}, "1": {"base": "10", "value": "1"}, "2": {"base": "10", "value": "4"}}`
+	resp, data := post(t, body, server.WithModulus(big.NewInt(7)))
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status %d: %s", resp.StatusCode, data)
+	}
+	var result server.Result
+	if err := json.Unmarshal(data, &result); err != nil {
+		t.Fatal(err)
+	}
+	if result.Secret != "5" {
+		t.Errorf("secret = %s, want 5", result.Secret)
+	}
+}
+
+func TestWithLogger(t *testing.T) {
+	var buf bytes.Buffer
+	resp, _ := post(t, readFile(t, "../../testcase_vss.json"), server.WithLogger(log.New(&buf, "", 0)))
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d", resp.StatusCode)
+	}
+	line := buf.String()
+	if !strings.HasPrefix(line, "POST /reconstruct 200 ") {
+		t.Errorf("log line %q, want POST /reconstruct 200 and a duration", line)
+	}
+	if strings.Contains(line, "123456789") {
+		t.Errorf("log line %q holds the secret", line)
+	}
+}
@@ -0,0 +1,120 @@
+package share
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// ConflictX is a share x that only one of two share sets being added has.
+const ConflictX ConflictKind = "x"
+
+// Add returns the shares of the sum of the secrets of a and b: since f+g
+// is a polynomial of the same degree through the sums of their values,
+// adding the shares at every x gives shares of f(0)+g(0) without
+// reconstructing either. a and b must have shares at the same x values and
+// the same k and n, and be over the same field: the integers, the prime
+// modulus, given when neither records one, or the same GF(2^m), where the
+// sum is xor. Anything else is a *ConflictError naming every mismatch.
+//
+// The sum carries no commitments, participants, expected value or secret
+// hash, which describe the inputs rather than their sum.
+func Add(a, b *Shares, modulus *big.Int) (*Shares, error) {
+	var conflicts []Conflict
+	conflict := func(kind ConflictKind, x, message string) {
+		conflicts = append(conflicts, Conflict{Kind: kind, X: x, Sources: [2]string{a.Source, b.Source}, Message: message})
+	}
+	if a.K != b.K {
+		conflict(ConflictThreshold, "", fmt.Sprintf("threshold mismatch: %s has k=%d but %s has k=%d", a.Source, a.K, b.Source, b.K))
+	}
+	if a.N != 0 && b.N != 0 && a.N != b.N {
+		conflict(ConflictCount, "", fmt.Sprintf("share count mismatch: %s has n=%d but %s has n=%d", a.Source, a.N, b.Source, b.N))
+	}
+
+	switch {
+	case (a.Field == FieldGF2m) != (b.Field == FieldGF2m):
+		conflict(ConflictModulus, "", fmt.Sprintf("field mismatch: only one of %s and %s is over GF(2^m)", a.Source, b.Source))
+	case a.Field == FieldGF2m && (a.Poly == nil || b.Poly == nil || a.Poly.Cmp(b.Poly) != 0):
+		conflict(ConflictModulus, "", fmt.Sprintf("polynomial mismatch: %s records poly %s but %s records %s", a.Source, polyText(a.Poly), b.Source, polyText(b.Poly)))
+	case a.Field == FieldGF2m:
+		if modulus != nil {
+			conflict(ConflictModulus, "", fmt.Sprintf("modulus mismatch: %s and %s are over GF(2^m), not modulo %s", a.Source, b.Source, modulus))
+		}
+	case a.Modulus != nil && b.Modulus != nil && a.Modulus.Cmp(b.Modulus) != 0:
+		conflict(ConflictModulus, "", fmt.Sprintf("modulus mismatch: %s records modulus %s but %s records %s", a.Source, a.Modulus, b.Source, b.Modulus))
+	case (a.Modulus == nil) != (b.Modulus == nil):
+		recorded, other := a, b
+		if recorded.Modulus == nil {
+			recorded, other = b, a
+		}
+		conflict(ConflictModulus, "", fmt.Sprintf("modulus mismatch: %s records modulus %s but %s records none", recorded.Source, recorded.Modulus, other.Source))
+	case a.Modulus != nil && modulus != nil && a.Modulus.Cmp(modulus) != 0:
+		conflict(ConflictModulus, "", fmt.Sprintf("modulus mismatch: %s and %s record modulus %s, not %s", a.Source, b.Source, a.Modulus, modulus))
+	case a.Modulus != nil:
+		modulus = a.Modulus
+	}
+
+	ys := make(map[string]*big.Int, len(b.Points))
+	for _, point := range b.Points {
+		ys[point.X.String()] = point.Y
+	}
+	var missing []string
+	for _, point := range a.Points {
+		key := point.X.String()
+		if _, ok := ys[key]; !ok {
+			missing = append(missing, key)
+			conflict(ConflictX, key, fmt.Sprintf("x mismatch: %s has a share at x=%s but %s does not", a.Source, key, b.Source))
+		}
+	}
+	if len(b.Points) != len(a.Points)-len(missing) {
+		inA := make(map[string]bool, len(a.Points))
+		for _, point := range a.Points {
+			inA[point.X.String()] = true
+		}
+		for _, point := range b.Points {
+			if key := point.X.String(); !inA[key] {
+				conflict(ConflictX, key, fmt.Sprintf("x mismatch: %s has a share at x=%s but %s does not", b.Source, key, a.Source))
+			}
+		}
+	}
+	if len(conflicts) > 0 {
+		return nil, &ConflictError{Conflicts: conflicts}
+	}
+
+	sum := &Shares{
+		N:             a.N,
+		K:             a.K,
+		Modulus:       modulus,
+		Field:         a.Field,
+		Poly:          a.Poly,
+		Deterministic: a.Deterministic || b.Deterministic,
+	}
+	if sum.N == 0 {
+		sum.N = b.N
+	}
+	if sum.Field != FieldGF2m && a.Field != b.Field {
+		// Named presets are equal when their moduli are; only keep a name
+		// both agree on.
+		sum.Field = ""
+	}
+	for _, point := range a.Points {
+		y := new(big.Int)
+		switch {
+		case a.Field == FieldGF2m:
+			y.Xor(point.Y, ys[point.X.String()])
+		case modulus != nil:
+			y.Add(point.Y, ys[point.X.String()])
+			y.Mod(y, modulus)
+		default:
+			y.Add(point.Y, ys[point.X.String()])
+		}
+		sum.Points = append(sum.Points, Point{X: point.X, Y: y, Base: point.Base})
+	}
+	return sum, nil
+}
+
+func polyText(poly *big.Int) string {
+	if poly == nil {
+		return "none"
+	}
+	return fmt.Sprintf("%#x", poly)
+}
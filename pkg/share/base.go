@@ -0,0 +1,129 @@
+package share
+
+import (
+	"encoding/ascii85"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// minBase and maxBase bound the numeric bases big.Int.SetString accepts.
+// The byte-oriented bases "64", "64url" and "85" are handled separately.
+const (
+	minBase = 2
+	maxBase = 62
+)
+
+// isByteBase reports whether base names one of the encodings that store
+// the big-endian bytes of a value rather than its digits.
+func isByteBase(base string) bool {
+	switch base {
+	case "64", "64url", "85":
+		return true
+	}
+	return false
+}
+
+// ValidBase reports whether EncodeValue accepts base.
+func ValidBase(base string) bool {
+	if isByteBase(base) {
+		return true
+	}
+	b, err := strconv.Atoi(base)
+	return err == nil && b >= minBase && b <= maxBase
+}
+
+// EncodeValue writes y in base, which is a number from 2 to 62 or one of
+// "64", "64url" and "85" for the standard and URL-safe base64 alphabets
+// and Ascii85 encoding of y's bytes.
+func EncodeValue(y *big.Int, base string) (string, error) {
+	if !isByteBase(base) {
+		b, err := strconv.Atoi(base)
+		if err != nil || b < minBase || b > maxBase {
+			return "", fmt.Errorf("%w: %s", ErrInvalidBase, base)
+		}
+		return y.Text(b), nil
+	}
+
+	if y.Sign() < 0 {
+		return "", fmt.Errorf("cannot write negative value %s in base %s", y.String(), base)
+	}
+	data := y.Bytes()
+	if len(data) == 0 {
+		data = []byte{0}
+	}
+	switch base {
+	case "64":
+		return base64.StdEncoding.EncodeToString(data), nil
+	case "64url":
+		return base64.URLEncoding.EncodeToString(data), nil
+	}
+	out := make([]byte, ascii85.MaxEncodedLen(len(data)))
+	return string(out[:ascii85.Encode(out, data)]), nil
+}
+
+// decodeBytes decodes a value written in one of the byte-oriented bases,
+// ignoring padding and whitespace such as line wraps.
+func decodeBytes(base, value string) (*big.Int, error) {
+	data, err := decodeRaw(base, value)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(data), nil
+}
+
+// decodeRaw decodes a byte string written in one of the byte-oriented
+// bases or, keeping leading zero bytes, in hex as base "16".
+func decodeRaw(base, value string) ([]byte, error) {
+	text := strings.Join(strings.Fields(value), "")
+	if text == "" {
+		return nil, errors.New("empty value")
+	}
+
+	var data []byte
+	var err error
+	switch base {
+	case "16":
+		if len(text) > 1 && text[0] == '0' && (text[1] == 'x' || text[1] == 'X') {
+			text = text[2:]
+		}
+		data, err = hex.DecodeString(text)
+	case "64":
+		data, err = base64.RawStdEncoding.DecodeString(strings.TrimRight(text, "="))
+	case "64url":
+		data, err = base64.RawURLEncoding.DecodeString(strings.TrimRight(text, "="))
+	case "85":
+		text = strings.TrimSuffix(strings.TrimPrefix(text, "<~"), "~>")
+		data = make([]byte, 4*len(text)+4)
+		var n int
+		n, _, err = ascii85.Decode(data, []byte(text), true)
+		data = data[:n]
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrInvalidBase, base)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// encodeRaw writes data in base "16", "64", "64url" or "85", keeping any
+// leading zero bytes.
+func encodeRaw(data []byte, base string) (string, error) {
+	switch base {
+	case "16":
+		return hex.EncodeToString(data), nil
+	case "64":
+		return base64.StdEncoding.EncodeToString(data), nil
+	case "64url":
+		return base64.URLEncoding.EncodeToString(data), nil
+	case "85":
+		out := make([]byte, ascii85.MaxEncodedLen(len(data)))
+		return string(out[:ascii85.Encode(out, data)]), nil
+	}
+	return "", fmt.Errorf("%w: %s", ErrInvalidBase, base)
+}
@@ -0,0 +1,57 @@
+package share
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+)
+
+// utf8BOM is the byte-order mark that Windows tools write at the start of
+// UTF-8 text.
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// errUTF16 is returned for input that starts with a UTF-16 byte-order
+// mark, which no parser reads.
+var errUTF16 = errors.New("input is UTF-16 text (it starts with a UTF-16 byte-order mark); save it as UTF-8")
+
+// skipBOM returns r without a leading UTF-8 byte-order mark, and whether
+// there was one.
+func skipBOM(r io.Reader) (io.Reader, bool, error) {
+	br, ok := r.(*bufio.Reader)
+	if !ok {
+		br = bufio.NewReader(r)
+	}
+	head, _ := br.Peek(len(utf8BOM))
+	if isUTF16(head) {
+		return nil, false, errUTF16
+	}
+	if !bytes.Equal(head, utf8BOM) {
+		return br, false, nil
+	}
+	br.Discard(len(utf8BOM))
+	return br, true, nil
+}
+
+// trimBOM returns data without a leading UTF-8 byte-order mark, and
+// whether there was one.
+func trimBOM(data []byte) ([]byte, bool, error) {
+	if isUTF16(data) {
+		return nil, false, errUTF16
+	}
+	trimmed, found := bytes.CutPrefix(data, utf8BOM)
+	return trimmed, found, nil
+}
+
+func isUTF16(head []byte) bool {
+	return len(head) >= 2 && (head[0] == 0xFF && head[1] == 0xFE || head[0] == 0xFE && head[1] == 0xFF)
+}
+
+// bomNote is added to the syntax errors of input that started with a
+// byte-order mark, which was removed before decoding.
+func bomNote(bom bool) string {
+	if bom {
+		return " (after removing a leading UTF-8 byte-order mark)"
+	}
+	return ""
+}
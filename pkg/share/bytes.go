@@ -0,0 +1,188 @@
+package share
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"sort"
+	"strings"
+)
+
+// FieldGF256 is the "field" of the keys object of share files made by
+// byte-wise sharing over GF(2^8).
+const FieldGF256 = "gf256"
+
+// ByteShares is the decoded content of a share file whose values are byte
+// strings of equal length rather than integers, as in byte-wise sharing
+// over GF(2^8). Points is sorted by x.
+type ByteShares struct {
+	N      int
+	K      int
+	Points []BytePoint
+	Source string
+
+	// Deterministic reports shares split from a fixed seed, as for
+	// Shares.
+	Deterministic bool
+}
+
+// BytePoint is one share of a ByteShares. X is never zero.
+type BytePoint struct {
+	X byte
+	Y []byte
+
+	// Source names the input the share was read from, if known.
+	Source string
+}
+
+// ParseByteShares decodes a JSON share document whose keys object has
+// "field": "gf256", or no field at all. Values are written in base "16",
+// "64", "64url" or "85", and leading zero bytes are kept; a share may
+// instead hold a "mnemonic" as written by EncodeMnemonic. x values must be
+// between 1 and 255.
+func ParseByteShares(r io.Reader, opts ...ParseOption) (*ByteShares, error) {
+	c := newParseConfig(opts)
+	c.field = FieldGF256
+
+	var points []BytePoint
+	var keys []string
+	var mnemonics mnemonicSet
+	keysData, found, _, err := c.streamDocument(r, func(entry rawEntry) error {
+		x, err := parseX(entry.xText)
+		if err != nil {
+			return err
+		}
+		if x.Sign() <= 0 || x.Cmp(big.NewInt(255)) > 0 {
+			return newParseError(entry.key, "x", entry.xText, fmt.Errorf("x must be between 1 and 255 in GF(256)"))
+		}
+		root, err := c.readRoot(entry)
+		if err != nil {
+			return err
+		}
+		var y []byte
+		if root.Mnemonic != "" {
+			if root.Value != "" {
+				return newParseError(entry.key, "mnemonic", root.Mnemonic, errors.New("share has both a mnemonic and a value"))
+			}
+			m, err := DecodeMnemonic(root.Mnemonic)
+			if err != nil {
+				return newParseError(entry.key, "mnemonic", root.Mnemonic, err)
+			}
+			if int64(m.Point.X) != x.Int64() {
+				return newParseError(entry.key, "mnemonic", root.Mnemonic, fmt.Errorf("mnemonic holds the share at x=%d", m.Point.X))
+			}
+			if err := mnemonics.add(fmt.Sprintf("entry %q", entry.key), m); err != nil {
+				return err
+			}
+			y = m.Point.Y
+		} else if y, err = decodeRaw(strings.TrimSpace(root.Base), root.Value); err != nil {
+			return newParseError(entry.key, "value", root.Value, fmt.Errorf("invalid value in base %q: %w", root.Base, err))
+		}
+		points = append(points, BytePoint{X: byte(x.Int64()), Y: y})
+		keys = append(keys, entry.key)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, errMissingKeys
+	}
+	if mnemonics.count > 0 && mnemonics.first.Threshold != keysData.K {
+		return nil, fmt.Errorf("\"keys\" object has k=%d, but the mnemonics record a threshold of %d", keysData.K, mnemonics.first.Threshold)
+	}
+	if c.requireThreshold && len(points) < keysData.K {
+		return nil, &InsufficientSharesError{Found: len(points), Needed: keysData.K, Context: "in file"}
+	}
+
+	order := make([]int, len(points))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(a, b int) bool { return points[order[a]].X < points[order[b]].X })
+	sorted := make([]BytePoint, len(points))
+	for i, j := range order {
+		sorted[i] = points[j]
+		if i > 0 && sorted[i].X == sorted[i-1].X {
+			return nil, &DuplicateXError{X: bigByte(sorted[i].X), Entries: []string{keys[order[i-1]], keys[j]}}
+		}
+		if len(sorted[i].Y) != len(sorted[0].Y) {
+			return nil, fmt.Errorf("share %s has %d bytes, but share %s has %d", keys[j], len(sorted[i].Y), keys[order[0]], len(sorted[0].Y))
+		}
+	}
+	return &ByteShares{N: keysData.N, K: keysData.K, Points: sorted, Deterministic: keysData.Deterministic}, nil
+}
+
+func bigByte(b byte) *big.Int {
+	return big.NewInt(int64(b))
+}
+
+// SetSource records name as the source of s and of every share in it.
+func (s *ByteShares) SetSource(name string) {
+	s.Source = name
+	for i := range s.Points {
+		s.Points[i].Source = name
+	}
+}
+
+// MergeBytes combines byte share documents into one, as Merge does for
+// integer shares.
+func MergeBytes(sets ...*ByteShares) (*ByteShares, error) {
+	if len(sets) == 0 {
+		return nil, errors.New("no share documents to merge")
+	}
+
+	merged := &ByteShares{N: sets[0].N, K: sets[0].K, Source: sets[0].Source}
+	seen := make(map[byte]BytePoint)
+	for _, set := range sets {
+		if set.K != merged.K {
+			return nil, fmt.Errorf("threshold mismatch: %s has k=%d but %s has k=%d", merged.Source, merged.K, set.Source, set.K)
+		}
+		merged.Deterministic = merged.Deterministic || set.Deterministic
+		if set.N > merged.N {
+			merged.N = set.N
+		}
+
+		for _, point := range set.Points {
+			if prev, ok := seen[point.X]; ok {
+				if !bytes.Equal(prev.Y, point.Y) {
+					return nil, fmt.Errorf("conflicting shares for x=%d in %s and %s", point.X, prev.Source, point.Source)
+				}
+				continue
+			}
+			seen[point.X] = point
+			merged.Points = append(merged.Points, point)
+		}
+	}
+
+	sort.Slice(merged.Points, func(a, b int) bool { return merged.Points[a].X < merged.Points[b].X })
+	return merged, nil
+}
+
+// WriteByteShares encodes s in the layout ParseByteShares accepts, writing
+// every value in base, which is "16", "64", "64url" or "85".
+func WriteByteShares(w io.Writer, s *ByteShares, base string) error {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "{\n    \"keys\": {\n        \"n\": %d,\n        \"k\": %d,\n        \"field\": %q", s.N, s.K, FieldGF256)
+	if s.Deterministic {
+		buf.WriteString(",\n        \"insecure_deterministic\": true")
+	}
+	buf.WriteString("\n    }")
+
+	for _, point := range s.Points {
+		text, err := encodeRaw(point.Y, base)
+		if err != nil {
+			return err
+		}
+		baseStr, _ := json.Marshal(base)
+		value, _ := json.Marshal(text)
+		fmt.Fprintf(&buf, ",\n    \"%d\": {\n        \"base\": %s,\n        \"value\": %s\n    }", point.X, baseStr, value)
+	}
+	buf.WriteString("\n}\n")
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
@@ -0,0 +1,62 @@
+package share
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// Case is one named share document from a file that bundles several test
+// cases. Err records why the case could not be parsed, so that one bad
+// case does not hide the others.
+type Case struct {
+	Name   string
+	Shares *Shares
+	Err    error
+}
+
+// ParseJSON decodes a JSON input that is either a single share document or
+// a multi-case document, in one streaming pass. Exactly one of the results
+// is non-nil on success.
+func ParseJSON(r io.Reader, opts ...ParseOption) (*Shares, []Case, error) {
+	c := newParseConfig(opts)
+	caseOpts := append(opts[:len(opts):len(opts)], RequireThreshold())
+
+	set := newPointSet(c)
+	var cases []Case
+	keysData, found, expected, err := c.streamDocument(r, func(entry rawEntry) error {
+		if entry.hasX || !hasKeys(entry.raw) {
+			return set.add(entry)
+		}
+		shares, err := ParseShares(bytes.NewReader(entry.raw), caseOpts...)
+		cases = append(cases, Case{Name: entry.key, Shares: shares, Err: err})
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !found && expected == "" && len(set.points) == 0 && len(cases) > 0 {
+		sort.Slice(cases, func(a, b int) bool { return cases[a].Name < cases[b].Name })
+		return nil, cases, nil
+	}
+	if len(cases) > 0 {
+		return nil, nil, fmt.Errorf(`entry %q has its own "keys" object, but the document is not made of test cases only`, cases[0].Name)
+	}
+	if !found {
+		return nil, nil, errMissingKeys
+	}
+	shares, err := set.shares(keysData, expected)
+	return shares, nil, err
+}
+
+// hasKeys reports whether raw is an object with a "keys" field, as the test
+// cases of a multi-case document are.
+func hasKeys(raw json.RawMessage) bool {
+	var fields struct {
+		Keys json.RawMessage `json:"keys"`
+	}
+	return json.Unmarshal(raw, &fields) == nil && fields.Keys != nil
+}
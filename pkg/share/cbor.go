@@ -0,0 +1,461 @@
+package share
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+)
+
+// cborSelfDescribeTag is the tag 55799 prefix that marks a CBOR document.
+var cborSelfDescribeTag = []byte{0xd9, 0xd9, 0xf7}
+
+const (
+	cborUnsigned = 0
+	cborNegative = 1
+	cborBytes    = 2
+	cborText     = 3
+	cborArray    = 4
+	cborMap      = 5
+	cborTag      = 6
+	cborSimple   = 7
+
+	cborIndefinite = 31
+	cborBreak      = 0xff
+
+	cborMaxDepth = 32
+)
+
+// IsCBOR reports whether data looks like the start of a CBOR share file:
+// either the self-describe tag or a map header.
+func IsCBOR(data []byte) bool {
+	if bytes.HasPrefix(data, cborSelfDescribeTag) {
+		return true
+	}
+	return len(data) > 0 && data[0]>>5 == cborMap
+}
+
+// mapEntry is one entry of a decoded binary map (CBOR or MessagePack),
+// kept in document order.
+type mapEntry struct {
+	key   any
+	value any
+}
+
+type cborDecoder struct {
+	r      *bufio.Reader
+	offset int64
+}
+
+// ParseCBOR decodes a CBOR share file. The top-level map holds a "keys" map
+// with integer n and k, and one entry per share keyed by the integer x. A
+// share's value is either a byte string holding y in big-endian order, a
+// bignum, an integer, or a text string in the given base.
+func ParseCBOR(r io.Reader, opts ...ParseOption) (*Shares, error) {
+	c := newParseConfig(opts)
+	d := &cborDecoder{r: bufio.NewReader(r)}
+	root, err := d.decode(0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode cbor: %w", err)
+	}
+
+	entries, ok := root.([]mapEntry)
+	if !ok {
+		return nil, fmt.Errorf("cbor document must be a map, got %s", valueTypeName(root))
+	}
+
+	shares := &Shares{}
+	haveKeys := false
+	var keys []string
+	for _, entry := range entries {
+		if name, ok := entry.key.(string); ok && name == "keys" {
+			haveKeys = true
+			fields, ok := entry.value.([]mapEntry)
+			if !ok {
+				return nil, fmt.Errorf("cbor 'keys' must be a map, got %s", valueTypeName(entry.value))
+			}
+			for _, field := range fields {
+				name, _ := field.key.(string)
+				v, ok := field.value.(*big.Int)
+				if !ok || !v.IsInt64() {
+					if name == "n" || name == "k" {
+						return nil, fmt.Errorf("cbor 'keys' field %s must be an integer, got %s", name, valueTypeName(field.value))
+					}
+					continue
+				}
+				switch name {
+				case "n":
+					shares.N = int(v.Int64())
+				case "k":
+					shares.K = int(v.Int64())
+				}
+			}
+			continue
+		}
+
+		x, ok := entry.key.(*big.Int)
+		if !ok {
+			return nil, fmt.Errorf("cbor share key must be an integer x, got %s", valueTypeName(entry.key))
+		}
+		y, err := c.binaryShareValue(x, entry.value)
+		if err != nil {
+			return nil, err
+		}
+		shares.Points = append(shares.Points, Point{X: x, Y: y})
+		keys = append(keys, x.String())
+	}
+
+	if !haveKeys {
+		return nil, errors.New("cbor input is missing the 'keys' map")
+	}
+	if err := checkKeys(shares.N, shares.K); err != nil {
+		return nil, err
+	}
+
+	if err := sortPoints(shares.Points, keys); err != nil {
+		return nil, err
+	}
+	return shares, nil
+}
+
+// binaryShareValue decodes the y value of a share map from a binary format.
+func (c *parseConfig) binaryShareValue(x *big.Int, v any) (*big.Int, error) {
+	fields, ok := v.([]mapEntry)
+	if !ok {
+		return nil, fmt.Errorf("share x=%s must be a map, got %s", x.String(), valueTypeName(v))
+	}
+
+	var base string
+	var value any
+	for _, field := range fields {
+		switch name, _ := field.key.(string); name {
+		case "base":
+			switch b := field.value.(type) {
+			case *big.Int:
+				base = b.String()
+			case string:
+				base = b
+			default:
+				return nil, fmt.Errorf("%w for x=%s: got %s", ErrInvalidBase, x.String(), valueTypeName(field.value))
+			}
+		case "value":
+			value = field.value
+		}
+	}
+
+	switch value := value.(type) {
+	case []byte:
+		return new(big.Int).SetBytes(value), nil
+	case *big.Int:
+		return value, nil
+	case string:
+		return c.decodeY(x.String(), x, base, value)
+	case nil:
+		return nil, fmt.Errorf("share x=%s has no value", x.String())
+	default:
+		return nil, fmt.Errorf("failed to decode y value for x=%s: got %s", x.String(), valueTypeName(value))
+	}
+}
+
+func valueTypeName(v any) string {
+	switch v.(type) {
+	case *big.Int:
+		return "integer"
+	case []byte:
+		return "byte string"
+	case string:
+		return "text string"
+	case []any:
+		return "array"
+	case []mapEntry:
+		return "map"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
+
+func (d *cborDecoder) readByte() (byte, error) {
+	b, err := d.r.ReadByte()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return 0, io.ErrUnexpectedEOF
+		}
+		return 0, err
+	}
+	d.offset++
+	return b, nil
+}
+
+// readHead reads an item header and returns its major type, additional
+// information and argument.
+func (d *cborDecoder) readHead() (major, info byte, arg uint64, err error) {
+	b, err := d.readByte()
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	major, info = b>>5, b&0x1f
+
+	switch {
+	case info < 24:
+		return major, info, uint64(info), nil
+	case info <= 27:
+		size := 1 << (info - 24)
+		for i := 0; i < size; i++ {
+			b, err := d.readByte()
+			if err != nil {
+				return 0, 0, 0, err
+			}
+			arg = arg<<8 | uint64(b)
+		}
+		return major, info, arg, nil
+	case info == cborIndefinite:
+		return major, info, 0, nil
+	default:
+		return 0, 0, 0, fmt.Errorf("reserved additional information %d at offset %d", info, d.offset-1)
+	}
+}
+
+func (d *cborDecoder) readN(n uint64) ([]byte, error) {
+	if n > math.MaxInt64 {
+		return nil, fmt.Errorf("string length %d at offset %d is too large", n, d.offset)
+	}
+	var buf bytes.Buffer
+	copied, err := io.CopyN(&buf, d.r, int64(n))
+	d.offset += copied
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// readString reads a definite or indefinite length byte or text string.
+func (d *cborDecoder) readString(major, info byte, arg uint64) ([]byte, error) {
+	if info != cborIndefinite {
+		return d.readN(arg)
+	}
+
+	var buf bytes.Buffer
+	for {
+		start := d.offset
+		chunkMajor, chunkInfo, chunkArg, err := d.readHead()
+		if err != nil {
+			return nil, err
+		}
+		if chunkMajor == cborSimple && chunkInfo == cborIndefinite {
+			return buf.Bytes(), nil
+		}
+		if chunkMajor != major || chunkInfo == cborIndefinite {
+			return nil, fmt.Errorf("invalid chunk of type %d in indefinite string at offset %d", chunkMajor, start)
+		}
+		chunk, err := d.readN(chunkArg)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(chunk)
+	}
+}
+
+// isBreak consumes the break code if it is next in the input.
+func (d *cborDecoder) isBreak() (bool, error) {
+	b, err := d.r.Peek(1)
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return false, io.ErrUnexpectedEOF
+		}
+		return false, err
+	}
+	if b[0] != cborBreak {
+		return false, nil
+	}
+	_, err = d.readByte()
+	return true, err
+}
+
+func (d *cborDecoder) decode(depth int) (any, error) {
+	if depth > cborMaxDepth {
+		return nil, fmt.Errorf("nesting deeper than %d levels at offset %d", cborMaxDepth, d.offset)
+	}
+
+	start := d.offset
+	major, info, arg, err := d.readHead()
+	if err != nil {
+		return nil, err
+	}
+
+	switch major {
+	case cborUnsigned:
+		return new(big.Int).SetUint64(arg), nil
+
+	case cborNegative:
+		v := new(big.Int).SetUint64(arg)
+		return v.Neg(v).Sub(v, big.NewInt(1)), nil
+
+	case cborBytes:
+		return d.readString(major, info, arg)
+
+	case cborText:
+		text, err := d.readString(major, info, arg)
+		return string(text), err
+
+	case cborArray:
+		var items []any
+		for i := uint64(0); info == cborIndefinite || i < arg; i++ {
+			if info == cborIndefinite {
+				done, err := d.isBreak()
+				if err != nil {
+					return nil, err
+				}
+				if done {
+					break
+				}
+			}
+			item, err := d.decode(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		return items, nil
+
+	case cborMap:
+		var pairs []mapEntry
+		for i := uint64(0); info == cborIndefinite || i < arg; i++ {
+			if info == cborIndefinite {
+				done, err := d.isBreak()
+				if err != nil {
+					return nil, err
+				}
+				if done {
+					break
+				}
+			}
+			key, err := d.decode(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			value, err := d.decode(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			pairs = append(pairs, mapEntry{key: key, value: value})
+		}
+		return pairs, nil
+
+	case cborTag:
+		content, err := d.decode(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		switch arg {
+		case 55799:
+			return content, nil
+		case 2, 3:
+			raw, ok := content.([]byte)
+			if !ok {
+				return nil, fmt.Errorf("bignum tag %d at offset %d must wrap a byte string", arg, start)
+			}
+			v := new(big.Int).SetBytes(raw)
+			if arg == 3 {
+				v.Neg(v).Sub(v, big.NewInt(1))
+			}
+			return v, nil
+		default:
+			return nil, fmt.Errorf("unsupported tag %d at offset %d", arg, start)
+		}
+
+	default:
+		return nil, fmt.Errorf("unsupported major type %d (simple value or float) at offset %d", major, start)
+	}
+}
+
+// WriteCBOR encodes s as a CBOR share file that ParseCBOR accepts. Values
+// are written as unsigned or negative bignums.
+func WriteCBOR(w io.Writer, s *Shares) error {
+	var buf bytes.Buffer
+	buf.Write(cborSelfDescribeTag)
+
+	cborWriteHead(&buf, cborMap, uint64(len(s.Points)+1))
+	cborWriteText(&buf, "keys")
+	cborWriteHead(&buf, cborMap, 2)
+	cborWriteText(&buf, "n")
+	cborWriteInt(&buf, big.NewInt(int64(s.N)))
+	cborWriteText(&buf, "k")
+	cborWriteInt(&buf, big.NewInt(int64(s.K)))
+
+	for _, point := range s.Points {
+		cborWriteInt(&buf, point.X)
+		cborWriteHead(&buf, cborMap, 1)
+		cborWriteText(&buf, "value")
+		cborWriteBignum(&buf, point.Y)
+	}
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+func cborWriteHead(buf *bytes.Buffer, major byte, arg uint64) {
+	switch {
+	case arg < 24:
+		buf.WriteByte(major<<5 | byte(arg))
+	case arg <= 0xff:
+		buf.WriteByte(major<<5 | 24)
+		buf.WriteByte(byte(arg))
+	case arg <= 0xffff:
+		buf.WriteByte(major<<5 | 25)
+		buf.Write([]byte{byte(arg >> 8), byte(arg)})
+	case arg <= 0xffffffff:
+		buf.WriteByte(major<<5 | 26)
+		buf.Write([]byte{byte(arg >> 24), byte(arg >> 16), byte(arg >> 8), byte(arg)})
+	default:
+		buf.WriteByte(major<<5 | 27)
+		for shift := 56; shift >= 0; shift -= 8 {
+			buf.WriteByte(byte(arg >> shift))
+		}
+	}
+}
+
+func cborWriteText(buf *bytes.Buffer, s string) {
+	cborWriteHead(buf, cborText, uint64(len(s)))
+	buf.WriteString(s)
+}
+
+// cborWriteInt writes v as a plain integer when it fits in 64 bits and as a
+// bignum otherwise.
+func cborWriteInt(buf *bytes.Buffer, v *big.Int) {
+	if v.Sign() >= 0 && v.IsUint64() {
+		cborWriteHead(buf, cborUnsigned, v.Uint64())
+		return
+	}
+	if v.Sign() < 0 {
+		magnitude := new(big.Int).Neg(v)
+		magnitude.Sub(magnitude, big.NewInt(1))
+		if magnitude.IsUint64() {
+			cborWriteHead(buf, cborNegative, magnitude.Uint64())
+			return
+		}
+	}
+	cborWriteBignum(buf, v)
+}
+
+func cborWriteBignum(buf *bytes.Buffer, v *big.Int) {
+	if v.Sign() >= 0 {
+		cborWriteHead(buf, cborTag, 2)
+		raw := v.Bytes()
+		cborWriteHead(buf, cborBytes, uint64(len(raw)))
+		buf.Write(raw)
+		return
+	}
+
+	magnitude := new(big.Int).Neg(v)
+	magnitude.Sub(magnitude, big.NewInt(1))
+	cborWriteHead(buf, cborTag, 3)
+	raw := magnitude.Bytes()
+	cborWriteHead(buf, cborBytes, uint64(len(raw)))
+	buf.Write(raw)
+}
@@ -0,0 +1,57 @@
+package share
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// checksumBytes is how much of a SHA-256 digest a checksum keeps.
+const checksumBytes = 8
+
+func digest(text string) string {
+	sum := sha256.Sum256([]byte(text))
+	return hex.EncodeToString(sum[:checksumBytes])
+}
+
+// shareChecksum returns the checksum of the share (x, y) written in base:
+// the truncated SHA-256 of "x|base|value", with x in decimal and the value
+// as EncodeValue writes it, so that any spelling of the same share agrees.
+func shareChecksum(x, y *big.Int, base string) (string, error) {
+	if base = strings.TrimSpace(base); base == "" {
+		base = "10"
+	}
+	value, err := EncodeValue(y, base)
+	if err != nil {
+		return "", err
+	}
+	return digest(x.String() + "|" + base + "|" + value), nil
+}
+
+// setChecksum returns the checksum of a whole share document: the
+// truncated SHA-256 of n, k and the share checksums in x order, all
+// separated by "|".
+func setChecksum(n, k int, sums []string) string {
+	parts := append([]string{strconv.Itoa(n), strconv.Itoa(k)}, sums...)
+	return digest(strings.Join(parts, "|"))
+}
+
+// checkSetChecksum compares the keys checksum of a document holding every
+// one of its n shares, sorted by x, against the share checksums. A document
+// holding only some of the shares cannot be checked as a whole.
+func checkSetChecksum(keysData tempKeys, points []Point, sums map[string]string) error {
+	if keysData.Checksum == "" || len(points) < keysData.N {
+		return nil
+	}
+	ordered := make([]string, len(points))
+	for i, point := range points {
+		ordered[i] = sums[point.X.String()]
+	}
+	if !strings.EqualFold(strings.TrimSpace(keysData.Checksum), setChecksum(keysData.N, keysData.K, ordered)) {
+		return fmt.Errorf(`%w: the "keys" checksum does not match; a share was added, removed or replaced, or n or k changed`, ErrChecksumMismatch)
+	}
+	return nil
+}
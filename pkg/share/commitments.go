@@ -0,0 +1,296 @@
+package share
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+)
+
+// Commitment schemes, as named by the "scheme" of the commitments object in
+// a keys object.
+const (
+	SchemeFeldman  = "feldman"
+	SchemePedersen = "pedersen"
+)
+
+// Commitments are commitments to the coefficients of a sharing polynomial
+// over the integers modulo a prime q, in the subgroup of order q of the
+// integers modulo the prime P, generated by G. Anyone holding them can
+// check a share without other shares.
+//
+// Feldman commitments are Values[i] = G^a_i mod P and reveal G^secret.
+// Pedersen commitments are Values[i] = G^a_i * H^b_i mod P for a random
+// blinding polynomial b, and reveal nothing about the secret; each share
+// then carries its blinding value b(x).
+type Commitments struct {
+	Scheme string
+	P, G   *big.Int
+	Values []*big.Int
+
+	// Q and H are set for Pedersen commitments only.
+	Q, H *big.Int
+}
+
+// minGroupBits is the least size of the group the commitments live in. The
+// commitments reveal G^secret, so discrete logarithms modulo P must be out
+// of reach even when q is small.
+const minGroupBits = 2048
+
+// feldmanGroup is the group FeldmanGroup found for one q.
+type feldmanGroup struct {
+	once sync.Once
+	p, g *big.Int
+	err  error
+}
+
+// feldmanGroups caches the *feldmanGroup of every q, by its decimal
+// string, since the search takes seconds.
+var feldmanGroups sync.Map
+
+// FeldmanGroup returns the smallest prime p = r*q + 1 of at least
+// minGroupBits bits with r even, and a generator g of its subgroup of
+// order q. q must be an odd prime or 2. The group of each q is searched
+// for once per process.
+func FeldmanGroup(q *big.Int) (p, g *big.Int, err error) {
+	v, _ := feldmanGroups.LoadOrStore(q.String(), new(feldmanGroup))
+	group := v.(*feldmanGroup)
+	group.once.Do(func() { group.p, group.g, group.err = findFeldmanGroup(q) })
+	if group.err != nil {
+		return nil, nil, group.err
+	}
+	return new(big.Int).Set(group.p), new(big.Int).Set(group.g), nil
+}
+
+func findFeldmanGroup(q *big.Int) (p, g *big.Int, err error) {
+	if !q.ProbablyPrime(20) {
+		return nil, nil, fmt.Errorf("modulus %s is not prime", q.String())
+	}
+
+	one := big.NewInt(1)
+	r := big.NewInt(2)
+	if shift := minGroupBits - q.BitLen(); shift > 1 {
+		r.Lsh(one, uint(shift))
+	}
+	p = new(big.Int)
+	for {
+		p.Mul(r, q)
+		p.Add(p, one)
+		if p.ProbablyPrime(20) {
+			break
+		}
+		r.Add(r, big.NewInt(2))
+	}
+
+	g = new(big.Int)
+	for h := int64(2); ; h++ {
+		if g.Exp(big.NewInt(h), r, p); g.Cmp(one) != 0 {
+			return p, g, nil
+		}
+	}
+}
+
+// Commit returns the Feldman commitments to coefficients, the polynomial
+// over the integers modulo the prime q, lowest degree first.
+func Commit(coefficients []*big.Int, q *big.Int) (*Commitments, error) {
+	p, g, err := FeldmanGroup(q)
+	if err != nil {
+		return nil, err
+	}
+	c := &Commitments{Scheme: SchemeFeldman, P: p, G: g, Values: make([]*big.Int, len(coefficients))}
+	for i, a := range coefficients {
+		c.Values[i] = new(big.Int).Exp(g, a, p)
+	}
+	return c, nil
+}
+
+// Verify reports whether the share p lies on the committed polynomial,
+// that is whether G^y, times H^blinding for Pedersen commitments, equals
+// the product of Values[i]^(x^i). A Pedersen share without a blinding
+// value never does.
+func (c *Commitments) Verify(p Point) bool {
+	// Every exponent may be reduced modulo the order of the group.
+	order := new(big.Int).Sub(c.P, big.NewInt(1))
+	want := new(big.Int).Exp(c.G, new(big.Int).Mod(p.Y, order), c.P)
+	if c.Scheme == SchemePedersen {
+		if p.Blinding == nil {
+			return false
+		}
+		blinding := new(big.Int).Exp(c.H, new(big.Int).Mod(p.Blinding, order), c.P)
+		want.Mul(want, blinding)
+		want.Mod(want, c.P)
+	}
+
+	got := big.NewInt(1)
+	power := big.NewInt(1)
+	xMod := new(big.Int).Mod(p.X, order)
+	term := new(big.Int)
+	for _, value := range c.Values {
+		term.Exp(value, power, c.P)
+		got.Mul(got, term)
+		got.Mod(got, c.P)
+		power.Mul(power, xMod)
+		power.Mod(power, order)
+	}
+	return got.Cmp(want) == 0
+}
+
+// Equal reports whether c and other are the same commitments.
+func (c *Commitments) Equal(other *Commitments) bool {
+	if c.Scheme != other.Scheme || c.P.Cmp(other.P) != 0 || c.G.Cmp(other.G) != 0 || len(c.Values) != len(other.Values) {
+		return false
+	}
+	if c.Scheme == SchemePedersen && (c.Q.Cmp(other.Q) != 0 || c.H.Cmp(other.H) != 0) {
+		return false
+	}
+	for i := range c.Values {
+		if c.Values[i].Cmp(other.Values[i]) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// VerifyCommitments checks every share of s against its commitments,
+// returning a *CommitmentError naming the shares that fail. A document
+// without commitments has nothing to check.
+func (s *Shares) VerifyCommitments() error {
+	if s.Commitments == nil {
+		return nil
+	}
+	var failed []Point
+	for _, point := range s.Points {
+		if !s.Commitments.Verify(point) {
+			failed = append(failed, point)
+		}
+	}
+	if len(failed) > 0 {
+		return &CommitmentError{Scheme: s.Commitments.Scheme, Points: failed}
+	}
+	return nil
+}
+
+type tempCommitments struct {
+	Scheme string   `json:"scheme"`
+	P      string   `json:"p"`
+	G      string   `json:"g"`
+	Q      string   `json:"q"`
+	H      string   `json:"h"`
+	Values []string `json:"values"`
+}
+
+// parseCommitments decodes the "commitments" object of a keys object,
+// which must commit to a polynomial with k coefficients over the integers
+// modulo q, if the keys object records q.
+func parseCommitments(raw json.RawMessage, k int, q *big.Int, strict bool) (*Commitments, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil || fields == nil {
+		return nil, fmt.Errorf(`"commitments" must be an object, got %s`, jsonTypeName(raw))
+	}
+	var doc tempCommitments
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, fmt.Errorf(`invalid "commitments" object: %w`, err)
+	}
+	known := []string{"scheme", "p", "g", "values"}
+	switch doc.Scheme {
+	case SchemeFeldman:
+	case SchemePedersen:
+		known = append(known, "q", "h")
+	default:
+		return nil, fmt.Errorf(`unknown commitment scheme %q`, doc.Scheme)
+	}
+	if strict {
+		if err := checkFields(fields, known); err != nil {
+			return nil, fmt.Errorf(`%w in "commitments" object`, err)
+		}
+	}
+
+	parse := func(name, text string) (*big.Int, error) {
+		v, ok := new(big.Int).SetString(strings.TrimSpace(text), 10)
+		if !ok {
+			return nil, fmt.Errorf(`invalid %s in "commitments" object: %q`, name, text)
+		}
+		return v, nil
+	}
+	c := &Commitments{Scheme: doc.Scheme}
+	var err error
+	if c.P, err = parse("p", doc.P); err != nil {
+		return nil, err
+	}
+	if c.G, err = parse("g", doc.G); err != nil {
+		return nil, err
+	}
+	if c.P.Cmp(big.NewInt(3)) < 0 || c.G.Cmp(big.NewInt(1)) <= 0 || c.G.Cmp(c.P) >= 0 {
+		return nil, errors.New(`invalid "commitments" object: g must be between 2 and p-1`)
+	}
+	if len(doc.Values) != k {
+		return nil, fmt.Errorf(`"commitments" object has %d values, but k=%d`, len(doc.Values), k)
+	}
+	for i, text := range doc.Values {
+		v, err := parse(fmt.Sprintf("value %d", i), text)
+		if err != nil {
+			return nil, err
+		}
+		if v.Sign() <= 0 || v.Cmp(c.P) >= 0 {
+			return nil, fmt.Errorf(`invalid value %d in "commitments" object: must be between 1 and p-1`, i)
+		}
+		c.Values = append(c.Values, v)
+	}
+
+	if c.Scheme == SchemePedersen {
+		if c.Q, err = parse("q", doc.Q); err != nil {
+			return nil, err
+		}
+		if c.H, err = parse("h", doc.H); err != nil {
+			return nil, err
+		}
+		if err := checkPedersenGroup(c); err != nil {
+			return nil, err
+		}
+	} else if q != nil {
+		if err := checkFeldmanGroup(c, q); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// checkFeldmanGroup checks that G and the values of the Feldman
+// commitments c lie in the subgroup of order q, so that no value can carry
+// a component outside the group the shares are checked in.
+func checkFeldmanGroup(c *Commitments, q *big.Int) error {
+	one := big.NewInt(1)
+	order := new(big.Int).Sub(c.P, one)
+	if new(big.Int).Mod(order, q).Sign() != 0 {
+		return errors.New(`invalid "commitments" object: p-1 is not a multiple of the modulus`)
+	}
+	power := new(big.Int)
+	if power.Exp(c.G, q, c.P).Cmp(one) != 0 {
+		return errors.New(`invalid "commitments" object: g does not generate a subgroup of the order of the modulus`)
+	}
+	for i, v := range c.Values {
+		if power.Exp(v, q, c.P).Cmp(one) != 0 {
+			return fmt.Errorf(`invalid value %d in "commitments" object: not in the subgroup generated by g`, i)
+		}
+	}
+	return nil
+}
+
+// writeCommitments writes c as the "commitments" member of a keys object.
+func writeCommitments(buf *bytes.Buffer, c *Commitments) {
+	fmt.Fprintf(buf, ",\n        \"commitments\": {\n            \"scheme\": %q,\n            \"p\": %q,\n            \"g\": %q,", c.Scheme, c.P.String(), c.G.String())
+	if c.Scheme == SchemePedersen {
+		fmt.Fprintf(buf, "\n            \"q\": %q,\n            \"h\": %q,", c.Q.String(), c.H.String())
+	}
+	buf.WriteString("\n            \"values\": [")
+	for i, v := range c.Values {
+		if i > 0 {
+			buf.WriteString(",")
+		}
+		fmt.Fprintf(buf, "\n                %q", v.String())
+	}
+	buf.WriteString("\n            ]\n        }")
+}
@@ -0,0 +1,48 @@
+package share
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// Convert encodes s again with share i written in bases[i%len(bases)], in
+// the layout of the document s was parsed from: the file of a participant,
+// the document of a single share, or a share file. It parses the result and
+// returns an error unless it holds the shares of s.
+func Convert(s *Shares, bases []string) ([]byte, error) {
+	var buf bytes.Buffer
+	var err error
+	switch {
+	case s.Participant != "":
+		err = WriteParticipant(&buf, s, s.Participant, bases)
+	case s.Single && len(s.Points) == 1:
+		err = WriteShare(&buf, s, 0, bases[0])
+	default:
+		err = WriteShares(&buf, s, bases)
+	}
+	if err != nil {
+		return nil, err
+	}
+	converted, _, err := ParseJSON(bytes.NewReader(buf.Bytes()), BinaryField())
+	if err != nil {
+		return nil, fmt.Errorf("converted document does not parse: %w", err)
+	}
+	if err := sameShares(s, converted); err != nil {
+		return nil, fmt.Errorf("converted document does not hold the same shares: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// sameShares reports the first share in which a and b differ.
+func sameShares(a, b *Shares) error {
+	if a.N != b.N || a.K != b.K || len(a.Points) != len(b.Points) {
+		return fmt.Errorf("n=%d, k=%d and %d shares became n=%d, k=%d and %d shares", a.N, a.K, len(a.Points), b.N, b.K, len(b.Points))
+	}
+	for i, p := range a.Points {
+		q := b.Points[i]
+		if p.X.Cmp(q.X) != 0 || p.Y.Cmp(q.Y) != 0 || (p.Blinding == nil) != (q.Blinding == nil) || p.Blinding != nil && p.Blinding.Cmp(q.Blinding) != 0 || p.Label != q.Label {
+			return fmt.Errorf("the share at x=%s changed", p.X)
+		}
+	}
+	return nil
+}
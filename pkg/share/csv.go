@@ -0,0 +1,117 @@
+package share
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ParseCSV decodes shares stored as CSV rows of x,base,value. Lines starting
+// with # are comments; a comment such as "# k=3 n=5" supplies the threshold
+// and share count. A non-zero k overrides the one found in the comments. An
+// optional header row naming the columns is skipped.
+func ParseCSV(r io.Reader, k int, opts ...ParseOption) (*Shares, error) {
+	c := newParseConfig(opts)
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read input: %w", err)
+	}
+	data, bom, err := trimBOM(data)
+	if err != nil {
+		return nil, err
+	}
+
+	n, commentK, err := scanCSVComments(data)
+	if err != nil {
+		return nil, err
+	}
+	if k == 0 {
+		k = commentK
+	}
+	if k < 1 {
+		return nil, fmt.Errorf("invalid k=%d: CSV input needs a \"# k=<k>\" comment or an explicit k", k)
+	}
+	if n != 0 && k > n {
+		return nil, fmt.Errorf("invalid k=%d: larger than n=%d", k, n)
+	}
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.Comment = '#'
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	var points []Point
+	var keys []string
+	for first := true; ; first = false {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse csv%s: %w", bomNote(bom), err)
+		}
+		row, _ := reader.FieldPos(0)
+
+		if first && strings.EqualFold(strings.TrimSpace(record[0]), "x") {
+			continue
+		}
+		if len(record) != 3 {
+			return nil, fmt.Errorf("row %d: expected 3 columns (x,base,value), got %d", row, len(record))
+		}
+
+		x, err := parseX(strings.TrimSpace(record[0]))
+		if err != nil {
+			return nil, fmt.Errorf("row %d: %w", row, err)
+		}
+		y, err := c.decodeY(strings.TrimSpace(record[0]), x, strings.TrimSpace(record[1]), strings.TrimSpace(record[2]))
+		if err != nil {
+			return nil, fmt.Errorf("row %d: %w", row, err)
+		}
+		points = append(points, Point{X: x, Y: y})
+		keys = append(keys, fmt.Sprintf("row %d", row))
+	}
+	if err := sortPoints(points, keys); err != nil {
+		return nil, err
+	}
+
+	return &Shares{N: n, K: k, Points: points}, nil
+}
+
+// scanCSVComments extracts n and k from key=value pairs in comment lines.
+func scanCSVComments(data []byte) (n, k int, err error) {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(nil, len(data)+1)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		fields := strings.FieldsFunc(text[1:], func(r rune) bool { return r == ' ' || r == ',' || r == '\t' })
+		for _, field := range fields {
+			name, value, ok := strings.Cut(field, "=")
+			if !ok {
+				continue
+			}
+
+			var target *int
+			switch strings.ToLower(name) {
+			case "k":
+				target = &k
+			case "n":
+				target = &n
+			default:
+				continue
+			}
+			if *target, err = strconv.Atoi(value); err != nil {
+				return 0, 0, fmt.Errorf("row %d: invalid %s in comment: %s", line, name, value)
+			}
+		}
+	}
+	return n, k, scanner.Err()
+}
@@ -0,0 +1,169 @@
+package share
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// ConflictVersion is a difference in the format version.
+	ConflictVersion ConflictKind = "version"
+	// ConflictField is a difference in the field the keys object names.
+	ConflictField ConflictKind = "field"
+	// ConflictBlinding is a share whose x two sources give different
+	// Pedersen blinding values.
+	ConflictBlinding ConflictKind = "blinding"
+	// ConflictLabel is a share two sources give different names.
+	ConflictLabel ConflictKind = "label"
+	// ConflictParticipant is a share two sources give to different
+	// participants.
+	ConflictParticipant ConflictKind = "participant"
+	// ConflictBase is a share two sources write in different bases.
+	ConflictBase ConflictKind = "base"
+	// ConflictDeterministic is a difference in the insecure_deterministic
+	// marking.
+	ConflictDeterministic ConflictKind = "insecure_deterministic"
+	// ConflictChecksum is a difference in whether the shares carry
+	// checksums.
+	ConflictChecksum ConflictKind = "checksum"
+)
+
+// Difference is one way two share documents differ, as found by Diff. A and
+// B are the values in the first and the second document, empty where it has
+// none. X is set for a difference in one share.
+//
+// Material differences change the secret the documents reconstruct to or
+// the shares they hold: n, k, the field, the set of x values and the values
+// of shares at the same x. The others, and ConflictBase in particular, are
+// only a matter of how the same shares are described or written.
+type Difference struct {
+	Kind     ConflictKind `json:"kind"`
+	X        string       `json:"x,omitempty"`
+	A        string       `json:"a,omitempty"`
+	B        string       `json:"b,omitempty"`
+	Material bool         `json:"material"`
+}
+
+// Diff compares the decoded content of two share documents: values are
+// compared as numbers, so the same share written in two bases differs only
+// in ConflictBase. The keys come first, then the shares by x, then the
+// metadata of the documents.
+func Diff(a, b *Shares) []Difference {
+	var diffs []Difference
+	add := func(kind ConflictKind, x, va, vb string, material bool) {
+		if va != vb {
+			diffs = append(diffs, Difference{Kind: kind, X: x, A: va, B: vb, Material: material})
+		}
+	}
+	itoa := func(n int) string {
+		if n == 0 {
+			return ""
+		}
+		return strconv.Itoa(n)
+	}
+
+	add(ConflictCount, "", itoa(a.N), itoa(b.N), true)
+	add(ConflictThreshold, "", itoa(a.K), itoa(b.K), true)
+	add(ConflictModulus, "", intText(a.Modulus), intText(b.Modulus), true)
+	add(ConflictModulus, "", polyDiffText(a.Poly), polyDiffText(b.Poly), true)
+	// A preset name is metadata when the moduli agree, but a binary field
+	// is not the field of any modulus.
+	add(ConflictField, "", a.Field, b.Field, (a.Field == FieldGF2m) != (b.Field == FieldGF2m))
+
+	pointsA := make(map[string]Point, len(a.Points))
+	for _, point := range a.Points {
+		pointsA[point.X.String()] = point
+	}
+	pointsB := make(map[string]Point, len(b.Points))
+	for _, point := range b.Points {
+		pointsB[point.X.String()] = point
+	}
+	xs := make([]*big.Int, 0, len(a.Points)+len(b.Points))
+	seen := make(map[string]bool)
+	for _, points := range [][]Point{a.Points, b.Points} {
+		for _, point := range points {
+			if key := point.X.String(); !seen[key] {
+				seen[key] = true
+				xs = append(xs, point.X)
+			}
+		}
+	}
+	sort.Slice(xs, func(i, j int) bool { return xs[i].Cmp(xs[j]) < 0 })
+	for _, x := range xs {
+		key := x.String()
+		pa, inA := pointsA[key]
+		pb, inB := pointsB[key]
+		switch {
+		case !inB:
+			add(ConflictX, key, intText(pa.Y), "", true)
+			continue
+		case !inA:
+			add(ConflictX, key, "", intText(pb.Y), true)
+			continue
+		}
+		add(ConflictY, key, intText(pa.Y), intText(pb.Y), true)
+		add(ConflictBlinding, key, intText(pa.Blinding), intText(pb.Blinding), true)
+		add(ConflictLabel, key, pa.Label, pb.Label, false)
+		add(ConflictParticipant, key, pa.Participant, pb.Participant, false)
+		add(ConflictBase, key, baseText(pa.Base), baseText(pb.Base), false)
+	}
+
+	add(ConflictVersion, "", itoa(a.Version), itoa(b.Version), false)
+	add(ConflictSecretHash, "", a.SecretSHA256, b.SecretSHA256, false)
+	add(ConflictExpected, "", a.Expected, b.Expected, false)
+	add(ConflictCommitments, "", commitmentsText(a.Commitments), commitmentsText(b.Commitments), false)
+	add(ConflictParticipants, "", participantsText(a.Participants), participantsText(b.Participants), false)
+	add(ConflictDeterministic, "", strconv.FormatBool(a.Deterministic), strconv.FormatBool(b.Deterministic), false)
+	add(ConflictChecksum, "", strconv.FormatBool(a.Checksummed), strconv.FormatBool(b.Checksummed), false)
+	return diffs
+}
+
+func intText(v *big.Int) string {
+	if v == nil {
+		return ""
+	}
+	return v.String()
+}
+
+func polyDiffText(poly *big.Int) string {
+	if poly == nil {
+		return ""
+	}
+	return polyText(poly)
+}
+
+// baseText is the base a value was written in, base 10 when its entry did
+// not say.
+func baseText(base string) string {
+	if base == "" {
+		return "10"
+	}
+	return base
+}
+
+// commitmentsText names the scheme of c and a fingerprint of its group and
+// values.
+func commitmentsText(c *Commitments) string {
+	if c == nil {
+		return ""
+	}
+	h := sha256.New()
+	for _, v := range append([]*big.Int{c.P, c.G, c.H}, c.Values...) {
+		if v != nil {
+			h.Write([]byte(v.String() + ","))
+		}
+	}
+	return fmt.Sprintf("%s %x", c.Scheme, h.Sum(nil)[:4])
+}
+
+func participantsText(participants []Participant) string {
+	parts := make([]string, len(participants))
+	for i, p := range participants {
+		parts[i] = fmt.Sprintf("%s:%d", p.Name, p.Weight)
+	}
+	return strings.Join(parts, ",")
+}
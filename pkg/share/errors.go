@@ -0,0 +1,160 @@
+package share
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// Sentinel errors for the failure classes callers may want to tell apart
+// with errors.Is. The returned errors carry more detail in their message
+// and, for the typed ones below, in their fields.
+var (
+	ErrInsufficientShares = errors.New("not enough shares")
+	ErrDuplicateX         = errors.New("duplicate x value")
+	ErrInvalidBase        = errors.New("invalid base")
+	ErrCommitmentMismatch = errors.New("share does not match the commitments")
+	ErrChecksumMismatch   = errors.New("checksum mismatch")
+)
+
+// InsufficientSharesError reports that fewer shares are available than the
+// threshold needs. It matches ErrInsufficientShares.
+type InsufficientSharesError struct {
+	Found, Needed int
+
+	// Context, if set, says where the shares were counted, as in
+	// "not enough points in file".
+	Context string
+
+	// Coverage, for shares split among weighted participants, says which
+	// of them the shares came from.
+	Coverage *Coverage
+}
+
+func (e *InsufficientSharesError) Error() string {
+	if e.Coverage != nil {
+		return fmt.Sprintf("not enough weight: %s", e.Coverage)
+	}
+	if e.Context == "" {
+		return fmt.Sprintf("not enough points: found %d, need %d", e.Found, e.Needed)
+	}
+	return fmt.Sprintf("not enough points %s: found %d, need %d", e.Context, e.Found, e.Needed)
+}
+
+func (e *InsufficientSharesError) Is(target error) bool {
+	return target == ErrInsufficientShares
+}
+
+// DuplicateXError reports two shares with the same x. Entries names the
+// two entries of the document when known. It matches ErrDuplicateX.
+type DuplicateXError struct {
+	X       *big.Int
+	Entries []string
+}
+
+func (e *DuplicateXError) Error() string {
+	if len(e.Entries) == 2 {
+		return fmt.Sprintf("duplicate x=%s (entries %q and %q)", e.X.String(), e.Entries[0], e.Entries[1])
+	}
+	return fmt.Sprintf("duplicate x=%s", e.X.String())
+}
+
+func (e *DuplicateXError) Is(target error) bool {
+	return target == ErrDuplicateX
+}
+
+// CommitmentError reports shares that do not lie on the polynomial their
+// document's commitments describe. It matches ErrCommitmentMismatch.
+type CommitmentError struct {
+	Scheme string
+	Points []Point
+}
+
+func (e *CommitmentError) Error() string {
+	xs := make([]string, len(e.Points))
+	for i, point := range e.Points {
+		xs[i] = point.X.String()
+		if point.Source != "" {
+			xs[i] += " (" + point.Source + ")"
+		}
+	}
+	scheme := "Feldman"
+	if e.Scheme == SchemePedersen {
+		scheme = "Pedersen"
+	}
+	if len(xs) == 1 {
+		return fmt.Sprintf("share x=%s does not match the %s commitments", xs[0], scheme)
+	}
+	return fmt.Sprintf("shares x=%s do not match the %s commitments", strings.Join(xs, ", "), scheme)
+}
+
+func (e *CommitmentError) Is(target error) bool {
+	return target == ErrCommitmentMismatch
+}
+
+// ChecksumError reports a share entry whose x, base and value do not match
+// its checksum. It matches ErrChecksumMismatch.
+type ChecksumError struct {
+	Key string
+}
+
+func (e *ChecksumError) Error() string {
+	return fmt.Sprintf("share %q fails its checksum: its x, base or value was altered", e.Key)
+}
+
+func (e *ChecksumError) Is(target error) bool {
+	return target == ErrChecksumMismatch
+}
+
+// maxSnippet bounds the length of ParseError.Snippet.
+const maxSnippet = 64
+
+// ParseError locates a decoding failure within a share document.
+type ParseError struct {
+	Key     string // the share entry, such as "3" or "shares[2]"
+	Field   string // "x", "base" or "value"; empty for the entry as a whole
+	Snippet string // the offending text, truncated to maxSnippet bytes
+	Err     error
+}
+
+func newParseError(key, field, text string, err error) *ParseError {
+	if len(text) > maxSnippet {
+		text = text[:maxSnippet] + "..."
+	}
+	return &ParseError{Key: key, Field: field, Snippet: text, Err: err}
+}
+
+func (e *ParseError) Error() string {
+	if e.Field == "" {
+		return fmt.Sprintf("%v (entry %q: %q)", e.Err, e.Key, e.Snippet)
+	}
+	return fmt.Sprintf("%v (entry %q, field %s: %q)", e.Err, e.Key, e.Field, e.Snippet)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// MnemonicChecksumError reports a mnemonic whose words do not match its
+// checksum. Word is the 1-based position of the word most likely wrong and
+// Suggestion the word that would make the checksum valid; when Swapped is
+// set, words Word and Word+1 are likely transposed instead. Word is zero
+// when no single mistake explains the mismatch.
+type MnemonicChecksumError struct {
+	Word       int
+	Got        string
+	Suggestion string
+	Swapped    bool
+}
+
+func (e *MnemonicChecksumError) Error() string {
+	switch {
+	case e.Word == 0:
+		return "invalid mnemonic checksum: more than one word is wrong"
+	case e.Swapped:
+		return fmt.Sprintf("invalid mnemonic checksum: words %d and %d look swapped", e.Word, e.Word+1)
+	default:
+		return fmt.Sprintf("invalid mnemonic checksum: word %d %q is likely wrong (%q would fit)", e.Word, e.Got, e.Suggestion)
+	}
+}
@@ -0,0 +1,39 @@
+package share_test
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/OmSingh2003/CATALOG-ASSIGNMENT/pkg/share"
+)
+
+func ExampleParseShares() {
+	shares, err := share.ParseShares(strings.NewReader(`{
+		"keys": {"n": 2, "k": 2},
+		"1": {"base": "2", "value": "111"},
+		"2": {"base": "16", "value": "ff"}
+	}`))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	for _, point := range shares.Points {
+		fmt.Println(point.X, point.Y)
+	}
+	// Output:
+	// 1 7
+	// 2 255
+}
+
+func ExampleSplit() {
+	modulus := big.NewInt(257)
+	shares, err := share.Split(big.NewInt(42), 3, 2, modulus,
+		share.WithCommitments(""), share.WithSeed([]byte("example")))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(len(shares.Points), shares.K, shares.Modulus)
+	// Output: 3 2 257
+}
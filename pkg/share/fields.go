@@ -0,0 +1,88 @@
+package share
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// FieldGF2m is the "field" of the keys object of shares over a binary
+// field GF(2^m), whose reduction polynomial the keys object records as
+// "poly".
+const FieldGF2m = "gf2m"
+
+// BinaryField makes the JSON parsers accept shares over GF(2^m), which they
+// otherwise reject so that they are not interpolated over the integers.
+func BinaryField() ParseOption {
+	return func(c *parseConfig) {
+		c.field = FieldGF2m
+	}
+}
+
+// FieldPreset is a named prime field a split can be made over instead of
+// giving its modulus. Split records the name as the "field" of the keys
+// object, so the shares name their field themselves.
+type FieldPreset struct {
+	Name        string
+	Description string
+	Modulus     *big.Int
+}
+
+var fieldPresets = []struct {
+	name, description, modulus string
+}{
+	{"secp256k1", "order of the secp256k1 group", "0xfffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141"},
+	{"p256", "order of the NIST P-256 group", "0xffffffff00000000ffffffffffffffffbce6faada7179e84f3b9cac2fc632551"},
+	{"ed25519", "order of the ed25519 prime-order subgroup, 2^252 + 27742317777372353535851937790883648493", "0x1000000000000000000000000000000014def9dea2f79cd65812631a5cf5d3ed"},
+	{"mersenne127", "the Mersenne prime 2^127 - 1", "0x7fffffffffffffffffffffffffffffff"},
+}
+
+// FieldPresets returns the named prime fields, in the order --field list
+// shows them.
+func FieldPresets() []FieldPreset {
+	presets := make([]FieldPreset, len(fieldPresets))
+	for i, p := range fieldPresets {
+		modulus, _ := new(big.Int).SetString(p.modulus, 0)
+		presets[i] = FieldPreset{Name: p.name, Description: p.description, Modulus: modulus}
+	}
+	return presets
+}
+
+// LookupField returns the preset called name, or nil if there is none.
+func LookupField(name string) *FieldPreset {
+	for _, p := range FieldPresets() {
+		if p.Name == name {
+			return &p
+		}
+	}
+	return nil
+}
+
+// resolveFieldKey sets the modulus of keys whose "field" names a preset,
+// which must agree with any "modulus" they also record.
+func resolveFieldKey(keysData *tempKeys) error {
+	preset := LookupField(keysData.Field)
+	if preset == nil {
+		return nil
+	}
+	if keysData.Modulus != nil && keysData.Modulus.Cmp(preset.Modulus) != 0 {
+		return fmt.Errorf(`"keys" object records "modulus" %s, but its "field" %s has modulus %s`, keysData.Modulus, preset.Name, preset.Modulus)
+	}
+	keysData.Modulus = preset.Modulus
+	return nil
+}
+
+// ParsePoly reads the reduction polynomial of GF(2^m), as a 0x hex or a
+// decimal bit-vector with bit i the coefficient of x^i.
+func ParsePoly(text string) (*big.Int, error) {
+	text = strings.TrimSpace(text)
+	digits, base := text, 10
+	if strings.HasPrefix(text, "0x") || strings.HasPrefix(text, "0X") {
+		digits, base = text[2:], 16
+	}
+	poly, ok := new(big.Int).SetString(digits, base)
+	if !ok || strings.HasPrefix(digits, "-") || strings.HasPrefix(digits, "+") || poly.Cmp(big.NewInt(2)) < 0 {
+		return nil, fmt.Errorf("invalid polynomial %q: must be a 0x hex or decimal bit-vector of degree at least 1", text)
+	}
+	return poly, nil
+}
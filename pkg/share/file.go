@@ -0,0 +1,236 @@
+package share
+
+import (
+	"bufio"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// errFileShares is what the share parsers report for a share of a file,
+// whose values are the chunk lists FileShareReader reads.
+var errFileShares = errors.New("shares are of a file split with split --in; reconstruct them with reconstruct --out")
+
+// FileShareWriter streams one share of a file split chunk by chunk over
+// GF(2^8) as the document
+//
+//	{"keys": {"n": ..., "k": ..., "x": ..., "field": "gf256", "chunk_size": ..., "base": ...},
+//	 "chunks": [...], "file": {"size": ..., "sha256": ...}}
+//
+// whose chunks are the share's bytes of each chunk of the file in order.
+// The file trailer comes last, since its hash is only known once every
+// chunk has been read.
+type FileShareWriter struct {
+	w      *bufio.Writer
+	base   string
+	chunks int
+}
+
+// NewFileShareWriter writes the keys object of the share at x to w. base is
+// the base of the chunks: "16", "64", "64url" or "85".
+func NewFileShareWriter(w io.Writer, n, k int, x byte, chunkSize int, base string, deterministic bool) (*FileShareWriter, error) {
+	if _, err := encodeRaw(nil, base); err != nil {
+		return nil, err
+	}
+	fw := &FileShareWriter{w: bufio.NewWriter(w), base: base}
+	fmt.Fprintf(fw.w, "{\n    \"keys\": {\n        \"n\": %d,\n        \"k\": %d,\n        \"x\": %d,\n        \"field\": %q,\n        \"chunk_size\": %d,\n        \"base\": %q", n, k, x, FieldGF256, chunkSize, base)
+	if deterministic {
+		fw.w.WriteString(",\n        \"insecure_deterministic\": true")
+	}
+	fw.w.WriteString("\n    },\n    \"chunks\": [")
+	return fw, nil
+}
+
+// WriteChunk appends the share's bytes of the next chunk.
+func (fw *FileShareWriter) WriteChunk(y []byte) error {
+	text, _ := encodeRaw(y, fw.base)
+	value, _ := json.Marshal(text)
+	if fw.chunks > 0 {
+		fw.w.WriteByte(',')
+	}
+	fw.chunks++
+	fw.w.WriteString("\n        ")
+	_, err := fw.w.Write(value)
+	return err
+}
+
+// Close writes the trailer recording the size and SHA-256, in hex, of the
+// whole file, and flushes the document. It does not close the underlying
+// writer.
+func (fw *FileShareWriter) Close(size int64, sha256 string) error {
+	if fw.chunks > 0 {
+		fw.w.WriteString("\n    ")
+	}
+	fmt.Fprintf(fw.w, "],\n    \"file\": {\n        \"size\": %d,\n        \"sha256\": %q\n    }\n}\n", size, sha256)
+	return fw.w.Flush()
+}
+
+// FileShareReader reads a document written by FileShareWriter a chunk at a
+// time, so that a file of any size is reconstructed without holding its
+// shares in memory.
+type FileShareReader struct {
+	N, K          int
+	X             byte
+	ChunkSize     int
+	Base          string
+	Deterministic bool
+
+	// Size and SHA256 are those the trailer records, set once Next has
+	// returned io.EOF.
+	Size   int64
+	SHA256 string
+
+	dec    *json.Decoder
+	chunks int
+	done   bool
+}
+
+type fileKeys struct {
+	N             *int    `json:"n"`
+	K             *int    `json:"k"`
+	X             *int    `json:"x"`
+	Field         string  `json:"field"`
+	ChunkSize     *int    `json:"chunk_size"`
+	Base          *string `json:"base"`
+	Deterministic bool    `json:"insecure_deterministic"`
+}
+
+// NewFileShareReader reads the keys object of the share document r holds,
+// up to the first chunk.
+func NewFileShareReader(r io.Reader) (*FileShareReader, error) {
+	dec := json.NewDecoder(r)
+	if err := expectDelim(dec, '{', "the share document must be a JSON object"); err != nil {
+		return nil, err
+	}
+	if err := expectKey(dec, "keys"); err != nil {
+		return nil, err
+	}
+	var keys fileKeys
+	if err := dec.Decode(&keys); err != nil {
+		return nil, fmt.Errorf(`invalid "keys" object: %w`, err)
+	}
+	for _, required := range []struct {
+		name    string
+		missing bool
+	}{{"n", keys.N == nil}, {"k", keys.K == nil}, {"x", keys.X == nil}, {"chunk_size", keys.ChunkSize == nil}, {"base", keys.Base == nil}} {
+		if required.missing {
+			return nil, fmt.Errorf(`"keys" object is missing %q`, required.name)
+		}
+	}
+	switch {
+	case keys.Field != FieldGF256:
+		return nil, fmt.Errorf(`"keys" object has "field" %q, but file shares are over %s`, keys.Field, FieldGF256)
+	case *keys.K < 1 || *keys.N < *keys.K || *keys.N > 255:
+		return nil, fmt.Errorf(`invalid "keys" object: n=%d and k=%d must satisfy 1 <= k <= n <= 255`, *keys.N, *keys.K)
+	case *keys.X < 1 || *keys.X > 255:
+		return nil, fmt.Errorf(`invalid "x" in "keys" object: must be between 1 and 255 in GF(256), got %d`, *keys.X)
+	case *keys.ChunkSize < 1:
+		return nil, fmt.Errorf(`invalid "chunk_size" in "keys" object: must be positive, got %d`, *keys.ChunkSize)
+	}
+	if _, err := encodeRaw(nil, *keys.Base); err != nil {
+		return nil, fmt.Errorf(`invalid "base" in "keys" object: %w`, err)
+	}
+	if err := expectKey(dec, "chunks"); err != nil {
+		return nil, err
+	}
+	if err := expectDelim(dec, '[', `"chunks" must be an array`); err != nil {
+		return nil, err
+	}
+	return &FileShareReader{
+		N: *keys.N, K: *keys.K, X: byte(*keys.X),
+		ChunkSize: *keys.ChunkSize, Base: *keys.Base, Deterministic: keys.Deterministic,
+		dec: dec,
+	}, nil
+}
+
+// Next returns the share's bytes of the next chunk, or io.EOF after the
+// last one once the trailer has been read.
+func (fr *FileShareReader) Next() ([]byte, error) {
+	if fr.done {
+		return nil, io.EOF
+	}
+	if !fr.dec.More() {
+		return nil, fr.readTrailer()
+	}
+	var text string
+	if err := fr.dec.Decode(&text); err != nil {
+		return nil, fmt.Errorf("invalid chunk %d: must be a string: %w", fr.chunks, err)
+	}
+	y, err := decodeRaw(fr.Base, text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid chunk %d in base %q: %w", fr.chunks, fr.Base, err)
+	}
+	if len(y) > fr.ChunkSize {
+		return nil, fmt.Errorf("chunk %d has %d bytes, more than the chunk size %d", fr.chunks, len(y), fr.ChunkSize)
+	}
+	fr.chunks++
+	return y, nil
+}
+
+func (fr *FileShareReader) readTrailer() error {
+	if err := expectDelim(fr.dec, ']', `"chunks" must be an array`); err != nil {
+		return err
+	}
+	if err := expectKey(fr.dec, "file"); err != nil {
+		return err
+	}
+	var trailer struct {
+		Size   *int64 `json:"size"`
+		SHA256 string `json:"sha256"`
+	}
+	if err := fr.dec.Decode(&trailer); err != nil {
+		return fmt.Errorf(`invalid "file" object: %w`, err)
+	}
+	if trailer.Size == nil || *trailer.Size < 0 {
+		return errors.New(`"file" object is missing a non-negative "size"`)
+	}
+	if sum, err := hex.DecodeString(trailer.SHA256); err != nil || len(sum) != 32 {
+		return fmt.Errorf(`invalid "sha256" in "file" object: must be 64 hex digits, got %q`, trailer.SHA256)
+	}
+	if err := expectDelim(fr.dec, '}', `unexpected content after the "file" object`); err != nil {
+		return err
+	}
+	fr.Size, fr.SHA256 = *trailer.Size, strings.ToLower(trailer.SHA256)
+	fr.done = true
+	return io.EOF
+}
+
+// IsFileShare reports whether r holds a share document written by
+// FileShareWriter, reading no further than its keys object.
+func IsFileShare(r io.Reader) bool {
+	dec := json.NewDecoder(r)
+	if expectDelim(dec, '{', "") != nil || expectKey(dec, "keys") != nil {
+		return false
+	}
+	var keys map[string]json.RawMessage
+	if dec.Decode(&keys) != nil {
+		return false
+	}
+	_, ok := keys["chunk_size"]
+	return ok
+}
+
+func expectDelim(dec *json.Decoder, want json.Delim, message string) error {
+	token, err := dec.Token()
+	if err != nil {
+		return fmt.Errorf("%s: %w", message, err)
+	}
+	if token != want {
+		return errors.New(message)
+	}
+	return nil
+}
+
+func expectKey(dec *json.Decoder, want string) error {
+	token, err := dec.Token()
+	if err != nil {
+		return fmt.Errorf("expected %q: %w", want, err)
+	}
+	if token != want {
+		return fmt.Errorf("expected %q, got %v", want, token)
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package share
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// Filter returns a copy of s restricted to the shares whose x is listed in
+// use (all shares when use is empty) and not listed in exclude.
+func (s *Shares) Filter(use, exclude []*big.Int) (*Shares, error) {
+	present := make(map[string]bool, len(s.Points))
+	for _, point := range s.Points {
+		present[point.X.String()] = true
+	}
+
+	var unknown, overlap []string
+	for _, x := range append(append([]*big.Int{}, use...), exclude...) {
+		if key := x.String(); !present[key] {
+			unknown = append(unknown, key)
+		}
+	}
+
+	excluded := make(map[string]bool, len(exclude))
+	for _, x := range exclude {
+		excluded[x.String()] = true
+	}
+
+	used := make(map[string]bool, len(use))
+	for _, x := range use {
+		key := x.String()
+		if excluded[key] {
+			overlap = append(overlap, key)
+		}
+		used[key] = true
+	}
+
+	if len(unknown) > 0 {
+		return nil, fmt.Errorf("unknown share x=%s", strings.Join(unknown, ", "))
+	}
+	if len(overlap) > 0 {
+		return nil, fmt.Errorf("share x=%s is both used and excluded", strings.Join(overlap, ", "))
+	}
+
+	filtered := &Shares{N: s.N, K: s.K, Source: s.Source, Commitments: s.Commitments, Deterministic: s.Deterministic, SecretSHA256: s.SecretSHA256, Modulus: s.Modulus, Field: s.Field, Poly: s.Poly}
+	for _, point := range s.Points {
+		key := point.X.String()
+		if excluded[key] || (len(used) > 0 && !used[key]) {
+			continue
+		}
+		filtered.Points = append(filtered.Points, point)
+	}
+
+	if len(filtered.Points) < s.K {
+		return nil, &InsufficientSharesError{Found: len(filtered.Points), Needed: s.K, Context: "after selection"}
+	}
+	return filtered, nil
+}
@@ -0,0 +1,102 @@
+package share_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/OmSingh2003/CATALOG-ASSIGNMENT/pkg/lagrange"
+	"github.com/OmSingh2003/CATALOG-ASSIGNMENT/pkg/share"
+)
+
+// roundTripBases are the bases FuzzRoundTrip writes shares in.
+var roundTripBases = []string{"2", "10", "16", "36", "62", "64", "64url", "85"}
+
+// FuzzRoundTrip splits a secret, writes the shares, parses them back and
+// checks that they reconstruct to the secret and its recorded hash.
+func FuzzRoundTrip(f *testing.F) {
+	sidecars, err := filepath.Glob("../selftest/vectors/*.expected.json")
+	if err != nil {
+		f.Fatal(err)
+	}
+	for i, path := range sidecars {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			f.Fatal(err)
+		}
+		var want struct {
+			Secret string `json:"secret"`
+			Mod    string `json:"mod"`
+			Binary bool   `json:"binary"`
+		}
+		if err := json.Unmarshal(data, &want); err != nil {
+			f.Fatalf("%s: %v", path, err)
+		}
+		secret, ok := new(big.Int).SetString(want.Secret, 0)
+		if !ok || secret.Sign() < 0 || want.Binary {
+			continue
+		}
+		f.Add(secret.Bytes(), uint8(5), uint8(3), uint8(i), want.Mod != "")
+	}
+	f.Add([]byte{}, uint8(1), uint8(1), uint8(0), false)
+	f.Add(bytes.Repeat([]byte{0xff}, 64), uint8(12), uint8(10), uint8(7), false)
+	f.Add(bytes.Repeat([]byte{0xff}, 32), uint8(3), uint8(2), uint8(5), true)
+
+	modulus := share.LookupField("secp256k1").Modulus
+	f.Fuzz(func(t *testing.T, data []byte, n, k, base uint8, modular bool) {
+		if len(data) > 256 {
+			return
+		}
+		n = n%20 + 1
+		k = k%n + 1
+		secret := new(big.Int).SetBytes(data)
+		var mod *big.Int
+		var opts []lagrange.Option
+		if modular {
+			mod = modulus
+			secret.Mod(secret, mod)
+			opts = append(opts, lagrange.WithModulus(mod))
+		}
+
+		// Commitments take seconds to generate and are left to the
+		// tests of their own.
+		shares, err := share.Split(secret, int(n), int(k), mod, share.WithSeed(data), share.WithCommitments(""))
+		if err != nil {
+			t.Fatalf("Split(%s, %d, %d): %v", secret, n, k, err)
+		}
+		var buf bytes.Buffer
+		bases := []string{roundTripBases[int(base)%len(roundTripBases)], "10"}
+		if err := share.WriteShares(&buf, shares, bases); err != nil {
+			t.Fatalf("WriteShares in bases %q: %v", bases, err)
+		}
+		parsed, err := share.ParseShares(&buf)
+		if err != nil {
+			t.Fatalf("ParseShares of written shares: %v\n%s", err, buf.String())
+		}
+		if len(parsed.Points) != int(n) || parsed.K != int(k) {
+			t.Fatalf("parsed %d shares with k=%d, want %d with k=%d", len(parsed.Points), parsed.K, n, k)
+		}
+		for _, d := range share.Diff(shares, parsed) {
+			if d.Material {
+				t.Errorf("parsed shares differ from the written ones: %+v", d)
+			}
+		}
+		points, _, err := parsed.Select()
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := lagrange.Interpolate(points, opts...)
+		if err != nil {
+			t.Fatalf("Interpolate: %v", err)
+		}
+		if got.Cmp(secret) != 0 {
+			t.Fatalf("reconstructed %s, want %s", got, secret)
+		}
+		if err := parsed.VerifySecret(got); err != nil {
+			t.Error(err)
+		}
+	})
+}
@@ -0,0 +1,127 @@
+package share
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"sort"
+)
+
+// Entry is one share entry of a JSON share document as Inspect found it.
+type Entry struct {
+	Key   string
+	X     *big.Int // nil when the x value is invalid
+	Base  string
+	Value string
+	Y     *big.Int // nil when the value could not be decoded
+
+	// Selected reports whether the entry is among the first k entries
+	// without problems, the ones a reconstruction would use once the
+	// document is fixed.
+	Selected bool
+
+	// Problems describes anything wrong or suspicious about the entry.
+	Problems []string
+}
+
+// Inspection is the content of a share document decoded without failing on
+// individual bad entries.
+type Inspection struct {
+	N, K    int
+	Entries []Entry
+
+	// Participant is the holder of a participant's file, of Weight points,
+	// and Participants all the holders of the split.
+	Participant  string
+	Weight       int
+	Participants []Participant
+}
+
+// Inspect decodes every entry of a JSON share document, recording problems
+// such as duplicate x values, out-of-range bases and values much shorter
+// than the others on the entries instead of returning an error. Entries
+// are sorted by x, with invalid x values last.
+func Inspect(r io.Reader, opts ...ParseOption) (*Inspection, error) {
+	c := newParseConfig(opts)
+
+	keysData, _, raws, err := c.readDocument(r)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]Entry, 0, len(raws))
+	for _, raw := range raws {
+		entry := Entry{Key: raw.key}
+		if x, err := parseX(raw.xText); err != nil {
+			entry.Problems = append(entry.Problems, "invalid x value")
+		} else {
+			entry.X = x
+		}
+
+		root, err := c.readRoot(raw)
+		if err != nil {
+			entry.Problems = append(entry.Problems, err.(*ParseError).Err.Error())
+			entries = append(entries, entry)
+			continue
+		}
+		entry.Base, entry.Value = root.Base, root.Value
+
+		x := entry.X
+		if x == nil {
+			x = new(big.Int)
+		}
+		y, err := c.decodeY(raw.key, x, root.Base, root.Value)
+		switch {
+		case errors.Is(err, ErrInvalidBase):
+			entry.Problems = append(entry.Problems, "invalid base")
+		case err != nil:
+			entry.Problems = append(entry.Problems, "value is not valid in its base")
+		default:
+			entry.Y = y
+		}
+		entries = append(entries, entry)
+	}
+
+	sort.SliceStable(entries, func(a, b int) bool {
+		xa, xb := entries[a].X, entries[b].X
+		switch {
+		case xa == nil || xb == nil:
+			return xb == nil && xa != nil
+		case xa.Cmp(xb) != 0:
+			return xa.Cmp(xb) < 0
+		}
+		return entries[a].Key < entries[b].Key
+	})
+
+	maxBits := 0
+	for _, entry := range entries {
+		if entry.Y != nil && entry.Y.BitLen() > maxBits {
+			maxBits = entry.Y.BitLen()
+		}
+	}
+
+	count := make(map[string]int)
+	for _, entry := range entries {
+		if entry.X != nil {
+			count[entry.X.String()]++
+		}
+	}
+
+	selected := 0
+	for i := range entries {
+		entry := &entries[i]
+		if entry.X != nil && count[entry.X.String()] > 1 {
+			entry.Problems = append(entry.Problems, fmt.Sprintf("duplicate x=%s", entry.X.String()))
+		}
+		if entry.Y != nil && maxBits >= 16 && entry.Y.BitLen()*2 < maxBits {
+			entry.Problems = append(entry.Problems, fmt.Sprintf("value is much shorter than the others (%d bits, largest is %d)", entry.Y.BitLen(), maxBits))
+		}
+		if len(entry.Problems) == 0 && selected < keysData.K {
+			entry.Selected = true
+			selected++
+		}
+	}
+
+	return &Inspection{N: keysData.N, K: keysData.K, Entries: entries, Participant: keysData.Participant, Weight: keysData.Weight, Participants: keysData.Participants}, nil
+}
@@ -0,0 +1,139 @@
+package share
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Lenient makes the JSON parsers accept // and /* */ comments outside
+// strings, with block comments nesting, and a trailing comma before the
+// closing } or ] of an object or array, as hand-edited files collect them.
+// The document is read whole and the extensions are blanked out with
+// spaces, keeping line breaks, so the offsets of the decoder are those of
+// the original text; syntax errors then give its line and column.
+func Lenient() ParseOption {
+	return func(c *parseConfig) {
+		c.lenient = true
+	}
+}
+
+// lenientJSON returns data with the comments and trailing commas blanked.
+func lenientJSON(data []byte) ([]byte, error) {
+	out := bytes.Clone(data)
+	blank := func(from, to int) {
+		for i := from; i < to; i++ {
+			if out[i] != '\n' && out[i] != '\r' {
+				out[i] = ' '
+			}
+		}
+	}
+
+	inString := false
+	for i := 0; i < len(out); i++ {
+		switch b := out[i]; {
+		case inString:
+			if b == '\\' {
+				i++
+			} else if b == '"' {
+				inString = false
+			}
+		case b == '"':
+			inString = true
+		case b == '/' && i+1 < len(out) && out[i+1] == '/':
+			end := bytes.IndexByte(out[i:], '\n')
+			if end < 0 {
+				end = len(out) - i
+			}
+			blank(i, i+end)
+			i += end - 1
+		case b == '/' && i+1 < len(out) && out[i+1] == '*':
+			start := i
+			depth := 0
+			for ; i+1 < len(out); i++ {
+				if out[i] == '/' && out[i+1] == '*' {
+					depth++
+					i++
+				} else if out[i] == '*' && out[i+1] == '/' {
+					if depth--; depth == 0 {
+						break
+					}
+					i++
+				}
+			}
+			if depth > 0 {
+				line, column := position(data, start)
+				return nil, fmt.Errorf("line %d, column %d: failed to unmarshal raw json: unterminated /* comment", line, column)
+			}
+			i++
+			blank(start, i+1)
+		}
+	}
+
+	// With the comments gone, a comma is trailing when only whitespace
+	// separates it from a closing bracket.
+	inString = false
+	for i := 0; i < len(out); i++ {
+		switch b := out[i]; {
+		case inString:
+			if b == '\\' {
+				i++
+			} else if b == '"' {
+				inString = false
+			}
+		case b == '"':
+			inString = true
+		case b == ',':
+			j := i + 1
+			for j < len(out) && isJSONSpace(out[j]) {
+				j++
+			}
+			if j < len(out) && (out[j] == '}' || out[j] == ']') {
+				out[i] = ' '
+			}
+		}
+	}
+	return out, nil
+}
+
+func isJSONSpace(b byte) bool {
+	return b == ' ' || b == '\t' || b == '\n' || b == '\r'
+}
+
+// position returns the 1-based line and column, in bytes, of offset in
+// data.
+func position(data []byte, offset int) (int, int) {
+	offset = min(offset, len(data))
+	line := 1 + bytes.Count(data[:offset], []byte("\n"))
+	return line, offset - bytes.LastIndexByte(data[:offset], '\n')
+}
+
+// lenientReader reads r whole for a lenient parse and returns its blanked
+// text, along with a function adding the line and column of the original
+// to a syntax error.
+func lenientReader(r io.Reader) (io.Reader, func(error) error, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read input: %w", err)
+	}
+	clean, err := lenientJSON(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	locate := func(err error) error {
+		var syntax *json.SyntaxError
+		if errors.As(err, &syntax) {
+			// Offset counts the bytes read up to and including the bad one.
+			line, column := position(data, max(0, int(syntax.Offset)-1))
+			return fmt.Errorf("line %d, column %d: %w", line, column, err)
+		}
+		if errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF) {
+			line, column := position(data, len(data))
+			return fmt.Errorf("line %d, column %d: %w", line, column, err)
+		}
+		return err
+	}
+	return bytes.NewReader(clean), locate, nil
+}
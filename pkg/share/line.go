@@ -0,0 +1,45 @@
+package share
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+// ParsePoint decodes one share typed on a line: tersely as "x base value",
+// as a document entry such as "2": {"base": "2", "value": "111"}, or as an
+// object with "x", "base" and "value" like an element of a "shares" array.
+// The share is decoded and validated as it would be in a share file.
+func ParsePoint(line string, opts ...ParseOption) (Point, error) {
+	text := strings.TrimSuffix(strings.TrimSpace(line), ",")
+	var entry rawEntry
+	switch {
+	case strings.HasPrefix(text, "{"):
+		xText, err := arrayXText("share", json.RawMessage(text))
+		if err != nil {
+			return Point{}, err
+		}
+		entry = rawEntry{key: xText, xText: xText, raw: json.RawMessage(text), hasX: true}
+	case strings.HasPrefix(text, `"`):
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal([]byte("{"+text+"}"), &fields); err != nil || len(fields) != 1 {
+			return Point{}, errors.New(`invalid share entry: want "x": {"base": ..., "value": ...}`)
+		}
+		for key, raw := range fields {
+			entry = rawEntry{key: key, xText: key, raw: raw}
+		}
+	default:
+		parts := strings.Fields(text)
+		if len(parts) != 3 {
+			return Point{}, errors.New("invalid share: want x base value, or a JSON entry")
+		}
+		raw, _ := json.Marshal(map[string]string{"base": parts[1], "value": parts[2]})
+		entry = rawEntry{key: parts[0], xText: parts[0], raw: raw}
+	}
+
+	p := newPointSet(newParseConfig(opts))
+	if err := p.add(entry); err != nil {
+		return Point{}, err
+	}
+	return p.points[0], nil
+}
@@ -0,0 +1,14 @@
+package share
+
+// Merge combines share documents into one. All documents must agree on k,
+// on n where they declare it, and on any expected value and commitments
+// they record.
+// A share that appears in several documents is kept once if its y values
+// are identical and is an error otherwise.
+func Merge(sets ...*Shares) (*Shares, error) {
+	set := NewShareSet()
+	for _, shares := range sets {
+		set.AddFrom(shares.Source, shares)
+	}
+	return set.Shares()
+}
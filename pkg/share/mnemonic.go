@@ -0,0 +1,359 @@
+package share
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+)
+
+// Mnemonics follow the share layout of SLIP-39 with a single group: a
+// 15-bit identifier, the extendable flag and iteration exponent, the group
+// fields, the member index and threshold, the share value padded to a
+// multiple of 10 bits, and an RS1024 checksum, written as words of the
+// SLIP-39 wordlist. The share at x is written with member index x-1.
+//
+// Only the encoding is shared with SLIP-39: the secret sits at x=0 of
+// the polynomials rather than being the encrypted master secret at x=255,
+// so these mnemonics do not restore SLIP-39 wallets, nor the reverse.
+const (
+	// MaxMnemonicShares is the most shares a mnemonic split may have, as
+	// the member index takes 4 bits.
+	MaxMnemonicShares = 16
+
+	mnemonicHeaderWords   = 4
+	mnemonicChecksumWords = 3
+	mnemonicMinBytes      = 16
+)
+
+// Mnemonic is one share written as a mnemonic. Threshold is the number of
+// shares needed to reconstruct, and Identifier, 15 bits, is common to every
+// share of one split so that shares of different secrets are not mixed.
+type Mnemonic struct {
+	Identifier uint16
+	Threshold  int
+	Point      BytePoint
+
+	// Extendable and IterationExponent are carried over from decoded
+	// mnemonics; split always writes them as zero.
+	Extendable        bool
+	IterationExponent int
+}
+
+var slip39Index = func() map[string]int {
+	index := make(map[string]int, 2*len(slip39Words))
+	for i, word := range slip39Words {
+		index[word] = i
+		index[word[:4]] = i
+	}
+	return index
+}()
+
+// EncodeMnemonic writes m as a space-separated word sequence. The share
+// value must be an even number of bytes, and at least 16, as in SLIP-39.
+func EncodeMnemonic(m Mnemonic) (string, error) {
+	x, y := m.Point.X, m.Point.Y
+	if x == 0 || x > MaxMnemonicShares {
+		return "", fmt.Errorf("share x=%d cannot be written as a mnemonic: x must be between 1 and %d", x, MaxMnemonicShares)
+	}
+	if m.Threshold < 1 || m.Threshold > MaxMnemonicShares {
+		return "", fmt.Errorf("threshold %d cannot be written as a mnemonic: must be between 1 and %d", m.Threshold, MaxMnemonicShares)
+	}
+	if len(y) < mnemonicMinBytes || len(y)%2 != 0 {
+		return "", fmt.Errorf("a %d-byte share cannot be written as a mnemonic: need an even number of bytes, at least %d", len(y), mnemonicMinBytes)
+	}
+	if m.Identifier >= 1<<15 || m.IterationExponent < 0 || m.IterationExponent > 15 {
+		return "", errors.New("mnemonic identifier or iteration exponent out of range")
+	}
+
+	id := int(m.Identifier)<<5 | m.IterationExponent
+	if m.Extendable {
+		id |= 1 << 4
+	}
+	// Group index 0 with a group threshold and count of 1 are all zero.
+	member := int(x-1)<<4 | (m.Threshold - 1)
+	indices := []int{id >> 10, id & 1023, member >> 10, member & 1023}
+
+	valueWords := (8*len(y) + 9) / 10
+	value := new(big.Int).SetBytes(y)
+	digits := make([]int, valueWords)
+	mask := big.NewInt(1023)
+	for i := valueWords - 1; i >= 0; i-- {
+		digits[i] = int(new(big.Int).And(value, mask).Int64())
+		value.Rsh(value, 10)
+	}
+	indices = append(indices, digits...)
+	indices = append(indices, rs1024Checksum(m.Extendable, indices)...)
+
+	words := make([]string, len(indices))
+	for i, index := range indices {
+		words[i] = slip39Words[index]
+	}
+	return strings.Join(words, " "), nil
+}
+
+// DecodeMnemonic reads a mnemonic written by EncodeMnemonic. Words are
+// matched case-insensitively, and their first four letters are enough. A
+// wrong checksum is reported as a *MnemonicChecksumError naming the word
+// that is likely wrong.
+func DecodeMnemonic(text string) (Mnemonic, error) {
+	var m Mnemonic
+	fields := strings.Fields(strings.ToLower(text))
+	minWords := mnemonicHeaderWords + (8*mnemonicMinBytes+9)/10 + mnemonicChecksumWords
+	if len(fields) < minWords {
+		return m, fmt.Errorf("mnemonic has %d words, need at least %d", len(fields), minWords)
+	}
+
+	indices := make([]int, len(fields))
+	for i, word := range fields {
+		index, ok := slip39Index[word]
+		if !ok && len(word) > 4 {
+			index, ok = slip39Index[word[:4]]
+		}
+		if !ok {
+			return m, fmt.Errorf("word %d %q is not in the SLIP-39 wordlist%s", i+1, word, suggestWord(word))
+		}
+		indices[i] = index
+	}
+
+	extendable := indices[1]>>4&1 == 1
+	if rs1024Polymod(extendable, indices) != 1 {
+		return m, locateMnemonicError(indices, fields)
+	}
+
+	id := indices[0]<<10 | indices[1]
+	member := indices[2]<<10 | indices[3]
+	groupIndex, groupThreshold, groupCount := member>>16, member>>12&15, member>>8&15
+	if groupIndex != 0 || groupThreshold != 0 || groupCount != 0 {
+		return m, errors.New("mnemonic belongs to a split with several groups, which is not supported")
+	}
+
+	valueBits := 10 * (len(indices) - mnemonicHeaderWords - mnemonicChecksumWords)
+	padding := valueBits % 16
+	if padding > 8 {
+		return m, fmt.Errorf("mnemonic has %d words, which is not a valid length", len(fields))
+	}
+	value := new(big.Int)
+	for _, index := range indices[mnemonicHeaderWords : len(indices)-mnemonicChecksumWords] {
+		value.Lsh(value, 10)
+		value.Or(value, big.NewInt(int64(index)))
+	}
+	size := (valueBits - padding) / 8
+	if value.BitLen() > 8*size {
+		return m, errors.New("mnemonic padding bits are not zero")
+	}
+
+	m.Identifier = uint16(id >> 5)
+	m.Extendable = extendable
+	m.IterationExponent = id & 15
+	m.Threshold = member&15 + 1
+	m.Point = BytePoint{X: byte(member>>4&15) + 1, Y: value.FillBytes(make([]byte, size))}
+	return m, nil
+}
+
+// locateMnemonicError finds the single replaced word, or the pair of
+// adjacent swapped words, that would make the checksum of indices valid.
+// RS1024 detects any three wrong words, so at most one such fix exists.
+func locateMnemonicError(indices []int, words []string) error {
+	trial := append([]int(nil), indices...)
+	for i := range trial {
+		for candidate := range slip39Words {
+			if candidate == indices[i] {
+				continue
+			}
+			trial[i] = candidate
+			// The extendable flag lives in the second word, so a fix there
+			// may change the checksum's customization string.
+			if rs1024Polymod(trial[1]>>4&1 == 1, trial) == 1 {
+				return &MnemonicChecksumError{Word: i + 1, Got: words[i], Suggestion: slip39Words[candidate]}
+			}
+		}
+		trial[i] = indices[i]
+	}
+	for i := 0; i+1 < len(trial); i++ {
+		if trial[i] == trial[i+1] {
+			continue
+		}
+		trial[i], trial[i+1] = trial[i+1], trial[i]
+		if rs1024Polymod(trial[1]>>4&1 == 1, trial) == 1 {
+			return &MnemonicChecksumError{Word: i + 1, Got: words[i], Swapped: true}
+		}
+		trial[i], trial[i+1] = trial[i+1], trial[i]
+	}
+	return &MnemonicChecksumError{}
+}
+
+// suggestWord names the wordlist entry closest to an unknown word, if one
+// is within two edits.
+func suggestWord(word string) string {
+	best, bestDistance := "", 3
+	for _, candidate := range slip39Words {
+		if d := editDistance(word, candidate); d < bestDistance {
+			best, bestDistance = candidate, d
+		}
+	}
+	if best == "" {
+		return ""
+	}
+	return fmt.Sprintf(" (did you mean %q?)", best)
+}
+
+var rs1024Generator = [10]uint32{
+	0xe0e040, 0x1c1c080, 0x3838100, 0x7070200, 0xe0e0009,
+	0x1c0c2412, 0x38086c24, 0x3090fc48, 0x21b1f890, 0x3f3f120,
+}
+
+func rs1024Customization(extendable bool) string {
+	if extendable {
+		return "shamir_extendable"
+	}
+	return "shamir"
+}
+
+func rs1024Polymod(extendable bool, values []int) uint32 {
+	chk := uint32(1)
+	step := func(v uint32) {
+		b := chk >> 20
+		chk = (chk&0xfffff)<<10 ^ v
+		for i, g := range rs1024Generator {
+			if b>>i&1 == 1 {
+				chk ^= g
+			}
+		}
+	}
+	for _, c := range []byte(rs1024Customization(extendable)) {
+		step(uint32(c))
+	}
+	for _, v := range values {
+		step(uint32(v))
+	}
+	return chk
+}
+
+func rs1024Checksum(extendable bool, values []int) []int {
+	polymod := rs1024Polymod(extendable, append(values[:len(values):len(values)], 0, 0, 0)) ^ 1
+	return []int{int(polymod >> 20 & 1023), int(polymod >> 10 & 1023), int(polymod & 1023)}
+}
+
+// mnemonicSet checks that the mnemonics of one input belong together.
+type mnemonicSet struct {
+	first Mnemonic
+	label string
+	count int
+}
+
+func (s *mnemonicSet) add(label string, m Mnemonic) error {
+	s.count++
+	if s.count == 1 {
+		s.first, s.label = m, label
+		return nil
+	}
+	switch {
+	case m.Identifier != s.first.Identifier:
+		return fmt.Errorf("mnemonic on %s belongs to a different split than the one on %s (identifier %d, not %d)", label, s.label, m.Identifier, s.first.Identifier)
+	case m.Threshold != s.first.Threshold:
+		return fmt.Errorf("mnemonic on %s has threshold %d, but the one on %s has %d", label, m.Threshold, s.label, s.first.Threshold)
+	}
+	return nil
+}
+
+// ParseMnemonics reads mnemonic shares, one per line. A line may start with
+// a label ending in ": ", such as "Share 1: "; blank lines and lines
+// starting with # are skipped. K is the threshold the mnemonics record.
+func ParseMnemonics(r io.Reader) (*ByteShares, error) {
+	var set mnemonicSet
+	var points []BytePoint
+	var labels []string
+	r, _, err := skipBOM(r)
+	if err != nil {
+		return nil, err
+	}
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		if i := strings.LastIndex(text, ": "); i >= 0 {
+			text = strings.TrimSpace(text[i+2:])
+		}
+
+		label := fmt.Sprintf("line %d", line)
+		m, err := DecodeMnemonic(text)
+		if err != nil {
+			return nil, newParseError(label, "", text, err)
+		}
+		if err := set.add(label, m); err != nil {
+			return nil, err
+		}
+		points = append(points, m.Point)
+		labels = append(labels, label)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read input: %w", err)
+	}
+	if len(points) == 0 {
+		return nil, errors.New("input holds no mnemonics")
+	}
+
+	for i, point := range points {
+		if len(point.Y) != len(points[0].Y) {
+			return nil, fmt.Errorf("share on %s has %d bytes, but share on %s has %d", labels[i], len(point.Y), labels[0], len(points[0].Y))
+		}
+		for j := range points[:i] {
+			if points[j].X == point.X {
+				return nil, &DuplicateXError{X: bigByte(point.X), Entries: []string{labels[j], labels[i]}}
+			}
+		}
+	}
+	return &ByteShares{K: set.first.Threshold, Points: points}, nil
+}
+
+// WriteMnemonics writes every share of s as a mnemonic on its own line,
+// under a new random identifier.
+func WriteMnemonics(w io.Writer, s *ByteShares) error {
+	var random [2]byte
+	if _, err := rand.Read(random[:]); err != nil {
+		return fmt.Errorf("failed to generate identifier: %w", err)
+	}
+	id := binary.BigEndian.Uint16(random[:]) >> 1
+
+	var buf strings.Builder
+	for _, point := range s.Points {
+		text, err := EncodeMnemonic(Mnemonic{Identifier: id, Threshold: s.K, Point: point})
+		if err != nil {
+			return err
+		}
+		buf.WriteString(text)
+		buf.WriteByte('\n')
+	}
+	_, err := io.WriteString(w, buf.String())
+	return err
+}
+
+// IsMnemonic reports whether head, the start of an input, looks like
+// mnemonic shares: on its first line that is not blank or a comment, the
+// first word after any label is in the SLIP-39 wordlist and more follow.
+func IsMnemonic(head []byte) bool {
+	var text string
+	head, _, _ = trimBOM(head)
+	for _, line := range strings.Split(string(head), "\n") {
+		if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "#") {
+			text = line
+			break
+		}
+	}
+	if i := strings.LastIndex(text, ": "); i >= 0 {
+		text = text[i+2:]
+	}
+	fields := strings.Fields(strings.ToLower(text))
+	if len(fields) < 2 {
+		return false
+	}
+	_, ok := slip39Index[fields[0]]
+	return ok
+}
@@ -0,0 +1,43 @@
+package share
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// MinAutoModulusBits is the size of the smallest prime AutoModulus picks,
+// so that even a small secret is split over a field far larger than any n.
+const MinAutoModulusBits = 64
+
+// AutoModulus returns the prime a split of secret uses when none is given:
+// the smallest prime of b bits, the first above 2^(b-1), where b is the
+// least power of two above the bit length of secret and at least
+// MinAutoModulusBits. The secret is then below 2^(b-1) and so below the
+// prime. The search is deterministic, so the same bit length always gives
+// the same prime. A negative secret has no modular split.
+func AutoModulus(secret *big.Int) (*big.Int, error) {
+	if secret.Sign() < 0 {
+		return nil, fmt.Errorf("secret must not be negative in modular mode")
+	}
+	bits := MinAutoModulusBits
+	for bits <= secret.BitLen() {
+		bits *= 2
+	}
+	candidate := new(big.Int).Lsh(big.NewInt(1), uint(bits-1))
+	candidate.Add(candidate, big.NewInt(1))
+	for !candidate.ProbablyPrime(20) {
+		candidate.Add(candidate, big.NewInt(2))
+	}
+	return candidate, nil
+}
+
+// parseModulusKey reads the "modulus" field of a keys object, a decimal
+// string or number.
+func parseModulusKey(text string) (*big.Int, error) {
+	modulus, ok := new(big.Int).SetString(strings.TrimSpace(text), 10)
+	if !ok || modulus.Cmp(big.NewInt(2)) < 0 {
+		return nil, fmt.Errorf(`invalid "modulus" in "keys" object: must be an integer of at least 2`)
+	}
+	return modulus, nil
+}
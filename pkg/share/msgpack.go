@@ -0,0 +1,343 @@
+package share
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"strconv"
+)
+
+const msgpackMaxDepth = 32
+
+// IsMsgpack reports whether data looks like the start of a MessagePack
+// share file, which always begins with a map header.
+func IsMsgpack(data []byte) bool {
+	if len(data) == 0 {
+		return false
+	}
+	b := data[0]
+	return b&0xf0 == 0x80 || b == 0xde || b == 0xdf
+}
+
+type msgpackDecoder struct {
+	r      *bufio.Reader
+	offset int64
+}
+
+// ParseMsgpack decodes a MessagePack share file laid out like the JSON
+// format. Share keys may be strings or integers, bases may be integers, and
+// a value may be binary data holding y in big-endian order.
+func ParseMsgpack(r io.Reader, opts ...ParseOption) (*Shares, error) {
+	c := newParseConfig(opts)
+	d := &msgpackDecoder{r: bufio.NewReader(r)}
+	root, err := d.decode(0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode msgpack: %w", err)
+	}
+
+	entries, ok := root.([]mapEntry)
+	if !ok {
+		return nil, fmt.Errorf("msgpack document must be a map, got %s", valueTypeName(root))
+	}
+
+	shares := &Shares{}
+	haveKeys := false
+	var keys []string
+	for _, entry := range entries {
+		var x *big.Int
+		switch key := entry.key.(type) {
+		case string:
+			if key == "keys" {
+				haveKeys = true
+				if err := msgpackKeys(shares, entry.value); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			v, err := parseX(key)
+			if err != nil {
+				return nil, err
+			}
+			x = v
+		case *big.Int:
+			x = key
+		default:
+			return nil, fmt.Errorf("msgpack share key must be a string or integer, got %s", valueTypeName(entry.key))
+		}
+
+		y, err := c.binaryShareValue(x, entry.value)
+		if err != nil {
+			return nil, fmt.Errorf("msgpack key %s: %w", x.String(), err)
+		}
+		shares.Points = append(shares.Points, Point{X: x, Y: y})
+		keys = append(keys, x.String())
+	}
+
+	if !haveKeys {
+		return nil, errors.New("msgpack input is missing the 'keys' map")
+	}
+	if err := checkKeys(shares.N, shares.K); err != nil {
+		return nil, err
+	}
+
+	if err := sortPoints(shares.Points, keys); err != nil {
+		return nil, err
+	}
+	return shares, nil
+}
+
+func msgpackKeys(shares *Shares, v any) error {
+	fields, ok := v.([]mapEntry)
+	if !ok {
+		return fmt.Errorf("msgpack key keys: must be a map, got %s", valueTypeName(v))
+	}
+
+	for _, field := range fields {
+		name, _ := field.key.(string)
+		if name != "n" && name != "k" {
+			continue
+		}
+
+		var value int
+		switch f := field.value.(type) {
+		case *big.Int:
+			if !f.IsInt64() {
+				return fmt.Errorf("msgpack key keys.%s: %s is out of range", name, f.String())
+			}
+			value = int(f.Int64())
+		case string:
+			var err error
+			if value, err = strconv.Atoi(f); err != nil {
+				return fmt.Errorf("msgpack key keys.%s: invalid integer %q", name, f)
+			}
+		default:
+			return fmt.Errorf("msgpack key keys.%s: must be an integer, got %s", name, valueTypeName(field.value))
+		}
+
+		if name == "n" {
+			shares.N = value
+		} else {
+			shares.K = value
+		}
+	}
+	return nil
+}
+
+func (d *msgpackDecoder) readN(n uint64) ([]byte, error) {
+	var buf bytes.Buffer
+	copied, err := io.CopyN(&buf, d.r, int64(n))
+	d.offset += copied
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// readUint reads a big-endian unsigned integer of size bytes.
+func (d *msgpackDecoder) readUint(size int) (uint64, error) {
+	raw, err := d.readN(uint64(size))
+	if err != nil {
+		return 0, err
+	}
+	var v uint64
+	for _, b := range raw {
+		v = v<<8 | uint64(b)
+	}
+	return v, nil
+}
+
+func (d *msgpackDecoder) decode(depth int) (any, error) {
+	if depth > msgpackMaxDepth {
+		return nil, fmt.Errorf("nesting deeper than %d levels at offset %d", msgpackMaxDepth, d.offset)
+	}
+
+	start := d.offset
+	b, err := d.r.ReadByte()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	d.offset++
+
+	switch {
+	case b <= 0x7f:
+		return big.NewInt(int64(b)), nil
+	case b >= 0xe0:
+		return big.NewInt(int64(int8(b))), nil
+	case b&0xf0 == 0x80:
+		return d.decodeMap(uint64(b&0x0f), depth)
+	case b&0xf0 == 0x90:
+		return d.decodeArray(uint64(b&0x0f), depth)
+	case b&0xe0 == 0xa0:
+		raw, err := d.readN(uint64(b & 0x1f))
+		return string(raw), err
+	}
+
+	switch b {
+	case 0xcc, 0xcd, 0xce, 0xcf:
+		v, err := d.readUint(1 << (b - 0xcc))
+		return new(big.Int).SetUint64(v), err
+	case 0xd0, 0xd1, 0xd2, 0xd3:
+		size := 1 << (b - 0xd0)
+		v, err := d.readUint(size)
+		if err != nil {
+			return nil, err
+		}
+		shift := 64 - 8*size
+		return big.NewInt(int64(v<<shift) >> shift), nil
+	case 0xc4, 0xc5, 0xc6:
+		n, err := d.readUint(1 << (b - 0xc4))
+		if err != nil {
+			return nil, err
+		}
+		return d.readN(n)
+	case 0xd9, 0xda, 0xdb:
+		n, err := d.readUint(1 << (b - 0xd9))
+		if err != nil {
+			return nil, err
+		}
+		raw, err := d.readN(n)
+		return string(raw), err
+	case 0xdc, 0xdd:
+		n, err := d.readUint(2 << (b - 0xdc))
+		if err != nil {
+			return nil, err
+		}
+		return d.decodeArray(n, depth)
+	case 0xde, 0xdf:
+		n, err := d.readUint(2 << (b - 0xde))
+		if err != nil {
+			return nil, err
+		}
+		return d.decodeMap(n, depth)
+	default:
+		return nil, fmt.Errorf("unsupported type byte 0x%02x at offset %d", b, start)
+	}
+}
+
+func (d *msgpackDecoder) decodeArray(n uint64, depth int) ([]any, error) {
+	var items []any
+	for i := uint64(0); i < n; i++ {
+		item, err := d.decode(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+func (d *msgpackDecoder) decodeMap(n uint64, depth int) ([]mapEntry, error) {
+	var entries []mapEntry
+	for i := uint64(0); i < n; i++ {
+		key, err := d.decode(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		value, err := d.decode(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, mapEntry{key: key, value: value})
+	}
+	return entries, nil
+}
+
+// WriteMsgpack encodes s as a MessagePack share file that ParseMsgpack
+// accepts. Non-negative values are written as binary data; negative ones
+// as decimal text.
+func WriteMsgpack(w io.Writer, s *Shares) error {
+	var buf bytes.Buffer
+
+	msgpackWriteMapHeader(&buf, len(s.Points)+1)
+	msgpackWriteString(&buf, "keys")
+	msgpackWriteMapHeader(&buf, 2)
+	msgpackWriteString(&buf, "n")
+	msgpackWriteInt(&buf, int64(s.N))
+	msgpackWriteString(&buf, "k")
+	msgpackWriteInt(&buf, int64(s.K))
+
+	for _, point := range s.Points {
+		msgpackWriteString(&buf, point.X.String())
+		if point.Y.Sign() >= 0 {
+			msgpackWriteMapHeader(&buf, 1)
+			msgpackWriteString(&buf, "value")
+			msgpackWriteBinary(&buf, point.Y.Bytes())
+			continue
+		}
+		msgpackWriteMapHeader(&buf, 2)
+		msgpackWriteString(&buf, "base")
+		msgpackWriteInt(&buf, 10)
+		msgpackWriteString(&buf, "value")
+		msgpackWriteString(&buf, point.Y.String())
+	}
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+func msgpackWriteMapHeader(buf *bytes.Buffer, n int) {
+	switch {
+	case n < 16:
+		buf.WriteByte(0x80 | byte(n))
+	case n <= 0xffff:
+		buf.WriteByte(0xde)
+		buf.Write(binary.BigEndian.AppendUint16(nil, uint16(n)))
+	default:
+		buf.WriteByte(0xdf)
+		buf.Write(binary.BigEndian.AppendUint32(nil, uint32(n)))
+	}
+}
+
+func msgpackWriteString(buf *bytes.Buffer, s string) {
+	switch n := len(s); {
+	case n < 32:
+		buf.WriteByte(0xa0 | byte(n))
+	case n <= 0xff:
+		buf.WriteByte(0xd9)
+		buf.WriteByte(byte(n))
+	case n <= 0xffff:
+		buf.WriteByte(0xda)
+		buf.Write(binary.BigEndian.AppendUint16(nil, uint16(n)))
+	default:
+		buf.WriteByte(0xdb)
+		buf.Write(binary.BigEndian.AppendUint32(nil, uint32(n)))
+	}
+	buf.WriteString(s)
+}
+
+func msgpackWriteBinary(buf *bytes.Buffer, data []byte) {
+	switch n := len(data); {
+	case n <= 0xff:
+		buf.WriteByte(0xc4)
+		buf.WriteByte(byte(n))
+	case n <= 0xffff:
+		buf.WriteByte(0xc5)
+		buf.Write(binary.BigEndian.AppendUint16(nil, uint16(n)))
+	default:
+		buf.WriteByte(0xc6)
+		buf.Write(binary.BigEndian.AppendUint32(nil, uint32(n)))
+	}
+	buf.Write(data)
+}
+
+func msgpackWriteInt(buf *bytes.Buffer, v int64) {
+	switch {
+	case v >= 0 && v <= 0x7f:
+		buf.WriteByte(byte(v))
+	case v < 0 && v >= -32:
+		buf.WriteByte(byte(int8(v)))
+	default:
+		buf.WriteByte(0xd3)
+		buf.Write(binary.BigEndian.AppendUint64(nil, uint64(v)))
+	}
+}
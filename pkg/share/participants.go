@@ -0,0 +1,221 @@
+package share
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Participant is a holder of weighted shares: the Weight points of a split
+// it is given are written to one file, so that a participant of weight 2
+// counts twice towards the threshold.
+type Participant struct {
+	Name   string `json:"name"`
+	Weight int    `json:"weight"`
+}
+
+// ParseParticipants reads a participants list, a JSON array of objects
+// with a "name" and an optional "weight", which defaults to 1.
+func ParseParticipants(r io.Reader) ([]Participant, error) {
+	var list []struct {
+		Name   string `json:"name"`
+		Weight *int   `json:"weight"`
+	}
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&list); err != nil {
+		return nil, fmt.Errorf("failed to parse participants: %w", err)
+	}
+	participants := make([]Participant, len(list))
+	for i, entry := range list {
+		participants[i] = Participant{Name: entry.Name, Weight: 1}
+		if entry.Weight != nil {
+			participants[i].Weight = *entry.Weight
+		}
+	}
+	if err := checkParticipants(participants); err != nil {
+		return nil, fmt.Errorf("invalid participants: %w", err)
+	}
+	return participants, nil
+}
+
+// checkParticipants reports what is wrong with a participants list, if
+// anything.
+func checkParticipants(participants []Participant) error {
+	if len(participants) == 0 {
+		return errors.New("the list is empty")
+	}
+	seen := make(map[string]bool)
+	for _, p := range participants {
+		switch {
+		case strings.TrimSpace(p.Name) == "":
+			return errors.New("every participant needs a name")
+		case seen[p.Name]:
+			return fmt.Errorf("%q is listed twice", p.Name)
+		case p.Weight < 1:
+			return fmt.Errorf("%q has weight %d, must be at least 1", p.Name, p.Weight)
+		}
+		seen[p.Name] = true
+	}
+	return nil
+}
+
+// TotalWeight returns the number of points participants hold together.
+func TotalWeight(participants []Participant) int {
+	total := 0
+	for _, p := range participants {
+		total += p.Weight
+	}
+	return total
+}
+
+// AssignParticipants gives the points of s to participants in x order,
+// Weight points each, and records the list on s. The weights must add up
+// to the number of points.
+func AssignParticipants(s *Shares, participants []Participant) error {
+	if err := checkParticipants(participants); err != nil {
+		return fmt.Errorf("invalid participants: %w", err)
+	}
+	if total := TotalWeight(participants); total != len(s.Points) {
+		return fmt.Errorf("participants hold weight %d in total, but there are %d shares", total, len(s.Points))
+	}
+	i := 0
+	for _, p := range participants {
+		for j := 0; j < p.Weight; j++ {
+			s.Points[i].Participant = p.Name
+			i++
+		}
+	}
+	s.Participants = participants
+	return nil
+}
+
+// WriteParticipant encodes the points of the participant name as a
+// document of its own, with the share of s.Points[i] in bases[i%len(bases)].
+// Its keys object names the participant and lists all of them, so that
+// reconstruction can say whose shares are missing.
+func WriteParticipant(w io.Writer, s *Shares, name string, bases []string) error {
+	var points []Point
+	var pointBases []string
+	for i, point := range s.Points {
+		if point.Participant == name {
+			points = append(points, point)
+			pointBases = append(pointBases, bases[i%len(bases)])
+		}
+	}
+	if len(points) == 0 {
+		return fmt.Errorf("participant %q holds no shares", name)
+	}
+	return writeDocument(w, s, points, pointBases, documentKeys{participant: name})
+}
+
+// weight returns the weight of the participant name, or 0 if it is not
+// listed.
+func weight(participants []Participant, name string) int {
+	for _, p := range participants {
+		if p.Name == name {
+			return p.Weight
+		}
+	}
+	return 0
+}
+
+// Coverage is how much of the threshold the participants whose shares
+// were gathered hold. Present lists them with the number of points each
+// brought, Missing the others with their weights.
+type Coverage struct {
+	Present, Missing []Participant
+	Weight, Needed   int
+}
+
+// Coverage returns the coverage of the threshold by the points of s, or
+// nil if its shares were not split among participants.
+func (s *Shares) Coverage() *Coverage {
+	if len(s.Participants) == 0 {
+		return nil
+	}
+	held := make(map[string]int)
+	for _, point := range s.Points {
+		held[point.Participant]++
+	}
+	return NewCoverage(s.Participants, held, s.K)
+}
+
+// NewCoverage returns the coverage of threshold k by the participants that
+// brought held[name] points.
+func NewCoverage(participants []Participant, held map[string]int, k int) *Coverage {
+	c := &Coverage{Needed: k}
+	for _, p := range participants {
+		if n := held[p.Name]; n > 0 {
+			c.Present = append(c.Present, Participant{Name: p.Name, Weight: n})
+			c.Weight += n
+		} else {
+			c.Missing = append(c.Missing, p)
+		}
+	}
+	return c
+}
+
+func (c *Coverage) String() string {
+	var b strings.Builder
+	if len(c.Present) == 0 {
+		b.WriteString("no participant holds any")
+	} else {
+		b.WriteString(listParticipants(c.Present))
+		if len(c.Present) == 1 {
+			b.WriteString(" holds")
+		} else {
+			b.WriteString(" hold")
+		}
+		fmt.Fprintf(&b, " weight %d", c.Weight)
+	}
+	fmt.Fprintf(&b, " of the %d needed", c.Needed)
+	if len(c.Missing) > 0 {
+		b.WriteString("; missing ")
+		b.WriteString(listParticipants(c.Missing))
+	}
+	return b.String()
+}
+
+func listParticipants(participants []Participant) string {
+	parts := make([]string, len(participants))
+	for i, p := range participants {
+		parts[i] = fmt.Sprintf("%s (weight %d)", p.Name, p.Weight)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// parseParticipantKeys reads the "participant", "weight" and
+// "participants" fields of a keys object.
+func parseParticipantKeys(fields map[string]json.RawMessage, keysData *tempKeys) error {
+	if value, ok := fields["participants"]; ok {
+		var list []Participant
+		if err := json.Unmarshal(value, &list); err != nil {
+			return fmt.Errorf(`invalid "participants" in "keys" object: must be an array of objects with a "name" and a "weight"`)
+		}
+		if err := checkParticipants(list); err != nil {
+			return fmt.Errorf(`invalid "participants" in "keys" object: %w`, err)
+		}
+		keysData.Participants = list
+	}
+	value, ok := fields["participant"]
+	if !ok {
+		return nil
+	}
+	if err := json.Unmarshal(value, &keysData.Participant); err != nil || keysData.Participant == "" {
+		return fmt.Errorf(`invalid "participant" in "keys" object: must be a non-empty string, got %s`, jsonTypeName(value))
+	}
+	keysData.Weight = weight(keysData.Participants, keysData.Participant)
+	if keysData.Weight == 0 {
+		return fmt.Errorf(`"keys" object names participant %q, who is not among its "participants"`, keysData.Participant)
+	}
+	if value, ok := fields["weight"]; ok {
+		var w int
+		if err := json.Unmarshal(value, &w); err != nil || w != keysData.Weight {
+			return fmt.Errorf(`invalid "weight" in "keys" object: must be the weight %d "participants" gives %q`, keysData.Weight, keysData.Participant)
+		}
+	}
+	return nil
+}
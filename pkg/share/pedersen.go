@@ -0,0 +1,79 @@
+package share
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// pedersenLabel seeds the derivation of the second generator H, so that
+// nobody, the dealer included, knows its discrete logarithm to base G.
+const pedersenLabel = "CATALOG-ASSIGNMENT pedersen h"
+
+// PedersenH derives the second Pedersen generator of the subgroup of order
+// q of the integers modulo p by hashing p into the subgroup. Verifiers
+// derive it again rather than trusting the H a file records.
+func PedersenH(p, q *big.Int) *big.Int {
+	r := new(big.Int).Sub(p, big.NewInt(1))
+	r.Quo(r, q)
+	prefix := append([]byte(pedersenLabel), p.Bytes()...)
+
+	h := new(big.Int)
+	var counter [8]byte
+	for attempt := uint64(0); ; attempt++ {
+		binary.BigEndian.PutUint64(counter[:], attempt)
+		// Hash 64 bits more than p has, so the reduction is close to
+		// uniform.
+		var stream []byte
+		for block := byte(0); len(stream)*8 < p.BitLen()+64; block++ {
+			digest := sha256.Sum256(append(append(append([]byte(nil), prefix...), counter[:]...), block))
+			stream = append(stream, digest[:]...)
+		}
+		h.SetBytes(stream)
+		h.Mod(h, p)
+		if h.Exp(h, r, p); h.Cmp(big.NewInt(1)) > 0 {
+			return h
+		}
+	}
+}
+
+// CommitPedersen returns the Pedersen commitments to coefficients, the
+// polynomial over the integers modulo the prime q, blinded by the
+// polynomial blinding of the same degree, both lowest degree first.
+func CommitPedersen(coefficients, blinding []*big.Int, q *big.Int) (*Commitments, error) {
+	if len(blinding) != len(coefficients) {
+		return nil, fmt.Errorf("blinding polynomial has %d coefficients, need %d", len(blinding), len(coefficients))
+	}
+	p, g, err := FeldmanGroup(q)
+	if err != nil {
+		return nil, err
+	}
+	h := PedersenH(p, q)
+	c := &Commitments{Scheme: SchemePedersen, P: p, G: g, Q: new(big.Int).Set(q), H: h, Values: make([]*big.Int, len(coefficients))}
+	term := new(big.Int)
+	for i, a := range coefficients {
+		v := new(big.Int).Exp(g, a, p)
+		v.Mul(v, term.Exp(h, blinding[i], p))
+		c.Values[i] = v.Mod(v, p)
+	}
+	return c, nil
+}
+
+// checkPedersenGroup checks that the group of Pedersen commitments c has a
+// subgroup of prime order Q generated by G, and that H is the generator
+// PedersenH derives for it.
+func checkPedersenGroup(c *Commitments) error {
+	if !c.Q.ProbablyPrime(20) {
+		return errors.New(`invalid "commitments" object: q is not prime`)
+	}
+	order := new(big.Int).Sub(c.P, big.NewInt(1))
+	if new(big.Int).Mod(order, c.Q).Sign() != 0 || new(big.Int).Exp(c.G, c.Q, c.P).Cmp(big.NewInt(1)) != 0 {
+		return errors.New(`invalid "commitments" object: g does not generate a subgroup of order q`)
+	}
+	if c.H.Cmp(PedersenH(c.P, c.Q)) != 0 {
+		return errors.New(`invalid "commitments" object: h is not the generator derived from p and q`)
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package share
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// Reshare returns a fresh set of n shares with threshold k of the secret of
+// old, without forming it: every one of the first old.K shares is itself
+// split by a random polynomial g_i of degree k-1 with g_i(0) = y_i, and the
+// new share at x is the sum of λ_i·g_i(x), λ_i being the Lagrange
+// coefficient at 0 of the old share. That sum is a random polynomial
+// through the old secret at 0, independent of the old one, so the old and
+// new shares do not combine. It works over the prime modulus old records,
+// or modulus when it records none.
+//
+// Only the Feldman commitments of WithCommitments are supported, computed
+// from those of every g_i; WithSeed is also honoured. The new shares keep
+// the field, expected value and secret hash of old.
+func Reshare(old *Shares, n, k int, modulus *big.Int, opts ...SplitOption) (*Shares, error) {
+	c := &splitConfig{scheme: SchemeFeldman, random: rand.Reader}
+	for _, opt := range opts {
+		opt(c)
+	}
+	switch {
+	case c.scheme == SchemePedersen:
+		return nil, errors.New("resharing with Pedersen commitments is not supported")
+	case c.scheme != "" && c.scheme != SchemeFeldman:
+		return nil, fmt.Errorf("unknown commitment scheme %q", c.scheme)
+	case old.Field == FieldGF2m:
+		return nil, errors.New("resharing shares over GF(2^m) is not supported")
+	case old.Modulus != nil && modulus != nil && old.Modulus.Cmp(modulus) != 0:
+		return nil, fmt.Errorf("modulus mismatch: %s records modulus %s, not %s", old.Source, old.Modulus, modulus)
+	case old.Modulus != nil:
+		modulus = old.Modulus
+	}
+	if modulus == nil {
+		return nil, errors.New("resharing without forming the secret needs a prime modulus")
+	}
+	if k < 1 {
+		return nil, fmt.Errorf("invalid k=%d: must be at least 1", k)
+	}
+	if n < k {
+		return nil, fmt.Errorf("invalid n=%d: must be at least k=%d", n, k)
+	}
+	if len(old.Points) < old.K {
+		return nil, &InsufficientSharesError{Found: len(old.Points), Needed: old.K}
+	}
+
+	used := old.Points[:old.K]
+	weights := make([]*big.Int, len(used))
+	for i, pi := range used {
+		numerator, denominator := big.NewInt(1), big.NewInt(1)
+		for m, pm := range used {
+			if m == i {
+				continue
+			}
+			numerator.Mul(numerator, pm.X)
+			numerator.Mod(numerator, modulus)
+			denominator.Mul(denominator, new(big.Int).Sub(pm.X, pi.X))
+			denominator.Mod(denominator, modulus)
+		}
+		inverse := new(big.Int).ModInverse(denominator, modulus)
+		if inverse == nil {
+			return nil, &DuplicateXError{X: pi.X}
+		}
+		weights[i] = numerator.Mul(numerator, inverse).Mod(numerator, modulus)
+	}
+
+	reshared := &Shares{
+		N:             n,
+		K:             k,
+		Modulus:       new(big.Int).Set(modulus),
+		Field:         old.Field,
+		Expected:      old.Expected,
+		SecretSHA256:  old.SecretSHA256,
+		Deterministic: c.deterministic,
+	}
+	for x := 1; x <= n; x++ {
+		reshared.Points = append(reshared.Points, Point{X: big.NewInt(int64(x)), Y: new(big.Int)})
+	}
+	var group *Commitments
+	if c.scheme == SchemeFeldman && modulus.ProbablyPrime(20) {
+		p, g, err := FeldmanGroup(modulus)
+		if err != nil {
+			return nil, err
+		}
+		group = &Commitments{Scheme: SchemeFeldman, P: p, G: g, Values: make([]*big.Int, k)}
+		for m := range group.Values {
+			group.Values[m] = big.NewInt(1)
+		}
+	}
+
+	for i, point := range used {
+		coefficients := make([]*big.Int, k)
+		coefficients[0] = point.Y
+		for m := 1; m < k; m++ {
+			coefficient, err := randomBelow(c.random, modulus)
+			if err != nil {
+				return nil, fmt.Errorf("failed to generate coefficient: %w", err)
+			}
+			coefficients[m] = coefficient
+		}
+		for _, target := range reshared.Points {
+			term := evaluatePolynomial(coefficients, target.X, modulus)
+			target.Y.Add(target.Y, term.Mul(term, weights[i]))
+			target.Y.Mod(target.Y, modulus)
+		}
+		if group != nil {
+			// (G^a)^λ for every coefficient a of g_i, multiplied together
+			// over i, commits to the sum without computing it.
+			for m, a := range coefficients {
+				term := new(big.Int).Exp(group.G, a, group.P)
+				term.Exp(term, weights[i], group.P)
+				group.Values[m].Mul(group.Values[m], term).Mod(group.Values[m], group.P)
+			}
+		}
+	}
+	reshared.Commitments = group
+	return reshared, nil
+}
@@ -0,0 +1,65 @@
+package share
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// Scale returns the shares of c times the secret of s: c·f is a polynomial
+// of the same degree through c times the values of f, so multiplying every
+// share's y by c gives shares of c·f(0) without reconstructing f(0). Over
+// the prime modulus s records, or modulus when it records none, the
+// products are reduced; over the integers c may be negative. Shares over
+// GF(2^m) are not supported.
+//
+// The result keeps the keys and metadata of s. Its Feldman or Pedersen
+// commitments are raised to the power c, and the Pedersen blinding values
+// scaled with the shares, so that it still verifies. The expected value and
+// secret hash of s do not describe the scaled secret and are dropped.
+func Scale(s *Shares, c, modulus *big.Int) (*Shares, error) {
+	switch {
+	case s.Field == FieldGF2m:
+		return nil, errors.New("scaling shares over GF(2^m) is not supported")
+	case s.Modulus != nil && modulus != nil && s.Modulus.Cmp(modulus) != 0:
+		return nil, fmt.Errorf("modulus mismatch: %s records modulus %s, not %s", s.Source, s.Modulus, modulus)
+	case s.Modulus != nil:
+		modulus = s.Modulus
+	}
+	if s.Commitments != nil && modulus == nil {
+		return nil, errors.New("shares with commitments must record their modulus to be scaled")
+	}
+	if modulus != nil {
+		c = new(big.Int).Mod(c, modulus)
+	}
+
+	scaled := *s
+	scaled.Modulus = modulus
+	scaled.Expected = ""
+	scaled.SecretSHA256 = ""
+	scaled.Points = make([]Point, len(s.Points))
+	for i, point := range s.Points {
+		point.Y = scaleValue(point.Y, c, modulus)
+		if point.Blinding != nil {
+			point.Blinding = scaleValue(point.Blinding, c, modulus)
+		}
+		scaled.Points[i] = point
+	}
+	if s.Commitments != nil {
+		commitments := *s.Commitments
+		commitments.Values = make([]*big.Int, len(s.Commitments.Values))
+		for i, value := range s.Commitments.Values {
+			commitments.Values[i] = new(big.Int).Exp(value, c, commitments.P)
+		}
+		scaled.Commitments = &commitments
+	}
+	return &scaled, nil
+}
+
+func scaleValue(v, c, modulus *big.Int) *big.Int {
+	product := new(big.Int).Mul(v, c)
+	if modulus != nil {
+		product.Mod(product, modulus)
+	}
+	return product
+}
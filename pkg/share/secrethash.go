@@ -0,0 +1,50 @@
+package share
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// ErrSecretHashMismatch is matched by the error VerifySecret returns for a
+// secret whose hash differs from the one recorded at split time.
+var ErrSecretHashMismatch = errors.New("secret does not match the SHA-256 recorded at split time")
+
+// SecretBytes returns the canonical bytes of secret that SecretSHA256
+// hashes: the big-endian bytes of its absolute value without leading
+// zeros, so that zero has none, preceded by a single zero byte if secret
+// is negative. No two secrets share an encoding, since that of a
+// non-negative secret never starts with a zero byte.
+func SecretBytes(secret *big.Int) []byte {
+	magnitude := new(big.Int).Abs(secret).Bytes()
+	if secret.Sign() < 0 {
+		return append([]byte{0}, magnitude...)
+	}
+	return magnitude
+}
+
+// SecretSHA256 returns the hex SHA-256 of SecretBytes(secret), which Split
+// records in the keys object as "secret_sha256".
+func SecretSHA256(secret *big.Int) string {
+	sum := sha256.Sum256(SecretBytes(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// VerifySecret checks a reconstructed secret against the SHA-256 the
+// document recorded at split time. It does nothing if none was recorded.
+func (s *Shares) VerifySecret(secret *big.Int) error {
+	if s.SecretSHA256 == "" || SecretSHA256(secret) == s.SecretSHA256 {
+		return nil
+	}
+	return fmt.Errorf("%w: the shares or the reconstruction are wrong", ErrSecretHashMismatch)
+}
+
+// parseSecretHash checks the "secret_sha256" field of a keys object.
+func parseSecretHash(text string) error {
+	if sum, err := hex.DecodeString(text); err != nil || len(sum) != sha256.Size || hex.EncodeToString(sum) != text {
+		return errors.New(`invalid "secret_sha256" in "keys" object: must be 64 lowercase hex digits`)
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package share
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"io"
+)
+
+// seedLabel keeps the stream of a seed apart from any other use of it.
+const seedLabel = "CATALOG-ASSIGNMENT deterministic split"
+
+// SeededReader returns an endless stream of bytes determined by seed: the
+// SHA-256 of a label, the seed and a 64-bit block counter, block after
+// block. It is the same on every platform, and anyone who knows the seed
+// can recompute it, so it is for reproducible fixtures only.
+func SeededReader(seed []byte) io.Reader {
+	return &seededReader{seed: append([]byte(nil), seed...)}
+}
+
+type seededReader struct {
+	seed    []byte
+	counter uint64
+	block   []byte
+}
+
+func (r *seededReader) Read(p []byte) (int, error) {
+	n := 0
+	for n < len(p) {
+		if len(r.block) == 0 {
+			var counter [8]byte
+			binary.BigEndian.PutUint64(counter[:], r.counter)
+			r.counter++
+			h := sha256.New()
+			h.Write([]byte(seedLabel))
+			h.Write(r.seed)
+			h.Write(counter[:])
+			r.block = h.Sum(nil)
+		}
+		copied := copy(p[n:], r.block)
+		r.block = r.block[copied:]
+		n += copied
+	}
+	return n, nil
+}
@@ -0,0 +1,1323 @@
+// Package share reads, writes and generates Shamir share files.
+package share
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+type Point struct {
+	X *big.Int
+	Y *big.Int
+
+	// Blinding is the share's value of the blinding polynomial of
+	// Pedersen commitments, or nil.
+	Blinding *big.Int
+
+	// Base is the base the value was written in, if the document records
+	// one.
+	Base string
+
+	// Source names the input the share was read from, if known.
+	Source string
+
+	// Label is the name the document gives the share when its x is
+	// stored inside it rather than as its key, such as "alice".
+	Label string
+
+	// Participant is the holder of the share if the split gave some
+	// holders several shares.
+	Participant string
+}
+
+// Name returns "x=<x>", followed by the label of the share if it has one,
+// for messages about the share.
+func (p Point) Name() string {
+	if p.Label == "" {
+		return "x=" + p.X.String()
+	}
+	return fmt.Sprintf("x=%s (%s)", p.X, p.Label)
+}
+
+// Shares is the decoded content of a share file. Points holds every share
+// in the file sorted by x; the first K of them are the ones used for
+// reconstruction.
+type Shares struct {
+	N      int
+	K      int
+	Points []Point
+
+	// Source names the input the shares were read from, if known.
+	Source string
+
+	// Expected is the secret the document claims to reconstruct to, as
+	// written in its optional "expected" entry.
+	Expected string
+
+	// SecretSHA256 is the hex SHA-256 of the secret, see SecretSHA256,
+	// that Split recorded in the keys object, if any.
+	SecretSHA256 string
+
+	// Modulus is the prime a modular split recorded in the keys object,
+	// nil for shares over the integers or that did not record it.
+	Modulus *big.Int
+
+	// Field is the name of the FieldPreset the split was made over, if
+	// any, whose modulus is then Modulus, or FieldGF2m for shares over the
+	// binary field reduced by Poly.
+	Field string
+	Poly  *big.Int
+
+	// Commitments, if the keys object has them, are the commitments
+	// every share can be checked against.
+	Commitments *Commitments
+
+	// Checksummed reports that every share carried a checksum, all of
+	// which matched.
+	Checksummed bool
+
+	// Version is the format version of a JSON share document.
+	Version int
+
+	// Deterministic reports shares split from a fixed seed, which the
+	// keys object marks "insecure_deterministic". They are test fixtures,
+	// not protection for a secret.
+	Deterministic bool
+
+	// Participants lists the holders of the shares and their weights if
+	// the split gave some of them several shares, and Participant names
+	// the one whose shares a document written by WriteParticipant holds.
+	Participants []Participant
+	Participant  string
+
+	// Single reports a document holding just one of the n shares, written
+	// by WriteShare, whose keys object names its x.
+	Single bool
+}
+
+type tempRoot struct {
+	Base     string
+	Value    string
+	Mnemonic string
+	Blinding string
+	Checksum string
+}
+
+type tempKeys struct {
+	N int `json:"n"`
+	K int `json:"k"`
+
+	// Field names the field the shares were made over, a FieldPreset or
+	// FieldGF256, empty for the integers or a prime field given by its
+	// modulus.
+	Field string `json:"field"`
+
+	Commitments *Commitments `json:"-"`
+	Checksum    string       `json:"-"`
+	Version     int          `json:"-"`
+
+	Deterministic bool     `json:"-"`
+	SecretSHA256  string   `json:"-"`
+	Modulus       *big.Int `json:"-"`
+	Poly          *big.Int `json:"-"`
+
+	// X is the x of the only share of a document written by WriteShare.
+	X *big.Int `json:"-"`
+
+	// Participant and Weight name the holder of the shares of a document
+	// written by WriteParticipant and how many it holds; Participants
+	// lists every holder.
+	Participant  string        `json:"-"`
+	Weight       int           `json:"-"`
+	Participants []Participant `json:"-"`
+}
+
+// rawEntry is one share entry of a JSON share document before decoding.
+// xText is the entry's key or, when hasX is set, its "x" field, which
+// elements of a "shares" array must have and other entries may. label is
+// the key of an entry whose x is not its key, such as "alice".
+type rawEntry struct {
+	key   string
+	xText string
+	label string
+	raw   json.RawMessage
+	hasX  bool
+}
+
+// streamDocument reads a JSON share document from r one top-level entry at
+// a time, passing each share entry to fn in file order as soon as it has
+// been read, so that only one raw entry is held in memory at a time. The
+// keys object is validated when it is reached; found reports whether the
+// document had one.
+func (c *parseConfig) streamDocument(r io.Reader, fn func(rawEntry) error) (keysData tempKeys, found bool, expected string, err error) {
+	r, bom, err := skipBOM(r)
+	if err != nil {
+		return keysData, false, "", err
+	}
+	if c.lenient {
+		var locate func(error) error
+		if r, locate, err = lenientReader(r); err != nil {
+			return keysData, false, "", err
+		}
+		defer func() {
+			if err != nil {
+				err = locate(err)
+			}
+		}()
+	}
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	syntaxErr := func(err error) error {
+		return fmt.Errorf("failed to unmarshal raw json%s: %w", bomNote(bom), err)
+	}
+
+	tok, err := dec.Token()
+	if err != nil {
+		return keysData, false, "", syntaxErr(err)
+	}
+	if tok != json.Delim('{') {
+		return keysData, false, "", syntaxErr(errors.New("input must be a JSON object"))
+	}
+
+	// flat holds the fields of a document that is itself a single share,
+	// {"keys": ..., "x": ..., "base": ..., "value": ...}.
+	flat := make(map[string]json.RawMessage)
+	entries := 0
+	seen := make(map[string]bool)
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return keysData, found, "", syntaxErr(err)
+		}
+		key := tok.(string)
+		if seen[key] && !c.allowDuplicateKeys {
+			return keysData, found, "", fmt.Errorf("duplicate key %q in the top-level object", key)
+		}
+		seen[key] = true
+
+		if key == "shares" {
+			if err := c.streamShares(dec, fn); err != nil {
+				return keysData, found, "", err
+			}
+			continue
+		}
+
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return keysData, found, "", syntaxErr(err)
+		}
+		if !c.allowDuplicateKeys {
+			if err := walkDuplicateKeys(json.NewDecoder(bytes.NewReader(raw)), fmt.Sprintf("entry %q", key)); err != nil {
+				return keysData, found, "", err
+			}
+		}
+
+		switch key {
+		case "keys":
+			if keysData, err = c.parseKeys(raw); err != nil {
+				return keysData, true, "", err
+			}
+			if err := checkKeys(keysData.N, keysData.K); err != nil {
+				return keysData, true, "", err
+			}
+			found = true
+			c.declared = keysData.N
+
+		case "expected":
+			if expected, err = jsonScalar(raw); err != nil {
+				return keysData, found, "", fmt.Errorf("invalid 'expected' entry: must be a string or number")
+			}
+
+		case "x", "base", "value", "blinding", "checksum":
+			flat[key] = raw
+
+		default:
+			entries++
+			entry, err := keyedEntry(key, raw)
+			if err != nil {
+				return keysData, found, "", err
+			}
+			if err := fn(entry); err != nil {
+				return keysData, found, "", err
+			}
+		}
+	}
+
+	if len(flat) > 0 {
+		if entries > 0 || seen["shares"] {
+			return keysData, found, "", errors.New(`document mixes top-level "x", "base" or "value" fields with share entries`)
+		}
+		element, _ := json.Marshal(flat)
+		xText, err := arrayXText("the document", element)
+		if err != nil {
+			return keysData, found, "", err
+		}
+		if err := fn(rawEntry{key: xText, xText: xText, raw: element, hasX: true}); err != nil {
+			return keysData, found, "", err
+		}
+		if keysData.X == nil {
+			keysData.X, _ = parseX(xText)
+		}
+	}
+
+	if _, err := dec.Token(); err != nil {
+		return keysData, found, "", syntaxErr(err)
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return keysData, found, "", syntaxErr(errors.New("unexpected data after the top-level object"))
+	}
+	return keysData, found, expected, nil
+}
+
+// streamShares passes each share of a "shares" entry to fn as it is read.
+// The entry is an array of elements with their own "x", or an object of
+// shares keyed by label or by x.
+func (c *parseConfig) streamShares(dec *json.Decoder, fn func(rawEntry) error) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return fmt.Errorf("failed to parse 'shares' entry: %w", err)
+	}
+	object := tok == json.Delim('{')
+	if !object && tok != json.Delim('[') {
+		return errors.New("failed to parse 'shares' entry: must be an array or an object")
+	}
+
+	for i := 0; dec.More(); i++ {
+		label := fmt.Sprintf("shares[%d]", i)
+		where := fmt.Sprintf(`element %d of entry "shares"`, i)
+		if object {
+			tok, err := dec.Token()
+			if err != nil {
+				return fmt.Errorf("failed to parse 'shares' entry: %w", err)
+			}
+			label = tok.(string)
+			where = fmt.Sprintf(`entry %q of "shares"`, label)
+		}
+		var element json.RawMessage
+		if err := dec.Decode(&element); err != nil {
+			return fmt.Errorf("failed to parse 'shares' entry: %w", err)
+		}
+		if !c.allowDuplicateKeys {
+			if err := walkDuplicateKeys(json.NewDecoder(bytes.NewReader(element)), where); err != nil {
+				return err
+			}
+		}
+		var entry rawEntry
+		if object {
+			entry, err = keyedEntry(label, element)
+		} else {
+			var xText string
+			xText, err = arrayXText(label, element)
+			entry = rawEntry{key: label, xText: xText, raw: element, hasX: true}
+		}
+		if err != nil {
+			return err
+		}
+		if err := fn(entry); err != nil {
+			return err
+		}
+	}
+
+	if _, err := dec.Token(); err != nil {
+		return fmt.Errorf("failed to parse 'shares' entry: %w", err)
+	}
+	return nil
+}
+
+// keyedEntry returns the share entry stored under key. Its x is its "x"
+// field if it has one, which must agree with a key that is itself an x,
+// and the key otherwise. A nested test case document is left alone.
+func keyedEntry(key string, raw json.RawMessage) (rawEntry, error) {
+	entry := rawEntry{key: key, xText: key, raw: raw}
+	var fields struct {
+		Keys json.RawMessage `json:"keys"`
+		X    json.RawMessage `json:"x"`
+	}
+	if json.Unmarshal(raw, &fields) != nil || fields.Keys != nil || fields.X == nil {
+		return entry, nil
+	}
+	xText, err := arrayXText(key, raw)
+	if err != nil {
+		return entry, err
+	}
+	entry.xText, entry.hasX = xText, true
+	keyX, err := parseX(key)
+	if err != nil {
+		entry.label = key
+		return entry, nil
+	}
+	if x, err := parseX(xText); err == nil && x.Cmp(keyX) != 0 {
+		return entry, fmt.Errorf("share %q has x=%s, which disagrees with its key", key, x)
+	}
+	return entry, nil
+}
+
+// readDocument reads a whole JSON share document, returning its keys
+// object, its optional expected value and its share entries in file order.
+func (c *parseConfig) readDocument(r io.Reader) (tempKeys, string, []rawEntry, error) {
+	var entries []rawEntry
+	index := make(map[string]int)
+	keysData, found, expected, err := c.streamDocument(r, func(entry rawEntry) error {
+		if i, ok := index[entry.key]; ok {
+			entries[i] = entry
+			return nil
+		}
+		index[entry.key] = len(entries)
+		entries = append(entries, entry)
+		return nil
+	})
+	if err != nil {
+		return keysData, "", nil, err
+	}
+	if !found {
+		return keysData, "", nil, errMissingKeys
+	}
+	return keysData, expected, entries, nil
+}
+
+var errMissingKeys = errors.New(`input is missing the required "keys" object`)
+
+// ParseOption configures ParseShares.
+type ParseOption func(*parseConfig)
+
+type parseConfig struct {
+	// field is the field the caller expects the shares to be over.
+	field string
+
+	requireThreshold   bool
+	strictValues       bool
+	allowDuplicateKeys bool
+	strictFields       bool
+	lenient            bool
+	ignoreChecksums    bool
+
+	progress func(parsed, total int)
+	ctx      context.Context
+	// declared is the n of the keys object read so far, for progress.
+	declared int
+}
+
+func newParseConfig(opts []ParseOption) *parseConfig {
+	c := &parseConfig{}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// RequireThreshold makes ParseShares fail when the document holds fewer
+// than k entries. Leave it off for documents that
+// hold only part of the shares, to be merged with others.
+func RequireThreshold() ParseOption {
+	return func(c *parseConfig) {
+		c.requireThreshold = true
+	}
+}
+
+// StrictValues makes the parsers reject values in the numeric bases that
+// contain whitespace or "_" digit separators instead of removing them.
+func StrictValues() ParseOption {
+	return func(c *parseConfig) {
+		c.strictValues = true
+	}
+}
+
+// AllowDuplicateKeys makes the JSON parsers accept objects that repeat a
+// key, keeping the last occurrence as encoding/json does. By default a
+// repeated key is an error, since it silently drops a share.
+func AllowDuplicateKeys() ParseOption {
+	return func(c *parseConfig) {
+		c.allowDuplicateKeys = true
+	}
+}
+
+// StrictFields makes the JSON parsers reject fields other than "version",
+// "n", "k", "x", "field", "commitments", "checksum",
+// "insecure_deterministic", "secret_sha256", "modulus", "poly" and the
+// participant fields in the keys object and "base", "value",
+// "blinding" and "checksum" (and "x" in a "shares" array) in share entries,
+// which are otherwise ignored.
+func StrictFields() ParseOption {
+	return func(c *parseConfig) {
+		c.strictFields = true
+	}
+}
+
+// IgnoreChecksums makes the JSON parsers accept shares that fail their
+// checksums, for comparing a copy that may have been altered with another.
+// The shares still report being Checksummed if they carry checksums.
+func IgnoreChecksums() ParseOption {
+	return func(c *parseConfig) {
+		c.ignoreChecksums = true
+	}
+}
+
+// WithProgress makes the JSON parsers call fn after decoding each share,
+// with the number decoded so far and the n declared by the keys object, or
+// 0 if it has not been read yet.
+func WithProgress(fn func(parsed, total int)) ParseOption {
+	return func(c *parseConfig) {
+		c.progress = fn
+	}
+}
+
+// WithContext makes the JSON parsers check ctx before decoding each share
+// and, once ctx is done, return its error wrapped with how many shares
+// were decoded.
+func WithContext(ctx context.Context) ParseOption {
+	return func(c *parseConfig) {
+		c.ctx = ctx
+	}
+}
+
+// ParseShares decodes a share document in the JSON layout with a "keys"
+// object and either one numbered entry per share, a "shares" array whose
+// elements carry their own "x", a "shares" object of entries keyed by x or
+// by a label with the x inside, or the "x", "base" and "value" of a single
+// share at the top level. A numbered entry may also hold its x, which must
+// then agree with its number. The document is streamed: each value is
+// decoded as soon as its entry has been read, so memory use grows with the
+// largest single value rather than with the size of the input.
+func ParseShares(r io.Reader, opts ...ParseOption) (*Shares, error) {
+	c := newParseConfig(opts)
+
+	set := newPointSet(c)
+	keysData, found, expected, err := c.streamDocument(r, set.add)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, errMissingKeys
+	}
+	return set.shares(keysData, expected)
+}
+
+// pointSet collects the decoded shares of a streamed JSON document.
+type pointSet struct {
+	c      *parseConfig
+	points []Point
+	keys   []string
+	index  map[string]int
+
+	// sums holds the checksum of every share that has one, by x.
+	sums map[string]string
+}
+
+func newPointSet(c *parseConfig) *pointSet {
+	return &pointSet{c: c, index: make(map[string]int), sums: make(map[string]string)}
+}
+
+// add decodes one share entry, keeping only its point.
+func (p *pointSet) add(entry rawEntry) error {
+	if p.c.ctx != nil {
+		if err := p.c.ctx.Err(); err != nil {
+			return fmt.Errorf("parsing stopped after %d shares: %w", len(p.points), err)
+		}
+	}
+	x, err := parseX(entry.xText)
+	if err != nil {
+		return err
+	}
+	root, err := p.c.readRoot(entry)
+	if err != nil {
+		return err
+	}
+	y, err := p.c.decodeY(entry.key, x, root.Base, root.Value)
+	if err != nil {
+		// A share that no longer decodes but has a checksum was altered.
+		if root.Checksum != "" && !p.c.ignoreChecksums {
+			return &ChecksumError{Key: entry.key}
+		}
+		return err
+	}
+	point := Point{X: x, Y: y, Base: strings.TrimSpace(root.Base), Label: entry.label}
+	if root.Blinding != "" {
+		blinding, ok := new(big.Int).SetString(strings.TrimSpace(root.Blinding), 10)
+		if !ok {
+			return newParseError(entry.key, "blinding", root.Blinding, errors.New("blinding must be a decimal integer"))
+		}
+		point.Blinding = blinding
+	}
+	if root.Checksum != "" && !p.c.ignoreChecksums {
+		sum, err := shareChecksum(x, y, root.Base)
+		if err != nil || !strings.EqualFold(strings.TrimSpace(root.Checksum), sum) {
+			return &ChecksumError{Key: entry.key}
+		}
+	}
+
+	// A repeated key, allowed by AllowDuplicateKeys, replaces the earlier
+	// entry as it would in encoding/json.
+	if i, ok := p.index[entry.key]; ok {
+		delete(p.sums, p.points[i].X.String())
+		p.points[i] = point
+		if root.Checksum != "" {
+			p.sums[x.String()] = strings.ToLower(strings.TrimSpace(root.Checksum))
+		}
+		return nil
+	}
+	if root.Checksum != "" {
+		p.sums[x.String()] = strings.ToLower(strings.TrimSpace(root.Checksum))
+	}
+	p.index[entry.key] = len(p.points)
+	p.points = append(p.points, point)
+	p.keys = append(p.keys, entry.key)
+	if p.c.progress != nil {
+		p.c.progress(len(p.points), p.c.declared)
+	}
+	return nil
+}
+
+func (p *pointSet) shares(keysData tempKeys, expected string) (*Shares, error) {
+	if p.c.requireThreshold && len(p.points) < keysData.K {
+		err := &InsufficientSharesError{Found: len(p.points), Needed: keysData.K, Context: "in file"}
+		if keysData.Participant != "" {
+			err.Coverage = NewCoverage(keysData.Participants, map[string]int{keysData.Participant: len(p.points)}, keysData.K)
+		}
+		return nil, err
+	}
+	if err := sortPoints(p.points, p.keys); err != nil {
+		return nil, err
+	}
+	if keysData.X != nil && (len(p.points) != 1 || p.points[0].X.Cmp(keysData.X) != 0) {
+		return nil, fmt.Errorf(`"keys" object names the single share x=%s, but the document does not hold exactly that share`, keysData.X)
+	}
+	for i := range p.points {
+		p.points[i].Participant = keysData.Participant
+	}
+	version := formatVersions[keysData.Version]
+	if version.checksums && keysData.Checksum == "" {
+		return nil, fmt.Errorf(`"keys" object has no checksum, which share format version %d requires`, keysData.Version)
+	}
+	if len(p.sums) > 0 || version.checksums {
+		for i, point := range p.points {
+			if _, ok := p.sums[point.X.String()]; ok {
+				continue
+			}
+			if version.checksums {
+				return nil, fmt.Errorf("share %q has no checksum, which share format version %d requires", p.keys[i], keysData.Version)
+			}
+			return nil, fmt.Errorf("share %q has no checksum, but other shares do", p.keys[i])
+		}
+		if err := checkSetChecksum(keysData, p.points, p.sums); err != nil && !p.c.ignoreChecksums {
+			return nil, err
+		}
+	}
+	return &Shares{
+		N:           keysData.N,
+		K:           keysData.K,
+		Points:      p.points,
+		Expected:    expected,
+		Commitments: keysData.Commitments,
+		Checksummed: len(p.sums) > 0,
+		Version:     keysData.Version,
+
+		Deterministic: keysData.Deterministic,
+		SecretSHA256:  keysData.SecretSHA256,
+		Modulus:       keysData.Modulus,
+		Field:         keysData.Field,
+		Poly:          keysData.Poly,
+		Participants:  keysData.Participants,
+		Participant:   keysData.Participant,
+		Single:        keysData.X != nil,
+	}, nil
+}
+
+// checkDuplicateKeys reports the first object in the JSON document data
+// that repeats a key. data must already be known to be valid JSON.
+func checkDuplicateKeys(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	return walkDuplicateKeys(dec, "the top-level object")
+}
+
+func walkDuplicateKeys(dec *json.Decoder, where string) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	switch tok {
+	case json.Delim('{'):
+		seen := make(map[string]bool)
+		for dec.More() {
+			keyTok, err := dec.Token()
+			if err != nil {
+				return err
+			}
+			key := keyTok.(string)
+			if seen[key] {
+				return fmt.Errorf("duplicate key %q in %s", key, where)
+			}
+			seen[key] = true
+			if err := walkDuplicateKeys(dec, fmt.Sprintf("entry %q", key)); err != nil {
+				return err
+			}
+		}
+		_, err = dec.Token()
+		return err
+	case json.Delim('['):
+		for i := 0; dec.More(); i++ {
+			if err := walkDuplicateKeys(dec, fmt.Sprintf("element %d of %s", i, where)); err != nil {
+				return err
+			}
+		}
+		_, err = dec.Token()
+		return err
+	}
+	return nil
+}
+
+// parseKeys reads the "keys" object of a JSON share document, naming the
+// missing or mistyped field when it is malformed.
+func (c *parseConfig) parseKeys(raw json.RawMessage) (tempKeys, error) {
+	var keysData tempKeys
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil || fields == nil {
+		return keysData, fmt.Errorf(`"keys" must be an object, got %s`, jsonTypeName(raw))
+	}
+	if c.strictFields {
+		if err := checkFields(fields, []string{"version", "n", "k", "x", "field", "commitments", "checksum", "insecure_deterministic", "secret_sha256", "modulus", "poly", "participant", "weight", "participants"}); err != nil {
+			return keysData, fmt.Errorf(`%w in "keys" object`, err)
+		}
+	}
+
+	keysData.Version = 1
+	if value, ok := fields["version"]; ok {
+		text, err := jsonScalar(value)
+		if err == nil {
+			keysData.Version, err = strconv.Atoi(strings.TrimSpace(text))
+		}
+		if err != nil {
+			return keysData, fmt.Errorf(`invalid "version" in "keys" object: must be an integer, got %s`, jsonTypeName(value))
+		}
+	}
+	if _, ok := formatVersions[keysData.Version]; !ok {
+		return keysData, &UnsupportedVersionError{Version: keysData.Version}
+	}
+
+	for _, field := range []struct {
+		name string
+		dst  *int
+	}{{"n", &keysData.N}, {"k", &keysData.K}} {
+		value, ok := fields[field.name]
+		if !ok {
+			return keysData, fmt.Errorf(`"keys" object is missing %q`, field.name)
+		}
+		text, err := jsonScalar(value)
+		if err == nil {
+			*field.dst, err = strconv.Atoi(strings.TrimSpace(text))
+		}
+		if err != nil {
+			return keysData, fmt.Errorf(`invalid %q in "keys" object: must be an integer, got %s`, field.name, jsonTypeName(value))
+		}
+	}
+
+	if value, ok := fields["x"]; ok {
+		text, err := jsonScalar(value)
+		x, valid := new(big.Int).SetString(strings.TrimSpace(text), 10)
+		if err != nil || !valid {
+			return keysData, fmt.Errorf(`invalid "x" in "keys" object: must be an integer, got %s`, jsonTypeName(value))
+		}
+		keysData.X = x
+	}
+
+	if value, ok := fields["field"]; ok {
+		if err := json.Unmarshal(value, &keysData.Field); err != nil {
+			return keysData, fmt.Errorf(`invalid "field" in "keys" object: must be a string, got %s`, jsonTypeName(value))
+		}
+	}
+	if _, ok := fields["chunk_size"]; ok {
+		return keysData, errFileShares
+	}
+	switch {
+	case keysData.Field == c.field:
+	case keysData.Field == FieldGF256:
+		return keysData, errors.New(`shares are over GF(256); reconstruct them with --field gf256`)
+	case keysData.Field == FieldGF2m:
+		return keysData, errors.New(`shares are over GF(2^m); reconstruct them with --field gf2m`)
+	case keysData.Field != "" && LookupField(keysData.Field) == nil:
+		return keysData, fmt.Errorf(`unknown "field" %q in "keys" object`, keysData.Field)
+	}
+
+	if err := parseParticipantKeys(fields, &keysData); err != nil {
+		return keysData, err
+	}
+
+	if value, ok := fields["insecure_deterministic"]; ok {
+		if err := json.Unmarshal(value, &keysData.Deterministic); err != nil {
+			return keysData, fmt.Errorf(`invalid "insecure_deterministic" in "keys" object: must be a boolean, got %s`, jsonTypeName(value))
+		}
+	}
+	if value, ok := fields["modulus"]; ok {
+		text, err := jsonScalar(value)
+		if err != nil {
+			return keysData, fmt.Errorf(`invalid "modulus" in "keys" object: must be an integer, got %s`, jsonTypeName(value))
+		}
+		if keysData.Modulus, err = parseModulusKey(text); err != nil {
+			return keysData, err
+		}
+	}
+	if err := resolveFieldKey(&keysData); err != nil {
+		return keysData, err
+	}
+	if value, ok := fields["poly"]; ok {
+		var text string
+		if err := json.Unmarshal(value, &text); err != nil {
+			return keysData, fmt.Errorf(`invalid "poly" in "keys" object: must be a string, got %s`, jsonTypeName(value))
+		}
+		if keysData.Field != FieldGF2m {
+			return keysData, errors.New(`"keys" object records a "poly" but its "field" is not gf2m`)
+		}
+		poly, err := ParsePoly(text)
+		if err != nil {
+			return keysData, fmt.Errorf(`invalid "poly" in "keys" object: %w`, err)
+		}
+		keysData.Poly = poly
+	}
+	if value, ok := fields["secret_sha256"]; ok {
+		if err := json.Unmarshal(value, &keysData.SecretSHA256); err != nil {
+			return keysData, fmt.Errorf(`invalid "secret_sha256" in "keys" object: must be a string, got %s`, jsonTypeName(value))
+		}
+		if err := parseSecretHash(keysData.SecretSHA256); err != nil {
+			return keysData, err
+		}
+	}
+	if value, ok := fields["checksum"]; ok {
+		if err := json.Unmarshal(value, &keysData.Checksum); err != nil {
+			return keysData, fmt.Errorf(`invalid "checksum" in "keys" object: must be a string, got %s`, jsonTypeName(value))
+		}
+	}
+	if value, ok := fields["commitments"]; ok {
+		commitments, err := parseCommitments(value, keysData.K, keysData.Modulus, c.strictFields)
+		if err != nil {
+			return keysData, err
+		}
+		keysData.Commitments = commitments
+	}
+	return keysData, nil
+}
+
+// readRoot reads the base and value of the share entry stored under key.
+// The base may be written as a JSON string or number, and so may the value
+// when the base is 10; a numeric value with no base is read in base 10.
+// Failures are returned as a *ParseError.
+func (c *parseConfig) readRoot(entry rawEntry) (tempRoot, error) {
+	var root tempRoot
+	key, raw := entry.key, entry.raw
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil || fields == nil {
+		return root, newParseError(key, "", string(raw), fmt.Errorf("share entry must be an object, got %s", jsonTypeName(raw)))
+	}
+	if c.strictFields {
+		known := []string{"base", "value", "checksum"}
+		if entry.hasX {
+			known = append(known, "x")
+		}
+		if c.field == FieldGF256 {
+			known = append(known, "mnemonic")
+		} else {
+			known = append(known, "blinding")
+		}
+		if err := checkFields(fields, known); err != nil {
+			return root, newParseError(key, "", string(raw), err)
+		}
+	}
+
+	if base, ok := fields["base"]; ok {
+		text, err := jsonScalar(base)
+		if err != nil {
+			return root, newParseError(key, "base", string(base), fmt.Errorf("base must be a string or number, got %s", jsonTypeName(base)))
+		}
+		root.Base = text
+	}
+	if checksum, ok := fields["checksum"]; ok {
+		if err := json.Unmarshal(checksum, &root.Checksum); err != nil {
+			return root, newParseError(key, "checksum", string(checksum), fmt.Errorf("checksum must be a string, got %s", jsonTypeName(checksum)))
+		}
+	}
+	if blinding, ok := fields["blinding"]; ok && c.field != FieldGF256 {
+		text, err := jsonScalar(blinding)
+		if err != nil {
+			return root, newParseError(key, "blinding", string(blinding), fmt.Errorf("blinding must be a string or number, got %s", jsonTypeName(blinding)))
+		}
+		root.Blinding = text
+	}
+	if mnemonic, ok := fields["mnemonic"]; ok && c.field == FieldGF256 {
+		if err := json.Unmarshal(mnemonic, &root.Mnemonic); err != nil {
+			return root, newParseError(key, "mnemonic", string(mnemonic), fmt.Errorf("mnemonic must be a string, got %s", jsonTypeName(mnemonic)))
+		}
+	}
+	value, ok := fields["value"]
+	if !ok {
+		return root, nil
+	}
+	if err := json.Unmarshal(value, &root.Value); err == nil {
+		return root, nil
+	}
+
+	text, err := jsonScalar(value)
+	if err != nil {
+		return root, newParseError(key, "value", string(value), fmt.Errorf("value must be a string or number, got %s", jsonTypeName(value)))
+	}
+	if strings.ContainsAny(text, ".eE") {
+		return root, newParseError(key, "value", text, errors.New("value must be an integer"))
+	}
+	switch strings.TrimSpace(root.Base) {
+	case "":
+		root.Base = "10"
+	case "10":
+	default:
+		return root, newParseError(key, "value", text, fmt.Errorf("numeric value needs base 10, got base %s", root.Base))
+	}
+	root.Value = text
+	return root, nil
+}
+
+// checkFields reports the first field of an object, in sorted order, that
+// is not one of known, suggesting a known name that is a likely typo.
+func checkFields(fields map[string]json.RawMessage, known []string) error {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		best, bestDistance := "", 3
+		for _, k := range known {
+			if name == k {
+				best, bestDistance = "", -1
+				break
+			}
+			if d := editDistance(name, k); d < bestDistance && d < len(name) {
+				best, bestDistance = k, d
+			}
+		}
+		switch {
+		case bestDistance < 0:
+		case best != "":
+			return fmt.Errorf("unknown field %q (did you mean %q?)", name, best)
+		default:
+			return fmt.Errorf("unknown field %q", name)
+		}
+	}
+	return nil
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+// jsonScalar returns the text of a JSON string, or the literal digits of a
+// JSON number.
+func jsonScalar(raw json.RawMessage) (string, error) {
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		return text, nil
+	}
+	var number json.Number
+	if err := json.Unmarshal(raw, &number); err != nil {
+		return "", err
+	}
+	return number.String(), nil
+}
+
+// jsonTypeName describes the kind of JSON value raw holds.
+func jsonTypeName(raw json.RawMessage) string {
+	trimmed := bytes.TrimSpace(raw)
+	if len(trimmed) == 0 {
+		return "nothing"
+	}
+	switch trimmed[0] {
+	case '{':
+		return "object"
+	case '[':
+		return "array"
+	case '"':
+		return "string " + string(trimmed)
+	case 't', 'f':
+		return "boolean"
+	case 'n':
+		return "null"
+	}
+	return "number " + string(trimmed)
+}
+
+func checkKeys(n, k int) error {
+	if k < 1 {
+		return fmt.Errorf("invalid k=%d in 'keys' object: must be at least 1", k)
+	}
+	if n < 1 {
+		return fmt.Errorf("invalid n=%d in 'keys' object: must be at least 1", n)
+	}
+	if k > n {
+		return fmt.Errorf("invalid k=%d in 'keys' object: larger than n=%d", k, n)
+	}
+	return nil
+}
+
+// CheckCount reports an error if the number of shares differs from the n
+// the document declares, which usually means it was truncated or
+// mis-assembled. An N of zero means the document did not declare one, and
+// a Single document holds one share by design.
+func (s *Shares) CheckCount() error {
+	if s.Participant != "" {
+		if w := weight(s.Participants, s.Participant); len(s.Points) != w {
+			return fmt.Errorf("input of participant %q holds %d shares, but its weight is %d", s.Participant, len(s.Points), w)
+		}
+		return nil
+	}
+	if s.N == 0 || s.Single || len(s.Points) == s.N {
+		return nil
+	}
+	return fmt.Errorf("input declares n=%d but contains %d shares", s.N, len(s.Points))
+}
+
+// parseX reads a decimal x value of any size. A leading "+" is accepted.
+func parseX(key string) (*big.Int, error) {
+	x, ok := new(big.Int).SetString(key, 10)
+	if !ok {
+		return nil, newParseError(key, "x", key, errors.New("invalid x value"))
+	}
+	return x, nil
+}
+
+// arrayXText reads the "x" field of an element of the "shares" array,
+// which may be a JSON number or a string.
+func arrayXText(label string, entry json.RawMessage) (string, error) {
+	var fields struct {
+		X json.RawMessage `json:"x"`
+	}
+	if err := json.Unmarshal(entry, &fields); err != nil {
+		return "", fmt.Errorf("failed to parse point '%s': %w", label, err)
+	}
+	if len(fields.X) == 0 {
+		return "", fmt.Errorf("invalid x value: %s has no \"x\" field", label)
+	}
+
+	var text string
+	if err := json.Unmarshal(fields.X, &text); err != nil {
+		text = string(fields.X)
+	}
+	return text, nil
+}
+
+// decodeY decodes the value of the share stored under key. Unless strict
+// values were requested, whitespace and "_" separators are removed from
+// values in the numeric bases first. Failures are returned as a
+// *ParseError.
+func (c *parseConfig) decodeY(key string, x *big.Int, baseStr, value string) (*big.Int, error) {
+	if trimmed := strings.TrimSpace(baseStr); isByteBase(trimmed) {
+		y, err := decodeBytes(trimmed, value)
+		if err != nil {
+			return nil, newParseError(key, "value", value, fmt.Errorf("failed to decode y value for x=%s: %w", x.String(), err))
+		}
+		return y, nil
+	}
+
+	digits := value
+	if !c.strictValues {
+		digits = strings.Map(func(r rune) rune {
+			if r == '_' || unicode.IsSpace(r) {
+				return -1
+			}
+			return r
+		}, value)
+	}
+	sign, prefix, prefixBase, rest := splitPrefix(digits)
+
+	var base int
+	if strings.TrimSpace(baseStr) == "" {
+		base = 10
+		if prefix != "" {
+			base, digits = prefixBase, sign+rest
+		}
+	} else {
+		b, err := strconv.Atoi(strings.TrimSpace(baseStr))
+		if err != nil {
+			return nil, newParseError(key, "base", baseStr, fmt.Errorf("%w for x=%s", ErrInvalidBase, x.String()))
+		}
+		if b < minBase || b > maxBase {
+			return nil, newParseError(key, "base", baseStr, fmt.Errorf("%w %d for share %s: must be between %d and %d", ErrInvalidBase, b, key, minBase, maxBase))
+		}
+		base = b
+
+		switch {
+		case prefix == "":
+		case prefixBase == base:
+			digits = sign + rest
+		case !isDigit(prefix[1], base):
+			return nil, newParseError(key, "value", value, fmt.Errorf("value of share %s has prefix %s but its base is %d; the base field takes precedence, so drop one of them", key, prefix, base))
+		}
+	}
+
+	y := new(big.Int)
+	_, success := y.SetString(digits, base)
+	if !success {
+		return nil, newParseError(key, "value", value, fmt.Errorf("failed to decode y value for x=%s", x.String()))
+	}
+	return y, nil
+}
+
+// sortPoints sorts points by x, keeping keys[i] as the name of points[i],
+// and reports the first two entries that share an x.
+func sortPoints(points []Point, keys []string) error {
+	order := make([]int, len(points))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(a, b int) bool {
+		if c := points[order[a]].X.Cmp(points[order[b]].X); c != 0 {
+			return c < 0
+		}
+		return keys[order[a]] < keys[order[b]]
+	})
+
+	sorted := make([]Point, len(points))
+	sortedKeys := make([]string, len(keys))
+	for i, j := range order {
+		sorted[i], sortedKeys[i] = points[j], keys[j]
+	}
+	copy(points, sorted)
+	copy(keys, sortedKeys)
+
+	for i := 1; i < len(points); i++ {
+		if points[i].X.Cmp(points[i-1].X) == 0 {
+			return &DuplicateXError{X: points[i].X, Entries: []string{keys[i-1], keys[i]}}
+		}
+	}
+	return nil
+}
+
+// splitPrefix splits a 0x, 0b or 0o radix prefix, in either case, off
+// value after an optional sign. prefix is empty when there is none.
+func splitPrefix(value string) (sign, prefix string, base int, rest string) {
+	rest = value
+	if strings.HasPrefix(rest, "-") || strings.HasPrefix(rest, "+") {
+		sign, rest = rest[:1], rest[1:]
+	}
+	if len(rest) < 2 || rest[0] != '0' {
+		return sign, "", 0, value
+	}
+	switch rest[1] {
+	case 'x', 'X':
+		base = 16
+	case 'b', 'B':
+		base = 2
+	case 'o', 'O':
+		base = 8
+	default:
+		return sign, "", 0, value
+	}
+	return sign, rest[:2], base, rest[2:]
+}
+
+// isDigit reports whether c is a digit in base, using the digits of
+// big.Int.SetString: case-insensitive letters up to base 36, and lower then
+// upper case letters above it.
+func isDigit(c byte, base int) bool {
+	var v int
+	switch {
+	case '0' <= c && c <= '9':
+		v = int(c - '0')
+	case 'a' <= c && c <= 'z':
+		v = int(c-'a') + 10
+	case 'A' <= c && c <= 'Z' && base <= 36:
+		v = int(c-'A') + 10
+	case 'A' <= c && c <= 'Z':
+		v = int(c-'A') + 36
+	default:
+		return false
+	}
+	return v < base
+}
+
+// NewShares returns the shares of a document decoded by other means than
+// the parsers of this package, such as a gRPC request, after the checks
+// they make: n and k must be valid, no two points may share an x, every
+// base must be one EncodeValue accepts and a modulus must be at least 2.
+// The points are sorted by x.
+func NewShares(n, k int, points []Point, modulus *big.Int) (*Shares, error) {
+	if err := checkKeys(n, k); err != nil {
+		return nil, err
+	}
+	if modulus != nil && modulus.Cmp(big.NewInt(2)) < 0 {
+		return nil, fmt.Errorf("invalid modulus %s: must be at least 2", modulus)
+	}
+	sorted := make([]Point, len(points))
+	keys := make([]string, len(points))
+	for i, point := range points {
+		if point.X == nil || point.Y == nil {
+			return nil, fmt.Errorf("share %d has no x or no y", i+1)
+		}
+		if point.Base != "" && !ValidBase(point.Base) {
+			return nil, fmt.Errorf("%w for x=%s: %s", ErrInvalidBase, point.X, point.Base)
+		}
+		sorted[i], keys[i] = point, point.X.String()
+	}
+	if err := sortPoints(sorted, keys); err != nil {
+		return nil, err
+	}
+	return &Shares{N: n, K: k, Points: sorted, Modulus: modulus}, nil
+}
+
+// SetSource records name as the origin of s and of each of its points.
+func (s *Shares) SetSource(name string) {
+	s.Source = name
+	for i := range s.Points {
+		s.Points[i].Source = name
+	}
+}
+
+// Select splits the points into the K used for reconstruction and the
+// remaining ones available for verification.
+func (s *Shares) Select() (selected, extra []Point, err error) {
+	if len(s.Points) < s.K {
+		return nil, nil, &InsufficientSharesError{Found: len(s.Points), Needed: s.K, Context: "in file", Coverage: s.Coverage()}
+	}
+	return s.Points[:s.K], s.Points[s.K:], nil
+}
+
+// WriteShares encodes s in the layout ParseShares accepts, at format
+// version CurrentVersion. Share i is written in bases[i%len(bases)], any
+// base EncodeValue accepts. Every share and the keys object carry
+// checksums.
+func WriteShares(w io.Writer, s *Shares, bases []string) error {
+	return writeDocument(w, s, s.Points, bases, documentKeys{})
+}
+
+// WriteShare encodes share i of s in base as a document of its own, whose
+// keys object names the share's x, for handing each share to a different
+// holder. Documents of different shares of s reconstruct together.
+func WriteShare(w io.Writer, s *Shares, i int, base string) error {
+	return writeDocument(w, s, s.Points[i:i+1], []string{base}, documentKeys{x: s.Points[i].X})
+}
+
+// documentKeys are the fields of the keys object of a document holding
+// part of the shares: the x of its only share or the participant whose
+// shares it holds.
+type documentKeys struct {
+	x           *big.Int
+	participant string
+}
+
+func writeDocument(w io.Writer, s *Shares, points []Point, bases []string, keys documentKeys) error {
+	var buf bytes.Buffer
+	sums := make([]string, len(points))
+	for i, point := range points {
+		sum, err := shareChecksum(point.X, point.Y, bases[i%len(bases)])
+		if err != nil {
+			return err
+		}
+		sums[i] = sum
+	}
+
+	fmt.Fprintf(&buf, "{\n    \"keys\": {\n        \"version\": %d,\n        \"n\": %d,\n        \"k\": %d", CurrentVersion, s.N, s.K)
+	if keys.x != nil {
+		fmt.Fprintf(&buf, ",\n        \"x\": %s", keys.x)
+	}
+	if keys.participant != "" {
+		name, _ := json.Marshal(keys.participant)
+		fmt.Fprintf(&buf, ",\n        \"participant\": %s,\n        \"weight\": %d", name, len(points))
+	}
+	if len(s.Participants) > 0 {
+		buf.WriteString(",\n        \"participants\": [")
+		for i, p := range s.Participants {
+			if i > 0 {
+				buf.WriteString(",")
+			}
+			name, _ := json.Marshal(p.Name)
+			fmt.Fprintf(&buf, "\n            {\"name\": %s, \"weight\": %d}", name, p.Weight)
+		}
+		buf.WriteString("\n        ]")
+	}
+	fmt.Fprintf(&buf, ",\n        \"checksum\": %q", setChecksum(s.N, s.K, sums))
+	if s.Deterministic {
+		buf.WriteString(",\n        \"insecure_deterministic\": true")
+	}
+	if s.Field != "" {
+		fmt.Fprintf(&buf, ",\n        \"field\": %q", s.Field)
+	}
+	if s.Poly != nil {
+		fmt.Fprintf(&buf, ",\n        \"poly\": \"%#x\"", s.Poly)
+	}
+	if s.Modulus != nil {
+		fmt.Fprintf(&buf, ",\n        \"modulus\": %q", s.Modulus.String())
+	}
+	if s.SecretSHA256 != "" {
+		fmt.Fprintf(&buf, ",\n        \"secret_sha256\": %q", s.SecretSHA256)
+	}
+	if s.Commitments != nil {
+		writeCommitments(&buf, s.Commitments)
+	}
+	buf.WriteString("\n    }")
+	if s.Expected != "" {
+		fmt.Fprintf(&buf, ",\n    \"expected\": %q", s.Expected)
+	}
+
+	// Shares the document named are kept in a "shares" object under their
+	// labels.
+	labeled := false
+	for _, point := range points {
+		labeled = labeled || point.Label != ""
+	}
+	indent, separator := "    ", ","
+	if labeled {
+		buf.WriteString(",\n    \"shares\": {")
+		indent, separator = "        ", ""
+	}
+	for i, point := range points {
+		base := bases[i%len(bases)]
+		text, err := EncodeValue(point.Y, base)
+		if err != nil {
+			return err
+		}
+		key, _ := json.Marshal(cmp.Or(point.Label, point.X.String()))
+		baseStr, _ := json.Marshal(base)
+		value, _ := json.Marshal(text)
+		fmt.Fprintf(&buf, "%s\n%s%s: {", separator, indent, key)
+		if point.Label != "" {
+			fmt.Fprintf(&buf, "\n%s    \"x\": %s,", indent, point.X)
+		}
+		fmt.Fprintf(&buf, "\n%s    \"base\": %s,\n%s    \"value\": %s", indent, baseStr, indent, value)
+		if point.Blinding != nil {
+			fmt.Fprintf(&buf, ",\n%s    \"blinding\": %q", indent, point.Blinding.String())
+		}
+		fmt.Fprintf(&buf, ",\n%s    \"checksum\": %q\n%s}", indent, sums[i], indent)
+		separator = ","
+	}
+	if labeled {
+		buf.WriteString("\n    }")
+	}
+	buf.WriteString("\n}\n")
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
@@ -0,0 +1,1761 @@
+package share_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/OmSingh2003/CATALOG-ASSIGNMENT/pkg/gf256"
+	"github.com/OmSingh2003/CATALOG-ASSIGNMENT/pkg/lagrange"
+	"github.com/OmSingh2003/CATALOG-ASSIGNMENT/pkg/share"
+)
+
+// testcase1 is the x and y of every share of testcase1.json and the files
+// that hold the same shares in other formats.
+var testcase1 = [][2]int64{{1, 4}, {2, 7}, {3, 12}, {6, 39}}
+
+func open(t testing.TB, path string) *os.File {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { f.Close() })
+	return f
+}
+
+func checkTestcase1(t *testing.T, s *share.Shares) {
+	t.Helper()
+	if s.N != 4 || s.K != 3 {
+		t.Errorf("n=%d k=%d, want n=4 k=3", s.N, s.K)
+	}
+	if len(s.Points) != len(testcase1) {
+		t.Fatalf("%d shares, want %d", len(s.Points), len(testcase1))
+	}
+	for i, want := range testcase1 {
+		if p := s.Points[i]; p.X.Int64() != want[0] || p.Y.Int64() != want[1] {
+			t.Errorf("share %d = (%s, %s), want (%d, %d)", i, p.X, p.Y, want[0], want[1])
+		}
+	}
+}
+
+func TestParseFormats(t *testing.T) {
+	for _, tc := range []struct {
+		path  string
+		parse func(io.Reader) (*share.Shares, error)
+	}{
+		{"testcase1.json", func(r io.Reader) (*share.Shares, error) { return share.ParseShares(r) }},
+		{"testcase1_array.json", func(r io.Reader) (*share.Shares, error) { return share.ParseShares(r) }},
+		{"testcase1_labeled.json", func(r io.Reader) (*share.Shares, error) { return share.ParseShares(r) }},
+		{"testcase1_numbers.json", func(r io.Reader) (*share.Shares, error) { return share.ParseShares(r) }},
+		{"testcase1_bom.json", func(r io.Reader) (*share.Shares, error) { return share.ParseShares(r) }},
+		{"testcase1_lenient.json", func(r io.Reader) (*share.Shares, error) { return share.ParseShares(r, share.Lenient()) }},
+		{"testcase1.csv", func(r io.Reader) (*share.Shares, error) { return share.ParseCSV(r, 0) }},
+		{"testcase1_bom.csv", func(r io.Reader) (*share.Shares, error) { return share.ParseCSV(r, 0) }},
+		{"testcase1.yaml", func(r io.Reader) (*share.Shares, error) { return share.ParseYAML(r) }},
+		{"testcase1.toml", func(r io.Reader) (*share.Shares, error) { return share.ParseTOML(r) }},
+	} {
+		t.Run(tc.path, func(t *testing.T) {
+			s, err := tc.parse(open(t, "../../"+tc.path))
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkTestcase1(t, s)
+		})
+	}
+}
+
+func TestBinaryFormatsRoundTrip(t *testing.T) {
+	shares, err := share.ParseShares(open(t, "../../testcase1.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		name  string
+		write func(io.Writer, *share.Shares) error
+		parse func(io.Reader, ...share.ParseOption) (*share.Shares, error)
+		is    func([]byte) bool
+	}{
+		{"cbor", share.WriteCBOR, share.ParseCBOR, share.IsCBOR},
+		{"msgpack", share.WriteMsgpack, share.ParseMsgpack, share.IsMsgpack},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := tc.write(&buf, shares); err != nil {
+				t.Fatal(err)
+			}
+			if !tc.is(buf.Bytes()) {
+				t.Errorf("written document is not recognised as %s", tc.name)
+			}
+			parsed, err := tc.parse(&buf)
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkTestcase1(t, parsed)
+		})
+	}
+}
+
+func TestWriteSharesRoundTrip(t *testing.T) {
+	shares, err := share.ParseShares(open(t, "../../testcase1.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := share.WriteShares(&buf, shares, []string{"2", "16", "62", "64"}); err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := share.ParseShares(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkTestcase1(t, parsed)
+	if !parsed.Checksummed {
+		t.Error("written shares carry no checksums")
+	}
+	for _, d := range share.Diff(shares, parsed) {
+		if d.Material {
+			t.Errorf("written shares differ: %+v", d)
+		}
+	}
+}
+
+func TestParsePoint(t *testing.T) {
+	for _, tc := range []struct {
+		line    string
+		x, y    int64
+		wantErr bool
+	}{
+		{line: "1 10 4", x: 1, y: 4},
+		{line: `"2": {"base": "2", "value": "111"},`, x: 2, y: 7},
+		{line: `{"x": "6", "base": "4", "value": "213"}`, x: 6, y: 39},
+		{line: "1 99 4", wantErr: true},
+		{line: "1 2 102", wantErr: true},
+		{line: "1 10", wantErr: true},
+		{line: "", wantErr: true},
+	} {
+		t.Run(tc.line, func(t *testing.T) {
+			p, err := share.ParsePoint(tc.line)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("ParsePoint(%q) = (%s, %s), want an error", tc.line, p.X, p.Y)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if p.X.Int64() != tc.x || p.Y.Int64() != tc.y {
+				t.Errorf("ParsePoint(%q) = (%s, %s), want (%d, %d)", tc.line, p.X, p.Y, tc.x, tc.y)
+			}
+		})
+	}
+}
+
+func TestParseJSONCases(t *testing.T) {
+	shares, cases, err := share.ParseJSON(open(t, "../../testcases.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if shares != nil || len(cases) < 2 {
+		t.Fatalf("got shares %v and %d cases, want only cases", shares, len(cases))
+	}
+	for i := 1; i < len(cases); i++ {
+		if cases[i-1].Name >= cases[i].Name {
+			t.Errorf("cases %q and %q are out of order", cases[i-1].Name, cases[i].Name)
+		}
+	}
+	for _, c := range cases {
+		if c.Name == "testcase1" {
+			checkTestcase1(t, c.Shares)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		document string
+		opts     []share.ParseOption
+		want     error
+		contains string
+	}{
+		{name: "not json", document: "{", contains: "failed to unmarshal"},
+		{name: "not an object", document: "[]", contains: "must be a JSON object"},
+		{name: "missing keys", document: `{"1": {"base": "10", "value": "4"}}`, contains: "keys"},
+		{name: "k larger than n", document: `{"keys": {"n": 1, "k": 2}}`, contains: "larger than n"},
+		{name: "invalid base", document: `{"keys": {"n": 1, "k": 1}, "1": {"base": "99", "value": "4"}}`, want: share.ErrInvalidBase},
+		{name: "invalid digit", document: `{"keys": {"n": 1, "k": 1}, "1": {"base": "2", "value": "102"}}`, contains: "x=1"},
+		{name: "empty value", document: `{"keys": {"n": 1, "k": 1}, "1": {"base": "10", "value": ""}}`, contains: "x=1"},
+		{name: "duplicate key", document: `{"keys": {"n": 2, "k": 1}, "1": {"base": "10", "value": "4"}, "1": {"base": "10", "value": "5"}}`, contains: "duplicate key"},
+		{name: "duplicate x", document: `{"keys": {"n": 2, "k": 1}, "1": {"base": "10", "value": "4"}, "01": {"base": "10", "value": "5"}}`, want: share.ErrDuplicateX},
+		{name: "strict fields", document: `{"keys": {"n": 1, "k": 1}, "1": {"base": "10", "value": "4", "vaule": "5"}}`, opts: []share.ParseOption{share.StrictFields()}, contains: "vaule"},
+		{name: "strict values", document: `{"keys": {"n": 1, "k": 1}, "1": {"base": "10", "value": "1 000"}}`, opts: []share.ParseOption{share.StrictValues()}, contains: "x=1"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := share.ParseShares(strings.NewReader(tc.document), tc.opts...)
+			if err == nil {
+				t.Fatal("ParseShares returned no error")
+			}
+			if tc.want != nil && !errors.Is(err, tc.want) {
+				t.Errorf("err = %v, want %v", err, tc.want)
+			}
+			if !strings.Contains(err.Error(), tc.contains) {
+				t.Errorf("err = %v, want it to contain %q", err, tc.contains)
+			}
+		})
+	}
+}
+
+func TestAllowDuplicateKeys(t *testing.T) {
+	s, err := share.ParseShares(open(t, "../../testcase1_duplicate_key.json"), share.AllowDuplicateKeys())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range s.Points {
+		if p.X.Int64() == 3 && p.Y.Int64() != 13 {
+			t.Errorf("duplicate key kept y=%s, want the last value 13", p.Y)
+		}
+	}
+	if _, err := share.ParseShares(open(t, "../../testcase1_duplicate_key.json")); err == nil {
+		t.Error("ParseShares accepted a duplicate key without AllowDuplicateKeys")
+	}
+}
+
+func TestVerifyCommitments(t *testing.T) {
+	for _, path := range []string{"testcase_vss.json", "testcase_pedersen.json"} {
+		t.Run(path, func(t *testing.T) {
+			s, err := share.ParseShares(open(t, "../../"+path))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if s.Commitments == nil {
+				t.Fatal("no commitments parsed")
+			}
+			if err := s.VerifyCommitments(); err != nil {
+				t.Fatal(err)
+			}
+
+			tampered := *s
+			tampered.Points = append([]share.Point(nil), s.Points...)
+			tampered.Points[1].Y = new(big.Int).Add(s.Points[1].Y, big.NewInt(1))
+			err = tampered.VerifyCommitments()
+			if !errors.Is(err, share.ErrCommitmentMismatch) {
+				t.Fatalf("VerifyCommitments of a tampered share: err = %v, want %v", err, share.ErrCommitmentMismatch)
+			}
+			var commitmentErr *share.CommitmentError
+			if !errors.As(err, &commitmentErr) {
+				t.Fatalf("err = %T, want *share.CommitmentError", err)
+			}
+		})
+	}
+}
+
+func TestParseRejectsCommitmentOutsideSubgroup(t *testing.T) {
+	data, err := os.ReadFile("../../testcase_vss.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := share.ParseShares(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// p-1 has order 2, so it lies outside the subgroup of odd order q.
+	outside := new(big.Int).Sub(s.Commitments.P, big.NewInt(1)).String()
+	tampered := strings.Replace(string(data), s.Commitments.Values[1].String(), outside, 1)
+	_, err = share.ParseShares(strings.NewReader(tampered))
+	if err == nil || !strings.Contains(err.Error(), "not in the subgroup") {
+		t.Errorf("err = %v, want a value outside the subgroup", err)
+	}
+}
+
+func TestFeldmanGroupCached(t *testing.T) {
+	if testing.Short() {
+		t.Skip("generating the commitment group takes seconds")
+	}
+	q := share.LookupField("mersenne127").Modulus
+	p, g, err := share.FeldmanGroup(q)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := new(big.Int).Set(p)
+	p.SetInt64(0)
+	again, g2, err := share.FeldmanGroup(q)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.Cmp(want) != 0 || g2.Cmp(g) != 0 {
+		t.Errorf("second FeldmanGroup = (%s, %s), want (%s, %s)", again, g2, want, g)
+	}
+}
+
+func TestSplitCommitments(t *testing.T) {
+	if testing.Short() {
+		t.Skip("generating the commitment group takes seconds")
+	}
+	modulus := share.LookupField("mersenne127").Modulus
+	for _, scheme := range []string{share.SchemeFeldman, share.SchemePedersen} {
+		t.Run(scheme, func(t *testing.T) {
+			s, err := share.Split(big.NewInt(42), 5, 3, modulus, share.WithCommitments(scheme), share.WithSeed([]byte(scheme)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if s.Commitments == nil || s.Commitments.Scheme != scheme {
+				t.Fatalf("commitments = %+v, want scheme %s", s.Commitments, scheme)
+			}
+			if err := s.VerifyCommitments(); err != nil {
+				t.Fatal(err)
+			}
+			s.Points[4].Y = new(big.Int).Xor(s.Points[4].Y, big.NewInt(1))
+			if err := s.VerifyCommitments(); !errors.Is(err, share.ErrCommitmentMismatch) {
+				t.Errorf("VerifyCommitments of a tampered share: err = %v, want %v", err, share.ErrCommitmentMismatch)
+			}
+		})
+	}
+}
+
+func TestSplitSecretHash(t *testing.T) {
+	if testing.Short() {
+		t.Skip("generating the commitment group takes seconds")
+	}
+	modulus := share.LookupField("mersenne127").Modulus
+	for _, tc := range []struct {
+		name string
+		opts []share.SplitOption
+		want bool
+	}{
+		{"feldman", []share.SplitOption{share.WithCommitments(share.SchemeFeldman)}, true},
+		{"feldman without hash", []share.SplitOption{share.WithCommitments(share.SchemeFeldman), share.WithSecretHash(false)}, false},
+		{"pedersen", []share.SplitOption{share.WithCommitments(share.SchemePedersen)}, false},
+		{"pedersen with hash", []share.SplitOption{share.WithCommitments(share.SchemePedersen), share.WithSecretHash(true)}, true},
+		{"no commitments", []share.SplitOption{share.WithCommitments("")}, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			s, err := share.Split(big.NewInt(42), 3, 2, modulus, tc.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := s.SecretSHA256 != ""; got != tc.want {
+				t.Errorf("records a secret hash: %t, want %t", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestShareSetAddLeavesSetOnConflict(t *testing.T) {
+	first := &share.Shares{N: 3, K: 2, Modulus: big.NewInt(101), Points: []share.Point{{X: big.NewInt(1), Y: big.NewInt(5)}}}
+	other := &share.Shares{N: 3, K: 2, Modulus: big.NewInt(103), Points: []share.Point{{X: big.NewInt(2), Y: big.NewInt(7)}}}
+	second := &share.Shares{N: 3, K: 2, Modulus: big.NewInt(101), Points: []share.Point{{X: big.NewInt(2), Y: big.NewInt(9)}}}
+
+	set := share.NewShareSet()
+	if conflicts := set.Add("a.json", first); conflicts != nil {
+		t.Fatalf("first source conflicts: %v", conflicts)
+	}
+	conflicts := set.Add("b.json", other)
+	if len(conflicts) != 1 || conflicts[0].Kind != share.ConflictModulus {
+		t.Fatalf("conflicts = %v, want one modulus mismatch", conflicts)
+	}
+	if set.Len() != 1 || set.Conflicts != nil {
+		t.Fatalf("the conflicting source changed the set: %d shares, conflicts %v", set.Len(), set.Conflicts)
+	}
+	if conflicts := set.Add("c.json", second); conflicts != nil {
+		t.Fatalf("matching source conflicts: %v", conflicts)
+	}
+	merged, err := set.Shares()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(merged.Points) != 2 || merged.Points[1].Y.Int64() != 9 || merged.Modulus.Int64() != 101 {
+		t.Errorf("merged = %+v, want the shares of a.json and c.json mod 101", merged)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	var sets []*share.Shares
+	for _, path := range []string{"testcase_single_1.json", "testcase_single_2.json", "testcase_single_3.json", "testcase_single_1.json"} {
+		s, err := share.ParseShares(open(t, "../../"+path))
+		if err != nil {
+			t.Fatal(err)
+		}
+		s.Source = path
+		sets = append(sets, s)
+	}
+	merged, err := share.Merge(sets...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if merged.K != 3 || len(merged.Points) != 3 {
+		t.Fatalf("merged k=%d with %d shares, want k=3 with the 3 distinct shares", merged.K, len(merged.Points))
+	}
+	for i, want := range testcase1[:3] {
+		if p := merged.Points[i]; p.X.Int64() != want[0] || p.Y.Int64() != want[1] {
+			t.Errorf("share %d = (%s, %s), want (%d, %d)", i, p.X, p.Y, want[0], want[1])
+		}
+	}
+
+	other := *sets[0]
+	other.Source = "other.json"
+	other.Points = []share.Point{{X: big.NewInt(1), Y: big.NewInt(5)}}
+	var conflict *share.ConflictError
+	if _, err := share.Merge(append(sets, &other)...); !errors.As(err, &conflict) {
+		t.Fatalf("merging a different y for x=1: err = %v, want a *share.ConflictError", err)
+	}
+	if len(conflict.Conflicts) != 1 || conflict.Conflicts[0].Kind != share.ConflictY || conflict.Conflicts[0].X != "1" {
+		t.Errorf("conflicts = %+v, want one for the y of x=1", conflict.Conflicts)
+	}
+}
+
+func TestParseErrorLocation(t *testing.T) {
+	long := strings.Repeat("9", 100) + "z"
+	for _, tc := range []struct {
+		name     string
+		document string
+		key      string
+		field    string
+		snippet  string
+	}{
+		{"bad digit", `{"keys": {"n": 2, "k": 1}, "1": {"base": "10", "value": "4"}, "2": {"base": "2", "value": "102"}}`, "2", "value", "102"},
+		{"bad base", `{"keys": {"n": 1, "k": 1}, "1": {"base": "ten", "value": "4"}}`, "1", "base", "ten"},
+		{"not an object", `{"keys": {"n": 1, "k": 1}, "1": [4]}`, "1", "", "[4]"},
+		{"long value", `{"keys": {"n": 1, "k": 1}, "1": {"base": "10", "value": "` + long + `"}}`, "1", "value", long[:64] + "..."},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := share.ParseShares(strings.NewReader(tc.document))
+			var parseErr *share.ParseError
+			if !errors.As(err, &parseErr) {
+				t.Fatalf("err = %v, want a *share.ParseError", err)
+			}
+			if parseErr.Key != tc.key || parseErr.Field != tc.field || parseErr.Snippet != tc.snippet {
+				t.Errorf("located at entry %q field %q snippet %q, want %q, %q and %q", parseErr.Key, parseErr.Field, parseErr.Snippet, tc.key, tc.field, tc.snippet)
+			}
+		})
+	}
+}
+
+func TestCheckCount(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		document string
+		wantErr  bool
+	}{
+		{"matches", `{"keys": {"n": 2, "k": 1}, "1": {"base": "10", "value": "4"}, "2": {"base": "10", "value": "4"}}`, false},
+		{"fewer", `{"keys": {"n": 3, "k": 1}, "1": {"base": "10", "value": "4"}, "2": {"base": "10", "value": "4"}}`, true},
+		{"more", `{"keys": {"n": 1, "k": 1}, "1": {"base": "10", "value": "4"}, "2": {"base": "10", "value": "4"}}`, true},
+		{"single", `{"keys": {"n": 4, "k": 3}, "x": 1, "base": "10", "value": "4"}`, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			s, err := share.ParseShares(strings.NewReader(tc.document))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := s.CheckCount(); (err != nil) != tc.wantErr {
+				t.Errorf("CheckCount() = %v, want an error: %t", err, tc.wantErr)
+			}
+		})
+	}
+	if _, err := share.ParseShares(strings.NewReader(`{"keys": {"n": 0, "k": 1}}`)); err == nil || !strings.Contains(err.Error(), "invalid n=0") {
+		t.Errorf("n=0: err = %v, want it rejected", err)
+	}
+}
+
+func TestParseThreshold(t *testing.T) {
+	const entries = `"1": {"base": "10", "value": "4"}, "2": {"base": "10", "value": "7"}`
+	for _, tc := range []struct {
+		k        int
+		want     error
+		contains string
+	}{
+		{k: -1, contains: "invalid k=-1"},
+		{k: 0, contains: "invalid k=0"},
+		{k: 1},
+		{k: 2},
+		{k: 3, want: share.ErrInsufficientShares, contains: "found 2, need 3"},
+	} {
+		t.Run(strconv.Itoa(tc.k), func(t *testing.T) {
+			document := fmt.Sprintf(`{"keys": {"n": 3, "k": %d}, %s}`, tc.k, entries)
+			s, err := share.ParseShares(strings.NewReader(document), share.RequireThreshold())
+			if tc.contains == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+				if s.K != tc.k || len(s.Points) != 2 {
+					t.Errorf("k=%d with %d shares, want k=%d with 2", s.K, len(s.Points), tc.k)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("ParseShares returned no error")
+			}
+			if tc.want != nil && !errors.Is(err, tc.want) {
+				t.Errorf("err = %v, want %v", err, tc.want)
+			}
+			if !strings.Contains(err.Error(), tc.contains) {
+				t.Errorf("err = %v, want it to contain %q", err, tc.contains)
+			}
+		})
+	}
+
+	// Without RequireThreshold a partial document parses, to be merged.
+	s, err := share.ParseShares(strings.NewReader(`{"keys": {"n": 3, "k": 3}, "1": {"base": "10", "value": "4"}}`))
+	if err != nil || len(s.Points) != 1 {
+		t.Errorf("partial document: %v, %v", s, err)
+	}
+}
+
+func TestParseKeysErrors(t *testing.T) {
+	for _, tc := range []struct {
+		document string
+		want     string
+	}{
+		{`{"1": {"base": "10", "value": "4"}}`, `missing the required "keys" object`},
+		{`{"keys": null}`, `"keys" must be an object, got null`},
+		{`{"keys": []}`, `"keys" must be an object, got array`},
+		{`{"keys": "n=1"}`, `"keys" must be an object, got string "n=1"`},
+		{`{"keys": {"n": 1}}`, `"keys" object is missing "k"`},
+		{`{"keys": {"k": 1}}`, `"keys" object is missing "n"`},
+		{`{"keys": {"n": 1, "k": "one"}}`, `invalid "k" in "keys" object: must be an integer, got string "one"`},
+		{`{"keys": {"n": 1.5, "k": 1}}`, `invalid "n" in "keys" object: must be an integer, got number 1.5`},
+	} {
+		_, err := share.ParseShares(strings.NewReader(tc.document))
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: err = %v, want %q", tc.document, err, tc.want)
+		}
+	}
+}
+
+func TestParseBaseRange(t *testing.T) {
+	parse := func(base, value string) (*share.Shares, error) {
+		return share.ParseShares(strings.NewReader(fmt.Sprintf(`{"keys": {"n": 1, "k": 1}, "1": {"base": %q, "value": %q}}`, base, value)))
+	}
+	for _, tc := range []struct {
+		base, value string
+		want        int64
+	}{
+		{"2", "101", 5},
+		{" 16 ", "ff", 255},
+		{"36", "z", 35},
+		{"62", "Z", 61},
+	} {
+		s, err := parse(tc.base, tc.value)
+		if err != nil {
+			t.Errorf("base %q: %v", tc.base, err)
+			continue
+		}
+		if y := s.Points[0].Y; y.Int64() != tc.want {
+			t.Errorf("base %q value %q = %s, want %d", tc.base, tc.value, y, tc.want)
+		}
+	}
+	for _, base := range []string{"0", "1", "63", "-10", "ten"} {
+		if _, err := parse(base, "1"); !errors.Is(err, share.ErrInvalidBase) {
+			t.Errorf("base %q: err = %v, want %v", base, err, share.ErrInvalidBase)
+		}
+	}
+}
+
+func TestByteBases(t *testing.T) {
+	y := new(big.Int).SetBytes([]byte("shamir\xff\xfe"))
+	for _, tc := range []struct {
+		base string
+		want string
+	}{
+		{"64", "c2hhbWly//4="},
+		{"64url", "c2hhbWly__4="},
+		{"85", "F(f!!Bla7Q"},
+	} {
+		t.Run(tc.base, func(t *testing.T) {
+			got, err := share.EncodeValue(y, tc.base)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tc.want {
+				t.Errorf("EncodeValue = %q, want %q", got, tc.want)
+			}
+			// Padding and line wraps are ignored on the way back.
+			value := strings.TrimRight(got, "=")
+			value = value[:4] + "\n  " + value[4:]
+			s, err := share.ParseShares(strings.NewReader(fmt.Sprintf(`{"keys": {"n": 1, "k": 1}, "1": {"base": %q, "value": %q}}`, tc.base, value)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if s.Points[0].Y.Cmp(y) != 0 {
+				t.Errorf("parsed %s, want %s", s.Points[0].Y, y)
+			}
+		})
+	}
+	if _, err := share.EncodeValue(big.NewInt(-1), "64"); err == nil {
+		t.Error("EncodeValue wrote a negative value in base 64")
+	}
+}
+
+// parseValue parses a document holding the single share entry entry at
+// x=1.
+func parseValue(entry string) (*share.Shares, error) {
+	return share.ParseShares(strings.NewReader(`{"keys": {"n": 1, "k": 1}, "1": ` + entry + `}`))
+}
+
+func TestParseNumericBase(t *testing.T) {
+	s, err := share.ParseShares(strings.NewReader(`{"keys": {"n": "2", "k": "1"}, "1": {"base": 16, "value": "ff"}, "2": {"base": " 8 ", "value": "17"}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.N != 2 || s.K != 1 || s.Points[0].Y.Int64() != 255 || s.Points[1].Y.Int64() != 15 {
+		t.Errorf("n=%d k=%d shares %v, want n=2 k=1 with y 255 and 15", s.N, s.K, s.Points)
+	}
+	for _, entry := range []string{`{"base": 16.5, "value": "1"}`, `{"base": true, "value": "1"}`} {
+		if _, err := parseValue(entry); err == nil {
+			t.Errorf("%s: parsed", entry)
+		}
+	}
+}
+
+func TestParseNumericValue(t *testing.T) {
+	for _, tc := range []struct {
+		entry string
+		want  string
+	}{
+		{`{"base": 10, "value": 123456789012345678901234567890}`, "123456789012345678901234567890"},
+		{`{"base": "10", "value": -5}`, "-5"},
+		{`{"value": 42}`, "42"},
+	} {
+		s, err := parseValue(tc.entry)
+		if err != nil {
+			t.Errorf("%s: %v", tc.entry, err)
+			continue
+		}
+		if got := s.Points[0].Y.String(); got != tc.want {
+			t.Errorf("%s: y = %s, want %s", tc.entry, got, tc.want)
+		}
+	}
+	for _, tc := range []struct {
+		entry string
+		want  string
+	}{
+		{`{"base": 10, "value": 1e3}`, "must be an integer"},
+		{`{"base": 10, "value": 1.5}`, "must be an integer"},
+		{`{"base": 16, "value": 10}`, "needs base 10"},
+	} {
+		if _, err := parseValue(tc.entry); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: err = %v, want %q", tc.entry, err, tc.want)
+		}
+	}
+}
+
+func TestParseLargeX(t *testing.T) {
+	s, err := share.ParseShares(open(t, "../../testcase_large_x.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"18446744073709551616", "18446744073709551623", "1180591620717411303424"}
+	if len(s.Points) != len(want) {
+		t.Fatalf("%d shares, want %d", len(s.Points), len(want))
+	}
+	for i, x := range want {
+		if got := s.Points[i].X.String(); got != x {
+			t.Errorf("share %d has x=%s, want %s", i, got, x)
+		}
+	}
+	// x=2^64 would wrap to 0 in a 64-bit integer and collide with x=0.
+	if _, err := share.ParseShares(strings.NewReader(`{"keys": {"n": 2, "k": 1}, "18446744073709551616": {"base": "10", "value": "1"}, "0": {"base": "10", "value": "1"}}`)); err != nil {
+		t.Errorf("x=2^64 and x=0 collide: %v", err)
+	}
+}
+
+func TestParseValueSeparators(t *testing.T) {
+	for _, tc := range []struct {
+		entry string
+		want  int64
+	}{
+		{`{"base": "10", "value": "1 000_000"}`, 1000000},
+		{`{"base": "16", "value": " ff_ff\n"}`, 0xffff},
+		{`{"base": "2", "value": "1010\t1010"}`, 0xaa},
+	} {
+		s, err := parseValue(tc.entry)
+		if err != nil {
+			t.Errorf("%s: %v", tc.entry, err)
+			continue
+		}
+		if y := s.Points[0].Y; y.Int64() != tc.want {
+			t.Errorf("%s: y = %s, want %d", tc.entry, y, tc.want)
+		}
+		document := `{"keys": {"n": 1, "k": 1}, "1": ` + tc.entry + `}`
+		if _, err := share.ParseShares(strings.NewReader(document), share.StrictValues()); err == nil {
+			t.Errorf("%s: StrictValues accepted the separators", tc.entry)
+		}
+	}
+	if _, err := parseValue(`{"base": "10", "value": "_ _"}`); err == nil {
+		t.Error("a value of separators only parsed")
+	}
+}
+
+func TestParseValuePrefix(t *testing.T) {
+	for _, tc := range []struct {
+		entry string
+		want  int64
+	}{
+		{`{"value": "0xff"}`, 255},
+		{`{"value": "0XFF"}`, 255},
+		{`{"value": "0b101"}`, 5},
+		{`{"value": "0o17"}`, 15},
+		{`{"value": "-0x10"}`, -16},
+		{`{"value": "12"}`, 12},
+	} {
+		s, err := parseValue(tc.entry)
+		if err != nil {
+			t.Errorf("%s: %v", tc.entry, err)
+			continue
+		}
+		if y := s.Points[0].Y; y.Int64() != tc.want {
+			t.Errorf("%s: y = %s, want %d", tc.entry, y, tc.want)
+		}
+	}
+	for _, tc := range []struct {
+		entry string
+		want  string
+	}{
+		{`{"base": "10", "value": "0x10"}`, "the base field takes precedence"},
+		{`{"value": "0z1"}`, "x=1"},
+	} {
+		if _, err := parseValue(tc.entry); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: err = %v, want %q", tc.entry, err, tc.want)
+		}
+	}
+}
+
+func TestStrictFields(t *testing.T) {
+	for _, tc := range []struct {
+		document string
+		want     string
+	}{
+		{`{"keys": {"n": 1, "k": 1}, "1": {"base": "10", "value": "4", "vaule": "5"}}`, `unknown field "vaule" (did you mean "value"?)`},
+		{`{"keys": {"n": 1, "k": 1}, "1": {"base": "10", "value": "4", "zzzzzz": "5"}}`, `unknown field "zzzzzz"`},
+		{`{"keys": {"n": 1, "k": 1, "treshold": 1}, "1": {"base": "10", "value": "4"}}`, `unknown field "treshold" in "keys" object`},
+	} {
+		if _, err := share.ParseShares(strings.NewReader(tc.document)); err != nil {
+			t.Errorf("%s: %v without StrictFields", tc.document, err)
+		}
+		_, err := share.ParseShares(strings.NewReader(tc.document), share.StrictFields())
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: err = %v, want %q", tc.document, err, tc.want)
+		}
+	}
+	_, err := share.ParseShares(strings.NewReader(`{"keys": {"n": 1, "k": 1}, "1": {"base": "10", "value": "4", "zzzzzz": "5"}}`), share.StrictFields())
+	if err != nil && strings.Contains(err.Error(), "did you mean") {
+		t.Errorf("err = %v, want no suggestion for an unrelated field", err)
+	}
+}
+
+func TestParseMnemonics(t *testing.T) {
+	s, err := share.ParseMnemonics(open(t, "../../testcase_mnemonic.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.K != 3 || len(s.Points) != 5 {
+		t.Fatalf("k=%d with %d shares, want k=3 with 5", s.K, len(s.Points))
+	}
+	for _, points := range [][]share.BytePoint{s.Points[:3], s.Points[2:]} {
+		shares := make([]gf256.Share, len(points))
+		for i, p := range points {
+			shares[i] = gf256.Share{X: p.X, Y: p.Y}
+		}
+		secret, err := gf256.Combine(shares)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := hex.EncodeToString(secret); got != "0123456789abcdef0123456789abcdef" {
+			t.Errorf("secret = %s, want 0123456789abcdef0123456789abcdef", got)
+		}
+	}
+}
+
+func TestMnemonicRoundTrip(t *testing.T) {
+	m := share.Mnemonic{
+		Identifier: 12345,
+		Threshold:  3,
+		Point:      share.BytePoint{X: 16, Y: bytes.Repeat([]byte{0xa5}, 32)},
+	}
+	text, err := share.EncodeMnemonic(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	words := strings.Fields(text)
+	if len(words) != 33 {
+		t.Errorf("%d words, want 33", len(words))
+	}
+	for _, input := range []string{text, strings.ToUpper(text), abbreviate(words)} {
+		got, err := share.DecodeMnemonic(input)
+		if err != nil {
+			t.Fatalf("DecodeMnemonic(%q): %v", input, err)
+		}
+		if got.Identifier != m.Identifier || got.Threshold != m.Threshold || got.Point.X != m.Point.X || !bytes.Equal(got.Point.Y, m.Point.Y) {
+			t.Errorf("DecodeMnemonic = %+v, want %+v", got, m)
+		}
+	}
+
+	for _, bad := range []share.Mnemonic{
+		{Threshold: 1, Point: share.BytePoint{X: 0, Y: make([]byte, 16)}},
+		{Threshold: 1, Point: share.BytePoint{X: 17, Y: make([]byte, 16)}},
+		{Threshold: 17, Point: share.BytePoint{X: 1, Y: make([]byte, 16)}},
+		{Threshold: 1, Point: share.BytePoint{X: 1, Y: make([]byte, 15)}},
+		{Threshold: 1, Point: share.BytePoint{X: 1, Y: make([]byte, 17)}},
+	} {
+		if _, err := share.EncodeMnemonic(bad); err == nil {
+			t.Errorf("EncodeMnemonic(%+v) returned no error", bad)
+		}
+	}
+}
+
+func abbreviate(words []string) string {
+	short := make([]string, len(words))
+	for i, word := range words {
+		short[i] = word[:min(4, len(word))]
+	}
+	return strings.Join(short, " ")
+}
+
+func TestDecodeMnemonicChecksum(t *testing.T) {
+	text, err := share.EncodeMnemonic(share.Mnemonic{Threshold: 2, Point: share.BytePoint{X: 1, Y: make([]byte, 16)}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	words := strings.Fields(text)
+
+	wrong := slices.Clone(words)
+	wrong[6] = "academic"
+	if wrong[6] == words[6] {
+		wrong[6] = "acid"
+	}
+	_, err = share.DecodeMnemonic(strings.Join(wrong, " "))
+	var checksumErr *share.MnemonicChecksumError
+	if !errors.As(err, &checksumErr) {
+		t.Fatalf("err = %v, want a *MnemonicChecksumError", err)
+	}
+	if checksumErr.Word != 7 || checksumErr.Suggestion != words[6] {
+		t.Errorf("err = %v, want word 7 with the suggestion %q", err, words[6])
+	}
+
+	if _, err := share.DecodeMnemonic(strings.Join(append(words[:len(words)-1], "zzzz"), " ")); err == nil || !strings.Contains(err.Error(), "not in the SLIP-39 wordlist") {
+		t.Errorf("err = %v, want an unknown word", err)
+	}
+	if _, err := share.DecodeMnemonic("academic acid"); err == nil {
+		t.Error("a two-word mnemonic decoded")
+	}
+}
+
+func TestReshare(t *testing.T) {
+	old, err := share.ParseShares(open(t, "../../testcase_reshare.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	reshared, err := share.Reshare(old, 5, 3, nil, share.WithSeed([]byte("reshare")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reshared.N != 5 || reshared.K != 3 || len(reshared.Points) != 5 {
+		t.Fatalf("n=%d k=%d with %d shares, want n=5 k=3 with 5", reshared.N, reshared.K, len(reshared.Points))
+	}
+	if reshared.Modulus.Cmp(old.Modulus) != 0 {
+		t.Errorf("modulus = %s, want %s", reshared.Modulus, old.Modulus)
+	}
+	if err := reshared.VerifyCommitments(); err != nil {
+		t.Errorf("new shares do not match their commitments: %v", err)
+	}
+	for _, points := range [][]share.Point{reshared.Points[:3], reshared.Points[2:]} {
+		secret, err := lagrange.Interpolate(points, lagrange.WithModulus(reshared.Modulus))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if secret.Int64() != 123456789 {
+			t.Errorf("secret = %s, want 123456789", secret)
+		}
+	}
+	for i, p := range reshared.Points[:old.K] {
+		if p.Y.Cmp(old.Points[i].Y) == 0 {
+			t.Errorf("new share x=%s equals the old one", p.X)
+		}
+	}
+
+	for _, tc := range []struct {
+		name    string
+		n, k    int
+		modulus *big.Int
+		opts    []share.SplitOption
+		want    string
+	}{
+		{"pedersen", 5, 3, nil, []share.SplitOption{share.WithCommitments(share.SchemePedersen)}, "Pedersen"},
+		{"modulus mismatch", 5, 3, big.NewInt(101), nil, "modulus mismatch"},
+		{"k of 0", 5, 0, nil, nil, "invalid k=0"},
+		{"n below k", 2, 3, nil, nil, "invalid n=2"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := share.Reshare(old, tc.n, tc.k, tc.modulus, tc.opts...); err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("err = %v, want %q", err, tc.want)
+			}
+		})
+	}
+	integers := &share.Shares{N: 3, K: 2, Points: testcase1Points()}
+	if _, err := share.Reshare(integers, 3, 2, nil); err == nil || !strings.Contains(err.Error(), "needs a prime modulus") {
+		t.Errorf("resharing over the integers: err = %v, want a missing modulus", err)
+	}
+}
+
+func testcase1Points() []share.Point {
+	points := make([]share.Point, len(testcase1))
+	for i, p := range testcase1 {
+		points[i] = share.Point{X: big.NewInt(p[0]), Y: big.NewInt(p[1])}
+	}
+	return points
+}
+
+func TestParseChecksums(t *testing.T) {
+	document, err := os.ReadFile("../../testcase_checksum.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := share.ParseShares(bytes.NewReader(document))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !s.Checksummed {
+		t.Error("Checksummed is false")
+	}
+
+	for _, tc := range []struct {
+		name     string
+		old, new string
+		want     error
+	}{
+		// The checksum covers the value, not its spelling.
+		{"uppercase value", `"122bf3db86ad40943"`, `"122BF3DB86AD40943"`, nil},
+		{"altered value", `"49825890389260245365"`, `"49825890389260245366"`, &share.ChecksumError{Key: "2"}},
+		{"altered base", `"base": "36"`, `"base": "35"`, &share.ChecksumError{Key: "3"}},
+		{"altered k", `"k": 3`, `"k": 2`, share.ErrChecksumMismatch},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			altered := strings.Replace(string(document), tc.old, tc.new, 1)
+			_, err := share.ParseShares(strings.NewReader(altered))
+			switch want := tc.want.(type) {
+			case nil:
+				if err != nil {
+					t.Errorf("err = %v, want nil", err)
+				}
+			case *share.ChecksumError:
+				var got *share.ChecksumError
+				if !errors.As(err, &got) || got.Key != want.Key || !errors.Is(err, share.ErrChecksumMismatch) {
+					t.Errorf("err = %v, want a checksum error for share %q", err, want.Key)
+				}
+			default:
+				if !errors.Is(err, want) {
+					t.Errorf("err = %v, want %v", err, want)
+				}
+			}
+		})
+	}
+
+	// Without every share the set checksum cannot be checked, but each
+	// share still is.
+	partial := strings.Replace(string(document), `"n": 4`, `"n": 5`, 1)
+	if _, err := share.ParseShares(strings.NewReader(partial)); err != nil {
+		t.Errorf("a document missing a share: %v", err)
+	}
+}
+
+func TestParseVersion(t *testing.T) {
+	s, err := share.ParseShares(open(t, "../../testcase1.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Version != 1 {
+		t.Errorf("a keys object without a version has version %d, want 1", s.Version)
+	}
+
+	var buf bytes.Buffer
+	if err := share.WriteShares(&buf, s, []string{"10"}); err != nil {
+		t.Fatal(err)
+	}
+	if s, err = share.ParseShares(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if s.Version != share.CurrentVersion {
+		t.Errorf("written shares have version %d, want %d", s.Version, share.CurrentVersion)
+	}
+
+	if _, err := share.ParseShares(strings.NewReader(`{"keys": {"version": 2, "n": 1, "k": 1}, "1": {"base": "10", "value": "4"}}`)); err == nil || !strings.Contains(err.Error(), "requires") {
+		t.Errorf("version 2 without checksums: err = %v, want a missing checksum", err)
+	}
+	for _, version := range []int{0, share.CurrentVersion + 1} {
+		document := fmt.Sprintf(`{"keys": {"version": %d, "n": 1, "k": 1}, "1": {"base": "10", "value": "4"}}`, version)
+		_, err := share.ParseShares(strings.NewReader(document))
+		var versionErr *share.UnsupportedVersionError
+		if !errors.As(err, &versionErr) || versionErr.Version != version {
+			t.Errorf("version %d: err = %v, want an *UnsupportedVersionError", version, err)
+		}
+	}
+}
+
+func TestSplitSeeded(t *testing.T) {
+	modulus := big.NewInt(2147483647)
+	split := func(seed string) *share.Shares {
+		t.Helper()
+		s, err := share.Split(big.NewInt(42), 5, 3, modulus, share.WithSeed([]byte(seed)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	}
+	a, b, c := split("fixture"), split("fixture"), split("other")
+	if !a.Deterministic {
+		t.Error("Deterministic is false for a seeded split")
+	}
+	for i := range a.Points {
+		if a.Points[i].Y.Cmp(b.Points[i].Y) != 0 {
+			t.Errorf("share x=%s differs between splits from the same seed", a.Points[i].X)
+		}
+	}
+	if slices.EqualFunc(a.Points, c.Points, func(p, q share.Point) bool { return p.Y.Cmp(q.Y) == 0 }) {
+		t.Error("splits from different seeds are the same")
+	}
+	if len(a.Commitments.Values) != 3 || a.Commitments.Values[1].Cmp(b.Commitments.Values[1]) != 0 {
+		t.Error("seeded splits have different commitments")
+	}
+
+	var buf bytes.Buffer
+	if err := share.WriteShares(&buf, a, []string{"10"}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), `"insecure_deterministic": true`) {
+		t.Errorf("written shares are not marked deterministic:\n%s", buf.String())
+	}
+	parsed, err := share.ParseShares(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !parsed.Deterministic {
+		t.Error("Deterministic is lost in a round trip")
+	}
+}
+
+func TestSeededReader(t *testing.T) {
+	// The stream does not depend on how it is read.
+	whole := make([]byte, 100)
+	if _, err := io.ReadFull(share.SeededReader([]byte("seed")), whole); err != nil {
+		t.Fatal(err)
+	}
+	r := share.SeededReader([]byte("seed"))
+	var pieces []byte
+	for _, n := range []int{1, 31, 33, 35} {
+		piece := make([]byte, n)
+		if _, err := io.ReadFull(r, piece); err != nil {
+			t.Fatal(err)
+		}
+		pieces = append(pieces, piece...)
+	}
+	if !bytes.Equal(whole, pieces) {
+		t.Error("reading in pieces gives a different stream")
+	}
+	// The first block is the SHA-256 of the label, the seed and a zero
+	// counter, so the stream is the same on every platform.
+	want := sha256.Sum256(append([]byte("CATALOG-ASSIGNMENT deterministic split"+"seed"), make([]byte, 8)...))
+	if !bytes.Equal(whole[:32], want[:]) {
+		t.Errorf("first block = %x, want %x", whole[:32], want)
+	}
+}
+
+func TestShareSetConflicts(t *testing.T) {
+	base := func() *share.Shares {
+		return &share.Shares{N: 3, K: 2, Expected: "3", SecretSHA256: "aa", Points: []share.Point{{X: big.NewInt(1), Y: big.NewInt(4)}}}
+	}
+	for _, tc := range []struct {
+		name   string
+		change func(*share.Shares)
+		kind   share.ConflictKind
+	}{
+		{"k", func(s *share.Shares) { s.K = 3 }, share.ConflictThreshold},
+		{"n", func(s *share.Shares) { s.N = 4 }, share.ConflictCount},
+		{"modulus", func(s *share.Shares) { s.Modulus = big.NewInt(101) }, share.ConflictModulus},
+		{"y", func(s *share.Shares) { s.Points[0].Y = big.NewInt(5) }, share.ConflictY},
+		{"expected", func(s *share.Shares) { s.Expected = "4" }, share.ConflictExpected},
+		{"secret hash", func(s *share.Shares) { s.SecretSHA256 = "bb" }, share.ConflictSecretHash},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			first, second := base(), base()
+			if tc.kind == share.ConflictModulus {
+				first.Modulus = big.NewInt(103)
+			}
+			tc.change(second)
+			set := share.NewShareSet()
+			set.AddFrom("a.json", first)
+			set.AddFrom("b.json", second)
+			if len(set.Conflicts) != 1 {
+				t.Fatalf("conflicts = %+v, want one", set.Conflicts)
+			}
+			c := set.Conflicts[0]
+			if c.Kind != tc.kind || c.Sources != [2]string{"a.json", "b.json"} {
+				t.Errorf("conflict = %+v, want a %s conflict between a.json and b.json", c, tc.kind)
+			}
+			if _, err := set.Shares(); err == nil || err.Error() != c.Message {
+				t.Errorf("Shares: err = %v, want %q", err, c.Message)
+			}
+		})
+	}
+
+	// A share both sources give alike is kept once, from the first.
+	set := share.NewShareSet()
+	set.AddFrom("a.json", base())
+	second := base()
+	second.Points = append(second.Points, share.Point{X: big.NewInt(2), Y: big.NewInt(7)})
+	set.AddFrom("b.json", second)
+	merged, err := set.Shares()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(merged.Points) != 2 || merged.Points[0].Source != "a.json" || merged.Points[1].Source != "b.json" {
+		t.Errorf("merged = %+v, want x=1 from a.json and x=2 from b.json", merged.Points)
+	}
+	if want := []share.Duplicate{{X: "1", Source: "b.json", First: "a.json"}}; !slices.Equal(set.Duplicates, want) {
+		t.Errorf("duplicates = %+v, want %+v", set.Duplicates, want)
+	}
+	if _, err := share.NewShareSet().Shares(); err == nil {
+		t.Error("an empty set returned shares")
+	}
+}
+
+func TestParseWithProgress(t *testing.T) {
+	var calls [][2]int
+	_, err := share.ParseShares(open(t, "../../testcase1.json"), share.WithProgress(func(parsed, total int) {
+		calls = append(calls, [2]int{parsed, total})
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := [][2]int{{1, 4}, {2, 4}, {3, 4}, {4, 4}}; !slices.Equal(calls, want) {
+		t.Errorf("progress calls = %v, want %v", calls, want)
+	}
+
+	// Before the keys object is read the total is unknown.
+	calls = nil
+	_, err = share.ParseShares(strings.NewReader(`{"1": {"base": "10", "value": "4"}, "keys": {"n": 1, "k": 1}}`), share.WithProgress(func(parsed, total int) {
+		calls = append(calls, [2]int{parsed, total})
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := [][2]int{{1, 0}}; !slices.Equal(calls, want) {
+		t.Errorf("progress calls = %v, want %v", calls, want)
+	}
+}
+
+func TestParseWithContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	_, err := share.ParseShares(open(t, "../../testcase1.json"), share.WithContext(ctx), share.WithProgress(func(parsed, total int) {
+		if parsed == 2 {
+			cancel()
+		}
+	}))
+	if !errors.Is(err, context.Canceled) || !strings.Contains(err.Error(), "after 2 shares") {
+		t.Errorf("err = %v, want one stopping after 2 shares", err)
+	}
+}
+
+func TestParseBOM(t *testing.T) {
+	const bom = "\xef\xbb\xbf"
+	yaml, err := os.ReadFile("../../testcase1.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	toml, err := os.ReadFile("../../testcase1.toml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, parse := range map[string]func() (*share.Shares, error){
+		"yaml": func() (*share.Shares, error) { return share.ParseYAML(strings.NewReader(bom + string(yaml))) },
+		"toml": func() (*share.Shares, error) { return share.ParseTOML(strings.NewReader(bom + string(toml))) },
+	} {
+		s, err := parse()
+		if err != nil {
+			t.Errorf("%s with a byte-order mark: %v", name, err)
+			continue
+		}
+		checkTestcase1(t, s)
+	}
+
+	_, err = share.ParseShares(strings.NewReader(bom + `{"keys": {"n": 1, "k": 1},}`))
+	if err == nil || !strings.Contains(err.Error(), "after removing a leading UTF-8 byte-order mark") {
+		t.Errorf("syntax error after a byte-order mark: err = %v, want a note about the mark", err)
+	}
+	for _, mark := range []string{"\xff\xfe", "\xfe\xff"} {
+		for name, parse := range map[string]func(io.Reader) error{
+			"json": func(r io.Reader) error { _, err := share.ParseShares(r); return err },
+			"csv":  func(r io.Reader) error { _, err := share.ParseCSV(r, 0); return err },
+		} {
+			if err := parse(strings.NewReader(mark + "{}")); err == nil || !strings.Contains(err.Error(), "UTF-16") {
+				t.Errorf("%s starting with %x: err = %v, want UTF-16 rejected", name, mark, err)
+			}
+		}
+	}
+}
+
+func TestLenient(t *testing.T) {
+	document := "{\"keys\": {\"n\": 2, \"k\": 2}, /* a /* nested */ comment */\n" +
+		"  \"1\": {\"base\": \"10\", \"value\": \"4\", \"note\": \"a // b /* c */\",}, // x=1\n" +
+		"  \"2\": {\"base\": \"10\", \"value\": \"7\"},\n" +
+		"}\n"
+	if _, err := share.ParseShares(strings.NewReader(document)); err == nil {
+		t.Error("comments and trailing commas parsed without Lenient")
+	}
+	s, err := share.ParseShares(strings.NewReader(document), share.Lenient())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(s.Points) != 2 || s.Points[0].Y.Int64() != 4 || s.Points[1].Y.Int64() != 7 {
+		t.Errorf("points = %+v, want (1, 4) and (2, 7)", s.Points)
+	}
+
+	for _, tc := range []struct {
+		document string
+		want     string
+	}{
+		// The line and column are those of the original text.
+		{"{\"keys\": {\"n\": 1, \"k\": 1}, // comment\n  \"1\": {\"base\": \"10\" \"value\": \"4\"}}", "line 2, column 22"},
+		{"{\"keys\": {\"n\": 1, \"k\": 1}\n/* never closed", "line 2, column 1: failed to unmarshal raw json: unterminated /* comment"},
+		{"{\"keys\": {\"n\": 1, \"k\": 1}, /* comment */\n  \"1\": {", "line 2, column 9"},
+	} {
+		if _, err := share.ParseShares(strings.NewReader(tc.document), share.Lenient()); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%q: err = %v, want %q", tc.document, err, tc.want)
+		}
+	}
+}
+
+func TestParseLabels(t *testing.T) {
+	s, err := share.ParseShares(open(t, "../../testcase1_labeled.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p := s.Points[0]; p.Label != "alice" || p.Name() != "x=1 (alice)" {
+		t.Errorf("first share has label %q and name %q, want alice and x=1 (alice)", p.Label, p.Name())
+	}
+
+	for _, tc := range []struct {
+		document string
+		want     string
+	}{
+		{`{"keys": {"n": 2, "k": 2}, "shares": {"alice": {"x": 1, "base": "10", "value": "4"}, "bob": {"x": 1, "base": "10", "value": "7"}}}`, `duplicate x=1 (entries "alice" and "bob")`},
+		{`{"keys": {"n": 1, "k": 1}, "1": {"x": 2, "base": "10", "value": "4"}}`, `share "1" has x=2, which disagrees with its key`},
+		{`{"keys": {"n": 1, "k": 1}, "shares": {"alice": {"base": "10", "value": "4"}}}`, "invalid x value"},
+	} {
+		if _, err := share.ParseShares(strings.NewReader(tc.document)); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: err = %v, want %q", tc.document, err, tc.want)
+		}
+	}
+	// A top-level entry may repeat the x of its key.
+	if _, err := parseValue(`{"x": 1, "base": "10", "value": "4"}`); err != nil {
+		t.Errorf("an x agreeing with its key: %v", err)
+	}
+
+	// Conflicts name the shares by their labels.
+	set := share.NewShareSet()
+	set.AddFrom("a.json", &share.Shares{N: 2, K: 2, Points: []share.Point{{X: big.NewInt(1), Y: big.NewInt(4), Label: "alice"}}})
+	set.AddFrom("b.json", &share.Shares{N: 2, K: 2, Points: []share.Point{{X: big.NewInt(1), Y: big.NewInt(5), Label: "carol"}}})
+	if len(set.Conflicts) != 1 || !strings.Contains(set.Conflicts[0].Message, "x=1 (alice and carol)") {
+		t.Errorf("conflicts = %+v, want one naming alice and carol", set.Conflicts)
+	}
+}
+
+func TestParseParticipants(t *testing.T) {
+	participants, err := share.ParseParticipants(strings.NewReader(`[{"name": "alice", "weight": 2}, {"name": "bob"}]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []share.Participant{{Name: "alice", Weight: 2}, {Name: "bob", Weight: 1}}; !slices.Equal(participants, want) {
+		t.Errorf("participants = %+v, want %+v", participants, want)
+	}
+	if total := share.TotalWeight(participants); total != 3 {
+		t.Errorf("TotalWeight = %d, want 3", total)
+	}
+	for _, tc := range []struct {
+		list string
+		want string
+	}{
+		{`[]`, "the list is empty"},
+		{`[{"name": " "}]`, "every participant needs a name"},
+		{`[{"name": "alice"}, {"name": "alice"}]`, `"alice" is listed twice`},
+		{`[{"name": "alice", "weight": 0}]`, `"alice" has weight 0`},
+		{`[{"name": "alice", "wieght": 2}]`, "unknown field"},
+	} {
+		if _, err := share.ParseParticipants(strings.NewReader(tc.list)); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: err = %v, want %q", tc.list, err, tc.want)
+		}
+	}
+}
+
+func TestParticipantCoverage(t *testing.T) {
+	s, err := share.Split(big.NewInt(42), 4, 3, nil, share.WithCommitments(""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	participants := []share.Participant{{Name: "alice", Weight: 2}, {Name: "bob", Weight: 1}, {Name: "carol", Weight: 1}}
+	if err := share.AssignParticipants(s, participants[:2]); err == nil {
+		t.Error("assigning weight 3 to 4 shares returned no error")
+	}
+	if err := share.AssignParticipants(s, participants); err != nil {
+		t.Fatal(err)
+	}
+
+	// The file of alice holds her two shares and names every participant.
+	var buf bytes.Buffer
+	if err := share.WriteParticipant(&buf, s, "alice", []string{"10"}); err != nil {
+		t.Fatal(err)
+	}
+	alice, err := share.ParseShares(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(alice.Points) != 2 || alice.Points[0].Participant != "alice" {
+		t.Fatalf("alice's file holds %+v, want her two shares", alice.Points)
+	}
+	coverage := alice.Coverage()
+	if coverage == nil || coverage.Weight != 2 || coverage.Needed != 3 {
+		t.Fatalf("coverage = %+v, want weight 2 of 3", coverage)
+	}
+	if want := "alice (weight 2) holds weight 2 of the 3 needed; missing bob (weight 1), carol (weight 1)"; coverage.String() != want {
+		t.Errorf("coverage = %q, want %q", coverage, want)
+	}
+	if err := share.WriteParticipant(&buf, s, "dave", []string{"10"}); err == nil {
+		t.Error("writing the file of an unknown participant returned no error")
+	}
+
+	if (&share.Shares{}).Coverage() != nil {
+		t.Error("shares without participants have a coverage")
+	}
+	if got := share.NewCoverage(participants, nil, 3).String(); got != "no participant holds any of the 3 needed; missing alice (weight 2), bob (weight 1), carol (weight 1)" {
+		t.Errorf("empty coverage = %q", got)
+	}
+}
+
+func TestAutoModulus(t *testing.T) {
+	for _, tc := range []struct {
+		secret *big.Int
+		bits   int
+	}{
+		{big.NewInt(0), 64},
+		{new(big.Int).Lsh(big.NewInt(1), 62), 64},
+		{new(big.Int).Lsh(big.NewInt(1), 63), 128},
+		{new(big.Int).Lsh(big.NewInt(1), 200), 256},
+	} {
+		p, err := share.AutoModulus(tc.secret)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p.BitLen() != tc.bits || !p.ProbablyPrime(20) || p.Cmp(tc.secret) <= 0 {
+			t.Errorf("AutoModulus(2^%d) = %s, want a %d-bit prime above the secret", tc.secret.BitLen()-1, p, tc.bits)
+		}
+	}
+	// The smallest 64-bit prime, as testcase_mod_auto.json records.
+	if p, _ := share.AutoModulus(big.NewInt(123456789)); p.String() != "9223372036854775837" {
+		t.Errorf("AutoModulus(123456789) = %s, want 9223372036854775837", p)
+	}
+	if _, err := share.AutoModulus(big.NewInt(-1)); err == nil {
+		t.Error("AutoModulus of a negative secret returned no error")
+	}
+
+	s, err := share.ParseShares(open(t, "../../testcase_mod_auto.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Modulus == nil || s.Modulus.String() != "9223372036854775837" {
+		t.Errorf("recorded modulus = %v, want 9223372036854775837", s.Modulus)
+	}
+	for _, modulus := range []string{`"1"`, `"abc"`, `"-7"`} {
+		document := `{"keys": {"n": 1, "k": 1, "modulus": ` + modulus + `}, "1": {"base": "10", "value": "4"}}`
+		if _, err := share.ParseShares(strings.NewReader(document)); err == nil || !strings.Contains(err.Error(), `invalid "modulus"`) {
+			t.Errorf("modulus %s: err = %v, want it rejected", modulus, err)
+		}
+	}
+}
+
+func TestFieldPresets(t *testing.T) {
+	bits := map[string]int{"secp256k1": 256, "p256": 256, "ed25519": 253, "mersenne127": 127}
+	presets := share.FieldPresets()
+	if len(presets) != len(bits) {
+		t.Fatalf("%d presets, want %d", len(presets), len(bits))
+	}
+	for _, p := range presets {
+		if p.Modulus.BitLen() != bits[p.Name] || !p.Modulus.ProbablyPrime(20) {
+			t.Errorf("%s has modulus %s, want a %d-bit prime", p.Name, p.Modulus, bits[p.Name])
+		}
+		if got := share.LookupField(p.Name); got == nil || got.Modulus.Cmp(p.Modulus) != 0 {
+			t.Errorf("LookupField(%s) = %v", p.Name, got)
+		}
+
+		s, err := share.ParseShares(open(t, "../../testcase_field_"+p.Name+".json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s.Field != p.Name || s.Modulus.Cmp(p.Modulus) != 0 {
+			t.Errorf("testcase_field_%s.json has field %q and modulus %s", p.Name, s.Field, s.Modulus)
+		}
+	}
+	if share.LookupField("p384") != nil {
+		t.Error("LookupField of an unknown name returned a preset")
+	}
+
+	// A named field implies its modulus, which a recorded one must match.
+	s, err := share.ParseShares(strings.NewReader(`{"keys": {"n": 1, "k": 1, "field": "mersenne127"}, "1": {"base": "10", "value": "4"}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Modulus.String() != "170141183460469231731687303715884105727" {
+		t.Errorf("modulus = %s, want 2^127 - 1", s.Modulus)
+	}
+	_, err = share.ParseShares(strings.NewReader(`{"keys": {"n": 1, "k": 1, "field": "mersenne127", "modulus": "101"}, "1": {"base": "10", "value": "4"}}`))
+	if err == nil || !strings.Contains(err.Error(), `but its "field" mersenne127 has modulus`) {
+		t.Errorf("disagreeing modulus: err = %v", err)
+	}
+}
+
+func TestAdd(t *testing.T) {
+	parse := func(path string) *share.Shares {
+		t.Helper()
+		s, err := share.ParseShares(open(t, "../../"+path))
+		if err != nil {
+			t.Fatal(err)
+		}
+		s.Source = path
+		return s
+	}
+	a, b, want := parse("testcase_add_a.json"), parse("testcase_add_b.json"), parse("testcase_add_sum.json")
+	sum, err := share.Add(a, b, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sum.N != 5 || sum.K != 3 || sum.Modulus.Cmp(a.Modulus) != 0 || len(sum.Points) != len(want.Points) {
+		t.Fatalf("sum has n=%d k=%d modulus %s and %d shares", sum.N, sum.K, sum.Modulus, len(sum.Points))
+	}
+	for i, p := range sum.Points {
+		if p.X.Cmp(want.Points[i].X) != 0 || p.Y.Cmp(want.Points[i].Y) != 0 {
+			t.Errorf("share %d = (%s, %s), want (%s, %s)", i, p.X, p.Y, want.Points[i].X, want.Points[i].Y)
+		}
+	}
+	if sum.Commitments != nil || sum.SecretSHA256 != "" {
+		t.Error("the sum carries the commitments or secret hash of an input")
+	}
+
+	// Over the integers the sum of f and g is exact; over GF(2^m) it is xor.
+	ints, err := share.Add(
+		&share.Shares{N: 2, K: 2, Points: testcase1Points()[:2]},
+		&share.Shares{N: 2, K: 2, Points: []share.Point{{X: big.NewInt(1), Y: big.NewInt(10)}, {X: big.NewInt(2), Y: big.NewInt(20)}}},
+		nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ints.Points[0].Y.Int64() != 14 || ints.Points[1].Y.Int64() != 27 {
+		t.Errorf("integer sum = %+v, want y=14 and 27", ints.Points)
+	}
+	poly := big.NewInt(0x11b)
+	xor, err := share.Add(
+		&share.Shares{K: 1, Field: share.FieldGF2m, Poly: poly, Points: []share.Point{{X: big.NewInt(1), Y: big.NewInt(0b1100)}}},
+		&share.Shares{K: 1, Field: share.FieldGF2m, Poly: poly, Points: []share.Point{{X: big.NewInt(1), Y: big.NewInt(0b1010)}}},
+		nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if xor.Points[0].Y.Int64() != 0b0110 {
+		t.Errorf("GF(2^m) sum = %s, want 6", xor.Points[0].Y)
+	}
+}
+
+func TestAddConflicts(t *testing.T) {
+	points := func(xs ...int64) []share.Point {
+		ps := make([]share.Point, len(xs))
+		for i, x := range xs {
+			ps[i] = share.Point{X: big.NewInt(x), Y: big.NewInt(x)}
+		}
+		return ps
+	}
+	for _, tc := range []struct {
+		name    string
+		b       share.Shares
+		modulus *big.Int
+		kinds   []share.ConflictKind
+	}{
+		{"k", share.Shares{N: 3, K: 3, Points: points(1, 2, 3)}, nil, []share.ConflictKind{share.ConflictThreshold}},
+		{"n", share.Shares{N: 4, K: 2, Points: points(1, 2, 3)}, nil, []share.ConflictKind{share.ConflictCount}},
+		{"x", share.Shares{N: 3, K: 2, Points: points(1, 2, 4)}, nil, []share.ConflictKind{share.ConflictX, share.ConflictX}},
+		{"recorded modulus", share.Shares{N: 3, K: 2, Modulus: big.NewInt(101), Points: points(1, 2, 3)}, nil, []share.ConflictKind{share.ConflictModulus}},
+		{"binary field", share.Shares{N: 3, K: 2, Field: share.FieldGF2m, Poly: big.NewInt(0x11b), Points: points(1, 2, 3)}, nil, []share.ConflictKind{share.ConflictModulus}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			a := &share.Shares{N: 3, K: 2, Source: "a.json", Points: points(1, 2, 3)}
+			b := tc.b
+			b.Source = "b.json"
+			_, err := share.Add(a, &b, tc.modulus)
+			var conflict *share.ConflictError
+			if !errors.As(err, &conflict) {
+				t.Fatalf("err = %v, want a *ConflictError", err)
+			}
+			kinds := make([]share.ConflictKind, len(conflict.Conflicts))
+			for i, c := range conflict.Conflicts {
+				kinds[i] = c.Kind
+			}
+			if !slices.Equal(kinds, tc.kinds) {
+				t.Errorf("conflicts = %+v, want kinds %v", conflict.Conflicts, tc.kinds)
+			}
+		})
+	}
+}
+
+func TestFileShare(t *testing.T) {
+	f, err := os.Open("../../testcase_file_1.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	r, err := share.NewFileShareReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.N != 3 || r.K != 2 || r.X != 1 || r.ChunkSize != 16 || r.Base != "16" || !r.Deterministic {
+		t.Errorf("keys = %+v", r)
+	}
+	var size int
+	for {
+		y, err := r.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		size += len(y)
+	}
+	want, err := os.ReadFile("../../testcase_file.bin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sum := sha256.Sum256(want); r.Size != int64(len(want)) || size != len(want) || r.SHA256 != hex.EncodeToString(sum[:]) {
+		t.Errorf("trailer records %d bytes with SHA-256 %s over %d bytes of chunks", r.Size, r.SHA256, size)
+	}
+	if _, err := r.Next(); err != io.EOF {
+		t.Errorf("Next after the trailer: err = %v, want io.EOF", err)
+	}
+}
+
+func TestFileShareRoundTrip(t *testing.T) {
+	for _, base := range []string{"16", "64", "64url", "85"} {
+		for _, chunks := range [][][]byte{nil, {{0, 0, 1}}, {{1, 2, 3, 4}, {5, 6}}} {
+			var buf bytes.Buffer
+			w, err := share.NewFileShareWriter(&buf, 3, 2, 2, 4, base, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, c := range chunks {
+				if err := w.WriteChunk(c); err != nil {
+					t.Fatal(err)
+				}
+			}
+			sum := strings.Repeat("ab", 32)
+			if err := w.Close(6, sum); err != nil {
+				t.Fatal(err)
+			}
+			if !share.IsFileShare(bytes.NewReader(buf.Bytes())) {
+				t.Fatalf("IsFileShare of %s is false", buf.Bytes())
+			}
+			r, err := share.NewFileShareReader(&buf)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got [][]byte
+			for {
+				y, err := r.Next()
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					t.Fatalf("base %s: %v", base, err)
+				}
+				got = append(got, y)
+			}
+			if !slices.EqualFunc(got, chunks, bytes.Equal) || r.Size != 6 || r.SHA256 != sum {
+				t.Errorf("base %s: read chunks %x of %d bytes with SHA-256 %s, want %x", base, got, r.Size, r.SHA256, chunks)
+			}
+		}
+	}
+	if share.IsFileShare(strings.NewReader(`{"keys": {"n": 1, "k": 1}, "1": {"base": "10", "value": "1"}}`)) {
+		t.Error("IsFileShare of a share file is true")
+	}
+	if _, err := share.NewFileShareWriter(io.Discard, 3, 2, 1, 4, "10", false); err == nil {
+		t.Error("NewFileShareWriter accepted base 10")
+	}
+}
+
+func TestFileShareReaderErrors(t *testing.T) {
+	const trailer = `], "file": {"size": 1, "sha256": "` + "00000000000000000000000000000000000000000000000000000000000000ff" + `"}}`
+	for _, tc := range []struct {
+		name, doc, want string
+	}{
+		{"field", `{"keys": {"n": 3, "k": 2, "x": 1, "field": "integer", "chunk_size": 4, "base": "16"}, "chunks": [` + trailer, `"field" "integer"`},
+		{"threshold", `{"keys": {"n": 1, "k": 2, "x": 1, "field": "gf256", "chunk_size": 4, "base": "16"}, "chunks": [` + trailer, "1 <= k <= n <= 255"},
+		{"x", `{"keys": {"n": 3, "k": 2, "x": 0, "field": "gf256", "chunk_size": 4, "base": "16"}, "chunks": [` + trailer, `invalid "x"`},
+		{"missing chunk size", `{"keys": {"n": 3, "k": 2, "x": 1, "field": "gf256", "base": "16"}, "chunks": [` + trailer, `missing "chunk_size"`},
+		{"long chunk", `{"keys": {"n": 3, "k": 2, "x": 1, "field": "gf256", "chunk_size": 1, "base": "16"}, "chunks": ["0102"` + trailer, "more than the chunk size"},
+		{"bad chunk", `{"keys": {"n": 3, "k": 2, "x": 1, "field": "gf256", "chunk_size": 4, "base": "16"}, "chunks": ["xyz"` + trailer, "invalid chunk 0"},
+		{"bad hash", `{"keys": {"n": 3, "k": 2, "x": 1, "field": "gf256", "chunk_size": 4, "base": "16"}, "chunks": [], "file": {"size": 1, "sha256": "ff"}}`, `invalid "sha256"`},
+		{"negative size", `{"keys": {"n": 3, "k": 2, "x": 1, "field": "gf256", "chunk_size": 4, "base": "16"}, "chunks": [], "file": {"size": -1, "sha256": "ff"}}`, "non-negative"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			r, err := share.NewFileShareReader(strings.NewReader(tc.doc))
+			for err == nil {
+				_, err = r.Next()
+			}
+			if err == io.EOF || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("err = %v, want one saying %q", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestScale(t *testing.T) {
+	secret, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+	modulus := share.LookupField("mersenne127").Modulus
+	s, err := share.Split(secret, 5, 3, modulus, share.WithCommitments(""), share.WithSeed([]byte("scale")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range []int64{7, -1, 0} {
+		scaled, err := share.Scale(s, big.NewInt(c), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if scaled.N != 5 || scaled.K != 3 || scaled.SecretSHA256 != "" || !scaled.Deterministic {
+			t.Errorf("scaled by %d: keys n=%d k=%d secret hash %q", c, scaled.N, scaled.K, scaled.SecretSHA256)
+		}
+		got, err := lagrange.Interpolate(scaled.Points[2:], lagrange.WithModulus(modulus))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := new(big.Int).Mul(secret, big.NewInt(c))
+		if want.Mod(want, modulus); got.Cmp(want) != 0 {
+			t.Errorf("scaled by %d: secret = %s, want %s", c, got, want)
+		}
+	}
+
+	// Over the integers a negative scalar gives shares of a negative secret.
+	scaled, err := share.Scale(&share.Shares{N: 4, K: 3, Points: testcase1Points()}, big.NewInt(-7), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := lagrange.Interpolate(scaled.Points); err != nil || got.Int64() != -21 {
+		t.Errorf("testcase1 scaled by -7 = %v, %v, want -21", got, err)
+	}
+
+	if _, err := share.Scale(s, big.NewInt(7), big.NewInt(101)); err == nil || !strings.Contains(err.Error(), "modulus mismatch") {
+		t.Errorf("scaling with a disagreeing modulus: err = %v", err)
+	}
+	if _, err := share.Scale(&share.Shares{K: 1, Field: share.FieldGF2m, Poly: big.NewInt(0x11b)}, big.NewInt(7), nil); err == nil {
+		t.Error("scaling shares over GF(2^m) passed")
+	}
+}
+
+func TestScaleCommitments(t *testing.T) {
+	if testing.Short() {
+		t.Skip("generating the commitment group takes seconds")
+	}
+	modulus := share.LookupField("mersenne127").Modulus
+	for _, scheme := range []string{share.SchemeFeldman, share.SchemePedersen} {
+		t.Run(scheme, func(t *testing.T) {
+			s, err := share.Split(big.NewInt(42), 5, 3, modulus, share.WithCommitments(scheme), share.WithSeed([]byte(scheme)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			scaled, err := share.Scale(s, big.NewInt(-3), nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := scaled.VerifyCommitments(); err != nil {
+				t.Errorf("the scaled shares do not verify: %v", err)
+			}
+		})
+	}
+}
+
+func TestDiff(t *testing.T) {
+	a := &share.Shares{N: 4, K: 3, Points: testcase1Points()}
+	change := func(f func(*share.Shares)) *share.Shares {
+		b := *a
+		b.Points = slices.Clone(a.Points)
+		f(&b)
+		return &b
+	}
+	for _, tc := range []struct {
+		name string
+		b    *share.Shares
+		want []share.Difference
+	}{
+		{"same", change(func(*share.Shares) {}), nil},
+		{"base", change(func(b *share.Shares) { b.Points[1].Base = "16" }),
+			[]share.Difference{{Kind: share.ConflictBase, X: "2", A: "10", B: "16"}}},
+		{"y", change(func(b *share.Shares) { b.Points[1].Y = big.NewInt(8) }),
+			[]share.Difference{{Kind: share.ConflictY, X: "2", A: "7", B: "8", Material: true}}},
+		{"missing share", change(func(b *share.Shares) { b.Points = b.Points[:3] }),
+			[]share.Difference{{Kind: share.ConflictX, X: "6", A: "39", Material: true}}},
+		{"keys", change(func(b *share.Shares) { b.K, b.Modulus = 2, big.NewInt(101) }),
+			[]share.Difference{{Kind: share.ConflictThreshold, A: "3", B: "2", Material: true}, {Kind: share.ConflictModulus, B: "101", Material: true}}},
+		{"metadata", change(func(b *share.Shares) { b.Version, b.Points[0].Label = 2, "alice" }),
+			[]share.Difference{{Kind: share.ConflictLabel, X: "1", B: "alice"}, {Kind: share.ConflictVersion, B: "2"}}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := share.Diff(a, tc.b); !slices.Equal(got, tc.want) {
+				t.Errorf("Diff = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+	if got := share.Diff(&share.Shares{N: 4, K: 3, Points: testcase1Points()[1:]}, a); len(got) != 1 || got[0].Kind != share.ConflictX || got[0].B != "4" || !got[0].Material {
+		t.Errorf("Diff with a share only in b = %+v", got)
+	}
+}
+
+func TestConvert(t *testing.T) {
+	for _, path := range []string{"testcase_checksum.json", "testcase1_labeled.json", "testcase_single_2.json", "testcase_gf2m.json"} {
+		t.Run(path, func(t *testing.T) {
+			s, _, err := share.ParseJSON(open(t, "../../"+path), share.BinaryField())
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, bases := range [][]string{{"16"}, {"7"}, {"85"}, {"2", "36"}, {"10"}} {
+				data, err := share.Convert(s, bases)
+				if err != nil {
+					t.Fatalf("bases %v: %v", bases, err)
+				}
+				converted, _, err := share.ParseJSON(bytes.NewReader(data), share.BinaryField())
+				if err != nil {
+					t.Fatalf("bases %v: %v", bases, err)
+				}
+				if converted.Single != s.Single || converted.Field != s.Field || len(converted.Points) != len(s.Points) {
+					t.Fatalf("bases %v: converted %s to %s", bases, path, data)
+				}
+				for i, p := range converted.Points {
+					if want := bases[i%len(bases)]; p.Base != want || p.X.Cmp(s.Points[i].X) != 0 || p.Y.Cmp(s.Points[i].Y) != 0 || p.Label != s.Points[i].Label {
+						t.Errorf("bases %v: share %d = %+v, want %+v in base %s", bases, i, p, s.Points[i], want)
+					}
+				}
+			}
+		})
+	}
+	s, _, err := share.ParseJSON(open(t, "../../testcase1.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := share.Convert(s, []string{"99"}); err == nil {
+		t.Error("Convert to base 99 passed")
+	}
+}
@@ -0,0 +1,243 @@
+package share
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// ConflictKind says what two sources of shares disagree on.
+type ConflictKind string
+
+const (
+	// ConflictY is a share whose x two sources give different y values.
+	ConflictY ConflictKind = "y"
+	// ConflictThreshold is a difference in k.
+	ConflictThreshold ConflictKind = "k"
+	// ConflictCount is a difference in the n two sources declare.
+	ConflictCount ConflictKind = "n"
+	// ConflictModulus is different field moduli, recorded or implied by
+	// commitments.
+	ConflictModulus ConflictKind = "modulus"
+	// ConflictCommitments is different commitments over the same field.
+	ConflictCommitments ConflictKind = "commitments"
+	// ConflictExpected is a difference in the expected secret recorded.
+	ConflictExpected ConflictKind = "expected"
+	// ConflictParticipants is a difference in the participants listed.
+	ConflictParticipants ConflictKind = "participants"
+	// ConflictSecretHash is a difference in the hash of the secret
+	// recorded at split time.
+	ConflictSecretHash ConflictKind = "secret_sha256"
+)
+
+// Conflict is a disagreement between two sources of shares. X is set for
+// a ConflictY.
+type Conflict struct {
+	Kind    ConflictKind `json:"kind"`
+	X       string       `json:"x,omitempty"`
+	Sources [2]string    `json:"sources"`
+	Message string       `json:"message"`
+}
+
+// Duplicate is a share given by a later source with the same y as the one
+// kept from an earlier source.
+type Duplicate struct {
+	X      string
+	Source string
+	First  string
+}
+
+// ConflictError reports every conflict found combining sources of shares.
+type ConflictError struct {
+	Conflicts []Conflict
+}
+
+func (e *ConflictError) Error() string {
+	if len(e.Conflicts) == 1 {
+		return e.Conflicts[0].Message
+	}
+	messages := make([]string, len(e.Conflicts))
+	for i, c := range e.Conflicts {
+		messages[i] = c.Message
+	}
+	return fmt.Sprintf("%d conflicts between share sources: %s", len(e.Conflicts), strings.Join(messages, "; "))
+}
+
+// ShareSet accumulates the shares of several sources, such as files,
+// environment variables and stdin, keeping one copy of each share and
+// recording where the sources disagree.
+type ShareSet struct {
+	Conflicts  []Conflict
+	Duplicates []Duplicate
+
+	merged  *Shares
+	seen    map[string]Point
+	sources map[string]string
+}
+
+// NewShareSet returns an empty ShareSet.
+func NewShareSet() *ShareSet {
+	return &ShareSet{seen: make(map[string]Point), sources: make(map[string]string)}
+}
+
+// AddFrom adds the shares read from source. A source that disagrees with
+// the earlier ones on k, n or the field of its commitments is recorded as
+// a conflict and none of its shares are added.
+func (s *ShareSet) AddFrom(source string, shares *Shares) {
+	if s.merged == nil {
+		s.merged = &Shares{N: shares.N, K: shares.K, Source: source, Checksummed: true}
+		s.sources["k"], s.sources["n"] = source, source
+	}
+	m := s.merged
+
+	before := len(s.Conflicts)
+	if shares.K != m.K {
+		s.conflict(ConflictThreshold, "", s.sources["k"], source, fmt.Sprintf("threshold mismatch: %s has k=%d but %s has k=%d", s.sources["k"], m.K, source, shares.K))
+	}
+	switch {
+	case shares.N == 0:
+	case m.N == 0:
+		m.N, s.sources["n"] = shares.N, source
+	case shares.N != m.N:
+		s.conflict(ConflictCount, "", s.sources["n"], source, fmt.Sprintf("share count mismatch: %s has n=%d but %s has n=%d", s.sources["n"], m.N, source, shares.N))
+	}
+	if shares.Commitments != nil && m.Commitments != nil && !m.Commitments.Equal(shares.Commitments) {
+		first := s.sources["commitments"]
+		// The group of the commitments is derived from the field modulus.
+		if m.Commitments.P.Cmp(shares.Commitments.P) != 0 {
+			s.conflict(ConflictModulus, "", first, source, fmt.Sprintf("modulus mismatch: the commitments of %s and %s are over different prime fields", first, source))
+		} else {
+			s.conflict(ConflictCommitments, "", first, source, fmt.Sprintf("conflicting commitments in %s and %s", first, source))
+		}
+	}
+	if shares.Modulus != nil && m.Modulus != nil && m.Modulus.Cmp(shares.Modulus) != 0 {
+		first := s.sources["modulus"]
+		s.conflict(ConflictModulus, "", first, source, fmt.Sprintf("modulus mismatch: %s records modulus %s but %s records %s", first, m.Modulus, source, shares.Modulus))
+	}
+	if shares.Poly != nil && m.Poly != nil && m.Poly.Cmp(shares.Poly) != 0 {
+		first := s.sources["poly"]
+		s.conflict(ConflictModulus, "", first, source, fmt.Sprintf("polynomial mismatch: %s records poly %#x but %s records %#x", first, m.Poly, source, shares.Poly))
+	}
+	if shares.Participants != nil && m.Participants != nil && !slices.Equal(shares.Participants, m.Participants) {
+		first := s.sources["participants"]
+		s.conflict(ConflictParticipants, "", first, source, fmt.Sprintf("conflicting participants in %s and %s", first, source))
+	}
+	if len(s.Conflicts) > before {
+		return
+	}
+
+	if shares.Modulus != nil && m.Modulus == nil {
+		m.Modulus, m.Field, s.sources["modulus"] = shares.Modulus, shares.Field, source
+	}
+	if shares.Poly != nil && m.Poly == nil {
+		m.Field, m.Poly, s.sources["poly"] = shares.Field, shares.Poly, source
+	}
+	if shares.Participants != nil && m.Participants == nil {
+		m.Participants, s.sources["participants"] = shares.Participants, source
+	}
+
+	if shares.Commitments != nil && m.Commitments == nil {
+		m.Commitments, s.sources["commitments"] = shares.Commitments, source
+	}
+	if shares.Expected != "" {
+		if m.Expected != "" && m.Expected != shares.Expected {
+			s.conflict(ConflictExpected, "", s.sources["expected"], source, fmt.Sprintf("conflicting expected values in %s and %s", s.sources["expected"], source))
+		} else if m.Expected == "" {
+			m.Expected, s.sources["expected"] = shares.Expected, source
+		}
+	}
+	if shares.SecretSHA256 != "" {
+		if m.SecretSHA256 != "" && m.SecretSHA256 != shares.SecretSHA256 {
+			s.conflict(ConflictSecretHash, "", s.sources["secret_sha256"], source, fmt.Sprintf("conflicting secret hashes in %s and %s", s.sources["secret_sha256"], source))
+		} else if m.SecretSHA256 == "" {
+			m.SecretSHA256, s.sources["secret_sha256"] = shares.SecretSHA256, source
+		}
+	}
+	m.Deterministic = m.Deterministic || shares.Deterministic
+	m.Checksummed = m.Checksummed && shares.Checksummed
+
+	for _, point := range shares.Points {
+		point.Source = source
+		key := point.X.String()
+		if prev, ok := s.seen[key]; ok {
+			if prev.Y.Cmp(point.Y) != 0 {
+				s.conflict(ConflictY, key, prev.Source, source, fmt.Sprintf("conflicting shares for %s in %s and %s", labelled(prev, point), prev.Source, source))
+			} else {
+				s.Duplicates = append(s.Duplicates, Duplicate{X: key, Source: source, First: prev.Source})
+			}
+			continue
+		}
+		s.seen[key] = point
+		m.Points = append(m.Points, point)
+	}
+}
+
+// Add adds the shares read from source as AddFrom does, unless they
+// conflict with the shares added before. Then the set is left as it was
+// and the conflicts are returned, so that the source can be read again
+// later.
+func (s *ShareSet) Add(source string, shares *Shares) []Conflict {
+	trial := s.clone()
+	trial.AddFrom(source, shares)
+	if conflicts := trial.Conflicts[len(s.Conflicts):]; len(conflicts) > 0 {
+		return conflicts
+	}
+	*s = *trial
+	return nil
+}
+
+// Len returns the number of distinct shares added.
+func (s *ShareSet) Len() int {
+	if s.merged == nil {
+		return 0
+	}
+	return len(s.merged.Points)
+}
+
+func (s *ShareSet) clone() *ShareSet {
+	c := &ShareSet{
+		Conflicts:  slices.Clone(s.Conflicts),
+		Duplicates: slices.Clone(s.Duplicates),
+		seen:       maps.Clone(s.seen),
+		sources:    maps.Clone(s.sources),
+	}
+	if s.merged != nil {
+		merged := *s.merged
+		merged.Points = slices.Clone(s.merged.Points)
+		c.merged = &merged
+	}
+	return c
+}
+
+func (s *ShareSet) conflict(kind ConflictKind, x, first, second, message string) {
+	s.Conflicts = append(s.Conflicts, Conflict{Kind: kind, X: x, Sources: [2]string{first, second}, Message: message})
+}
+
+// Shares returns the combined shares sorted by x, each with the source it
+// was kept from and Checksummed if every source was, or a *ConflictError if any sources disagreed.
+func (s *ShareSet) Shares() (*Shares, error) {
+	if len(s.Conflicts) > 0 {
+		return nil, &ConflictError{Conflicts: s.Conflicts}
+	}
+	if s.merged == nil {
+		return nil, errors.New("no share documents to merge")
+	}
+	points := s.merged.Points
+	sort.Slice(points, func(a, b int) bool { return points[a].X.Cmp(points[b].X) < 0 })
+	return s.merged, nil
+}
+
+// labelled names the share two sources give for the same x, with the
+// labels they give it.
+func labelled(first, second Point) string {
+	switch {
+	case first.Label == second.Label || second.Label == "":
+		return first.Name()
+	case first.Label == "":
+		return second.Name()
+	}
+	return fmt.Sprintf("x=%s (%s and %s)", first.X, first.Label, second.Label)
+}
@@ -0,0 +1,177 @@
+package share
+
+import (
+	"crypto/rand"
+	"fmt"
+	"io"
+	"math/big"
+)
+
+// SplitOption configures Split.
+type SplitOption func(*splitConfig)
+
+type splitConfig struct {
+	scheme        string
+	random        io.Reader
+	deterministic bool
+	secretHash    bool
+	secretHashSet bool
+}
+
+// WithCommitments selects the commitments modular splits carry:
+// SchemeFeldman, the default, SchemePedersen, or "" for none.
+func WithCommitments(scheme string) SplitOption {
+	return func(c *splitConfig) {
+		c.scheme = scheme
+	}
+}
+
+// WithSeed makes Split draw its coefficients from SeededReader(seed)
+// instead of crypto/rand, and mark the shares Deterministic. The same seed
+// and arguments always give the same shares, so a seeded split must never
+// protect a real secret.
+func WithSeed(seed []byte) SplitOption {
+	return func(c *splitConfig) {
+		c.random = SeededReader(seed)
+		c.deterministic = true
+	}
+}
+
+// WithSecretHash controls whether the shares record SecretSHA256 of the
+// secret, so that reconstruction can confirm it recovered the original.
+// The hash lets a holder of a single share test guesses at a low-entropy
+// secret, as Feldman commitments do. Shares record it by default except
+// with SchemePedersen, whose commitments hide the secret and would be
+// undone by the hash.
+func WithSecretHash(enabled bool) SplitOption {
+	return func(c *splitConfig) {
+		c.secretHash, c.secretHashSet = enabled, true
+	}
+}
+
+// Split generates n shares of secret with threshold k from a random
+// polynomial of degree k-1. When modulus is non-nil the polynomial is taken
+// over the integers modulo that prime and the secret must lie in [0, modulus),
+// and the shares carry commitments to its coefficients if the modulus is
+// prime.
+func Split(secret *big.Int, n, k int, modulus *big.Int, opts ...SplitOption) (*Shares, error) {
+	c := &splitConfig{scheme: SchemeFeldman, random: rand.Reader}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if !c.secretHashSet {
+		c.secretHash = c.scheme != SchemePedersen
+	}
+	switch c.scheme {
+	case "", SchemeFeldman, SchemePedersen:
+	default:
+		return nil, fmt.Errorf("unknown commitment scheme %q", c.scheme)
+	}
+
+	if k < 1 {
+		return nil, fmt.Errorf("invalid k=%d: must be at least 1", k)
+	}
+	if n < k {
+		return nil, fmt.Errorf("invalid n=%d: must be at least k=%d", n, k)
+	}
+	if modulus != nil && (secret.Sign() < 0 || secret.Cmp(modulus) >= 0) {
+		return nil, fmt.Errorf("secret must be in the range [0, %s) in modular mode", modulus.String())
+	}
+
+	bits := secret.BitLen()
+	if bits < 64 {
+		bits = 64
+	}
+
+	coefficients := make([]*big.Int, k)
+	coefficients[0] = new(big.Int).Set(secret)
+	for i := 1; i < k; i++ {
+		coefficient, err := randomCoefficient(c.random, modulus, bits)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate coefficient: %w", err)
+		}
+		coefficients[i] = coefficient
+	}
+
+	points := make([]Point, 0, n)
+	for i := 1; i <= n; i++ {
+		x := big.NewInt(int64(i))
+		points = append(points, Point{X: x, Y: evaluatePolynomial(coefficients, x, modulus)})
+	}
+	shares := &Shares{N: n, K: k, Points: points, Deterministic: c.deterministic}
+	if modulus != nil {
+		shares.Modulus = new(big.Int).Set(modulus)
+	}
+	if c.secretHash {
+		shares.SecretSHA256 = SecretSHA256(secret)
+	}
+	if modulus == nil || c.scheme == "" || !modulus.ProbablyPrime(20) {
+		return shares, nil
+	}
+
+	if c.scheme == SchemeFeldman {
+		commitments, err := Commit(coefficients, modulus)
+		if err != nil {
+			return nil, err
+		}
+		shares.Commitments = commitments
+		return shares, nil
+	}
+
+	blinding := make([]*big.Int, k)
+	for i := range blinding {
+		b, err := randomCoefficient(c.random, modulus, bits)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate blinding coefficient: %w", err)
+		}
+		blinding[i] = b
+	}
+	commitments, err := CommitPedersen(coefficients, blinding, modulus)
+	if err != nil {
+		return nil, err
+	}
+	for i := range points {
+		points[i].Blinding = evaluatePolynomial(blinding, points[i].X, modulus)
+	}
+	shares.Commitments = commitments
+	return shares, nil
+}
+
+func randomCoefficient(random io.Reader, modulus *big.Int, bits int) (*big.Int, error) {
+	if modulus != nil {
+		return randomBelow(random, modulus)
+	}
+	return randomBelow(random, new(big.Int).Lsh(big.NewInt(1), uint(bits)))
+}
+
+// randomBelow returns a uniform value in [0, max) read from random by
+// rejection sampling. Unlike crypto/rand.Int, how it consumes random is
+// fixed here, so a seeded stream gives the same value on every Go release.
+func randomBelow(random io.Reader, max *big.Int) (*big.Int, error) {
+	bitLen := new(big.Int).Sub(max, big.NewInt(1)).BitLen()
+	buf := make([]byte, (bitLen+7)/8)
+	v := new(big.Int)
+	for {
+		if _, err := io.ReadFull(random, buf); err != nil {
+			return nil, err
+		}
+		if extra := len(buf)*8 - bitLen; extra > 0 {
+			buf[0] &= 0xff >> extra
+		}
+		if v.SetBytes(buf).Cmp(max) < 0 {
+			return v, nil
+		}
+	}
+}
+
+func evaluatePolynomial(coefficients []*big.Int, x *big.Int, modulus *big.Int) *big.Int {
+	y := big.NewInt(0)
+	for i := len(coefficients) - 1; i >= 0; i-- {
+		y.Mul(y, x)
+		y.Add(y, coefficients[i])
+		if modulus != nil {
+			y.Mod(y, modulus)
+		}
+	}
+	return y
+}
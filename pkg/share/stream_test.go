@@ -0,0 +1,135 @@
+package share_test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"runtime"
+	"runtime/metrics"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/OmSingh2003/CATALOG-ASSIGNMENT/pkg/share"
+)
+
+// digits reads n hex digits cycling through 1 to f, so that no value has a
+// leading zero.
+type digits struct{ n, i int }
+
+func (d *digits) Read(p []byte) (int, error) {
+	if d.i == d.n {
+		return 0, io.EOF
+	}
+	p = p[:min(len(p), d.n-d.i)]
+	for j := range p {
+		p[j] = "123456789abcdef"[(d.i+j)%15]
+	}
+	d.i += len(p)
+	return len(p), nil
+}
+
+// syntheticDocument returns a share document of count base-16 shares of size
+// digits each, generated as it is read.
+func syntheticDocument(count, size int) io.Reader {
+	readers := []io.Reader{strings.NewReader(fmt.Sprintf(`{"keys": {"n": %d, "k": %d}`, count, count))}
+	for x := 1; x <= count; x++ {
+		readers = append(readers,
+			strings.NewReader(fmt.Sprintf(`, "%d": {"base": "16", "value": "`, x)),
+			&digits{n: size},
+			strings.NewReader(`"}`))
+	}
+	return io.MultiReader(append(readers, strings.NewReader("}"))...)
+}
+
+func TestParseSyntheticDocument(t *testing.T) {
+	const count, size = 3, 4096
+	s, err := share.ParseShares(syntheticDocument(count, size))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := io.ReadAll(&digits{n: size})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(s.Points) != count {
+		t.Fatalf("%d shares, want %d", len(s.Points), count)
+	}
+	for _, point := range s.Points {
+		if got := point.Y.Text(16); got != string(want) {
+			t.Errorf("share x=%s has %d digits, want the %d generated", point.X, len(got), size)
+		}
+	}
+}
+
+// peakHeap samples the live heap until stop is called and returns the
+// largest size seen.
+func peakHeap() (stop func() uint64) {
+	sample := []metrics.Sample{{Name: "/memory/classes/heap/objects:bytes"}}
+	var (
+		peak uint64
+		wg   sync.WaitGroup
+	)
+	done := make(chan struct{})
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(time.Millisecond)
+		defer ticker.Stop()
+		for {
+			metrics.Read(sample)
+			peak = max(peak, sample[0].Value.Uint64())
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return func() uint64 {
+		close(done)
+		wg.Wait()
+		return peak
+	}
+}
+
+// BenchmarkParseSharesStream parses a ~200MB document of 200 shares of 1MiB
+// digits each. stream hands ParseShares the generated document; readall
+// first reads it into memory, as parsing did before it was streamed. The
+// decoded points take about half the document; the peak-heap-MB of readall
+// exceeds that of stream by the document and the copies ReadAll grows.
+func BenchmarkParseSharesStream(b *testing.B) {
+	const count, size = 200, 1 << 20
+	for _, bc := range []struct {
+		name  string
+		input func() (io.Reader, error)
+	}{
+		{"stream", func() (io.Reader, error) { return syntheticDocument(count, size), nil }},
+		{"readall", func() (io.Reader, error) {
+			data, err := io.ReadAll(syntheticDocument(count, size))
+			return bytes.NewReader(data), err
+		}},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(count * size))
+			var peak uint64
+			for i := 0; i < b.N; i++ {
+				runtime.GC()
+				stop := peakHeap()
+				r, err := bc.input()
+				if err != nil {
+					b.Fatal(err)
+				}
+				s, err := share.ParseShares(r)
+				if err != nil {
+					b.Fatal(err)
+				}
+				peak = max(peak, stop())
+				runtime.KeepAlive(s)
+			}
+			b.ReportMetric(float64(peak)/(1<<20), "peak-heap-MB")
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package share
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/BurntSushi/toml"
+)
+
+type tomlDocument struct {
+	Keys   *tempKeys            `toml:"keys"`
+	Shares map[string]tomlShare `toml:"shares"`
+}
+
+type tomlShare struct {
+	Base  any `toml:"base"`
+	Value any `toml:"value"`
+}
+
+// ParseTOML decodes shares from a TOML document with a [keys] table and one
+// [shares.<x>] table per share.
+func ParseTOML(r io.Reader, opts ...ParseOption) (*Shares, error) {
+	c := newParseConfig(opts)
+	r, bom, err := skipBOM(r)
+	if err != nil {
+		return nil, err
+	}
+	var doc tomlDocument
+	if _, err := toml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal toml%s: %w", bomNote(bom), err)
+	}
+	if doc.Keys == nil {
+		return nil, fmt.Errorf("toml input is missing the [keys] table")
+	}
+	if err := checkKeys(doc.Keys.N, doc.Keys.K); err != nil {
+		return nil, err
+	}
+
+	points := make([]Point, 0, len(doc.Shares))
+	keys := make([]string, 0, len(doc.Shares))
+	for key, entry := range doc.Shares {
+		x, err := parseX(key)
+		if err != nil {
+			return nil, err
+		}
+
+		base, err := tomlScalar(entry.Base)
+		if err != nil {
+			return nil, fmt.Errorf("%w for x=%d: %w", ErrInvalidBase, x, err)
+		}
+		value, err := tomlScalar(entry.Value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for x=%d: %w", x, err)
+		}
+
+		y, err := c.decodeY(key, x, base, value)
+		if err != nil {
+			return nil, err
+		}
+		points = append(points, Point{X: x, Y: y})
+		keys = append(keys, key)
+	}
+	if err := sortPoints(points, keys); err != nil {
+		return nil, err
+	}
+
+	return &Shares{N: doc.Keys.N, K: doc.Keys.K, Points: points}, nil
+}
+
+// tomlScalar renders a TOML string or integer as text.
+func tomlScalar(v any) (string, error) {
+	switch v := v.(type) {
+	case string:
+		return v, nil
+	case int64:
+		return fmt.Sprint(v), nil
+	case nil:
+		return "", fmt.Errorf("missing")
+	default:
+		return "", fmt.Errorf("expected a string or integer, got %T", v)
+	}
+}
@@ -0,0 +1,83 @@
+package share
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ParseVault reads HashiCorp Vault unseal-key shares, one per line, as
+// printed by "vault operator init": the share bytes followed by a final
+// byte holding x, written in base64 or hex. A line may keep Vault's
+// "Unseal Key 1: " label; blank lines and lines starting with # are
+// skipped. Vault does not record the threshold, so K is zero and every
+// share is meant to be used.
+func ParseVault(r io.Reader) (*ByteShares, error) {
+	var points []BytePoint
+	var labels []string
+	r, _, err := skipBOM(r)
+	if err != nil {
+		return nil, err
+	}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1<<20)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		if i := strings.LastIndex(text, ": "); i >= 0 && strings.HasPrefix(text, "Unseal Key") {
+			text = strings.TrimSpace(text[i+2:])
+		}
+
+		label := fmt.Sprintf("line %d", line)
+		data, err := decodeVaultKey(text)
+		if err != nil {
+			return nil, newParseError(label, "", text, err)
+		}
+		if len(data) < 2 {
+			return nil, newParseError(label, "", text, fmt.Errorf("share has %d bytes, need at least 2", len(data)))
+		}
+		x := data[len(data)-1]
+		if x == 0 {
+			return nil, newParseError(label, "", text, fmt.Errorf("share has x=0"))
+		}
+		points = append(points, BytePoint{X: x, Y: data[:len(data)-1]})
+		labels = append(labels, label)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read input: %w", err)
+	}
+	if len(points) == 0 {
+		return nil, fmt.Errorf("input holds no unseal keys")
+	}
+
+	for i, point := range points {
+		if len(point.Y) != len(points[0].Y) {
+			return nil, fmt.Errorf("share on %s has %d bytes, but share on %s has %d", labels[i], len(point.Y), labels[0], len(points[0].Y))
+		}
+		for j := range points[:i] {
+			if points[j].X == point.X {
+				return nil, &DuplicateXError{X: bigByte(point.X), Entries: []string{labels[j], labels[i]}}
+			}
+		}
+	}
+	return &ByteShares{Points: points}, nil
+}
+
+// decodeVaultKey decodes an unseal key written in hex or in base64, with or
+// without padding. A key made only of an even number of hex digits is read
+// as hex; Vault's base64 keys practically always contain other characters.
+func decodeVaultKey(text string) ([]byte, error) {
+	if len(text)%2 == 0 && strings.Trim(text, "0123456789abcdefABCDEF") == "" {
+		return hex.DecodeString(text)
+	}
+	data, err := base64.RawStdEncoding.DecodeString(strings.TrimRight(text, "="))
+	if err != nil {
+		return nil, fmt.Errorf("unseal key is neither hex nor base64: %w", err)
+	}
+	return data, nil
+}
@@ -0,0 +1,35 @@
+package share
+
+import "fmt"
+
+// CurrentVersion is the version of the JSON share format WriteShares
+// writes, recorded as "version" in the keys object.
+const CurrentVersion = 2
+
+// formatVersion describes what a version of the JSON share format requires
+// beyond the layout all versions share.
+type formatVersion struct {
+	// checksums makes a checksum mandatory on every share and on the keys
+	// object.
+	checksums bool
+}
+
+// formatVersions lists every version the parsers read. Version 1 is the
+// original layout, and what a keys object without "version" holds.
+var formatVersions = map[int]formatVersion{
+	1: {},
+	2: {checksums: true},
+}
+
+// UnsupportedVersionError reports a share document whose format version
+// the parsers do not know, usually because a newer tool wrote it.
+type UnsupportedVersionError struct {
+	Version int
+}
+
+func (e *UnsupportedVersionError) Error() string {
+	if e.Version > CurrentVersion {
+		return fmt.Sprintf("this file requires a newer tool: it uses share format version %d, and this tool reads versions 1 to %d", e.Version, CurrentVersion)
+	}
+	return fmt.Sprintf("unknown share format version %d", e.Version)
+}
@@ -0,0 +1,116 @@
+package share
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ParseYAML decodes shares from a YAML document with the same layout as the
+// JSON format. Bases, n and k may be written as plain integers or strings.
+func ParseYAML(r io.Reader, opts ...ParseOption) (*Shares, error) {
+	c := newParseConfig(opts)
+	r, bom, err := skipBOM(r)
+	if err != nil {
+		return nil, err
+	}
+	var doc yaml.Node
+	if err := yaml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal yaml%s: %w", bomNote(bom), err)
+	}
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 {
+		return nil, fmt.Errorf("yaml document is empty")
+	}
+
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("yaml path $: expected a mapping, line %d", root.Line)
+	}
+
+	shares := &Shares{}
+	haveKeys := false
+	var keys []string
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		keyNode, valueNode := root.Content[i], root.Content[i+1]
+		key := keyNode.Value
+
+		if key == "keys" {
+			haveKeys = true
+			fields, err := yamlFields(valueNode, "$.keys")
+			if err != nil {
+				return nil, err
+			}
+			if shares.N, err = yamlInt(fields, "n", "$.keys"); err != nil {
+				return nil, err
+			}
+			if shares.K, err = yamlInt(fields, "k", "$.keys"); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		path := "$." + key
+		x, err := parseX(key)
+		if err != nil {
+			return nil, fmt.Errorf("yaml path %s: %w", path, err)
+		}
+		fields, err := yamlFields(valueNode, path)
+		if err != nil {
+			return nil, err
+		}
+
+		base, value := fields["base"], fields["value"]
+		if base == nil || value == nil {
+			return nil, fmt.Errorf("yaml path %s: share needs both base and value, line %d", path, valueNode.Line)
+		}
+		y, err := c.decodeY(key, x, base.Value, value.Value)
+		if err != nil {
+			return nil, fmt.Errorf("yaml path %s: %w", path, err)
+		}
+		shares.Points = append(shares.Points, Point{X: x, Y: y})
+		keys = append(keys, path)
+	}
+
+	if !haveKeys {
+		return nil, fmt.Errorf("yaml path $.keys: missing")
+	}
+	if err := checkKeys(shares.N, shares.K); err != nil {
+		return nil, err
+	}
+
+	if err := sortPoints(shares.Points, keys); err != nil {
+		return nil, err
+	}
+	return shares, nil
+}
+
+// yamlFields indexes the scalar values of a mapping node by key.
+func yamlFields(node *yaml.Node, path string) (map[string]*yaml.Node, error) {
+	if node.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("yaml path %s: expected a mapping, line %d", path, node.Line)
+	}
+
+	fields := make(map[string]*yaml.Node, len(node.Content)/2)
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		name, value := node.Content[i].Value, node.Content[i+1]
+		if value.Kind != yaml.ScalarNode {
+			return nil, fmt.Errorf("yaml path %s.%s: expected a scalar, line %d", path, name, value.Line)
+		}
+		fields[name] = value
+	}
+	return fields, nil
+}
+
+func yamlInt(fields map[string]*yaml.Node, name, path string) (int, error) {
+	node := fields[name]
+	if node == nil {
+		return 0, fmt.Errorf("yaml path %s.%s: missing", path, name)
+	}
+	v, err := strconv.Atoi(node.Value)
+	if err != nil {
+		return 0, fmt.Errorf("yaml path %s.%s: invalid integer %q, line %d", path, name, node.Value, node.Line)
+	}
+	return v, nil
+}
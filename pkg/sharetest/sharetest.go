@@ -0,0 +1,266 @@
+// Package sharetest checks that splitting and reconstructing agree: it
+// splits random secrets, writes and parses the shares, reconstructs from a
+// random k of them and compares the result with the secret. A failing case
+// is shrunk to the smallest threshold and secret that still fail.
+package sharetest
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"math/rand/v2"
+
+	"github.com/OmSingh2003/CATALOG-ASSIGNMENT/pkg/gf256"
+	"github.com/OmSingh2003/CATALOG-ASSIGNMENT/pkg/lagrange"
+	"github.com/OmSingh2003/CATALOG-ASSIGNMENT/pkg/reconstruct"
+	"github.com/OmSingh2003/CATALOG-ASSIGNMENT/pkg/share"
+)
+
+// The fields a case can be split over. FieldInteger splits over the
+// integers and reconstructs by exact interpolation over the rationals.
+const (
+	FieldInteger = "integer"
+	FieldPrime   = "prime"
+	FieldGF256   = share.FieldGF256
+)
+
+// DefaultModulus is the prime of FieldPrime cases that set none, 2^127-1.
+var DefaultModulus, _ = new(big.Int).SetString("170141183460469231731687303715884105727", 10)
+
+// bases are the encodings the shares of a case are written in, in turn.
+var bases = []string{"10", "16", "36", "62", "64"}
+
+// Case is one round trip. Seed determines the coefficients of the split
+// and the k shares reconstructed from, so that a case can be run again.
+// Commitments is the scheme FieldPrime shares carry, none if empty; they
+// make every case far slower.
+type Case struct {
+	Field       string
+	Modulus     *big.Int
+	Commitments string
+	Secret      *big.Int
+	N, K        int
+	Seed        uint64
+}
+
+func (c Case) String() string {
+	s := fmt.Sprintf("%s n=%d k=%d secret=%s seed=%d", c.Field, c.N, c.K, c.Secret, c.Seed)
+	if c.Field == FieldPrime {
+		s += " mod=" + c.modulus().String()
+	}
+	return s
+}
+
+func (c Case) modulus() *big.Int {
+	if c.Modulus != nil {
+		return c.Modulus
+	}
+	return DefaultModulus
+}
+
+// Check runs c and returns why it failed, or nil.
+func Check(c Case) (err error) {
+	// A panic is a failure like any other, and is shrunk as one.
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	random := rand.New(rand.NewPCG(c.Seed, 0))
+	var seed [8]byte
+	binary.BigEndian.PutUint64(seed[:], random.Uint64())
+	if c.Field == FieldGF256 {
+		return checkGF256(c, random, seed[:])
+	}
+
+	var modulus *big.Int
+	var opts []lagrange.Option
+	if c.Field == FieldPrime {
+		modulus = c.modulus()
+		opts = append(opts, lagrange.WithModulus(modulus))
+	} else if c.Field != FieldInteger {
+		return fmt.Errorf("unknown field %q", c.Field)
+	}
+
+	split, err := share.Split(c.Secret, c.N, c.K, modulus, share.WithSeed(seed[:]), share.WithCommitments(c.Commitments))
+	if err != nil {
+		return fmt.Errorf("split: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := share.WriteShares(&buf, split, bases); err != nil {
+		return fmt.Errorf("write: %w", err)
+	}
+	parsed, err := share.ParseShares(&buf, share.RequireThreshold())
+	if err != nil {
+		return fmt.Errorf("parse: %w", err)
+	}
+
+	result, err := reconstruct.Shares(parsed, reconstruct.Options{Lagrange: opts})
+	if err != nil {
+		return fmt.Errorf("reconstruct from all %d shares: %w", c.N, err)
+	}
+	if result.Secret.Cmp(c.Secret) != 0 {
+		return fmt.Errorf("reconstructed %s from all %d shares", result.Secret, c.N)
+	}
+
+	subset := make([]share.Point, c.K)
+	for i, j := range random.Perm(c.N)[:c.K] {
+		subset[i] = parsed.Points[j]
+	}
+	got, err := lagrange.Interpolate(subset, opts...)
+	if err != nil {
+		return fmt.Errorf("reconstruct from x=%s: %w", xs(subset), err)
+	}
+	if got.Cmp(c.Secret) != 0 {
+		return fmt.Errorf("reconstructed %s from x=%s", got, xs(subset))
+	}
+	return nil
+}
+
+func checkGF256(c Case, random *rand.Rand, seed []byte) error {
+	if c.Secret.Sign() < 0 {
+		return fmt.Errorf("negative secret %s cannot be split into bytes", c.Secret)
+	}
+	secret := c.Secret.FillBytes(make([]byte, max(1, (c.Secret.BitLen()+7)/8)))
+	split, err := gf256.SplitFrom(share.SeededReader(seed), secret, c.N, c.K)
+	if err != nil {
+		return fmt.Errorf("split: %w", err)
+	}
+	subset := make([]gf256.Share, c.K)
+	for i, j := range random.Perm(c.N)[:c.K] {
+		subset[i] = split[j]
+	}
+	got, err := gf256.Combine(subset)
+	if err != nil {
+		return fmt.Errorf("combine: %w", err)
+	}
+	if !bytes.Equal(got, secret) {
+		return fmt.Errorf("combined %x", got)
+	}
+	return nil
+}
+
+func xs(points []share.Point) string {
+	var buf bytes.Buffer
+	for i, p := range points {
+		if i > 0 {
+			buf.WriteString(",")
+		}
+		buf.WriteString(p.X.String())
+	}
+	return buf.String()
+}
+
+// Params are the ranges Run draws its cases from.
+type Params struct {
+	// Iterations is the number of cases run.
+	Iterations int
+
+	// Field is FieldInteger, FieldPrime or FieldGF256; Modulus is the
+	// prime of FieldPrime, DefaultModulus if nil, and Commitments the
+	// scheme of its shares.
+	Field       string
+	Modulus     *big.Int
+	Commitments string
+
+	// Every case has a threshold in [MinK, MaxK], n - k in [0, MaxExtra]
+	// and a secret of Bits bits, reduced modulo the prime of FieldPrime.
+	MinK, MaxK int
+	MaxExtra   int
+	Bits       int
+
+	// Seed seeds every case, so that a run can be repeated.
+	Seed uint64
+}
+
+// Failure is a case that failed, shrunk from the one first found.
+type Failure struct {
+	Case     Case
+	Original Case
+	Err      error
+}
+
+func (f *Failure) Error() string {
+	return fmt.Sprintf("round trip %s failed: %v (shrunk from %s)", f.Case, f.Err, f.Original)
+}
+
+// Run checks p.Iterations random cases and returns the first that fails,
+// shrunk, or nil if all pass.
+func Run(p Params) (*Failure, error) {
+	if p.MinK < 1 || p.MaxK < p.MinK || p.MaxExtra < 0 || p.Bits < 1 {
+		return nil, fmt.Errorf("invalid params: need 1 <= MinK <= MaxK, MaxExtra >= 0 and Bits >= 1")
+	}
+	if p.Field == FieldGF256 && p.MaxK+p.MaxExtra > gf256.MaxShares {
+		return nil, fmt.Errorf("invalid params: at most %d shares fit in GF(256)", gf256.MaxShares)
+	}
+	random := rand.New(rand.NewPCG(p.Seed, 1))
+	for i := 0; i < p.Iterations; i++ {
+		k := p.MinK + random.IntN(p.MaxK-p.MinK+1)
+		c := Case{
+			Field:       p.Field,
+			Modulus:     p.Modulus,
+			Commitments: p.Commitments,
+			Secret:      randomSecret(random, p.Bits),
+			N:           k + random.IntN(p.MaxExtra+1),
+			K:           k,
+			Seed:        random.Uint64(),
+		}
+		if p.Field == FieldPrime {
+			c.Secret.Mod(c.Secret, c.modulus())
+		}
+		if err := Check(c); err != nil {
+			shrunk := Shrink(c)
+			return &Failure{Case: shrunk, Original: c, Err: Check(shrunk)}, nil
+		}
+	}
+	return nil, nil
+}
+
+// randomSecret returns a random secret of exactly bits bits.
+func randomSecret(random *rand.Rand, bits int) *big.Int {
+	buf := make([]byte, (bits+7)/8)
+	for i := range buf {
+		buf[i] = byte(random.Uint32())
+	}
+	v := new(big.Int).SetBytes(buf)
+	v.Rsh(v, uint(len(buf)*8-bits))
+	return v.SetBit(v, bits-1, 1)
+}
+
+// Shrink returns a smaller variant of the failing case c that still
+// fails: first with k, and n with it, lowered while the case fails, then
+// with the secret moved towards zero by ever smaller steps, s - s/2,
+// s - s/4, ... s - 1, as long as one of them fails.
+func Shrink(c Case) Case {
+	for c.K > 1 {
+		smaller := c
+		smaller.K--
+		smaller.N--
+		if Check(smaller) == nil {
+			break
+		}
+		c = smaller
+	}
+	for c.Secret.Sign() > 0 {
+		smaller := c
+		smaller.Secret = new(big.Int)
+		if Check(smaller) != nil {
+			return smaller
+		}
+		shrunk := false
+		for d := uint(1); !shrunk; d++ {
+			step := new(big.Int).Rsh(c.Secret, d)
+			if step.Sign() == 0 {
+				step.SetInt64(1)
+			}
+			smaller.Secret = new(big.Int).Sub(c.Secret, step)
+			if Check(smaller) != nil {
+				c, shrunk = smaller, true
+			} else if step.Cmp(big.NewInt(1)) == 0 {
+				return c
+			}
+		}
+	}
+	return c
+}
@@ -0,0 +1,73 @@
+package sharetest
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	for _, p := range []Params{
+		{Iterations: 50, Field: FieldInteger, MinK: 1, MaxK: 6, MaxExtra: 3, Bits: 100, Seed: 1},
+		{Iterations: 50, Field: FieldPrime, MinK: 1, MaxK: 6, MaxExtra: 3, Bits: 200, Seed: 2},
+		{Iterations: 50, Field: FieldPrime, Modulus: big.NewInt(257), MinK: 1, MaxK: 6, MaxExtra: 3, Bits: 16, Seed: 3},
+		{Iterations: 50, Field: FieldGF256, MinK: 1, MaxK: 6, MaxExtra: 3, Bits: 64, Seed: 4},
+	} {
+		t.Run(p.Field, func(t *testing.T) {
+			failure, err := Run(p)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if failure != nil {
+				t.Fatal(failure)
+			}
+		})
+	}
+}
+
+func TestRunInvalidParams(t *testing.T) {
+	for _, p := range []Params{
+		{Field: FieldInteger, MinK: 0, MaxK: 3, Bits: 8},
+		{Field: FieldInteger, MinK: 3, MaxK: 2, Bits: 8},
+		{Field: FieldInteger, MinK: 1, MaxK: 2, MaxExtra: -1, Bits: 8},
+		{Field: FieldInteger, MinK: 1, MaxK: 2, Bits: 0},
+		{Field: FieldGF256, MinK: 1, MaxK: 200, MaxExtra: 100, Bits: 8},
+	} {
+		if _, err := Run(p); err == nil {
+			t.Errorf("Run(%+v) returned no error", p)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	secret, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+	for _, c := range []Case{
+		{Field: FieldInteger, Secret: secret, N: 5, K: 3, Seed: 7},
+		{Field: FieldPrime, Secret: secret, N: 5, K: 3, Seed: 7},
+		{Field: FieldGF256, Secret: secret, N: 5, K: 3, Seed: 7},
+		{Field: FieldPrime, Secret: big.NewInt(0), N: 1, K: 1, Seed: 7},
+	} {
+		if err := Check(c); err != nil {
+			t.Errorf("Check(%s): %v", c, err)
+		}
+	}
+
+	// A secret outside the field cannot round trip.
+	c := Case{Field: FieldPrime, Modulus: big.NewInt(101), Secret: big.NewInt(101), N: 3, K: 2, Seed: 7}
+	if err := Check(c); err == nil {
+		t.Errorf("Check(%s) passed", c)
+	}
+}
+
+func TestShrink(t *testing.T) {
+	// An unknown field fails every case, so Shrink takes it down to the
+	// smallest one.
+	c := Case{Field: "nope", Secret: big.NewInt(1000), N: 6, K: 4, Seed: 1}
+	shrunk := Shrink(c)
+	if shrunk.K != 1 || shrunk.N != 3 || shrunk.Secret.Sign() != 0 {
+		t.Errorf("Shrink(%s) = %s, want k=1 n=3 secret=0", c, shrunk)
+	}
+	if err := Check(shrunk); err == nil || !strings.Contains(err.Error(), "unknown field") {
+		t.Errorf("Check of the shrunk case: err = %v, want an unknown field", err)
+	}
+}
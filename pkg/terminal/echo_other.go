@@ -0,0 +1,9 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd)
+
+package terminal
+
+import "errors"
+
+func disableEcho(fd uintptr) (func(), error) {
+	return nil, errors.New("cannot turn off terminal echo on this platform")
+}
@@ -0,0 +1,27 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd
+
+package terminal
+
+import (
+	"fmt"
+	"syscall"
+	"unsafe"
+)
+
+// disableEcho turns off echo on the terminal fd and returns a function
+// restoring its previous state.
+func disableEcho(fd uintptr) (func(), error) {
+	var old syscall.Termios
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, getTermios, uintptr(unsafe.Pointer(&old))); errno != 0 {
+		return nil, fmt.Errorf("failed to read terminal state: %w", errno)
+	}
+	quiet := old
+	quiet.Lflag &^= syscall.ECHO
+	quiet.Lflag |= syscall.ICANON | syscall.ISIG
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, setTermios, uintptr(unsafe.Pointer(&quiet))); errno != 0 {
+		return nil, fmt.Errorf("failed to turn off echo: %w", errno)
+	}
+	return func() {
+		syscall.Syscall(syscall.SYS_IOCTL, fd, setTermios, uintptr(unsafe.Pointer(&old)))
+	}, nil
+}
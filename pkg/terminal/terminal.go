@@ -0,0 +1,56 @@
+// Package terminal reads passphrases from the controlling terminal
+// without echoing them.
+package terminal
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+)
+
+// ErrNoTerminal is returned when there is no controlling terminal to
+// prompt on.
+var ErrNoTerminal = errors.New("no terminal to prompt on")
+
+// ReadPassphrase writes prompt to the controlling terminal and reads one
+// line from it with echo turned off. Standard input may be in use for
+// share data, so it is never read.
+func ReadPassphrase(prompt string) ([]byte, error) {
+	tty, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if err != nil {
+		return nil, ErrNoTerminal
+	}
+	defer tty.Close()
+
+	restore, err := disableEcho(tty.Fd())
+	if err != nil {
+		return nil, err
+	}
+	// An interrupt must not leave the terminal without echo.
+	interrupted := make(chan os.Signal, 1)
+	signal.Notify(interrupted, os.Interrupt)
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-interrupted:
+			restore()
+			fmt.Fprintln(tty)
+			os.Exit(130)
+		case <-done:
+		}
+	}()
+
+	fmt.Fprint(tty, prompt)
+	line, err := bufio.NewReader(tty).ReadString('\n')
+	close(done)
+	signal.Stop(interrupted)
+	restore()
+	fmt.Fprintln(tty)
+	if err != nil && line == "" {
+		return nil, fmt.Errorf("failed to read passphrase: %w", err)
+	}
+	return []byte(strings.TrimRight(line, "\r\n")), nil
+}
@@ -0,0 +1,220 @@
+// Package testvectors generates a corpus of valid and invalid share files,
+// each with a sidecar stating the secret it reconstructs to or a substring
+// of the error it must fail with.
+package testvectors
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"path/filepath"
+
+	"github.com/OmSingh2003/CATALOG-ASSIGNMENT/pkg/share"
+)
+
+// DefaultSeed seeds the corpus unless Config.Seed is set, so that the same
+// corpus is generated every time.
+var DefaultSeed = []byte("CATALOG-ASSIGNMENT test vectors")
+
+// Config configures Generate.
+type Config struct {
+	// Digits is the number of decimal digits of the secret of the huge
+	// value vector.
+	Digits int
+
+	// Seed seeds every split and random secret; nil uses DefaultSeed.
+	Seed []byte
+}
+
+// Vector is one share file of the corpus. Exactly one of Secret, the
+// decimal secret the file reconstructs to, and Error, a substring of the
+// error reconstructing it fails with, is set.
+type Vector struct {
+	Name   string `json:"-"`
+	Data   []byte `json:"-"`
+	Secret string `json:"secret,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Generate returns the corpus: splits over a range of k and n, one split
+// per base from 2 to 62, negative values, a huge secret, and files with a
+// duplicate x, no keys object, a bad base, a bad digit, a truncated value
+// and too few shares.
+func Generate(c Config) ([]Vector, error) {
+	if c.Digits < 1 {
+		return nil, fmt.Errorf("invalid digit count %d: must be at least 1", c.Digits)
+	}
+	seed := c.Seed
+	if seed == nil {
+		seed = DefaultSeed
+	}
+	g := &generator{seed: seed, random: share.SeededReader(seed)}
+
+	for _, kn := range [][2]int{{1, 1}, {1, 3}, {2, 2}, {2, 3}, {3, 5}, {5, 8}, {10, 12}} {
+		g.split(fmt.Sprintf("valid_k%d_n%d", kn[0], kn[1]), g.secret(8), kn[1], kn[0], []string{"10"})
+	}
+	for base := 2; base <= 62; base++ {
+		g.split(fmt.Sprintf("base_%02d", base), g.secret(8), 4, 3, []string{fmt.Sprint(base)})
+	}
+	g.split("mixed_bases", g.secret(16), 5, 3, []string{"2", "10", "16", "36", "62"})
+	g.split(fmt.Sprintf("huge_%d_digits", c.Digits), g.hugeSecret(c.Digits), 5, 3, []string{"10"})
+	g.negative()
+
+	g.invalid("invalid_duplicate_x", `duplicate key "2"`, `{
+    "keys": {"n": 3, "k": 2},
+    "1": {"base": "10", "value": "5"},
+    "2": {"base": "10", "value": "8"},
+    "2": {"base": "10", "value": "9"}
+}
+`)
+	g.invalid("invalid_missing_keys", `missing the required "keys" object`, `{
+    "1": {"base": "10", "value": "5"},
+    "2": {"base": "10", "value": "8"}
+}
+`)
+	g.invalid("invalid_bad_base", "invalid base 99", `{
+    "keys": {"n": 2, "k": 2},
+    "1": {"base": "99", "value": "5"},
+    "2": {"base": "10", "value": "8"}
+}
+`)
+	g.invalid("invalid_bad_digit", "failed to decode y value for x=1", `{
+    "keys": {"n": 2, "k": 2},
+    "1": {"base": "2", "value": "1021"},
+    "2": {"base": "10", "value": "8"}
+}
+`)
+	g.invalid("invalid_too_few_shares", "found 2, need 3", `{
+    "keys": {"n": 3, "k": 3},
+    "1": {"base": "10", "value": "5"},
+    "2": {"base": "10", "value": "8"}
+}
+`)
+	g.truncated("valid_k3_n5")
+	if g.err != nil {
+		return nil, g.err
+	}
+	return g.vectors, nil
+}
+
+// Write writes every vector to dir as <name>.json, with its sidecar
+// <name>.expected.json holding {"secret": ...} or {"error": ...}.
+func Write(dir string, vectors []Vector) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	for _, v := range vectors {
+		if err := os.WriteFile(filepath.Join(dir, v.Name+".json"), v.Data, 0o644); err != nil {
+			return err
+		}
+		sidecar, _ := json.MarshalIndent(v, "", "    ")
+		if err := os.WriteFile(filepath.Join(dir, v.Name+".expected.json"), append(sidecar, '\n'), 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// generator accumulates vectors, keeping the first error.
+type generator struct {
+	seed    []byte
+	random  io.Reader
+	vectors []Vector
+	err     error
+}
+
+// secret returns a random secret of size bytes.
+func (g *generator) secret(size int) *big.Int {
+	buf := make([]byte, size)
+	g.read(buf)
+	return new(big.Int).SetBytes(buf)
+}
+
+// hugeSecret returns a random secret of exactly digits decimal digits.
+func (g *generator) hugeSecret(digits int) *big.Int {
+	buf := make([]byte, digits)
+	g.read(buf)
+	text := make([]byte, digits)
+	for i, b := range buf {
+		text[i] = '0' + b%10
+	}
+	text[0] = '1' + buf[0]%9
+	v, _ := new(big.Int).SetString(string(text), 10)
+	return v
+}
+
+func (g *generator) read(buf []byte) {
+	if _, err := io.ReadFull(g.random, buf); err != nil && g.err == nil {
+		g.err = err
+	}
+}
+
+// split adds a vector of secret split into n shares with threshold k.
+// Every split is seeded with its name as well, so that adding a vector
+// leaves the shares of the others as they were.
+func (g *generator) split(name string, secret *big.Int, n, k int, bases []string) {
+	if g.err != nil {
+		return
+	}
+	seed := append(append([]byte(nil), g.seed...), name...)
+	shares, err := share.Split(secret, n, k, nil, share.WithSeed(seed))
+	if err != nil {
+		g.err = fmt.Errorf("%s: %w", name, err)
+		return
+	}
+	g.write(name, shares, bases, secret)
+}
+
+// negative adds a vector whose secret and share values are negative.
+func (g *generator) negative() {
+	coefficients := []*big.Int{g.secret(8), g.secret(8), g.secret(8)}
+	shares := &share.Shares{N: 4, K: 3, Deterministic: true}
+	for _, c := range coefficients {
+		c.Neg(c)
+	}
+	for x := int64(1); x <= 4; x++ {
+		y := new(big.Int)
+		for i := len(coefficients) - 1; i >= 0; i-- {
+			y.Mul(y, big.NewInt(x))
+			y.Add(y, coefficients[i])
+		}
+		shares.Points = append(shares.Points, share.Point{X: big.NewInt(x), Y: y})
+	}
+	g.write("negative", shares, []string{"10", "16"}, coefficients[0])
+}
+
+func (g *generator) write(name string, shares *share.Shares, bases []string, secret *big.Int) {
+	if g.err != nil {
+		return
+	}
+	var buf bytes.Buffer
+	if err := share.WriteShares(&buf, shares, bases); err != nil {
+		g.err = fmt.Errorf("%s: %w", name, err)
+		return
+	}
+	g.vectors = append(g.vectors, Vector{Name: name, Data: buf.Bytes(), Secret: secret.String()})
+}
+
+func (g *generator) invalid(name, errorText, data string) {
+	g.vectors = append(g.vectors, Vector{Name: name, Data: []byte(data), Error: errorText})
+}
+
+// truncated adds a copy of the vector from cut off in the middle of its
+// last share value.
+func (g *generator) truncated(from string) {
+	for _, v := range g.vectors {
+		if v.Name != from {
+			continue
+		}
+		marker := []byte(`"value": "`)
+		cut := bytes.LastIndex(v.Data, marker) + len(marker) + 3
+		g.invalid("invalid_truncated_value", "unexpected EOF", string(v.Data[:cut]))
+		return
+	}
+	if g.err == nil {
+		g.err = fmt.Errorf("no vector %s to truncate", from)
+	}
+}
@@ -0,0 +1,122 @@
+package testvectors
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/OmSingh2003/CATALOG-ASSIGNMENT/pkg/reconstruct"
+	"github.com/OmSingh2003/CATALOG-ASSIGNMENT/pkg/share"
+)
+
+func TestGenerate(t *testing.T) {
+	vectors, err := Generate(Config{Digits: 120})
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := make(map[string]bool)
+	for _, v := range vectors {
+		names[v.Name] = true
+		t.Run(v.Name, func(t *testing.T) {
+			if (v.Secret == "") == (v.Error == "") {
+				t.Fatalf("secret %q and error %q: exactly one must be set", v.Secret, v.Error)
+			}
+			result, err := reconstructData(v.Data)
+			if v.Error != "" {
+				if err == nil || !strings.Contains(err.Error(), v.Error) {
+					t.Errorf("err = %v, want one containing %q", err, v.Error)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := result.Secret.String(); got != v.Secret {
+				t.Errorf("secret = %s, want %s", got, v.Secret)
+			}
+		})
+	}
+	for _, name := range []string{"valid_k1_n1", "base_02", "base_62", "mixed_bases", "huge_120_digits", "negative", "invalid_truncated_value"} {
+		if !names[name] {
+			t.Errorf("no vector %s", name)
+		}
+	}
+	for _, v := range vectors {
+		if v.Name == "huge_120_digits" && len(v.Secret) != 120 {
+			t.Errorf("huge secret has %d digits, want 120", len(v.Secret))
+		}
+	}
+}
+
+func reconstructData(data []byte) (*reconstruct.Result, error) {
+	shares, err := share.ParseShares(bytes.NewReader(data), share.RequireThreshold())
+	if err != nil {
+		return nil, err
+	}
+	return reconstruct.Shares(shares, reconstruct.Options{})
+}
+
+func TestGenerateDeterministic(t *testing.T) {
+	a, err := Generate(Config{Digits: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := Generate(Config{Digits: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := Generate(Config{Digits: 10, Seed: []byte("other")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(a) != len(b) {
+		t.Fatalf("%d and %d vectors", len(a), len(b))
+	}
+	same := true
+	for i := range a {
+		if a[i].Name != b[i].Name || !bytes.Equal(a[i].Data, b[i].Data) {
+			t.Errorf("vector %s differs between runs", a[i].Name)
+		}
+		same = same && bytes.Equal(a[i].Data, c[i].Data)
+	}
+	if same {
+		t.Error("a different seed gives the same corpus")
+	}
+	if _, err := Generate(Config{}); err == nil {
+		t.Error("Generate with no digits returned no error")
+	}
+}
+
+func TestWrite(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "vectors")
+	vectors := []Vector{
+		{Name: "valid", Data: []byte(`{"keys": {"n": 1, "k": 1}, "1": {"base": "10", "value": "4"}}`), Secret: "4"},
+		{Name: "invalid", Data: []byte(`{}`), Error: "missing"},
+	}
+	if err := Write(dir, vectors); err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range vectors {
+		data, err := os.ReadFile(filepath.Join(dir, v.Name+".json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(data, v.Data) {
+			t.Errorf("%s.json = %s, want %s", v.Name, data, v.Data)
+		}
+		sidecar, err := os.ReadFile(filepath.Join(dir, v.Name+".expected.json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got Vector
+		if err := json.Unmarshal(sidecar, &got); err != nil {
+			t.Fatal(err)
+		}
+		if got.Secret != v.Secret || got.Error != v.Error {
+			t.Errorf("%s.expected.json = %s", v.Name, sidecar)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+// Package watch collects the shares of the share files dropped into a
+// directory, as custodians deliver them, until they reach their threshold.
+package watch
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/OmSingh2003/CATALOG-ASSIGNMENT/pkg/share"
+)
+
+// Options configures Dir.
+type Options struct {
+	// Interval is the time between two scans of the directory.
+	Interval time.Duration
+
+	// Read parses the share file at path.
+	Read func(path string) (*share.Shares, error)
+
+	// Skipped, if set, is called for every file skipped and the reason:
+	// the error Read returned, or a *share.ConflictError if its shares
+	// disagree with the ones received before.
+	Skipped func(path string, err error)
+
+	// Duplicate, if set, is called for every share whose x was received
+	// before, with the same y.
+	Duplicate func(d share.Duplicate)
+
+	// Received, if set, is called with the shares received so far every
+	// time a file adds some.
+	Received func(s *share.Shares)
+}
+
+// Dir polls dir until the *.json files in it hold k shares, and returns
+// them merged with the files they came from. A skipped file is read again
+// once it changes, so that a file caught half written is retried, and an
+// empty one is not read until it is written. Dir returns the error of ctx
+// if it is done first.
+func Dir(ctx context.Context, dir string, opts Options) (*share.Shares, []string, error) {
+	type fileState struct {
+		size    int64
+		modTime time.Time
+	}
+	seen := make(map[string]fileState)
+	set := share.NewShareSet()
+	var files []string
+
+	for {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to watch %s: %w", dir, err)
+		}
+		for _, entry := range entries {
+			if entry.IsDir() || !strings.EqualFold(filepath.Ext(entry.Name()), ".json") {
+				continue
+			}
+			info, err := entry.Info()
+			if err != nil {
+				continue
+			}
+			state := fileState{size: info.Size(), modTime: info.ModTime()}
+			if prev, ok := seen[entry.Name()]; ok && prev == state {
+				continue
+			}
+			seen[entry.Name()] = state
+			if state.size == 0 {
+				continue
+			}
+
+			path := filepath.Join(dir, entry.Name())
+			shares, err := opts.Read(path)
+			if err != nil {
+				opts.skipped(path, err)
+				continue
+			}
+			// A file that disagrees with the earlier ones on k, the field,
+			// the secret hash or a share adds nothing.
+			before, duplicates := set.Len(), len(set.Duplicates)
+			if conflicts := set.Add(path, shares); conflicts != nil {
+				opts.skipped(path, &share.ConflictError{Conflicts: conflicts})
+				continue
+			}
+			if opts.Duplicate != nil {
+				for _, d := range set.Duplicates[duplicates:] {
+					opts.Duplicate(d)
+				}
+			}
+			if set.Len() == before {
+				continue
+			}
+			files = append(files, path)
+			merged, err := set.Shares()
+			if err != nil {
+				return nil, nil, err
+			}
+			if opts.Received != nil {
+				opts.Received(merged)
+			}
+			if len(merged.Points) >= merged.K {
+				return merged, files, nil
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return nil, nil, fmt.Errorf("stopped watching %s after receiving %d shares: %w", dir, set.Len(), ctx.Err())
+		case <-time.After(opts.Interval):
+		}
+	}
+}
+
+func (opts *Options) skipped(path string, err error) {
+	if opts.Skipped != nil {
+		opts.Skipped(path, err)
+	}
+}
@@ -0,0 +1,120 @@
+package watch
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/OmSingh2003/CATALOG-ASSIGNMENT/pkg/share"
+)
+
+func read(path string) (*share.Shares, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return share.ParseShares(f)
+}
+
+// write writes a share file into dir. It is called from the goroutines
+// that deliver files late too, so it does not stop the test.
+func write(t *testing.T, dir, name, data string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0o600); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestDir(t *testing.T) {
+	dir := t.TempDir()
+	write(t, dir, "a.json", `{"keys": {"n": 4, "k": 3}, "1": {"base": "10", "value": "4"}, "2": {"base": "2", "value": "111"}}`)
+	write(t, dir, "b.json", `{"keys": {"n": 4, "k": 3}, "2": {"base": "10", "value": "7"}}`)
+	write(t, dir, "c.json", `{"keys": {"n": 4, "k": 2}, "3": {"base": "10", "value": "12"}}`)
+	write(t, dir, "d.json", `not json`)
+	write(t, dir, "empty.json", ``)
+	write(t, dir, "notes.txt", `{"keys": {"n": 4, "k": 3}, "6": {"base": "10", "value": "39"}}`)
+
+	var skipped []string
+	var duplicates []share.Duplicate
+	var received []int
+	opts := Options{
+		Interval: time.Millisecond,
+		Read:     read,
+		Skipped: func(path string, err error) {
+			var conflict *share.ConflictError
+			if filepath.Base(path) == "c.json" && !errors.As(err, &conflict) {
+				t.Errorf("c.json skipped with %v, want a conflict", err)
+			}
+			skipped = append(skipped, filepath.Base(path))
+		},
+		Duplicate: func(d share.Duplicate) { duplicates = append(duplicates, d) },
+		Received:  func(s *share.Shares) { received = append(received, len(s.Points)) },
+	}
+
+	// The first scan finds two shares; the third arrives later.
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		time.Sleep(20 * time.Millisecond)
+		write(t, dir, "e.json", `{"keys": {"n": 4, "k": 3}, "6": {"base": "10", "value": "39"}}`)
+	}()
+	merged, files, err := Dir(context.Background(), dir, opts)
+	<-done
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(merged.Points) != 3 || merged.K != 3 {
+		t.Errorf("merged %d shares with k=%d, want 3 with k=3", len(merged.Points), merged.K)
+	}
+	if got := strings.Join(files, ","); got != filepath.Join(dir, "a.json")+","+filepath.Join(dir, "e.json") {
+		t.Errorf("files = %s, want a.json and e.json", got)
+	}
+	if strings.Join(skipped, ",") != "c.json,d.json" {
+		t.Errorf("skipped %v, want c.json and d.json", skipped)
+	}
+	if len(duplicates) != 1 || duplicates[0].X != "2" || duplicates[0].First != filepath.Join(dir, "a.json") {
+		t.Errorf("duplicates = %+v, want x=2 from a.json", duplicates)
+	}
+	if len(received) != 2 || received[0] != 2 || received[1] != 3 {
+		t.Errorf("received %v, want 2 then 3 shares", received)
+	}
+}
+
+func TestDirRetriesChangedFile(t *testing.T) {
+	dir := t.TempDir()
+	write(t, dir, "a.json", `{"keys": {"n": 2, "k": 2}, "1": {"base": "10", "value": "4"}, "2": {"base": "10"`)
+	var skips int
+	opts := Options{Interval: time.Millisecond, Read: read, Skipped: func(string, error) { skips++ }}
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		write(t, dir, "a.json", `{"keys": {"n": 2, "k": 2}, "1": {"base": "10", "value": "4"}, "2": {"base": "10", "value": "7"}}`)
+	}()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	merged, _, err := Dir(ctx, dir, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(merged.Points) != 2 || skips != 1 {
+		t.Errorf("merged %d shares after %d skips, want 2 after the half-written file was skipped once", len(merged.Points), skips)
+	}
+}
+
+func TestDirStops(t *testing.T) {
+	dir := t.TempDir()
+	write(t, dir, "a.json", `{"keys": {"n": 4, "k": 3}, "1": {"base": "10", "value": "4"}}`)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, _, err := Dir(ctx, dir, Options{Interval: time.Millisecond, Read: read})
+	if !errors.Is(err, context.DeadlineExceeded) || !strings.Contains(err.Error(), "after receiving 1 shares") {
+		t.Errorf("err = %v, want the deadline after 1 share", err)
+	}
+	if _, _, err := Dir(context.Background(), filepath.Join(dir, "missing"), Options{Read: read}); err == nil {
+		t.Error("watching a missing directory passed")
+	}
+}
@@ -0,0 +1,422 @@
+// Shamir secret sharing service: the split and reconstruct operations of
+// the command line tool, served over gRPC. The Go code in this directory is
+// generated from this file with protoc-gen-go and protoc-gen-go-grpc, and
+// pkg/grpcserver serves it with pkg/share and pkg/reconstruct, as
+// pkg/server does over HTTP. Regenerate it from the repository root with
+//
+//   protoc -I proto --go_out=proto --go_opt=paths=source_relative \
+//     --go-grpc_out=proto --go-grpc_opt=paths=source_relative \
+//     proto/shamir/v1/shamir.proto
+//
+// Errors map to status codes as follows:
+//   INVALID_ARGUMENT     the shares or request do not parse, or a base is
+//                        invalid
+//   FAILED_PRECONDITION  fewer shares than the threshold, or shares with
+//                        commitments but no modulus
+//   DATA_LOSS            a checksum or commitment does not match, or an
+//                        unused share does not lie on the polynomial
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.12
+// 	protoc        (unknown)
+// source: shamir/v1/shamir.proto
+
+package shamirv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Shares is a share set, as a JSON share document holds one.
+type Shares struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	N      uint32                 `protobuf:"varint,1,opt,name=n,proto3" json:"n,omitempty"`
+	K      uint32                 `protobuf:"varint,2,opt,name=k,proto3" json:"k,omitempty"`
+	Shares []*Share               `protobuf:"bytes,3,rep,name=shares,proto3" json:"shares,omitempty"`
+	// modulus, big-endian, selects the prime field; empty means the
+	// integers.
+	Modulus       []byte `protobuf:"bytes,4,opt,name=modulus,proto3" json:"modulus,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Shares) Reset() {
+	*x = Shares{}
+	mi := &file_shamir_v1_shamir_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Shares) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Shares) ProtoMessage() {}
+
+func (x *Shares) ProtoReflect() protoreflect.Message {
+	mi := &file_shamir_v1_shamir_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Shares.ProtoReflect.Descriptor instead.
+func (*Shares) Descriptor() ([]byte, []int) {
+	return file_shamir_v1_shamir_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Shares) GetN() uint32 {
+	if x != nil {
+		return x.N
+	}
+	return 0
+}
+
+func (x *Shares) GetK() uint32 {
+	if x != nil {
+		return x.K
+	}
+	return 0
+}
+
+func (x *Shares) GetShares() []*Share {
+	if x != nil {
+		return x.Shares
+	}
+	return nil
+}
+
+func (x *Shares) GetModulus() []byte {
+	if x != nil {
+		return x.Modulus
+	}
+	return nil
+}
+
+// Share is one point. Values travel as big-endian magnitudes with a sign
+// flag, since shares over the integers may have a negative x or y; base
+// says how the value was or should be written in a share file, "10" if
+// empty.
+type Share struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	X             []byte                 `protobuf:"bytes,1,opt,name=x,proto3" json:"x,omitempty"`
+	Y             []byte                 `protobuf:"bytes,2,opt,name=y,proto3" json:"y,omitempty"`
+	YNegative     bool                   `protobuf:"varint,3,opt,name=y_negative,json=yNegative,proto3" json:"y_negative,omitempty"`
+	Base          string                 `protobuf:"bytes,4,opt,name=base,proto3" json:"base,omitempty"`
+	XNegative     bool                   `protobuf:"varint,5,opt,name=x_negative,json=xNegative,proto3" json:"x_negative,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Share) Reset() {
+	*x = Share{}
+	mi := &file_shamir_v1_shamir_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Share) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Share) ProtoMessage() {}
+
+func (x *Share) ProtoReflect() protoreflect.Message {
+	mi := &file_shamir_v1_shamir_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Share.ProtoReflect.Descriptor instead.
+func (*Share) Descriptor() ([]byte, []int) {
+	return file_shamir_v1_shamir_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Share) GetX() []byte {
+	if x != nil {
+		return x.X
+	}
+	return nil
+}
+
+func (x *Share) GetY() []byte {
+	if x != nil {
+		return x.Y
+	}
+	return nil
+}
+
+func (x *Share) GetYNegative() bool {
+	if x != nil {
+		return x.YNegative
+	}
+	return false
+}
+
+func (x *Share) GetBase() string {
+	if x != nil {
+		return x.Base
+	}
+	return ""
+}
+
+func (x *Share) GetXNegative() bool {
+	if x != nil {
+		return x.XNegative
+	}
+	return false
+}
+
+type Secret struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// value is the big-endian magnitude of the secret.
+	Value    []byte `protobuf:"bytes,1,opt,name=value,proto3" json:"value,omitempty"`
+	Negative bool   `protobuf:"varint,2,opt,name=negative,proto3" json:"negative,omitempty"`
+	// points_used holds every interpolated share.
+	PointsUsed    []*Share `protobuf:"bytes,3,rep,name=points_used,json=pointsUsed,proto3" json:"points_used,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Secret) Reset() {
+	*x = Secret{}
+	mi := &file_shamir_v1_shamir_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Secret) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Secret) ProtoMessage() {}
+
+func (x *Secret) ProtoReflect() protoreflect.Message {
+	mi := &file_shamir_v1_shamir_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Secret.ProtoReflect.Descriptor instead.
+func (*Secret) Descriptor() ([]byte, []int) {
+	return file_shamir_v1_shamir_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Secret) GetValue() []byte {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+func (x *Secret) GetNegative() bool {
+	if x != nil {
+		return x.Negative
+	}
+	return false
+}
+
+func (x *Secret) GetPointsUsed() []*Share {
+	if x != nil {
+		return x.PointsUsed
+	}
+	return nil
+}
+
+type SplitRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// secret is the big-endian secret to split.
+	Secret []byte `protobuf:"bytes,1,opt,name=secret,proto3" json:"secret,omitempty"`
+	N      uint32 `protobuf:"varint,2,opt,name=n,proto3" json:"n,omitempty"`
+	K      uint32 `protobuf:"varint,3,opt,name=k,proto3" json:"k,omitempty"`
+	// modulus, big-endian, splits over the prime field it names.
+	Modulus []byte `protobuf:"bytes,4,opt,name=modulus,proto3" json:"modulus,omitempty"`
+	// base is the base hint every returned share carries.
+	Base          string `protobuf:"bytes,5,opt,name=base,proto3" json:"base,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SplitRequest) Reset() {
+	*x = SplitRequest{}
+	mi := &file_shamir_v1_shamir_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SplitRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SplitRequest) ProtoMessage() {}
+
+func (x *SplitRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shamir_v1_shamir_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SplitRequest.ProtoReflect.Descriptor instead.
+func (*SplitRequest) Descriptor() ([]byte, []int) {
+	return file_shamir_v1_shamir_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *SplitRequest) GetSecret() []byte {
+	if x != nil {
+		return x.Secret
+	}
+	return nil
+}
+
+func (x *SplitRequest) GetN() uint32 {
+	if x != nil {
+		return x.N
+	}
+	return 0
+}
+
+func (x *SplitRequest) GetK() uint32 {
+	if x != nil {
+		return x.K
+	}
+	return 0
+}
+
+func (x *SplitRequest) GetModulus() []byte {
+	if x != nil {
+		return x.Modulus
+	}
+	return nil
+}
+
+func (x *SplitRequest) GetBase() string {
+	if x != nil {
+		return x.Base
+	}
+	return ""
+}
+
+var File_shamir_v1_shamir_proto protoreflect.FileDescriptor
+
+const file_shamir_v1_shamir_proto_rawDesc = "" +
+	"\n" +
+	"\x16shamir/v1/shamir.proto\x12\tshamir.v1\"h\n" +
+	"\x06Shares\x12\f\n" +
+	"\x01n\x18\x01 \x01(\rR\x01n\x12\f\n" +
+	"\x01k\x18\x02 \x01(\rR\x01k\x12(\n" +
+	"\x06shares\x18\x03 \x03(\v2\x10.shamir.v1.ShareR\x06shares\x12\x18\n" +
+	"\amodulus\x18\x04 \x01(\fR\amodulus\"u\n" +
+	"\x05Share\x12\f\n" +
+	"\x01x\x18\x01 \x01(\fR\x01x\x12\f\n" +
+	"\x01y\x18\x02 \x01(\fR\x01y\x12\x1d\n" +
+	"\n" +
+	"y_negative\x18\x03 \x01(\bR\tyNegative\x12\x12\n" +
+	"\x04base\x18\x04 \x01(\tR\x04base\x12\x1d\n" +
+	"\n" +
+	"x_negative\x18\x05 \x01(\bR\txNegative\"m\n" +
+	"\x06Secret\x12\x14\n" +
+	"\x05value\x18\x01 \x01(\fR\x05value\x12\x1a\n" +
+	"\bnegative\x18\x02 \x01(\bR\bnegative\x121\n" +
+	"\vpoints_used\x18\x03 \x03(\v2\x10.shamir.v1.ShareR\n" +
+	"pointsUsed\"p\n" +
+	"\fSplitRequest\x12\x16\n" +
+	"\x06secret\x18\x01 \x01(\fR\x06secret\x12\f\n" +
+	"\x01n\x18\x02 \x01(\rR\x01n\x12\f\n" +
+	"\x01k\x18\x03 \x01(\rR\x01k\x12\x18\n" +
+	"\amodulus\x18\x04 \x01(\fR\amodulus\x12\x12\n" +
+	"\x04base\x18\x05 \x01(\tR\x04base2r\n" +
+	"\x06Shamir\x123\n" +
+	"\vReconstruct\x12\x11.shamir.v1.Shares\x1a\x11.shamir.v1.Secret\x123\n" +
+	"\x05Split\x12\x17.shamir.v1.SplitRequest\x1a\x11.shamir.v1.SharesBDZBgithub.com/OmSingh2003/CATALOG-ASSIGNMENT/proto/shamir/v1;shamirv1b\x06proto3"
+
+var (
+	file_shamir_v1_shamir_proto_rawDescOnce sync.Once
+	file_shamir_v1_shamir_proto_rawDescData []byte
+)
+
+func file_shamir_v1_shamir_proto_rawDescGZIP() []byte {
+	file_shamir_v1_shamir_proto_rawDescOnce.Do(func() {
+		file_shamir_v1_shamir_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_shamir_v1_shamir_proto_rawDesc), len(file_shamir_v1_shamir_proto_rawDesc)))
+	})
+	return file_shamir_v1_shamir_proto_rawDescData
+}
+
+var file_shamir_v1_shamir_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_shamir_v1_shamir_proto_goTypes = []any{
+	(*Shares)(nil),       // 0: shamir.v1.Shares
+	(*Share)(nil),        // 1: shamir.v1.Share
+	(*Secret)(nil),       // 2: shamir.v1.Secret
+	(*SplitRequest)(nil), // 3: shamir.v1.SplitRequest
+}
+var file_shamir_v1_shamir_proto_depIdxs = []int32{
+	1, // 0: shamir.v1.Shares.shares:type_name -> shamir.v1.Share
+	1, // 1: shamir.v1.Secret.points_used:type_name -> shamir.v1.Share
+	0, // 2: shamir.v1.Shamir.Reconstruct:input_type -> shamir.v1.Shares
+	3, // 3: shamir.v1.Shamir.Split:input_type -> shamir.v1.SplitRequest
+	2, // 4: shamir.v1.Shamir.Reconstruct:output_type -> shamir.v1.Secret
+	0, // 5: shamir.v1.Shamir.Split:output_type -> shamir.v1.Shares
+	4, // [4:6] is the sub-list for method output_type
+	2, // [2:4] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_shamir_v1_shamir_proto_init() }
+func file_shamir_v1_shamir_proto_init() {
+	if File_shamir_v1_shamir_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_shamir_v1_shamir_proto_rawDesc), len(file_shamir_v1_shamir_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   4,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_shamir_v1_shamir_proto_goTypes,
+		DependencyIndexes: file_shamir_v1_shamir_proto_depIdxs,
+		MessageInfos:      file_shamir_v1_shamir_proto_msgTypes,
+	}.Build()
+	File_shamir_v1_shamir_proto = out.File
+	file_shamir_v1_shamir_proto_goTypes = nil
+	file_shamir_v1_shamir_proto_depIdxs = nil
+}